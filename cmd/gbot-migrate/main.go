@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/bradykim7/gbot/internal/crawler"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.uber.org/zap"
+)
+
+// seedFood is one entry of the seed foods JSON file
+type seedFood struct {
+	Name     string          `json:"name"`
+	FoodType models.FoodType `json:"food_type"`
+}
+
+func main() {
+	seedPath := flag.String("seed-foods", "", "path to a JSON array of {\"name\", \"food_type\"} foods to add")
+	reconcile := flag.Bool("reconcile-notified", false, "set notified on products already recorded in notified_products")
+	foodGuild := flag.String("food-guild", "", "guild ID whose food list -seed-foods adds to (default: the shared list)")
+	assignShared := flag.Bool("assign-shared-foods", false, "move the shared foods, including those saved before food lists were per guild, to -food-guild")
+	canonicalizeURLs := flag.Bool("canonicalize-urls", false, "rewrite product and notified product URLs saved before URLs were canonicalized, removing duplicates")
+	flag.Parse()
+
+	// Initialize logger
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+
+	log := logger.Named("gbot-migrate")
+
+	if *assignShared && *foodGuild == "" {
+		fmt.Fprintln(os.Stderr, "-assign-shared-foods requires -food-guild")
+		os.Exit(2)
+	}
+
+	err = run(log, options{
+		seedPath:         *seedPath,
+		foodGuild:        *foodGuild,
+		assignShared:     *assignShared,
+		canonicalizeURLs: *canonicalizeURLs,
+		reconcile:        *reconcile,
+	})
+	if err != nil {
+		log.Error("Migration failed", zap.Error(err))
+	}
+
+	// Flush buffered logs before exiting; os.Exit skips deferred calls
+	_ = logger.Sync()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// options are the optional steps selected by flags
+type options struct {
+	seedPath         string
+	foodGuild        string
+	assignShared     bool
+	canonicalizeURLs bool
+	reconcile        bool
+}
+
+// run creates every index the bot and crawler use, then optionally assigns
+// shared foods to a guild, seeds foods, canonicalizes stored URLs and
+// reconciles product notified flags.
+// Every step is idempotent, so it is safe to run against an existing deployment.
+func run(log *zap.Logger, opts options) error {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := storage.NewMongoDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Disconnect(); err != nil {
+			log.Error("Error disconnecting from MongoDB", zap.Error(err))
+		}
+	}()
+
+	ctx, cancel := db.OperationContext(context.Background())
+	defer cancel()
+
+	before, err := db.IndexNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureIndexes(ctx, db, cfg, log); err != nil {
+		return err
+	}
+
+	after, err := db.IndexNames(ctx)
+	if err != nil {
+		return err
+	}
+	reportIndexes(before, after)
+
+	if opts.assignShared {
+		assignCtx, cancelAssign := db.OperationContext(context.Background())
+		defer cancelAssign()
+		moved, skipped, err := storage.NewFoodRepository(db, log).AssignSharedFoods(assignCtx, opts.foodGuild)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Shared foods moved to guild %s: %d, left shared (name taken): %d\n", opts.foodGuild, moved, skipped)
+	}
+
+	if opts.seedPath != "" {
+		seedCtx, cancelSeed := db.OperationContext(context.Background())
+		defer cancelSeed()
+		if err := seedFoods(seedCtx, storage.NewFoodRepository(db, log), opts.seedPath, opts.foodGuild); err != nil {
+			return err
+		}
+	}
+
+	// Before reconciling, which matches products to notifications by URL
+	if opts.canonicalizeURLs {
+		urlCtx, cancelURLs := db.OperationContext(context.Background())
+		defer cancelURLs()
+		rewritten, removed, err := crawler.CanonicalizeStoredURLs(urlCtx, db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("URLs canonicalized: %d, duplicates removed: %d\n", rewritten, removed)
+	}
+
+	if opts.reconcile {
+		reconcileCtx, cancelReconcile := db.OperationContext(context.Background())
+		defer cancelReconcile()
+		updated, err := crawler.ReconcileNotifiedProducts(reconcileCtx, db)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Products marked as notified: %d\n", updated)
+	}
+
+	return nil
+}
+
+// ensureIndexes runs every index setup the services perform on startup,
+// including the TTL indices on deal messages, reminders and command usage
+func ensureIndexes(ctx context.Context, db *storage.MongoDB, cfg *config.Config, log *zap.Logger) error {
+	if err := crawler.SetupDatabaseIndices(ctx, db, cfg, log); err != nil {
+		return fmt.Errorf("failed to set up crawler indices: %w", err)
+	}
+
+	// Short links are only indexed by the crawler when click tracking is on;
+	// create them anyway so enabling it later needs no migration
+	if err := storage.NewShortLinkRepository(db, log).EnsureIndexes(ctx); err != nil {
+		return fmt.Errorf("failed to set up short link indices: %w", err)
+	}
+	if err := storage.NewFoodRepository(db, log).EnsureIndexes(ctx); err != nil {
+		return fmt.Errorf("failed to set up food indices: %w", err)
+	}
+	if err := storage.NewReminderRepository(db, log).EnsureIndexes(ctx); err != nil {
+		return fmt.Errorf("failed to set up reminder indices: %w", err)
+	}
+	if err := storage.NewCommandUsageRepository(db, log).EnsureIndexes(ctx); err != nil {
+		return fmt.Errorf("failed to set up command usage indices: %w", err)
+	}
+	return nil
+}
+
+// reportIndexes prints which indexes were created by this run and which already existed
+func reportIndexes(before, after map[string][]string) {
+	existed := make(map[string]bool)
+	for collection, names := range before {
+		for _, name := range names {
+			existed[collection+"."+name] = true
+		}
+	}
+
+	var created, kept []string
+	for collection, names := range after {
+		for _, name := range names {
+			key := collection + "." + name
+			if existed[key] {
+				kept = append(kept, key)
+			} else {
+				created = append(created, key)
+			}
+		}
+	}
+	sort.Strings(created)
+	sort.Strings(kept)
+
+	fmt.Printf("Indexes created: %d\n", len(created))
+	for _, key := range created {
+		fmt.Printf("  + %s\n", key)
+	}
+	fmt.Printf("Indexes already present: %d\n", len(kept))
+	for _, key := range kept {
+		fmt.Printf("  = %s\n", key)
+	}
+}
+
+// seedFoods adds the foods in the JSON file at path to a guild's list (the
+// shared list if guildID is empty), skipping ones that are already registered
+func seedFoods(ctx context.Context, repo *storage.FoodRepository, path, guildID string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	var seeds []seedFood
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return fmt.Errorf("failed to parse seed file: %w", err)
+	}
+
+	created, existing := 0, 0
+	for _, seed := range seeds {
+		if seed.FoodType != models.FoodTypeLunch && seed.FoodType != models.FoodTypeDinner {
+			return fmt.Errorf("invalid food_type %q for %q, expected lunch or dinner", seed.FoodType, seed.Name)
+		}
+		if models.NormalizeFoodName(seed.Name) == "" {
+			return fmt.Errorf("seed food with empty name")
+		}
+
+		food := models.NewFood(seed.Name, seed.FoodType, "gbot-migrate")
+		food.GuildID = guildID
+		err := repo.SaveFood(ctx, food)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			existing++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed food %q: %w", seed.Name, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Foods added: %d, already present: %d\n", created, existing)
+	return nil
+}
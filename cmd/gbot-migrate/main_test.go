@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// captureStdout returns what fn prints to standard output
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(out)
+}
+
+// writeSeedFile writes a seed foods file and returns its path
+func writeSeedFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "foods.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write seed file: %v", err)
+	}
+	return path
+}
+
+func TestReportIndexes(t *testing.T) {
+	before := map[string][]string{"products": {"_id_", "url_1"}}
+	after := map[string][]string{
+		"products":       {"_id_", "url_1", "last_seen_at_1"},
+		"keyword_alerts": {"_id_"},
+	}
+
+	out := captureStdout(t, func() { reportIndexes(before, after) })
+
+	want := "Indexes created: 2\n" +
+		"  + keyword_alerts._id_\n" +
+		"  + products.last_seen_at_1\n" +
+		"Indexes already present: 2\n" +
+		"  = products._id_\n" +
+		"  = products.url_1\n"
+	if out != want {
+		t.Errorf("report =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestSeedFoods(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	cfg := &config.Config{MongoDBName: "test", DBOperationTimeoutSeconds: 10}
+
+	mt.Run("idempotent", func(mt *mtest.T) {
+		path := writeSeedFile(mt.T, `[{"name": "김치찌개", "food_type": "lunch"}, {"name": "비빔밥", "food_type": "dinner"}]`)
+		repo := storage.NewFoodRepository(storage.NewMongoDBFromClient(mt.Client, cfg, zap.NewNop()), zap.NewNop())
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.foods", mtest.FirstBatch), // 김치찌개 is new
+			mtest.CreateCursorResponse(0, "test.foods", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateCursorResponse(0, "test.foods", mtest.FirstBatch, bson.D{ // 비빔밥 is already registered
+				{Key: "name", Value: "비빔밥"},
+				{Key: "food_type", Value: "dinner"},
+				{Key: "is_active", Value: true},
+			}),
+		)
+
+		var err error
+		out := captureStdout(mt.T, func() { err = seedFoods(context.Background(), repo, path, "") })
+		if err != nil {
+			mt.Fatalf("seedFoods() error = %v", err)
+		}
+		if want := "Foods added: 1, already present: 1\n"; out != want {
+			mt.Errorf("output = %q, want %q", out, want)
+		}
+	})
+
+	mt.Run("invalid food type", func(mt *mtest.T) {
+		path := writeSeedFile(mt.T, `[{"name": "김치찌개", "food_type": "brunch"}]`)
+		repo := storage.NewFoodRepository(storage.NewMongoDBFromClient(mt.Client, cfg, zap.NewNop()), zap.NewNop())
+
+		err := seedFoods(context.Background(), repo, path, "")
+		if err == nil || !strings.Contains(err.Error(), "brunch") {
+			mt.Errorf("seedFoods() error = %v, want the invalid food type named", err)
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 0 {
+			mt.Errorf("sent %d commands, want none", n)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -17,20 +18,34 @@ func main() {
 	if err != nil {
 		panic("Failed to initialize logger: " + err.Error())
 	}
-	defer logger.Sync()
-	
+
 	log := logger.Named("hybabot")
-	
+
+	err = run(log)
+	if err != nil {
+		log.Error("Discord bot exited with error", zap.Error(err))
+	}
+
+	// Flush buffered logs before exiting; os.Exit skips deferred calls
+	_ = logger.Sync()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the bot and blocks until it shuts down
+func run(log *zap.Logger) error {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load configuration", zap.Error(err))
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-	
+	log.Info("Effective configuration", zap.String("config", cfg.Summary()))
+
 	// Create context that will be canceled on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	
+
 	// Handle graceful shutdown
 	go func() {
 		sc := make(chan os.Signal, 1)
@@ -39,16 +54,17 @@ func main() {
 		log.Info("Received shutdown signal, gracefully shutting down...")
 		cancel()
 	}()
-	
+
 	// Initialize and run the bot
 	discordBot, err := bot.New(cfg, log)
 	if err != nil {
-		log.Fatal("Failed to initialize bot", zap.Error(err))
+		return fmt.Errorf("failed to initialize bot: %w", err)
 	}
-	
+
 	if err := discordBot.Start(ctx); err != nil {
-		log.Fatal("Bot error", zap.Error(err))
+		return fmt.Errorf("bot error: %w", err)
 	}
-	
+
 	log.Info("Discord bot shut down successfully")
-}
\ No newline at end of file
+	return nil
+}
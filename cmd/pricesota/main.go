@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,20 +19,34 @@ func main() {
 	if err != nil {
 		panic("Failed to initialize logger: " + err.Error())
 	}
-	defer logger.Sync()
-	
+
 	log := logger.Named("pricesota")
-	
+
+	err = run(log)
+	if err != nil {
+		log.Error("Web crawler service exited with error", zap.Error(err))
+	}
+
+	// Flush buffered logs before exiting; os.Exit skips deferred calls
+	_ = logger.Sync()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// run starts the crawler service and blocks until it shuts down
+func run(log *zap.Logger) error {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load configuration", zap.Error(err))
+		return fmt.Errorf("failed to load configuration: %w", err)
 	}
-	
+	log.Info("Effective configuration", zap.String("config", cfg.Summary()))
+
 	// Create context that will be canceled on interrupt
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	
+
 	// Handle graceful shutdown
 	go func() {
 		sc := make(chan os.Signal, 1)
@@ -40,27 +55,37 @@ func main() {
 		log.Info("Received shutdown signal, gracefully shutting down...")
 		cancel()
 	}()
-	
+
 	// Initialize improved crawler
 	webCrawler, err := crawler.NewImprovedCrawler(cfg, log)
 	if err != nil {
-		log.Fatal("Failed to initialize crawler", zap.Error(err))
+		return fmt.Errorf("failed to initialize crawler: %w", err)
 	}
 	defer func() {
 		if err := webCrawler.Close(); err != nil {
 			log.Error("Error closing crawler", zap.Error(err))
 		}
 	}()
-	
+
 	// Start crawler with scheduled runs
 	log.Info("Starting web crawler service")
-	
+
 	// Configure interval
 	interval := time.Duration(cfg.CrawlIntervalMinutes) * time.Minute
 	log.Info("Crawler configured", zap.Duration("interval", interval))
-	
+
+	// Start weekly popular-deals digest
+	go webCrawler.StartWeeklyDigest(ctx)
+
+	// Start daily/weekly alert summary DMs
+	go webCrawler.StartAlertSummaries(ctx)
+
+	// Start HTTP server for health, stats and click-tracking redirects
+	go webCrawler.StartHTTPServer(ctx)
+
 	// Start scheduled runs (this blocks until context is canceled)
 	webCrawler.StartScheduledRuns(ctx, interval)
-	
+
 	log.Info("Web crawler service shut down successfully")
-}
\ No newline at end of file
+	return nil
+}
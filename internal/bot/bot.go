@@ -3,12 +3,14 @@ package bot
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/bradykim7/gbot/internal/bot/commands"
 	"github.com/bradykim7/gbot/internal/storage"
 	"github.com/bradykim7/gbot/pkg/config"
-	"go.uber.org/zap"
 	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
 )
 
 // Bot은 Discord 봇을 나타냅니다
@@ -18,10 +20,27 @@ type Bot struct {
 	log      *zap.Logger
 	commands *commands.Registry
 	db       *storage.MongoDB
+
+	// dealReactions는 특가 알림의 🔔 반응으로 키워드 알림을 만듭니다
+	dealReactions *commands.DealReactionHandler
+
+	// alertRepo는 삭제된 채널/서버의 알림을 정리합니다
+	alertRepo *storage.AlertRepository
+
+	// ready는 Discord 세션과 DB가 모두 준비되었는지 나타냅니다
+	ready atomic.Bool
 }
 
+// readyRetryInterval은 준비 중 DB 연결을 다시 확인하는 간격입니다
+const readyRetryInterval = 5 * time.Second
+
 // New는 새로운 Bot 인스턴스를 생성합니다
 func New(cfg *config.Config, log *zap.Logger) (*Bot, error) {
+	// 설정 검증은 웹훅 전송 시 토큰을 생략할 수 있게 하지만, 봇은 항상 토큰이 필요합니다
+	if cfg.DiscordToken == "" {
+		return nil, fmt.Errorf("DISCORD_TOKEN 환경 변수가 필요합니다")
+	}
+
 	// Discord 세션 생성
 	session, err := discordgo.New("Bot " + cfg.DiscordToken)
 	if err != nil {
@@ -33,29 +52,35 @@ func New(cfg *config.Config, log *zap.Logger) (*Bot, error) {
 	if err != nil {
 		return nil, fmt.Errorf("MongoDB 연결 오류: %w", err)
 	}
-	
+
 	// 봇 인스턴스 생성
 	bot := &Bot{
-		session:  session,
-		config:   cfg,
-		log:      log.Named("bot"),
-		commands: commands.NewRegistry(cfg.CommandPrefix, log),
-		db:       db,
+		session:   session,
+		config:    cfg,
+		log:       log.Named("bot"),
+		commands:  commands.NewRegistry(cfg.CommandPrefix, log),
+		db:        db,
+		alertRepo: storage.NewAlertRepository(db, log),
 	}
-	
+
 	// 이벤트 핸들러 설정
 	session.AddHandler(bot.onReady)
 	session.AddHandler(bot.onMessageCreate)
-	
-	// Intents 설정
-	session.Identify.Intents = discordgo.IntentsGuildMessages | 
-		discordgo.IntentsGuildVoiceStates | 
-		discordgo.IntentsDirectMessages | 
+	session.AddHandler(bot.onMessageReactionAdd)
+	session.AddHandler(bot.onChannelDelete)
+	session.AddHandler(bot.onGuildDelete)
+
+	// Intents 설정 (IntentsGuilds: 채널/서버 삭제 이벤트)
+	session.Identify.Intents = discordgo.IntentsGuilds |
+		discordgo.IntentsGuildMessages |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsGuildVoiceStates |
+		discordgo.IntentsDirectMessages |
 		discordgo.IntentsMessageContent
-	
+
 	// 명령어 등록
 	bot.registerCommands()
-	
+
 	return bot, nil
 }
 
@@ -65,12 +90,31 @@ func (b *Bot) Start(ctx context.Context) error {
 	if err := b.session.Open(); err != nil {
 		return fmt.Errorf("Discord 세션 열기 오류: %w", err)
 	}
-	
+
 	b.log.Info("봇이 실행 중입니다. 종료하려면 CTRL-C를 누르세요.")
-	
+
+	// 음식 메뉴(기존 메뉴의 정규화 이름도 채움)와 명령어 사용량 인덱스 생성
+	indexCtx, cancel := b.db.OperationContext(ctx)
+	if err := storage.NewFoodRepository(b.db, b.log).EnsureIndexes(indexCtx); err != nil {
+		b.log.Warn("음식 메뉴 인덱스 생성 실패", zap.Error(err))
+	}
+	if err := storage.NewCommandUsageRepository(b.db, b.log).EnsureIndexes(indexCtx); err != nil {
+		b.log.Warn("명령어 사용량 인덱스 생성 실패", zap.Error(err))
+	}
+	cancel()
+
+	// 리마인더 스케줄러 시작
+	go newReminderScheduler(b.session, b.db, b.log).Run(ctx)
+
+	// 점심 추천 스케줄러 시작 (채널이 설정된 경우)
+	if b.config.FoodChannelID != "" {
+		at, _ := time.Parse("15:04", b.config.FoodScheduleTime)
+		go newFoodScheduler(b.session, b.db, b.log, b.config.FoodChannelID, at, b.config.FoodScheduleSkipWeekends).Run(ctx)
+	}
+
 	// 컨텍스트가 취소될 때까지 대기
 	<-ctx.Done()
-	
+
 	// 리소스 정리
 	return b.Close()
 }
@@ -80,25 +124,46 @@ func (b *Bot) Close() error {
 	if err := b.session.Close(); err != nil {
 		return fmt.Errorf("Discord 세션 닫기 오류: %w", err)
 	}
-	
+
 	if err := b.db.Disconnect(); err != nil {
 		return fmt.Errorf("MongoDB 연결 해제 오류: %w", err)
 	}
-	
+
 	return nil
 }
 
 // onReady는 봇이 준비되었을 때의 이벤트 핸들러입니다
 func (b *Bot) onReady(s *discordgo.Session, r *discordgo.Ready) {
-	b.log.Info("봇 로그인 완료", 
-		zap.String("username", r.User.Username), 
+	b.log.Info("봇 로그인 완료",
+		zap.String("username", r.User.Username),
 		zap.String("discriminator", r.User.Discriminator))
-	
+
 	// 상태 설정
 	err := s.UpdateGameStatus(0, "with golang")
 	if err != nil {
 		b.log.Error("상태 설정 오류", zap.Error(err))
 	}
+
+	// DB 연결이 확인되면 명령어 처리 시작
+	go b.waitForDatabase()
+}
+
+// waitForDatabase는 DB 핑이 성공할 때까지 재시도한 뒤 봇을 준비 상태로 표시합니다
+func (b *Bot) waitForDatabase() {
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), readyRetryInterval)
+		err := b.db.Ping(ctx)
+		cancel()
+
+		if err == nil {
+			b.ready.Store(true)
+			b.log.Info("봇 준비 완료, 명령어 처리를 시작합니다")
+			return
+		}
+
+		b.log.Warn("DB 연결 확인 실패, 재시도합니다", zap.Error(err))
+		time.Sleep(readyRetryInterval)
+	}
 }
 
 // onMessageCreate는 메시지가 생성되었을 때의 이벤트 핸들러입니다
@@ -107,34 +172,144 @@ func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate)
 	if m.Author.ID == s.State.User.ID {
 		return
 	}
-	
+
 	// 메시지 로깅
-	b.log.Debug("메시지 수신됨", 
-		zap.String("guild_id", m.GuildID), 
-		zap.String("channel_id", m.ChannelID), 
-		zap.String("user_id", m.Author.ID), 
-		zap.String("username", m.Author.Username), 
+	b.log.Debug("메시지 수신됨",
+		zap.String("guild_id", m.GuildID),
+		zap.String("channel_id", m.ChannelID),
+		zap.String("user_id", m.Author.ID),
+		zap.String("username", m.Author.Username),
 		zap.String("content", m.Content))
-	
+
+	// 준비되기 전에는 명령어 대신 안내 메시지로 응답
+	if !b.ready.Load() {
+		b.commands.HandleNotReady(s, m)
+		return
+	}
+
 	// 명령어 처리
 	b.commands.Handle(s, m)
 }
 
+// onMessageReactionAdd는 메시지에 반응이 추가되었을 때의 이벤트 핸들러입니다
+func (b *Bot) onMessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if !b.ready.Load() {
+		return
+	}
+
+	b.dealReactions.Handle(s, r)
+}
+
+// onChannelDelete는 채널이 삭제되면 그 채널로 알림을 보내던 키워드 알림을 비활성화합니다
+func (b *Bot) onChannelDelete(s *discordgo.Session, c *discordgo.ChannelDelete) {
+	ctx, cancel := b.db.OperationContext(context.Background())
+	defer cancel()
+
+	count, err := b.alertRepo.DeactivateChannelAlerts(ctx, c.ID)
+	if err != nil {
+		b.log.Error("삭제된 채널의 알림 정리 실패", zap.Error(err), zap.String("channel_id", c.ID))
+		return
+	}
+
+	if count > 0 {
+		b.log.Info("삭제된 채널의 알림 비활성화",
+			zap.String("channel_id", c.ID),
+			zap.String("guild_id", c.GuildID),
+			zap.Int64("alerts", count))
+	}
+}
+
+// onGuildDelete는 봇이 서버에서 제거되면 그 서버의 키워드 알림을 비활성화합니다.
+// 서버 장애로 일시적으로 사용할 수 없게 된 경우(Unavailable)는 무시합니다.
+func (b *Bot) onGuildDelete(s *discordgo.Session, g *discordgo.GuildDelete) {
+	if g.Unavailable {
+		b.log.Warn("서버를 일시적으로 사용할 수 없음", zap.String("guild_id", g.ID))
+		return
+	}
+
+	ctx, cancel := b.db.OperationContext(context.Background())
+	defer cancel()
+
+	count, err := b.alertRepo.DeactivateGuildAlerts(ctx, g.ID)
+	if err != nil {
+		b.log.Error("제거된 서버의 알림 정리 실패", zap.Error(err), zap.String("guild_id", g.ID))
+		return
+	}
+
+	b.log.Info("제거된 서버의 알림 비활성화",
+		zap.String("guild_id", g.ID),
+		zap.Int64("alerts", count))
+}
+
 // registerCommands는 모든 명령어를 등록합니다
 func (b *Bot) registerCommands() {
+	// 도움말/오류 응답 자동 삭제
+	commands.SetTemporaryReplyTTL(time.Duration(b.config.TemporaryReplySeconds) * time.Second)
+
+	// 서버별 접두사 (도움말과 사용법도 서버의 접두사로 안내)
+	prefixes := commands.NewPrefixCache(storage.NewGuildSettingsRepository(b.db, b.log), b.config.CommandPrefix, b.log)
+	b.commands.SetPrefixCache(prefixes)
+
 	// Ping 명령어 등록
 	pingCmd := commands.NewPingCommand(b.config.CommandPrefix)
 	b.commands.Register("ping", pingCmd)
-	
+
 	// 알림 명령어 등록
-	alertCmd := commands.NewAlertCommand(b.log, b.db, b.config.CommandPrefix)
+	alertCmd := commands.NewAlertCommand(b.log, b.db, prefixes, b.config.AlertMinKeywordLength)
 	b.commands.Register("alert", alertCmd)
 	b.commands.Register("알림", alertCmd) // Korean alias
-	
+	b.dealReactions = commands.NewDealReactionHandler(b.log, b.db, alertCmd)
+
 	// 음식 명령어 등록
-	foodCmd := commands.NewFoodCommand(b.log, b.db, b.config.CommandPrefix)
+	foodCmd := commands.NewFoodCommand(b.log, b.db, prefixes)
 	b.commands.Register("food", foodCmd)
 	b.commands.Register("메뉴", foodCmd) // Korean alias
-	
+
+	// 리마인더 명령어 등록
+	remindCmd := commands.NewRemindCommand(b.log, b.db, prefixes)
+	b.commands.Register("remind", remindCmd)
+	b.commands.Register("리마인드", remindCmd) // Korean alias
+
+	// 날씨 명령어 등록 (API 키가 없으면 안내 메시지로 응답)
+	var weatherClient commands.WeatherClient
+	if b.config.WeatherAPIKey != "" {
+		weatherClient = commands.NewOpenWeatherClient(b.config.WeatherAPIURL, b.config.WeatherAPIKey)
+	}
+	weatherCmd := commands.NewWeatherCommand(b.log, weatherClient, b.config.WeatherDefaultCity, prefixes)
+	b.commands.Register("weather", weatherCmd)
+	b.commands.Register("날씨", weatherCmd) // Korean alias
+
+	// 투표 명령어 등록
+	pollCmd := commands.NewPollCommand(b.log, b.db, prefixes)
+	b.commands.Register("poll", pollCmd)
+	b.commands.Register("투표", pollCmd) // Korean alias
+
+	// 주사위 명령어 등록
+	rollCmd := commands.NewRollCommand(b.log, prefixes)
+	b.commands.Register("roll", rollCmd)
+	b.commands.Register("주사위", rollCmd) // Korean alias
+
+	// 상품 검색 명령어 등록
+	searchCmd := commands.NewSearchCommand(b.log, b.db, prefixes)
+	b.commands.Register("search", searchCmd)
+	b.commands.Register("검색", searchCmd) // Korean alias
+
+	// 인기 상품 명령어 등록
+	hotCmd := commands.NewHotCommand(b.log, b.db, prefixes)
+	b.commands.Register("hot", hotCmd)
+	b.commands.Register("인기", hotCmd) // Korean alias
+
+	// 서버별 접두사 명령어 등록
+	prefixCmd := commands.NewPrefixCommand(b.log, b.db, prefixes)
+	b.commands.Register("prefix", prefixCmd)
+	b.commands.Register("접두사", prefixCmd) // Korean alias
+
+	// 명령어 사용량 기록 및 통계 명령어 등록
+	usageRepo := storage.NewCommandUsageRepository(b.db, b.log)
+	b.commands.SetUsageRecorder(usageRepo)
+	statsCmd := commands.NewStatsCommand(b.log, usageRepo, prefixes)
+	b.commands.Register("stats", statsCmd)
+	b.commands.Register("통계", statsCmd) // Korean alias
+
 	// TODO: 다른 명령어들도 구현되는 대로 등록
-}
\ No newline at end of file
+}
@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/bot/commands"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// spyCommand records the times it was executed
+type spyCommand struct {
+	runs int
+}
+
+func (c *spyCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	c.runs++
+}
+
+func (c *spyCommand) Help(prefix string) string {
+	return prefix + "spy"
+}
+
+// replySession returns a Discord session logged in as the bot that records
+// the contents of the messages sent through it
+func replySession(t *testing.T) (*discordgo.Session, func() []string) {
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.State.User = &discordgo.User{ID: "bot"}
+
+	var mu sync.Mutex
+	var replies []string
+	session.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var msg discordgo.MessageSend
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("failed to decode message: %v", err)
+		}
+		mu.Lock()
+		replies = append(replies, msg.Content)
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": "message-1"}`))),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+		}, nil
+	})}
+	return session, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), replies...)
+	}
+}
+
+func userMessage(content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ChannelID: "channel-1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "user-1", Username: "user"},
+	}}
+}
+
+func TestCommandBeforeReady(t *testing.T) {
+	spy := &spyCommand{}
+	registry := commands.NewRegistry("!", zap.NewNop())
+	registry.Register("spy", spy)
+	b := &Bot{log: zap.NewNop(), commands: registry}
+	session, replies := replySession(t)
+
+	b.onMessageCreate(session, userMessage("!spy"))
+	b.onMessageCreate(session, userMessage("안녕하세요"))
+	b.onMessageCreate(session, userMessage("!unknown"))
+
+	if spy.runs != 0 {
+		t.Errorf("command ran %d times before ready, want 0", spy.runs)
+	}
+	if got := replies(); len(got) != 1 || got[0] != "아직 준비 중입니다. 잠시 후 다시 시도해주세요." {
+		t.Errorf("replied %q, want one not-ready notice", got)
+	}
+
+	b.ready.Store(true)
+	b.onMessageCreate(session, userMessage("!spy"))
+	if spy.runs != 1 {
+		t.Errorf("command ran %d times after ready, want 1", spy.runs)
+	}
+	if got := replies(); len(got) != 1 {
+		t.Errorf("replied %q after ready, want no more notices", got)
+	}
+}
+
+func TestWaitForDatabaseMarksReady(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("ping", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		db := storage.NewMongoDBFromClient(mt.Client, &config.Config{MongoDBName: "test", DBOperationTimeoutSeconds: 10}, zap.NewNop())
+		b := &Bot{log: zap.NewNop(), db: db}
+
+		b.waitForDatabase()
+
+		if !b.ready.Load() {
+			mt.Error("bot isn't ready after the database answered a ping")
+		}
+	})
+}
+
+func TestDeletedChannelDeactivatesAlerts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	// deactivation returns the single batch update sent for a delete event
+	deactivation := func(mt *mtest.T) bson.Raw {
+		mt.Helper()
+		var updates []bson.Raw
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" {
+				updates = append(updates, event.Command)
+			}
+		}
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d updates, want one batch update", len(updates))
+		}
+		if got := updates[0].Lookup("update").StringValue(); got != "keyword_alerts" {
+			mt.Errorf("updated %s, want keyword_alerts", got)
+		}
+		u := updates[0].Lookup("updates").Array().Index(0).Value().Document()
+		if multi, _ := u.Lookup("multi").BooleanOK(); !multi {
+			mt.Error("update isn't a batch update")
+		}
+		if active, ok := u.Lookup("u", "$set", "is_active").BooleanOK(); !ok || active {
+			mt.Errorf("update = %v, want is_active set to false", u.Lookup("u"))
+		}
+		return u.Lookup("q").Document()
+	}
+
+	newBot := func(mt *mtest.T) *Bot {
+		db := storage.NewMongoDBFromClient(mt.Client, &config.Config{MongoDBName: "test", DBOperationTimeoutSeconds: 10}, zap.NewNop())
+		return &Bot{log: zap.NewNop(), db: db, alertRepo: storage.NewAlertRepository(db, zap.NewNop())}
+	}
+
+	mt.Run("channel delete", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+		b := newBot(mt)
+
+		b.onChannelDelete(nil, &discordgo.ChannelDelete{Channel: &discordgo.Channel{ID: "channel-1", GuildID: "guild-1"}})
+
+		filter := deactivation(mt)
+		if got := filter.Lookup("channel_id").StringValue(); got != "channel-1" {
+			mt.Errorf("filter channel_id = %q, want channel-1", got)
+		}
+		if _, err := filter.LookupErr("guild_id"); err == nil {
+			mt.Error("filter reaches the guild's other channels")
+		}
+		if !filter.Lookup("is_active").Boolean() {
+			mt.Error("filter doesn't limit the update to active alerts")
+		}
+	})
+
+	mt.Run("guild delete", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 5}, bson.E{Key: "nModified", Value: 5}))
+		b := newBot(mt)
+
+		b.onGuildDelete(nil, &discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1"}})
+
+		if got := deactivation(mt).Lookup("guild_id").StringValue(); got != "guild-1" {
+			mt.Errorf("filter guild_id = %q, want guild-1", got)
+		}
+	})
+
+	mt.Run("guild outage", func(mt *mtest.T) {
+		b := newBot(mt)
+
+		b.onGuildDelete(nil, &discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1", Unavailable: true}})
+
+		if events := mt.GetAllStartedEvents(); len(events) != 0 {
+			mt.Errorf("sent %d commands for an unavailable guild, want none", len(events))
+		}
+	})
+}
@@ -2,32 +2,63 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bradykim7/gbot/internal/embeds"
 	"github.com/bradykim7/gbot/internal/models"
 	"github.com/bradykim7/gbot/internal/storage"
 	"github.com/bwmarrin/discordgo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
+const (
+	// alertTestProductLimit는 alert test 명령어가 검사할 최근 상품 수입니다
+	alertTestProductLimit = 200
+	// alertTestExampleLimit는 alert test 결과에 보여줄 예시 상품 수입니다
+	alertTestExampleLimit = 5
+	// alertHistoryWindow는 alert history 명령어가 조회하는 기간입니다
+	alertHistoryWindow = 30 * 24 * time.Hour
+	// alertHistoryLimit는 alert history 결과에 보여줄 최대 기록 수입니다
+	alertHistoryLimit = 10
+	// alertListPageSize는 alert list 한 페이지에 보여줄 알림 수입니다
+	alertListPageSize = 10
+	// alertSnoozeMax는 채널 알림을 중지할 수 있는 최대 기간입니다
+	alertSnoozeMax = 7 * 24 * time.Hour
+	// alertMaxCooldown는 알림별로 설정할 수 있는 최대 재알림 간격입니다
+	alertMaxCooldown = 24 * time.Hour
+	// alertPopularDefault는 alert popular 명령어가 기본으로 보여줄 키워드 수입니다
+	alertPopularDefault = 10
+	// alertPopularMax는 alert popular 명령어로 볼 수 있는 최대 키워드 수입니다 (임베드 필드 제한)
+	alertPopularMax = 25
+)
+
 // AlertCommand는 키워드 알림 관련 명령어를 처리합니다
 type AlertCommand struct {
-	log    *zap.Logger
-	db     *storage.MongoDB
-	prefix string
+	log              *zap.Logger
+	db               *storage.MongoDB
+	prefixes         *PrefixCache
+	minKeywordLength int
+	alertRepo        *storage.AlertRepository
+	matchRepo        *storage.AlertMatchRepository
+	snoozeRepo       *storage.ChannelSnoozeRepository
 }
 
 // Execute implements the Command interface
 func (c *AlertCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
 	if len(args) == 0 {
-		c.sendHelpMessage(s, m.ChannelID)
+		c.sendHelpMessage(s, m)
 		return
 	}
 
-	subCommand := args[0]
+	// 라틴 문자 하위 명령어는 대소문자를 구분하지 않습니다 (키워드는 그대로 둡니다)
+	subCommand := strings.ToLower(args[0])
 	args = args[1:]
 
 	switch subCommand {
@@ -35,40 +66,133 @@ func (c *AlertCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 		c.handleAddAlertFromArgs(s, m, args)
 	case "remove", "삭제":
 		c.handleRemoveAlertFromArgs(s, m, args)
+	case "addserver", "서버추가":
+		c.handleAddServerAlertFromArgs(s, m, args)
+	case "removeserver", "서버삭제":
+		c.handleRemoveServerAlertFromArgs(s, m, args)
 	case "list", "목록":
 		c.handleListAlertsFromArgs(s, m, args)
+	case "test", "테스트":
+		c.handleTestAlertFromArgs(s, m, args)
+	case "history", "기록":
+		c.handleAlertHistoryFromArgs(s, m, args)
+	case "quiet", "방해금지":
+		c.handleQuietHoursFromArgs(s, m, args)
+	case "cooldown", "간격":
+		c.handleCooldownFromArgs(s, m, args)
+	case "summary", "요약":
+		c.handleSummaryModeFromArgs(s, m, args)
+	case "snooze", "중지":
+		c.handleSnoozeFromArgs(s, m, args)
+	case "popular", "인기":
+		c.handlePopularAlertsFromArgs(s, m, args)
+	case "trends", "트렌드":
+		c.handleAlertTrendsFromArgs(s, m, args)
+	case "preview", "미리보기":
+		c.handleAlertPreviewFromArgs(s, m, args)
 	default:
-		c.sendHelpMessage(s, m.ChannelID)
+		c.sendHelpMessage(s, m)
 	}
 }
 
 // Help implements the Command interface
-func (c *AlertCommand) Help() string {
+func (c *AlertCommand) Help(prefix string) string {
 	return fmt.Sprintf("**Alert Command Usage**\n"+
-		"%s alert add [keyword] - Add a keyword alert\n"+
+		"%s alert add [--whole-word] [--lang ko/en] [--role @role] [--under price] [--best|--best-discount] [keyword] - Add a keyword alert (--whole-word: match whole words only, --lang: notification language, --role: admin only, ping a role instead of you, --under: only deals at or below the price, --best/--best-discount: only the cheapest/biggest-discount match once a day)\n"+
 		"%s alert remove [keyword] - Remove a keyword alert\n"+
-		"%s alert list - List all your keyword alerts", 
-		c.prefix, c.prefix, c.prefix)
+		"%s alert addserver [--role @role] [keyword] - (Admin) Add a server alert that notifies this channel without mentions, or pings a role\n"+
+		"%s alert removeserver [keyword] - (Admin) Remove a server alert\n"+
+		"%s alert list [all] - List your keyword alerts in this server (all: every server)\n"+
+		"%s alert test [--whole-word] [keyword] - Check which recent deals a keyword would have matched\n"+
+		"%s alert history [keyword] - Show deals your alert matched in the last 30 days\n"+
+		"%s alert quiet [23:00-08:00|off] - Hold your alerts during quiet hours and deliver them when the window ends\n"+
+		"%s alert cooldown [30m|off|default] [keyword] - Wait at least this long before notifying you again for a keyword\n"+
+		"%s alert summary [realtime|daily|weekly] [keyword] - Get matches as a daily or weekly DM instead of real-time pings (no keyword: all your alerts)\n"+
+		"%s alert snooze [2h|off] - (Admin) Pause all alert notifications in this channel\n"+
+		"%s alert popular [N] - (Admin) Show the keywords whose alerts fired the most in this server\n"+
+		"%s alert trends [N] - (Admin) Show the keywords most users subscribe to across all servers\n"+
+		"%s alert preview [--lang ko/en] - (Admin) Send a sample deal notification here to check the format and the bot's permissions\n"+
+		"React with "+models.DealAlertEmoji+" on a deal notification to add an alert for that product",
+		prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix)
 }
 
-// sendHelpMessage sends the help message to the specified channel
-func (c *AlertCommand) sendHelpMessage(s *discordgo.Session, channelID string) {
-	s.ChannelMessageSend(channelID, c.Help())
+// sendHelpMessage sends the help message, with the guild's prefix, to the message's channel
+func (c *AlertCommand) sendHelpMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
 }
 
 // handleAddAlertFromArgs processes alert add command from parsed arguments
 func (c *AlertCommand) handleAddAlertFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
-	if len(args) == 0 {
-		s.ChannelMessageSend(m.ChannelID, "추가할 키워드를 입력해주세요.")
+	// 옵션 분리
+	parsed, ok := parseCommandArgs(s, m.ChannelID, args,
+		[]string{"whole-word", "best", "best-discount"},
+		[]string{"under", "lang", "role"})
+	if !ok {
 		return
 	}
-	
-	keyword := strings.Join(args, " ")
-	
+
+	wholeWord := parsed.Has("whole-word")
+
+	maxPrice := 0
+	if value, ok := parsed.Flag("under"); ok {
+		price, _ := strconv.Atoi(strings.ReplaceAll(value, ",", ""))
+		if price <= 0 {
+			sendError(s, m.ChannelID, "--under 옵션에는 최대 가격을 숫자로 입력해주세요. (예: --under 500000)")
+			return
+		}
+		maxPrice = price
+	}
+
+	bestDeal := ""
+	switch {
+	case parsed.Has("best") && parsed.Has("best-discount"):
+		sendError(s, m.ChannelID, "--best와 --best-discount 옵션은 함께 쓸 수 없습니다.")
+		return
+	case parsed.Has("best"):
+		bestDeal = models.BestDealPrice
+	case parsed.Has("best-discount"):
+		bestDeal = models.BestDealDiscount
+	}
+
+	language := ""
+	if value, ok := parsed.Flag("lang"); ok {
+		if !models.IsSupportedLanguage(value) {
+			sendError(s, m.ChannelID, "--lang 옵션에는 ko 또는 en을 입력해주세요.")
+			return
+		}
+		language = value
+	}
+
+	roleID := ""
+	if value, ok := parsed.Flag("role"); ok {
+		id, ok := parseRoleMention(value)
+		if !ok {
+			sendError(s, m.ChannelID, "--role 옵션에는 역할 멘션(@역할) 또는 역할 ID를 입력해주세요.")
+			return
+		}
+		roleID = id
+	}
+
+	keywordArgs := parsed.Positional
+	if len(keywordArgs) == 0 {
+		sendError(s, m.ChannelID, "추가할 키워드를 입력해주세요.")
+		return
+	}
+
+	// 역할 멘션은 서버 관리자만 설정 가능
+	if roleID != "" && !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	keyword := models.CleanKeyword(strings.Join(keywordArgs, " "))
+	if !c.validateKeyword(s, m.ChannelID, keyword) {
+		return
+	}
+
 	// Create timeout context for database operations
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	
+
 	// 데이터베이스에 알림 생성
 	alert := models.KeywordAlert{
 		Keyword:   keyword,
@@ -78,34 +202,44 @@ func (c *AlertCommand) handleAddAlertFromArgs(s *discordgo.Session, m *discordgo
 		GuildID:   m.GuildID,
 		CreatedAt: time.Now().Unix(),
 		IsActive:  true,
+		WholeWord: wholeWord,
+		Language:  language,
+		RoleID:    roleID,
+		MaxPrice:  maxPrice,
+		BestDeal:  bestDeal,
 	}
 
-	// 알림이 이미 존재하는지 확인
-	exists, err := c.checkAlertExists(ctx, m.Author.ID, keyword)
-	if err != nil {
-		c.log.Error("알림 존재 여부 확인 실패", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "알림 존재 여부를 확인하는 중 오류가 발생했습니다.")
+	if err := c.addUserAlert(ctx, &alert); err != nil {
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림이 이미 존재합니다.", keyword))
+			return
+		}
+		c.log.Error("알림 추가 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림을 추가하는 중 오류가 발생했습니다.")
 		return
 	}
 
-	if exists {
-		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림이 이미 존재합니다.", keyword))
-		return
+	// 응답 임베드 생성
+	description := fmt.Sprintf("키워드: **%s**에 대한 알림이 성공적으로 추가되었습니다.", keyword)
+	if wholeWord {
+		description += "\n단어 단위로 일치하는 상품만 알림을 보냅니다."
 	}
-
-	// 알림 삽입
-	collection := c.db.Collection("keyword_alerts")
-	_, err = collection.InsertOne(ctx, alert)
-	if err != nil {
-		c.log.Error("알림 삽입 실패", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "알림을 추가하는 중 오류가 발생했습니다.")
-		return
+	if roleID != "" {
+		description += fmt.Sprintf("\n알림 시 <@&%s> 역할을 멘션합니다.", roleID)
+	}
+	if maxPrice > 0 {
+		description += fmt.Sprintf("\n%s원 이하인 상품만 알림을 보냅니다.", models.FormatKoreanNumber(maxPrice))
+	}
+	switch bestDeal {
+	case models.BestDealPrice:
+		description += "\n일치할 때마다 알리지 않고, 하루에 한 번 가장 싼 특가만 알립니다."
+	case models.BestDealDiscount:
+		description += "\n일치할 때마다 알리지 않고, 하루에 한 번 할인율이 가장 높은 특가만 알립니다."
 	}
 
-	// 응답 임베드 생성
 	embed := &discordgo.MessageEmbed{
 		Title:       "키워드 알림 추가됨",
-		Description: fmt.Sprintf("키워드: **%s**에 대한 알림이 성공적으로 추가되었습니다.", keyword),
+		Description: description,
 		Color:       0x00ff00, // 녹색
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
@@ -113,21 +247,27 @@ func (c *AlertCommand) handleAddAlertFromArgs(s *discordgo.Session, m *discordgo
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	c.log.Info("알림 추가됨", 
-		zap.String("keyword", keyword), 
+	c.log.Info("알림 추가됨",
+		zap.String("keyword", keyword),
 		zap.String("user_id", m.Author.ID),
-		zap.String("author", m.Author.Username))
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		zap.String("author", m.Author.Username),
+		zap.Bool("whole_word", wholeWord),
+		zap.Int("max_price", maxPrice),
+		zap.String("best_deal", bestDeal))
+	sendEmbed(s, m.ChannelID, embed)
 }
 
 // handleRemoveAlertFromArgs processes alert remove command from parsed arguments
 func (c *AlertCommand) handleRemoveAlertFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
 	if len(args) == 0 {
-		s.ChannelMessageSend(m.ChannelID, "삭제할 키워드를 입력해주세요.")
+		sendError(s, m.ChannelID, "삭제할 키워드를 입력해주세요.")
+		return
+	}
+
+	keyword, ok := keywordFromArgs(s, m.ChannelID, args)
+	if !ok {
 		return
 	}
-	
-	keyword := strings.Join(args, " ")
 
 	// Create timeout context for database operations
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -136,19 +276,20 @@ func (c *AlertCommand) handleRemoveAlertFromArgs(s *discordgo.Session, m *discor
 	// 데이터베이스에서 알림 삭제
 	collection := c.db.Collection("keyword_alerts")
 	filter := bson.M{
-		"user_id": m.Author.ID,
-		"keyword": keyword,
+		"user_id":            m.Author.ID,
+		"normalized_keyword": models.NormalizeKeyword(keyword),
+		"scope":              bson.M{"$ne": models.AlertScopeServer},
 	}
 
 	result, err := collection.DeleteOne(ctx, filter)
 	if err != nil {
 		c.log.Error("알림 삭제 실패", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "알림을 삭제하는 중 오류가 발생했습니다.")
+		sendError(s, m.ChannelID, "알림을 삭제하는 중 오류가 발생했습니다.")
 		return
 	}
 
 	if result.DeletedCount == 0 {
-		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림을 찾을 수 없습니다.", keyword))
+		sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림을 찾을 수 없습니다.", keyword))
 		return
 	}
 
@@ -163,10 +304,10 @@ func (c *AlertCommand) handleRemoveAlertFromArgs(s *discordgo.Session, m *discor
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	c.log.Info("알림 삭제됨", 
-		zap.String("keyword", keyword), 
+	c.log.Info("알림 삭제됨",
+		zap.String("keyword", keyword),
 		zap.String("user_id", m.Author.ID))
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
 }
 
 // handleListAlertsFromArgs processes alert list command from parsed arguments
@@ -174,38 +315,268 @@ func (c *AlertCommand) handleListAlertsFromArgs(s *discordgo.Session, m *discord
 	// Create timeout context for database operations
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	
+
 	// 데이터베이스에서 알림 목록 가져오기
-	collection := c.db.Collection("keyword_alerts")
-	filter := bson.M{
-		"user_id":  m.Author.ID,
-		"is_active": true,
+	// 서버에서는 해당 서버의 알림만, "all"이나 DM에서는 모든 서버의 알림을 보여줌
+	allGuilds := m.GuildID == "" || (len(args) > 0 && (strings.ToLower(args[0]) == "all" || args[0] == "전체"))
+	var alerts []models.KeywordAlert
+	if allGuilds {
+		collection := c.db.Collection("keyword_alerts")
+		filter := bson.M{
+			"user_id":   m.Author.ID,
+			"is_active": true,
+		}
+
+		cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+		if err != nil {
+			c.log.Error("알림 목록 조회 실패", zap.Error(err))
+			sendError(s, m.ChannelID, "알림 목록을 조회하는 중 오류가 발생했습니다.")
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err := cursor.All(ctx, &alerts); err != nil {
+			c.log.Error("알림 디코딩 실패", zap.Error(err))
+			sendError(s, m.ChannelID, "알림 정보를 디코딩하는 중 오류가 발생했습니다.")
+			return
+		}
+	} else {
+		var err error
+		alerts, err = c.alertRepo.GetAlertsByGuild(ctx, m.GuildID, m.Author.ID)
+		if err != nil {
+			c.log.Error("알림 목록 조회 실패", zap.Error(err))
+			sendError(s, m.ChannelID, "알림 목록을 조회하는 중 오류가 발생했습니다.")
+			return
+		}
+	}
+
+	if len(alerts) == 0 {
+		if allGuilds {
+			sendMessage(s, m.ChannelID, "활성화된 알림이 없습니다.")
+		} else {
+			sendMessage(s, m.ChannelID, fmt.Sprintf("이 서버에 활성화된 알림이 없습니다. 모든 서버의 알림은 %s alert list all로 확인하세요.", c.prefixes.Prefix(m.GuildID)))
+		}
+		return
+	}
+
+	// 페이지별 응답 임베드 생성
+	now := time.Now()
+	var embeds []*discordgo.MessageEmbed
+	for start := 0; start < len(alerts); start += alertListPageSize {
+		end := start + alertListPageSize
+		if end > len(alerts) {
+			end = len(alerts)
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:       "키워드 알림 목록",
+			Description: fmt.Sprintf("%d개의 활성화된 알림이 있습니다:", len(alerts)),
+			Color:       0x0000ff, // 파란색
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+
+		// 각 알림에 대한 필드 추가
+		for i, alert := range alerts[start:end] {
+			value := models.EscapeDiscord(alert.Keyword)
+			if alert.IsServerAlert() {
+				value += " [서버 알림]"
+			}
+			if alert.MaxPrice > 0 {
+				value += fmt.Sprintf(" [%s원 이하]", models.FormatKoreanNumber(alert.MaxPrice))
+			}
+			switch alert.BestDeal {
+			case models.BestDealPrice:
+				value += " [하루 최저가]"
+			case models.BestDealDiscount:
+				value += " [하루 최고 할인]"
+			}
+			if allGuilds && alert.ChannelID != "" {
+				value += fmt.Sprintf(" (<#%s>)", alert.ChannelID)
+			}
+			value += "\n" + formatAlertStats(alert, now)
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  fmt.Sprintf("알림 #%d", start+i+1),
+				Value: value,
+			})
+		}
+
+		embeds = append(embeds, embed)
+	}
+
+	c.log.Info("알림 목록 조회됨",
+		zap.String("user_id", m.Author.ID),
+		zap.Int("count", len(alerts)))
+	if err := NewPaginator(embeds, m.Author.ID).Send(s, m.ChannelID); err != nil {
+		c.log.Error("알림 목록 전송 실패", zap.Error(err))
+	}
+}
+
+// formatAlertStats는 알림의 생성 시점과 알림 횟수, 마지막 알림 시점을 한 줄로 표시합니다
+func formatAlertStats(alert models.KeywordAlert, now time.Time) string {
+	stats := fmt.Sprintf("알림 %d회", alert.NotifyCount)
+	if alert.CreatedAt > 0 {
+		stats = fmt.Sprintf("%s 생성 · %s", formatTimeAgo(time.Unix(alert.CreatedAt, 0), now), stats)
+	}
+	if alert.LastNotified > 0 {
+		stats += fmt.Sprintf(" · 마지막 알림 %s", formatTimeAgo(time.Unix(alert.LastNotified, 0), now))
+	}
+	return stats
+}
+
+// formatTimeAgo는 t가 now로부터 얼마나 지났는지 "3일 전"처럼 가장 큰 단위로 표시합니다
+func formatTimeAgo(t, now time.Time) string {
+	elapsed := now.Sub(t)
+	switch {
+	case elapsed < time.Minute:
+		return "방금 전"
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%d분 전", int(elapsed/time.Minute))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%d시간 전", int(elapsed/time.Hour))
+	case elapsed < 30*24*time.Hour:
+		return fmt.Sprintf("%d일 전", int(elapsed/(24*time.Hour)))
+	case elapsed < 365*24*time.Hour:
+		return fmt.Sprintf("%d개월 전", int(elapsed/(30*24*time.Hour)))
+	default:
+		return fmt.Sprintf("%d년 전", int(elapsed/(365*24*time.Hour)))
+	}
+}
+
+// handleTestAlertFromArgs processes alert test command from parsed arguments
+func (c *AlertCommand) handleTestAlertFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	// 옵션 분리
+	parsed, ok := parseCommandArgs(s, m.ChannelID, args, []string{"whole-word"}, nil)
+	if !ok {
+		return
+	}
+	wholeWord := parsed.Has("whole-word")
+
+	if len(parsed.Positional) == 0 {
+		sendError(s, m.ChannelID, "테스트할 키워드를 입력해주세요.")
+		return
 	}
 
-	cursor, err := collection.Find(ctx, filter)
+	keyword := strings.Join(parsed.Positional, " ")
+
+	// Create timeout context for database operations
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 최근 상품 조회
+	collection := c.db.Collection("products")
+	opts := options.Find().
+		SetSort(bson.D{{Key: "crawled_at", Value: -1}}).
+		SetLimit(alertTestProductLimit)
+
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
 	if err != nil {
-		c.log.Error("알림 목록 조회 실패", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "알림 목록을 조회하는 중 오류가 발생했습니다.")
+		c.log.Error("최근 상품 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "최근 상품을 조회하는 중 오류가 발생했습니다.")
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var alerts []models.KeywordAlert
-	if err := cursor.All(ctx, &alerts); err != nil {
-		c.log.Error("알림 디코딩 실패", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "알림 정보를 디코딩하는 중 오류가 발생했습니다.")
+	var products []models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		c.log.Error("상품 디코딩 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "상품 정보를 디코딩하는 중 오류가 발생했습니다.")
 		return
 	}
 
-	if len(alerts) == 0 {
-		s.ChannelMessageSend(m.ChannelID, "활성화된 알림이 없습니다.")
+	// 알림 매칭과 동일한 규칙으로 검사
+	alert := models.KeywordAlert{Keyword: keyword, WholeWord: wholeWord}
+	var matched []models.Product
+	for i := range products {
+		if alert.Matches(&products[i]) {
+			matched = append(matched, products[i])
+		}
+	}
+
+	// 응답 임베드 생성
+	embed := &discordgo.MessageEmbed{
+		Title: "키워드 알림 테스트",
+		Description: fmt.Sprintf("최근 상품 %d개 중 **%s** 키워드와 일치하는 상품은 %d개입니다.",
+			len(products), keyword, len(matched)),
+		Color: 0xffff00, // 노란색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	for i, product := range matched {
+		if i >= alertTestExampleLimit {
+			break
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  models.SanitizeTitle(product.Title),
+			Value: fmt.Sprintf("%s | %s", product.GetPriceString(), product.URL),
+		})
+	}
+
+	c.log.Info("알림 테스트됨",
+		zap.String("keyword", keyword),
+		zap.String("user_id", m.Author.ID),
+		zap.Int("matched", len(matched)))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleAlertHistoryFromArgs processes alert history command from parsed arguments
+func (c *AlertCommand) handleAlertHistoryFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendError(s, m.ChannelID, "기록을 조회할 키워드를 입력해주세요.")
+		return
+	}
+
+	keyword, ok := keywordFromArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
+
+	// Create timeout context for database operations
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 사용자의 알림 조회
+	var alert models.KeywordAlert
+	err := c.db.Collection("keyword_alerts").FindOne(ctx, bson.M{
+		"user_id":            m.Author.ID,
+		"normalized_keyword": models.NormalizeKeyword(keyword),
+		"scope":              bson.M{"$ne": models.AlertScopeServer},
+	}).Decode(&alert)
+	if err == mongo.ErrNoDocuments {
+		sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림을 찾을 수 없습니다.", keyword))
+		return
+	}
+	if err != nil {
+		c.log.Error("알림 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림을 조회하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	// 기간 내 일치 기록 조회
+	since := time.Now().Add(-alertHistoryWindow)
+	total, err := c.matchRepo.CountMatches(ctx, alert.ID, since, time.Time{})
+	if err != nil {
+		c.log.Error("알림 기록 집계 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림 기록을 조회하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	matches, err := c.matchRepo.GetMatches(ctx, alert.ID, since, time.Time{}, alertHistoryLimit)
+	if err != nil {
+		c.log.Error("알림 기록 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림 기록을 조회하는 중 오류가 발생했습니다.")
 		return
 	}
 
 	// 응답 임베드 생성
 	embed := &discordgo.MessageEmbed{
-		Title:       "키워드 알림 목록",
-		Description: fmt.Sprintf("%d개의 활성화된 알림이 있습니다:", len(alerts)),
+		Title:       "키워드 알림 기록",
+		Description: fmt.Sprintf("최근 30일 동안 **%s** 키워드로 %d개의 특가 알림을 받았습니다.", keyword, total),
 		Color:       0x0000ff, // 파란색
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
@@ -213,27 +584,743 @@ func (c *AlertCommand) handleListAlertsFromArgs(s *discordgo.Session, m *discord
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	// 각 알림에 대한 필드 추가
-	for i, alert := range alerts {
+	for _, match := range matches {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
-			Name:  fmt.Sprintf("알림 #%d", i+1),
-			Value: alert.Keyword,
+			Name:  models.SanitizeTitle(match.ProductTitle),
+			Value: fmt.Sprintf("%s | %s", match.MatchedAt.Format("2006-01-02 15:04"), match.ProductURL),
 		})
 	}
 
-	c.log.Info("알림 목록 조회됨", 
-		zap.String("user_id", m.Author.ID), 
-		zap.Int("count", len(alerts)))
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	c.log.Info("알림 기록 조회됨",
+		zap.String("keyword", keyword),
+		zap.String("user_id", m.Author.ID),
+		zap.Int64("total", total))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleQuietHoursFromArgs processes alert quiet command from parsed arguments.
+// Quiet hours apply to all of the user's personal alerts.
+func (c *AlertCommand) handleQuietHoursFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("사용법: %s alert quiet [23:00-08:00|off]", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	var update bson.M
+	var description string
+	if strings.ToLower(args[0]) == "off" || args[0] == "해제" {
+		update = bson.M{"$unset": bson.M{"quiet_start": "", "quiet_end": ""}}
+		description = "방해 금지 시간대가 해제되었습니다. 알림이 즉시 전송됩니다."
+	} else {
+		start, end, err := models.ParseQuietHours(strings.Join(args, ""))
+		if err != nil {
+			sendError(s, m.ChannelID, "시간대 형식이 올바르지 않습니다. 예: 23:00-08:00")
+			return
+		}
+		update = bson.M{"$set": bson.M{"quiet_start": start, "quiet_end": end}}
+		description = fmt.Sprintf("**%s ~ %s** 사이의 알림은 보류되었다가 시간대가 끝나면 전송됩니다.", start, end)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := c.db.Collection("keyword_alerts")
+	filter := bson.M{
+		"user_id": m.Author.ID,
+		"scope":   bson.M{"$ne": models.AlertScopeServer},
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		c.log.Error("방해 금지 시간대 설정 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "방해 금지 시간대를 설정하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		sendError(s, m.ChannelID, "설정할 알림이 없습니다. 먼저 알림을 추가해주세요.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "방해 금지 시간대",
+		Description: description,
+		Color:       0x9966FF, // Purple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	c.log.Info("방해 금지 시간대 설정됨",
+		zap.String("user_id", m.Author.ID),
+		zap.Int64("alerts", result.MatchedCount))
+	sendEmbed(s, m.ChannelID, embed)
 }
 
-// checkAlertExists는 사용자 ID와 키워드로 알림이 존재하는지 확인합니다
+// handleCooldownFromArgs sets how long an alert waits before notifying again.
+// "default" falls back to the configured cooldown, "off" disables it for the alert.
+func (c *AlertCommand) handleCooldownFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) < 2 {
+		sendError(s, m.ChannelID, fmt.Sprintf("사용법: %s alert cooldown [30m|off|default] [키워드]", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	keyword, ok := keywordFromArgs(s, m.ChannelID, args[1:])
+	if !ok {
+		return
+	}
+
+	var update bson.M
+	var description string
+	switch strings.ToLower(args[0]) {
+	case "default", "기본":
+		update = bson.M{"$unset": bson.M{"cooldown_minutes": ""}}
+		description = fmt.Sprintf("**%s** 알림의 재알림 간격을 기본값으로 되돌렸습니다.", keyword)
+	case "off", "해제":
+		update = bson.M{"$set": bson.M{"cooldown_minutes": models.CooldownOff}}
+		description = fmt.Sprintf("**%s** 알림은 일치하는 특가가 올라올 때마다 바로 알려드립니다.", keyword)
+	default:
+		d, err := parseReminderDuration(strings.ToLower(args[0]))
+		if err != nil || d < time.Minute || d > alertMaxCooldown {
+			sendError(s, m.ChannelID, "간격은 1분에서 24시간 사이로 입력해주세요. (예: 30m, 2h)")
+			return
+		}
+		minutes := int(d / time.Minute)
+		update = bson.M{"$set": bson.M{"cooldown_minutes": minutes}}
+		description = fmt.Sprintf("**%s** 알림을 보낸 뒤 %d분 동안은 같은 키워드로 다시 알리지 않습니다.", keyword, minutes)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := c.db.Collection("keyword_alerts")
+	filter := bson.M{
+		"user_id":            m.Author.ID,
+		"normalized_keyword": models.NormalizeKeyword(keyword),
+		"scope":              bson.M{"$ne": models.AlertScopeServer},
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		c.log.Error("재알림 간격 설정 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "재알림 간격을 설정하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림을 찾을 수 없습니다.", keyword))
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "재알림 간격",
+		Description: description,
+		Color:       0x9966FF, // Purple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	c.log.Info("재알림 간격 설정됨",
+		zap.String("user_id", m.Author.ID),
+		zap.String("keyword", keyword),
+		zap.String("cooldown", args[0]))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleSummaryModeFromArgs sets whether alerts ping in real time or are
+// collected into a daily or weekly summary DM. Without a keyword it applies
+// to all of the user's personal alerts.
+func (c *AlertCommand) handleSummaryModeFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("사용법: %s alert summary [realtime|daily|weekly] [키워드]", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	mode, ok := models.ParseSummaryMode(args[0])
+	if !ok {
+		sendError(s, m.ChannelID, "전송 방식은 realtime, daily, weekly 중 하나로 입력해주세요.")
+		return
+	}
+
+	filter := bson.M{
+		"user_id": m.Author.ID,
+		"scope":   bson.M{"$ne": models.AlertScopeServer},
+	}
+	target := "모든 알림"
+	keyword, ok := keywordFromArgs(s, m.ChannelID, args[1:])
+	if !ok {
+		return
+	}
+	if keyword != "" {
+		filter["normalized_keyword"] = models.NormalizeKeyword(keyword)
+		target = fmt.Sprintf("**%s** 알림", keyword)
+	}
+
+	update := bson.M{"$set": bson.M{"summary_mode": mode}}
+	var description string
+	switch mode {
+	case models.SummaryDaily:
+		description = fmt.Sprintf("%s은 바로 알리지 않고, 하루 동안 일치한 특가를 모아 매일 DM으로 보내드립니다.", target)
+	case models.SummaryWeekly:
+		description = fmt.Sprintf("%s은 바로 알리지 않고, 일주일 동안 일치한 특가를 모아 매주 DM으로 보내드립니다.", target)
+	default:
+		update = bson.M{"$unset": bson.M{"summary_mode": ""}}
+		description = fmt.Sprintf("%s은 일치하는 특가가 올라오면 바로 알려드립니다.", target)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := c.db.Collection("keyword_alerts")
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		c.log.Error("알림 전송 방식 설정 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림 전송 방식을 설정하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		if keyword != "" {
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 알림을 찾을 수 없습니다.", keyword))
+		} else {
+			sendError(s, m.ChannelID, "설정할 알림이 없습니다. 먼저 알림을 추가해주세요.")
+		}
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "알림 전송 방식",
+		Description: description,
+		Color:       0x9966FF, // Purple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	c.log.Info("알림 전송 방식 설정됨",
+		zap.String("user_id", m.Author.ID),
+		zap.String("mode", mode),
+		zap.Int64("alerts", result.MatchedCount))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleSnoozeFromArgs processes alert snooze command from parsed arguments
+func (c *AlertCommand) handleSnoozeFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	if len(args) == 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("사용법: %s alert snooze [2h|off]", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if strings.ToLower(args[0]) == "off" || args[0] == "해제" {
+		cleared, err := c.snoozeRepo.ClearSnooze(ctx, m.ChannelID)
+		if err != nil {
+			c.log.Error("채널 알림 중지 해제 실패", zap.Error(err))
+			sendError(s, m.ChannelID, "알림 중지를 해제하는 중 오류가 발생했습니다.")
+			return
+		}
+		if !cleared {
+			sendError(s, m.ChannelID, "이 채널은 알림이 중지되어 있지 않습니다.")
+			return
+		}
+		sendMessage(s, m.ChannelID, "이 채널의 알림이 다시 전송됩니다.")
+		return
+	}
+
+	d, err := parseReminderDuration(args[0])
+	if err != nil || d <= 0 || d > alertSnoozeMax {
+		sendError(s, m.ChannelID, "기간 형식이 올바르지 않습니다. 최대 7일까지 가능합니다. 예: 30m, 2h, 1d")
+		return
+	}
+
+	until := time.Now().Add(d)
+	snooze := models.NewChannelSnooze(m.ChannelID, m.GuildID, m.Author.ID, until)
+	if err := c.snoozeRepo.SetSnooze(ctx, snooze); err != nil {
+		c.log.Error("채널 알림 중지 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림을 중지하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "채널 알림 중지",
+		Description: fmt.Sprintf("**%s**까지 이 채널의 키워드 알림을 보내지 않습니다.", until.Format("2006-01-02 15:04")),
+		Color:       0x808080, // 회색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	c.log.Info("채널 알림 중지됨",
+		zap.String("channel_id", m.ChannelID),
+		zap.String("user_id", m.Author.ID),
+		zap.Time("until", until))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handlePopularAlertsFromArgs processes alert popular command from parsed arguments.
+// It shows the keywords whose alerts fired the most in this server.
+func (c *AlertCommand) handlePopularAlertsFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	limit, ok := parseKeywordLimit(args)
+	if !ok {
+		sendError(s, m.ChannelID, fmt.Sprintf("표시할 키워드 수는 1에서 %d 사이로 입력해주세요.", alertPopularMax))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	keywords, err := c.alertRepo.PopularKeywords(ctx, m.GuildID, limit)
+	if err != nil {
+		c.log.Error("인기 키워드 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "인기 키워드를 조회하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if len(keywords) == 0 {
+		sendMessage(s, m.ChannelID, "이 서버에서 아직 알림이 발송된 키워드가 없습니다.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "인기 알림 키워드",
+		Description: "이 서버에서 알림이 가장 많이 발송된 키워드입니다.",
+		Color:       0x0000ff, // 파란색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	for i, keyword := range keywords {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%d. %s", i+1, models.EscapeDiscord(keyword.Keyword)),
+			Value: fmt.Sprintf("알림 %d회 · 등록 %d명", keyword.NotifyCount, keyword.Subscribers),
+		})
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleAlertTrendsFromArgs processes alert trends command from parsed arguments.
+// Unlike popular, it counts subscriptions rather than notifications, across every server.
+func (c *AlertCommand) handleAlertTrendsFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	limit, ok := parseKeywordLimit(args)
+	if !ok {
+		sendError(s, m.ChannelID, fmt.Sprintf("표시할 키워드 수는 1에서 %d 사이로 입력해주세요.", alertPopularMax))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	trends, err := c.alertRepo.KeywordTrends(ctx, limit)
+	if err != nil {
+		c.log.Error("키워드 트렌드 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "키워드 트렌드를 조회하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if trends.TotalAlerts == 0 {
+		sendMessage(s, m.ChannelID, "등록된 알림이 없습니다.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "알림 키워드 트렌드",
+		Description: fmt.Sprintf("전체 서버에서 사용자 %d명이 알림 %d개를 등록했습니다.\n가장 많이 등록된 키워드입니다:",
+			trends.UniqueSubscribers, trends.TotalAlerts),
+		Color: 0x0000ff, // 파란색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	for i, trend := range trends.Top {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%d. %s", i+1, models.EscapeDiscord(trend.Keyword)),
+			Value: fmt.Sprintf("등록 %d명", trend.Subscribers),
+		})
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleAlertPreviewFromArgs processes alert preview command from parsed arguments.
+// It sends a sample product through the same message builder the notifier uses,
+// so admins can check the format and that the bot can post in this channel.
+func (c *AlertCommand) handleAlertPreviewFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	parsed, ok := parseCommandArgs(s, m.ChannelID, args, nil, []string{"lang"})
+	if !ok {
+		return
+	}
+
+	language := models.LanguageKorean
+	if value, ok := parsed.Flag("lang"); ok || len(parsed.Positional) > 0 {
+		if len(parsed.Positional) > 0 || !models.IsSupportedLanguage(value) {
+			sendError(s, m.ChannelID, "--lang 옵션에는 ko 또는 en을 입력해주세요.")
+			return
+		}
+		language = value
+	}
+
+	missing, err := missingBotSendPermissions(s, m.ChannelID)
+	if err != nil {
+		c.log.Error("봇 권한 확인 실패", zap.Error(err), zap.String("channel_id", m.ChannelID))
+		sendError(s, m.ChannelID, "봇 권한을 확인하는 중 오류가 발생했습니다.")
+		return
+	}
+	if len(missing) > 0 {
+		// 메시지 보내기 권한이 없으면 이 안내도 실패하므로 로그로도 남깁니다
+		c.log.Warn("봇 권한 부족", zap.Strings("missing", missing), zap.String("channel_id", m.ChannelID))
+		sendError(s, m.ChannelID, fmt.Sprintf("봇에 이 채널의 다음 권한이 없어 알림을 보낼 수 없습니다: %s", strings.Join(missing, ", ")))
+		return
+	}
+
+	alert := models.KeywordAlert{
+		UserID:    m.Author.ID,
+		Username:  m.Author.Username,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Keyword:   "미리보기",
+		Language:  language,
+	}
+	message := embeds.ProductMessage(previewProduct(), []models.KeywordAlert{alert}, embeds.MessagesFor(language))
+
+	if _, err := s.ChannelMessageSendComplex(m.ChannelID, message); err != nil {
+		c.log.Error("알림 미리보기 전송 실패", zap.Error(err), zap.String("channel_id", m.ChannelID))
+		sendError(s, m.ChannelID, "알림 미리보기를 보내지 못했습니다. 봇 권한을 확인해주세요.")
+	}
+}
+
+// previewProduct는 알림 미리보기에 사용할 예시 상품을 만듭니다.
+// 모든 선택 필드를 채워 실제 알림에 나올 수 있는 모든 항목이 보이게 합니다.
+func previewProduct() models.Product {
+	return models.Product{
+		Title:         "[미리보기] 예시 특가 상품 - 무선 이어폰 (무료배송)",
+		URL:           "https://www.ppomppu.co.kr/zboard/zboard.php?id=ppomppu",
+		Source:        "ppomppu",
+		KOPrice:       39900,
+		OriginalPrice: 79800,
+		DiscountRate:  50,
+		Comments:      42,
+		Views:         1234,
+		IsHot:         true,
+		CrawledAt:     time.Now(),
+	}
+}
+
+// parseKeywordLimit는 popular/trends 명령어의 선택적 개수 인자를 해석합니다
+func parseKeywordLimit(args []string) (int, bool) {
+	if len(args) == 0 {
+		return alertPopularDefault, true
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 || n > alertPopularMax {
+		return 0, false
+	}
+	return n, true
+}
+
+// handleAddServerAlertFromArgs processes alert addserver command from parsed arguments.
+// Server alerts notify the channel they were created in without mentioning anyone.
+func (c *AlertCommand) handleAddServerAlertFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	parsed, ok := parseCommandArgs(s, m.ChannelID, args, nil, []string{"role"})
+	if !ok {
+		return
+	}
+
+	roleID := ""
+	if value, ok := parsed.Flag("role"); ok {
+		id, ok := parseRoleMention(value)
+		if !ok {
+			sendError(s, m.ChannelID, "--role 옵션에는 역할 멘션(@역할) 또는 역할 ID를 입력해주세요.")
+			return
+		}
+		roleID = id
+	}
+	keywordArgs := parsed.Positional
+
+	if len(keywordArgs) == 0 {
+		sendError(s, m.ChannelID, "추가할 키워드를 입력해주세요.")
+		return
+	}
+
+	keyword := models.CleanKeyword(strings.Join(keywordArgs, " "))
+	if !c.validateKeyword(s, m.ChannelID, keyword) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := c.db.Collection("keyword_alerts")
+
+	// 이 서버에 같은 키워드의 서버 알림이 있는지 확인
+	count, err := collection.CountDocuments(ctx, serverAlertFilter(m.GuildID, keyword))
+	if err != nil {
+		c.log.Error("서버 알림 존재 여부 확인 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림 존재 여부를 확인하는 중 오류가 발생했습니다.")
+		return
+	}
+	if count > 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 서버 알림이 이미 존재합니다.", keyword))
+		return
+	}
+
+	alert := models.KeywordAlert{
+		Keyword:           keyword,
+		NormalizedKeyword: models.NormalizeKeyword(keyword),
+		UserID:            m.Author.ID,
+		Username:          m.Author.Username,
+		ChannelID:         m.ChannelID,
+		GuildID:           m.GuildID,
+		CreatedAt:         time.Now().Unix(),
+		IsActive:          true,
+		Scope:             models.AlertScopeServer,
+		RoleID:            roleID,
+	}
+
+	err = c.alertRepo.InsertAlert(ctx, &alert)
+	if err != nil {
+		// 같은 키워드의 서버 알림이 동시에 추가되면 고유 인덱스에서 중복 키 오류가 납니다
+		if mongo.IsDuplicateKeyError(err) {
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 서버 알림이 이미 존재합니다.", keyword))
+			return
+		}
+		c.log.Error("서버 알림 삽입 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림을 추가하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	description := fmt.Sprintf("키워드: **%s**와 일치하는 특가를 이 채널에 알립니다.", models.EscapeDiscord(keyword))
+	if roleID != "" {
+		description += fmt.Sprintf("\n알림 시 <@&%s> 역할을 멘션합니다.", roleID)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "서버 알림 추가됨",
+		Description: description,
+		Color:       0x00ff00, // 녹색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	c.log.Info("서버 알림 추가됨",
+		zap.String("keyword", keyword),
+		zap.String("guild_id", m.GuildID),
+		zap.String("channel_id", m.ChannelID),
+		zap.String("user_id", m.Author.ID))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleRemoveServerAlertFromArgs processes alert removeserver command from parsed arguments
+func (c *AlertCommand) handleRemoveServerAlertFromArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if !c.requireServerAdmin(s, m) {
+		return
+	}
+
+	if len(args) == 0 {
+		sendError(s, m.ChannelID, "삭제할 키워드를 입력해주세요.")
+		return
+	}
+
+	keyword, ok := keywordFromArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := c.db.Collection("keyword_alerts")
+	result, err := collection.DeleteOne(ctx, serverAlertFilter(m.GuildID, keyword))
+	if err != nil {
+		c.log.Error("서버 알림 삭제 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "알림을 삭제하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		sendError(s, m.ChannelID, fmt.Sprintf("'%s' 키워드에 대한 서버 알림을 찾을 수 없습니다.", keyword))
+		return
+	}
+
+	c.log.Info("서버 알림 삭제됨",
+		zap.String("keyword", keyword),
+		zap.String("guild_id", m.GuildID),
+		zap.String("user_id", m.Author.ID))
+	sendMessage(s, m.ChannelID, fmt.Sprintf("키워드: **%s**에 대한 서버 알림이 삭제되었습니다.", models.EscapeDiscord(keyword)))
+}
+
+// requireServerAdmin은 서버 관리 권한이 없으면 안내 메시지를 보내고 false를 반환합니다
+func (c *AlertCommand) requireServerAdmin(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	if m.GuildID == "" {
+		sendError(s, m.ChannelID, "서버 알림은 서버 채널에서만 관리할 수 있습니다.")
+		return false
+	}
+
+	admin, err := isServerAdmin(s, m.ChannelID, m.Author.ID)
+	if err != nil {
+		c.log.Error("권한 확인 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "권한을 확인하는 중 오류가 발생했습니다.")
+		return false
+	}
+	if !admin {
+		sendError(s, m.ChannelID, "서버 알림은 서버 관리 권한이 있는 사용자만 관리할 수 있습니다.")
+		return false
+	}
+
+	return true
+}
+
+// validateKeyword는 키워드가 너무 짧거나 일반적이면 이유를 안내하고 false를 반환합니다
+func (c *AlertCommand) validateKeyword(s *discordgo.Session, channelID, keyword string) bool {
+	if err := models.ValidateKeyword(keyword, c.minKeywordLength); err != nil {
+		sendError(s, channelID, keywordErrorMessage(err, keyword, c.minKeywordLength))
+		return false
+	}
+	return true
+}
+
+// keywordErrorMessage는 ValidateKeyword가 키워드를 거부한 이유를 안내하는 메시지를 반환합니다
+func keywordErrorMessage(err error, keyword string, minLength int) string {
+	switch {
+	case errors.Is(err, models.ErrKeywordNoLetters):
+		return "키워드에는 문자나 숫자가 포함되어야 합니다."
+	case errors.Is(err, models.ErrKeywordTooShort):
+		return fmt.Sprintf("키워드는 %d글자 이상 입력해주세요. 너무 짧은 키워드는 거의 모든 특가와 일치합니다.", minLength)
+	default:
+		return fmt.Sprintf("'%s'은(는) 거의 모든 특가에 포함되는 단어라 알림으로 등록할 수 없습니다. 상품명처럼 더 구체적인 키워드를 입력해주세요.",
+			models.EscapeDiscord(keyword))
+	}
+}
+
+// keywordFromArgs joins the positional arguments into a keyword the way alert
+// add does, so a keyword added with quotes can be referred to with them too.
+// It replies in the channel and returns false if the arguments don't parse.
+func keywordFromArgs(s *discordgo.Session, channelID string, args []string) (string, bool) {
+	parsed, ok := parseCommandArgs(s, channelID, args, nil, nil)
+	if !ok {
+		return "", false
+	}
+	return models.CleanKeyword(strings.Join(parsed.Positional, " ")), true
+}
+
+// parseRoleMention은 "<@&ID>" 형식의 역할 멘션이나 숫자 역할 ID에서 역할 ID를 추출합니다
+func parseRoleMention(arg string) (string, bool) {
+	id := arg
+	if strings.HasPrefix(arg, "<@&") && strings.HasSuffix(arg, ">") {
+		id = arg[3 : len(arg)-1]
+	}
+	if id == "" {
+		return "", false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	return id, true
+}
+
+// serverAlertFilter는 서버의 키워드 서버 알림을 찾는 필터를 반환합니다
+func serverAlertFilter(guildID, keyword string) bson.M {
+	return bson.M{
+		"guild_id":           guildID,
+		"normalized_keyword": models.NormalizeKeyword(keyword),
+		"scope":              models.AlertScopeServer,
+	}
+}
+
+// addUserAlert는 개인 알림을 저장합니다.
+// 키워드를 정리하고 중복 검사용 키를 채우며, 사용자의 방해 금지 시간대를 새 알림에도 적용합니다.
+// 정규화한 키워드가 같은 알림이 이미 있으면 storage.ErrAlreadyExists를 반환합니다.
+func (c *AlertCommand) addUserAlert(ctx context.Context, alert *models.KeywordAlert) error {
+	collection := c.db.Collection("keyword_alerts")
+
+	alert.Keyword = models.CleanKeyword(alert.Keyword)
+	alert.NormalizedKeyword = models.NormalizeKeyword(alert.Keyword)
+	// 새 알림은 알림 기록 없이 시작합니다 (필드가 항상 저장되어 목록/내보내기에서 읽을 수 있음)
+	alert.NotifyCount = 0
+	alert.LastNotified = 0
+
+	var quietAlert models.KeywordAlert
+	err := collection.FindOne(ctx, bson.M{
+		"user_id":     alert.UserID,
+		"quiet_start": bson.M{"$exists": true},
+	}).Decode(&quietAlert)
+	if err == nil {
+		alert.QuietStart = quietAlert.QuietStart
+		alert.QuietEnd = quietAlert.QuietEnd
+	}
+
+	exists, err := c.checkAlertExists(ctx, alert.UserID, alert.Keyword)
+	if err != nil {
+		return fmt.Errorf("failed to check existing alert: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("alert %q: %w", alert.Keyword, storage.ErrAlreadyExists)
+	}
+
+	// 봇 권한 부족 등으로 비활성화된 같은 키워드 알림은 새 알림으로 대체합니다 (고유 인덱스 충돌 방지)
+	_, err = collection.DeleteMany(ctx, bson.M{
+		"user_id":            alert.UserID,
+		"normalized_keyword": alert.NormalizedKeyword,
+		"is_active":          false,
+		"scope":              bson.M{"$ne": models.AlertScopeServer},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove inactive alert: %w", err)
+	}
+
+	err = c.alertRepo.InsertAlert(ctx, alert)
+	// 동시에 같은 키워드를 추가하면 고유 인덱스에서 중복 키 오류가 납니다
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("alert %q: %w", alert.Keyword, storage.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to insert alert: %w", err)
+	}
+
+	return nil
+}
+
+// checkAlertExists는 사용자 ID와 정규화한 키워드로 알림이 존재하는지 확인합니다
 func (c *AlertCommand) checkAlertExists(ctx context.Context, userID, keyword string) (bool, error) {
 	collection := c.db.Collection("keyword_alerts")
 	filter := bson.M{
-		"user_id": userID,
-		"keyword": keyword,
-		"is_active": true,
+		"user_id":            userID,
+		"normalized_keyword": models.NormalizeKeyword(keyword),
+		"is_active":          true,
+		"scope":              bson.M{"$ne": models.AlertScopeServer},
 	}
 
 	count, err := collection.CountDocuments(ctx, filter)
@@ -242,4 +1329,17 @@ func (c *AlertCommand) checkAlertExists(ctx context.Context, userID, keyword str
 	}
 
 	return count > 0, nil
-}
\ No newline at end of file
+}
+
+// NewAlertCommand는 새로운 알림 명령어 핸들러를 생성합니다
+func NewAlertCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache, minKeywordLength int) *AlertCommand {
+	return &AlertCommand{
+		log:              log.Named("alert-command"),
+		db:               db,
+		prefixes:         prefixes,
+		minKeywordLength: minKeywordLength,
+		alertRepo:        storage.NewAlertRepository(db, log),
+		matchRepo:        storage.NewAlertMatchRepository(db, log),
+		snoozeRepo:       storage.NewChannelSnoozeRepository(db, log),
+	}
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestFormatAlertStats(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		alert models.KeywordAlert
+		want  string
+	}{
+		{
+			name:  "never notified",
+			alert: models.KeywordAlert{CreatedAt: now.Add(-3 * 24 * time.Hour).Unix()},
+			want:  "3일 전 생성 · 알림 0회",
+		},
+		{
+			name: "notified",
+			alert: models.KeywordAlert{
+				CreatedAt:    now.Add(-60 * 24 * time.Hour).Unix(),
+				NotifyCount:  12,
+				LastNotified: now.Add(-90 * time.Minute).Unix(),
+			},
+			want: "2개월 전 생성 · 알림 12회 · 마지막 알림 1시간 전",
+		},
+		{
+			name:  "no creation time",
+			alert: models.KeywordAlert{NotifyCount: 1, LastNotified: now.Add(-30 * time.Second).Unix()},
+			want:  "알림 1회 · 마지막 알림 방금 전",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := formatAlertStats(tt.alert, now); got != tt.want {
+			t.Errorf("%s: formatAlertStats() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// addGuildState puts a guild owned by ownerID into the session state, with the
+// bot granted botPerms in channel-1 through the @everyone role
+func addGuildState(t *testing.T, session *discordgo.Session, ownerID string, botPerms int64) {
+	t.Helper()
+	session.State.User = &discordgo.User{ID: "bot"}
+	guild := &discordgo.Guild{
+		ID:      "guild-1",
+		OwnerID: ownerID,
+		Roles:   []*discordgo.Role{{ID: "guild-1", Permissions: botPerms}},
+	}
+	if err := session.State.GuildAdd(guild); err != nil {
+		t.Fatalf("failed to add guild: %v", err)
+	}
+	if err := session.State.ChannelAdd(&discordgo.Channel{ID: "channel-1", GuildID: "guild-1"}); err != nil {
+		t.Fatalf("failed to add channel: %v", err)
+	}
+	for _, id := range []string{"bot", ownerID} {
+		if err := session.State.MemberAdd(&discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: id}}); err != nil {
+			t.Fatalf("failed to add member: %v", err)
+		}
+	}
+}
+
+func TestAlertPreview(t *testing.T) {
+	canSend := int64(discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks)
+	m := &discordgo.MessageCreate{Message: &discordgo.Message{
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Author:    &discordgo.User{ID: "admin-1", Username: "admin"},
+	}}
+
+	t.Run("sends the deal embed", func(t *testing.T) {
+		session := newRecordingSession(t)
+		addGuildState(t, session.Session, "admin-1", canSend)
+		c := &AlertCommand{log: zap.NewNop()}
+
+		c.handleAlertPreviewFromArgs(session.Session, m, nil)
+
+		sends := session.sends()
+		if len(sends) != 1 || len(sends[0].Embeds) != 1 {
+			t.Fatalf("sent %+v, want one message with the deal embed", sends)
+		}
+		embed := sends[0].Embeds[0]
+		product := previewProduct()
+		if embed.Title == "" || embed.URL != product.URL || embed.Color == 0 || len(embed.Fields) == 0 {
+			t.Errorf("embed = %+v, want a titled, linked and colored deal embed with fields", embed)
+		}
+		if n := utf8.RuneCountInString(embed.Title); n > embeds.TitleLimit {
+			t.Errorf("title is %d characters, want at most %d", n, embeds.TitleLimit)
+		}
+		if sends[0].Content != "<@admin-1>" {
+			t.Errorf("Content = %q, want the admin mentioned like an alert owner", sends[0].Content)
+		}
+	})
+
+	t.Run("bot can't embed links", func(t *testing.T) {
+		session := newRecordingSession(t)
+		addGuildState(t, session.Session, "admin-1", discordgo.PermissionViewChannel|discordgo.PermissionSendMessages)
+		c := &AlertCommand{log: zap.NewNop()}
+
+		c.handleAlertPreviewFromArgs(session.Session, m, nil)
+
+		sends := session.sends()
+		if len(sends) != 1 || len(sends[0].Embeds) != 0 || !strings.Contains(sends[0].Content, "링크 첨부") {
+			t.Errorf("sent %+v, want only a notice naming the missing permission", sends)
+		}
+	})
+}
+
+func TestAlertHistoryIgnoresServerAlerts(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("only a server alert", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse())
+		session := newRecordingSession(mt.T)
+		c := NewAlertCommand(zap.NewNop(), newMockDB(mt), staticPrefixes(nil), 2)
+		m := &discordgo.MessageCreate{Message: &discordgo.Message{
+			ChannelID: "channel-1",
+			GuildID:   "guild-1",
+			Author:    &discordgo.User{ID: "admin-1", Username: "admin"},
+		}}
+
+		c.handleAlertHistoryFromArgs(session.Session, m, []string{"모니터"})
+
+		finds := startedCommands(mt, "find")
+		if len(finds) != 1 {
+			mt.Fatalf("sent %d finds, want 1", len(finds))
+		}
+		scope := finds[0].Lookup("filter", "scope", "$ne")
+		if got, ok := scope.StringValueOK(); !ok || got != models.AlertScopeServer {
+			mt.Errorf("history filter scope = %s, want server alerts excluded", scope)
+		}
+		if messages := session.messages(); len(messages) != 1 || !strings.Contains(messages[0], "알림을 찾을 수 없습니다") {
+			mt.Errorf("replied %q, want the alert not found", messages)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var errUnterminatedQuote = errors.New("unterminated quote")
+
+// Args are command arguments split into positional values and flags
+type Args struct {
+	Positional []string
+	Flags      map[string]string // 대시를 뺀 플래그 이름 -> 값 (값이 없는 플래그는 "")
+}
+
+// ParseArgs parses command arguments. The arguments are joined back together
+// and re-split on whitespace, keeping "double-quoted" sections together with
+// the quotes removed; \" and \\ stand for a literal quote and backslash.
+//
+// Unquoted --name and -n tokens are flags. Flags listed in valueFlags take the
+// following token as their value, which can also be given as --name=value.
+// A value flag without a value is recorded with an empty value, so the
+// command can explain what it expected. A bare -- ends the flags, and tokens
+// such as -5 or -> are positional.
+func ParseArgs(args []string, valueFlags ...string) (*Args, error) {
+	tokens, err := tokenizeArgs(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	takesValue := make(map[string]bool, len(valueFlags))
+	for _, name := range valueFlags {
+		takesValue[name] = true
+	}
+
+	parsed := &Args{Flags: make(map[string]string)}
+	flagsDone := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if flagsDone || token.quoted {
+			parsed.Positional = append(parsed.Positional, token.value)
+			continue
+		}
+		if token.value == "--" {
+			flagsDone = true
+			continue
+		}
+
+		name, value, hasValue, ok := splitFlag(token.value)
+		if !ok {
+			parsed.Positional = append(parsed.Positional, token.value)
+			continue
+		}
+		if !hasValue && takesValue[name] && i+1 < len(tokens) {
+			value = tokens[i+1].value
+			i++
+		}
+		parsed.Flags[name] = value
+	}
+
+	return parsed, nil
+}
+
+// Has reports whether the flag was given
+func (a *Args) Has(name string) bool {
+	_, ok := a.Flags[name]
+	return ok
+}
+
+// Flag returns the value of a flag and whether it was given
+func (a *Args) Flag(name string) (string, bool) {
+	value, ok := a.Flags[name]
+	return value, ok
+}
+
+// UnknownFlag returns a flag not in known, formatted as typed (--name or -n),
+// so a command can reject typos instead of silently ignoring them
+func (a *Args) UnknownFlag(known ...string) (string, bool) {
+	names := make([]string, 0, len(a.Flags))
+	for name := range a.Flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		isKnown := false
+		for _, k := range known {
+			if name == k {
+				isKnown = true
+				break
+			}
+		}
+		if !isKnown {
+			if len([]rune(name)) == 1 {
+				return "-" + name, true
+			}
+			return "--" + name, true
+		}
+	}
+	return "", false
+}
+
+// splitFlag parses a --name, --name=value or -n token. Anything else, such as
+// a negative number or an arrow, is not a flag.
+func splitFlag(token string) (name, value string, hasValue, ok bool) {
+	if strings.HasPrefix(token, "--") {
+		name = token[2:]
+		if i := strings.IndexByte(name, '='); i >= 0 {
+			name, value, hasValue = name[:i], name[i+1:], true
+		}
+		if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+			return "", "", false, false
+		}
+		return name, value, hasValue, true
+	}
+
+	runes := []rune(token)
+	if len(runes) == 2 && runes[0] == '-' && unicode.IsLetter(runes[1]) {
+		return string(runes[1]), "", false, true
+	}
+	return "", "", false, false
+}
+
+// argToken is one whitespace-separated argument. quoted is set if any part of
+// it was quoted, so a quoted "--name" stays a positional value.
+type argToken struct {
+	value  string
+	quoted bool
+}
+
+// tokenizeArgs splits s on whitespace, keeping "double-quoted" sections
+// together as a single argument with the quotes removed. Curly quotes, which
+// mobile keyboards often insert, count as quotes too. A quote only opens a
+// section at the start of an argument, so an inch mark such as 27" stays
+// literal.
+func tokenizeArgs(s string) ([]argToken, error) {
+	var (
+		tokens    []argToken
+		current   strings.Builder
+		quoted    bool // 따옴표 안인지
+		escaped   bool // 직전 문자가 백슬래시인지
+		started   bool
+		wasQuoted bool
+	)
+
+	for _, r := range s {
+		if escaped {
+			escaped = false
+			if isQuote(r) || r == '\\' {
+				current.WriteRune(r)
+				continue
+			}
+			// 다른 문자 앞의 백슬래시는 그대로 둡니다
+			current.WriteRune('\\')
+		}
+
+		switch {
+		case r == '\\':
+			escaped = true
+			started = true
+		case isQuote(r) && (quoted || !started):
+			quoted = !quoted
+			started = true
+			wasQuoted = true
+		case unicode.IsSpace(r) && !quoted:
+			if started {
+				tokens = append(tokens, argToken{value: current.String(), quoted: wasQuoted})
+				current.Reset()
+				started = false
+				wasQuoted = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+
+	if quoted {
+		return nil, errUnterminatedQuote
+	}
+	if escaped {
+		current.WriteRune('\\')
+	}
+	if started {
+		tokens = append(tokens, argToken{value: current.String(), quoted: wasQuoted})
+	}
+
+	return tokens, nil
+}
+
+// isQuote reports whether r is a straight or curly double quote
+func isQuote(r rune) bool {
+	return r == '"' || r == '“' || r == '”'
+}
+
+// parseQuotedArgs splits s like tokenizeArgs, without looking for flags
+func parseQuotedArgs(s string) ([]string, error) {
+	tokens, err := tokenizeArgs(s)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		parts = append(parts, token.value)
+	}
+	return parts, nil
+}
+
+// parseCommandArgs parses args with ParseArgs for a command that accepts
+// boolFlags and valueFlags. On an unterminated quote or an unknown flag it
+// replies in the channel and returns false.
+func parseCommandArgs(s *discordgo.Session, channelID string, args []string, boolFlags, valueFlags []string) (*Args, bool) {
+	parsed, err := ParseArgs(args, valueFlags...)
+	if err != nil {
+		sendError(s, channelID, "따옴표가 닫히지 않았습니다. 입력을 다시 확인해주세요.")
+		return nil, false
+	}
+
+	if flag, ok := parsed.UnknownFlag(append(append([]string{}, boolFlags...), valueFlags...)...); ok {
+		sendError(s, channelID, fmt.Sprintf("알 수 없는 옵션입니다: %s", flag))
+		return nil, false
+	}
+
+	return parsed, true
+}
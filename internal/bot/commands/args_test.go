@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		valueFlags []string
+		positional []string
+		flags      map[string]string
+	}{
+		{
+			name:       "plain",
+			args:       []string{"add", "모니터"},
+			positional: []string{"add", "모니터"},
+		},
+		{
+			name:       "quoted",
+			args:       []string{"add", `"게이밍`, `모니터"`},
+			positional: []string{"add", "게이밍 모니터"},
+		},
+		{
+			name:       "curly quotes",
+			args:       []string{"add", "“게이밍", "모니터”"},
+			positional: []string{"add", "게이밍 모니터"},
+		},
+		{
+			name:       "escaped quote",
+			args:       []string{`"27\"`, `모니터"`},
+			positional: []string{`27" 모니터`},
+		},
+		{
+			name:       "inch mark",
+			args:       []string{"add", `27"`, "모니터"},
+			positional: []string{"add", `27"`, "모니터"},
+		},
+		{
+			name:       "inch mark inside a quote",
+			args:       []string{`"27\"`, `4K"`, "모니터"},
+			positional: []string{`27" 4K`, "모니터"},
+		},
+		{
+			name:       "flags between positionals",
+			args:       []string{"add", "--whole-word", "rtx", "--min", "100000", "4090", "-q"},
+			valueFlags: []string{"min"},
+			positional: []string{"add", "rtx", "4090"},
+			flags:      map[string]string{"whole-word": "", "min": "100000", "q": ""},
+		},
+		{
+			name:       "flag with equals",
+			args:       []string{"--lang=en", "rtx"},
+			valueFlags: []string{"lang"},
+			positional: []string{"rtx"},
+			flags:      map[string]string{"lang": "en"},
+		},
+		{
+			name:       "value flag without a value",
+			args:       []string{"rtx", "--role"},
+			valueFlags: []string{"role"},
+			positional: []string{"rtx"},
+			flags:      map[string]string{"role": ""},
+		},
+		{
+			name:       "quoted flag is positional",
+			args:       []string{`"--whole-word"`},
+			positional: []string{"--whole-word"},
+		},
+		{
+			name:       "double dash ends flags",
+			args:       []string{"--", "-q", "--x"},
+			positional: []string{"-q", "--x"},
+		},
+		{
+			name:       "not flags",
+			args:       []string{"-5", "->", "--"},
+			positional: []string{"-5", "->"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseArgs(tt.args, tt.valueFlags...)
+			if err != nil {
+				t.Fatalf("ParseArgs() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed.Positional, tt.positional) {
+				t.Errorf("Positional = %q, want %q", parsed.Positional, tt.positional)
+			}
+			flags := tt.flags
+			if flags == nil {
+				flags = map[string]string{}
+			}
+			if !reflect.DeepEqual(parsed.Flags, flags) {
+				t.Errorf("Flags = %v, want %v", parsed.Flags, flags)
+			}
+		})
+	}
+}
+
+func TestParseArgsUnterminatedQuote(t *testing.T) {
+	if _, err := ParseArgs([]string{"add", `"게이밍`, "모니터"}); !errors.Is(err, errUnterminatedQuote) {
+		t.Errorf("ParseArgs() error = %v, want errUnterminatedQuote", err)
+	}
+}
+
+func TestUnknownFlag(t *testing.T) {
+	parsed, err := ParseArgs([]string{"rtx", "--whole-word", "-x", "--lnag", "en"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if flag, ok := parsed.UnknownFlag("whole-word", "x"); !ok || flag != "--lnag" {
+		t.Errorf("UnknownFlag() = %q, %v, want --lnag", flag, ok)
+	}
+	if flag, ok := parsed.UnknownFlag("whole-word", "lnag"); !ok || flag != "-x" {
+		t.Errorf("UnknownFlag() = %q, %v, want -x", flag, ok)
+	}
+	if _, ok := parsed.UnknownFlag("whole-word", "x", "lnag"); ok {
+		t.Error("UnknownFlag() reported a known flag")
+	}
+}
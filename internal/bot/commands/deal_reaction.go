@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// DealReactionHandler는 특가 알림 메시지에 🔔 반응을 단 사용자에게 해당 상품의 키워드 알림을 만들어 줍니다
+type DealReactionHandler struct {
+	log    *zap.Logger
+	deals  *storage.DealMessageRepository
+	alerts *AlertCommand
+}
+
+// Handle은 MessageReactionAdd 이벤트를 처리합니다.
+// 봇이 게시한 특가 메시지의 🔔 반응만 처리하고 나머지는 무시합니다.
+func (h *DealReactionHandler) Handle(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if r.Emoji.Name != models.DealAlertEmoji || r.UserID == s.State.User.ID {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	deal, err := h.deals.GetMessage(ctx, r.MessageID)
+	if err != nil {
+		h.log.Error("Failed to look up deal message", zap.Error(err), zap.String("message_id", r.MessageID))
+		return
+	}
+	if deal == nil {
+		return // 특가 알림 메시지가 아니거나 만료됨
+	}
+
+	keyword, problem := h.alertKeyword(deal)
+	if problem != "" {
+		h.sendDM(s, r.UserID, problem)
+		return
+	}
+
+	username := r.UserID
+	if r.Member != nil && r.Member.User != nil {
+		username = r.Member.User.Username
+	}
+
+	alert := models.KeywordAlert{
+		Keyword:   keyword,
+		UserID:    r.UserID,
+		Username:  username,
+		ChannelID: r.ChannelID,
+		GuildID:   r.GuildID,
+		CreatedAt: time.Now().Unix(),
+		IsActive:  true,
+	}
+
+	if err := h.alerts.addUserAlert(ctx, &alert); err != nil {
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			h.sendDM(s, r.UserID, fmt.Sprintf("'%s' 키워드에 대한 알림이 이미 존재합니다.", keyword))
+			return
+		}
+		h.log.Error("Failed to add alert from reaction", zap.Error(err), zap.String("user_id", r.UserID))
+		h.sendDM(s, r.UserID, "알림을 추가하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	h.log.Info("알림 추가됨 (반응)",
+		zap.String("keyword", keyword),
+		zap.String("user_id", r.UserID),
+		zap.String("message_id", r.MessageID))
+	h.sendDM(s, r.UserID, fmt.Sprintf("🔔 **%s** 키워드 알림이 추가되었습니다.\n상품: %s",
+		models.EscapeDiscord(keyword), models.EscapeDiscord(deal.ProductTitle)))
+}
+
+// alertKeyword는 특가 메시지로 만들 알림 키워드를 반환합니다.
+// 키워드를 만들 수 없거나 명령어로 추가하는 알림과 같은 기준으로 너무 짧거나
+// 일반적이면 사용자에게 보낼 안내를 대신 반환합니다.
+func (h *DealReactionHandler) alertKeyword(deal *models.DealMessage) (keyword, problem string) {
+	keyword = deal.SuggestAlertKeyword()
+	if keyword == "" {
+		return "", "이 상품에서 키워드를 만들 수 없습니다. `alert add` 명령어로 직접 등록해주세요."
+	}
+	if err := models.ValidateKeyword(keyword, h.alerts.minKeywordLength); err != nil {
+		return "", keywordErrorMessage(err, keyword, h.alerts.minKeywordLength) + " `alert add` 명령어로 직접 등록해주세요."
+	}
+	return keyword, ""
+}
+
+// sendDM은 사용자에게 DM을 보냅니다. DM을 막아 둔 사용자는 로그만 남깁니다.
+func (h *DealReactionHandler) sendDM(s *discordgo.Session, userID, content string) {
+	channel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		h.log.Warn("Failed to open DM channel", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+
+	if _, err := sendMessage(s, channel.ID, content); err != nil {
+		h.log.Warn("Failed to send DM", zap.Error(err), zap.String("user_id", userID))
+	}
+}
+
+// NewDealReactionHandler는 새로운 특가 반응 핸들러를 생성합니다
+func NewDealReactionHandler(log *zap.Logger, db *storage.MongoDB, alerts *AlertCommand) *DealReactionHandler {
+	return &DealReactionHandler{
+		log:    log.Named("deal-reaction"),
+		deals:  storage.NewDealMessageRepository(db, log),
+		alerts: alerts,
+	}
+}
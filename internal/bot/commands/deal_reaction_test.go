@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestDealReactionAlertKeyword(t *testing.T) {
+	h := &DealReactionHandler{alerts: NewAlertCommand(zap.NewNop(), nil, staticPrefixes(nil), 2)}
+
+	tests := []struct {
+		name    string
+		deal    models.DealMessage
+		want    string
+		problem string
+	}{
+		{"product name", models.DealMessage{ProductName: "LG 27인치 모니터", ProductTitle: "[11번가] LG 모니터 특가"}, "LG 27인치 모니터", ""},
+		{"nothing to use", models.DealMessage{}, "", "키워드를 만들 수 없습니다"},
+		{"too short", models.DealMessage{ProductTitle: "[쿠팡] 원"}, "", "2글자 이상"},
+		{"too generic", models.DealMessage{ProductTitle: "[쿠팡] 특가"}, "", "구체적인 키워드"},
+		{"only punctuation", models.DealMessage{ProductTitle: "[쿠팡] !!"}, "", "문자나 숫자"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyword, problem := h.alertKeyword(&tt.deal)
+			if keyword != tt.want {
+				t.Errorf("keyword = %q, want %q", keyword, tt.want)
+			}
+			if tt.problem == "" && problem != "" {
+				t.Errorf("problem = %q, want none", problem)
+			}
+			if tt.problem != "" && !strings.Contains(problem, tt.problem) {
+				t.Errorf("problem = %q, want it to mention %q", problem, tt.problem)
+			}
+		})
+	}
+}
+
+func TestDealReactionCreatesAlert(t *testing.T) {
+	mt := newMockTest(t)
+
+	deal := bson.D{
+		{Key: "_id", Value: "message-1"},
+		{Key: "channel_id", Value: "deals"},
+		{Key: "product_title", Value: "[11번가] LG 27인치 모니터 특가"},
+		{Key: "product_name", Value: "LG 27인치 모니터"},
+	}
+	reaction := func(emoji string) *discordgo.MessageReactionAdd {
+		return &discordgo.MessageReactionAdd{MessageReaction: &discordgo.MessageReaction{
+			UserID:    "user-1",
+			MessageID: "message-1",
+			ChannelID: "deals",
+			GuildID:   "guild-1",
+			Emoji:     discordgo.Emoji{Name: emoji},
+		}}
+	}
+	newHandler := func(mt *mtest.T) (*DealReactionHandler, *recordingSession) {
+		session := newRecordingSession(mt.T)
+		session.State.User = &discordgo.User{ID: "bot"}
+		db := newMockDB(mt)
+		return NewDealReactionHandler(zap.NewNop(), db, NewAlertCommand(zap.NewNop(), db, staticPrefixes(nil), 2)), session
+	}
+
+	mt.Run("bell", func(mt *mtest.T) {
+		h, session := newHandler(mt)
+		mt.AddMockResponses(
+			cursorResponse(deal), // deal message
+			cursorResponse(),     // no quiet hours
+			cursorResponse(bson.D{{Key: "n", Value: 0}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}), // inactive alerts removed
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), // alert inserted
+		)
+
+		h.Handle(session.Session, reaction(models.DealAlertEmoji))
+
+		inserts := startedCommands(mt, "insert")
+		if len(inserts) != 1 {
+			mt.Fatalf("sent %d inserts, want 1", len(inserts))
+		}
+		doc := inserts[0].Lookup("documents").Array().Index(0).Value().Document()
+		if got := doc.Lookup("keyword").StringValue(); got != "LG 27인치 모니터" {
+			mt.Errorf("alert keyword = %q, want the product name", got)
+		}
+		if got := doc.Lookup("user_id").StringValue(); got != "user-1" {
+			mt.Errorf("alert user = %q, want the reacting user", got)
+		}
+		sends := session.sends()
+		if len(sends) != 1 || sends[0].ChannelID != "dm-user-1" || !strings.Contains(sends[0].Content, "키워드 알림이 추가되었습니다") {
+			mt.Errorf("sent %+v, want a DM confirming the alert", sends)
+		}
+	})
+
+	mt.Run("already exists", func(mt *mtest.T) {
+		h, session := newHandler(mt)
+		mt.AddMockResponses(
+			cursorResponse(deal),
+			cursorResponse(),
+			cursorResponse(bson.D{{Key: "n", Value: 1}}),
+		)
+
+		h.Handle(session.Session, reaction(models.DealAlertEmoji))
+
+		if n := len(startedCommands(mt, "insert")); n != 0 {
+			mt.Errorf("sent %d inserts, want 0", n)
+		}
+		if messages := session.messages(); len(messages) != 1 || !strings.Contains(messages[0], "이미 존재합니다") {
+			mt.Errorf("sent %q, want a DM saying the alert exists", messages)
+		}
+	})
+
+	mt.Run("other emoji", func(mt *mtest.T) {
+		h, session := newHandler(mt)
+
+		h.Handle(session.Session, reaction("👍"))
+
+		if n := len(mt.GetAllStartedEvents()); n != 0 {
+			mt.Errorf("sent %d database commands, want none", n)
+		}
+		if messages := session.messages(); len(messages) != 0 {
+			mt.Errorf("sent %q, want nothing", messages)
+		}
+	})
+}
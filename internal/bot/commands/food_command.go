@@ -2,8 +2,10 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bradykim7/gbot/internal/models"
@@ -14,20 +16,77 @@ import (
 
 // FoodCommand는 음식 추천 관련 명령어를 처리합니다
 type FoodCommand struct {
-	log      *zap.Logger
-	db       *storage.MongoDB
-	prefix   string
-	repo     *storage.FoodRepository
+	log       *zap.Logger
+	db        *storage.MongoDB
+	prefixes  *PrefixCache
+	repo      *storage.FoodRepository
+	deletions *foodDeletionLog
+	requests  *storage.FoodRequestRepository
+}
+
+// foodStatsLimit는 mystats에 표시할 최대 메뉴 수입니다
+const foodStatsLimit = 10
+
+// foodDeletionLogSize는 채널별로 기억하는 최근 삭제 수입니다
+const foodDeletionLogSize = 10
+
+// foodDeletion은 되돌릴 수 있는 메뉴 삭제 기록입니다
+type foodDeletion struct {
+	name     string
+	foodType models.FoodType
+}
+
+// foodDeletionLog는 채널별 최근 메뉴 삭제를 기억합니다 (봇 재시작 시 초기화됩니다)
+type foodDeletionLog struct {
+	mu        sync.Mutex
+	byChannel map[string][]foodDeletion
+}
+
+// newFoodDeletionLog는 빈 삭제 기록을 생성합니다
+func newFoodDeletionLog() *foodDeletionLog {
+	return &foodDeletionLog{byChannel: make(map[string][]foodDeletion)}
+}
+
+// push는 채널의 삭제 기록을 추가하고 오래된 기록은 버립니다
+func (l *foodDeletionLog) push(channelID string, deletion foodDeletion) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := append(l.byChannel[channelID], deletion)
+	if len(entries) > foodDeletionLogSize {
+		entries = entries[len(entries)-foodDeletionLogSize:]
+	}
+	l.byChannel[channelID] = entries
+}
+
+// pop은 채널의 가장 최근 삭제 기록을 꺼냅니다
+func (l *foodDeletionLog) pop(channelID string) (foodDeletion, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := l.byChannel[channelID]
+	if len(entries) == 0 {
+		return foodDeletion{}, false
+	}
+
+	last := entries[len(entries)-1]
+	if len(entries) == 1 {
+		delete(l.byChannel, channelID)
+	} else {
+		l.byChannel[channelID] = entries[:len(entries)-1]
+	}
+	return last, true
 }
 
 // Execute implements the Command interface
 func (c *FoodCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
 	if len(args) == 0 {
-		c.sendHelpMessage(s, m.ChannelID)
+		c.sendHelpMessage(s, m)
 		return
 	}
 
-	subCommand := args[0]
+	// 라틴 문자 하위 명령어는 대소문자를 구분하지 않습니다 (메뉴 이름은 그대로 둡니다)
+	subCommand := strings.ToLower(args[0])
 	args = args[1:]
 
 	switch subCommand {
@@ -41,25 +100,37 @@ func (c *FoodCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate,
 		c.handleRegisterFoodArgs(s, m, args)
 	case "remove", "삭제":
 		c.handleDeleteFoodArgs(s, m, args)
+	case "restore", "복구":
+		c.handleRestoreFoodArgs(s, m, args)
+	case "edit", "수정":
+		c.handleEditFoodArgs(s, m, args)
+	case "undo", "되돌리기":
+		c.handleUndoDelete(s, m)
+	case "mystats", "내통계":
+		c.handleMyStats(s, m)
 	default:
-		c.sendHelpMessage(s, m.ChannelID)
+		c.sendHelpMessage(s, m)
 	}
 }
 
 // Help implements the Command interface
-func (c *FoodCommand) Help() string {
+func (c *FoodCommand) Help(prefix string) string {
 	return fmt.Sprintf("**Food Command Usage**\n"+
 		"%s food lunch/점심 - Get lunch recommendation\n"+
 		"%s food dinner/저녁 - Get dinner recommendation\n"+
 		"%s food list/목록 [lunch/dinner] - List all registered food\n"+
 		"%s food add/추가 [lunch/dinner] [name] - Add new food\n"+
-		"%s food remove/삭제 [lunch/dinner] [name] - Remove food",
-		c.prefix, c.prefix, c.prefix, c.prefix, c.prefix)
+		"%s food remove/삭제 [lunch/dinner] [name] - Remove food\n"+
+		"%s food restore/복구 [lunch/dinner] [name] - Restore a removed food\n"+
+		"%s food edit/수정 [lunch/dinner] [old name] [new name] - Rename a food (quote names with spaces, or put -> between them)\n"+
+		"%s food undo/되돌리기 - Restore the last food removed in this channel\n"+
+		"%s food mystats/내통계 - Show the foods recommended to you most often",
+		prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix, prefix)
 }
 
-// sendHelpMessage sends the help message to the specified channel
-func (c *FoodCommand) sendHelpMessage(s *discordgo.Session, channelID string) {
-	s.ChannelMessageSend(channelID, c.Help())
+// sendHelpMessage sends the help message, with the guild's prefix, to the message's channel
+func (c *FoodCommand) sendHelpMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
+	sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
 }
 
 // handleLunchRecommendArgs handles the lunch recommendation with arguments
@@ -68,10 +139,14 @@ func (c *FoodCommand) handleLunchRecommendArgs(s *discordgo.Session, m *discordg
 	defer cancel()
 
 	// Get random lunch food
-	food, err := c.repo.GetRandomFood(ctx, models.FoodTypeLunch)
+	food, err := c.repo.GetRandomFood(ctx, m.GuildID, models.FoodTypeLunch)
+	if errors.Is(err, storage.ErrNoFoods) {
+		c.sendNoFoodsHint(s, m, models.FoodTypeLunch)
+		return
+	}
 	if err != nil {
 		c.log.Error("Failed to get random lunch food", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "점심 추천을 가져오는 중 오류가 발생했습니다.")
+		sendError(s, m.ChannelID, "점심 추천을 가져오는 중 오류가 발생했습니다.")
 		return
 	}
 
@@ -86,7 +161,8 @@ func (c *FoodCommand) handleLunchRecommendArgs(s *discordgo.Session, m *discordg
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
+	go c.recordRequest(m.Author.ID, m.ChannelID, food)
 }
 
 // handleDinnerRecommendArgs handles the dinner recommendation with arguments
@@ -95,10 +171,14 @@ func (c *FoodCommand) handleDinnerRecommendArgs(s *discordgo.Session, m *discord
 	defer cancel()
 
 	// Get random dinner food
-	food, err := c.repo.GetRandomFood(ctx, models.FoodTypeDinner)
+	food, err := c.repo.GetRandomFood(ctx, m.GuildID, models.FoodTypeDinner)
+	if errors.Is(err, storage.ErrNoFoods) {
+		c.sendNoFoodsHint(s, m, models.FoodTypeDinner)
+		return
+	}
 	if err != nil {
 		c.log.Error("Failed to get random dinner food", zap.Error(err))
-		s.ChannelMessageSend(m.ChannelID, "저녁 추천을 가져오는 중 오류가 발생했습니다.")
+		sendError(s, m.ChannelID, "저녁 추천을 가져오는 중 오류가 발생했습니다.")
 		return
 	}
 
@@ -113,7 +193,62 @@ func (c *FoodCommand) handleDinnerRecommendArgs(s *discordgo.Session, m *discord
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
+	go c.recordRequest(m.Author.ID, m.ChannelID, food)
+}
+
+// recordRequest logs a recommendation for mystats. It runs in the background
+// so a slow or failed write never delays the reply.
+func (c *FoodCommand) recordRequest(userID, channelID string, food *models.Food) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.requests.RecordRequest(ctx, models.NewFoodRequest(userID, channelID, food)); err != nil {
+		c.log.Warn("Failed to record food request", zap.Error(err), zap.String("user_id", userID))
+	}
+}
+
+// handleMyStats shows the foods most often recommended to the requesting user
+func (c *FoodCommand) handleMyStats(s *discordgo.Session, m *discordgo.MessageCreate) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stats, err := c.requests.GetTopFoodsByUser(ctx, m.Author.ID, foodStatsLimit)
+	if err != nil {
+		c.log.Error("Failed to get food stats", zap.Error(err), zap.String("user_id", m.Author.ID))
+		sendError(s, m.ChannelID, "메뉴 통계를 가져오는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if len(stats) == 0 {
+		sendMessage(s, m.ChannelID, fmt.Sprintf("아직 추천받은 메뉴가 없습니다. `%sfood 점심`으로 추천을 받아보세요!", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	var lines []string
+	for i, stat := range stats {
+		lines = append(lines, fmt.Sprintf("%d. **%s** (%s) - %d회",
+			i+1, models.EscapeDiscord(stat.Name), foodTypeLabel(stat.FoodType), stat.Count))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s님이 가장 많이 추천받은 메뉴", m.Author.Username),
+		Description: strings.Join(lines, "\n"),
+		Color:       0xFF9900, // Orange
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// sendNoFoodsHint tells the user to register a food of the given type first
+func (c *FoodCommand) sendNoFoodsHint(s *discordgo.Session, m *discordgo.MessageCreate, foodType models.FoodType) {
+	typeStr := foodTypeLabel(foodType)
+	sendMessage(s, m.ChannelID, fmt.Sprintf("등록된 %s 메뉴가 없습니다. %s 메뉴를 먼저 등록해주세요: `%sfood add %s [메뉴 이름]`",
+		typeStr, typeStr, c.prefixes.Prefix(m.GuildID), typeStr))
 }
 
 // handleListFoodArgs handles listing food with arguments
@@ -126,7 +261,7 @@ func (c *FoodCommand) handleListFoodArgs(s *discordgo.Session, m *discordgo.Mess
 
 	// Determine food type from arguments
 	if len(args) > 0 {
-		switch args[0] {
+		switch strings.ToLower(args[0]) {
 		case "lunch", "점심":
 			foodType = models.FoodTypeLunch
 			title = "점심 메뉴 목록"
@@ -144,7 +279,7 @@ func (c *FoodCommand) handleListFoodArgs(s *discordgo.Session, m *discordgo.Mess
 		var lunchMsg, dinnerMsg string
 
 		// Get lunch foods
-		lunchFoods, err := c.repo.GetAllFoods(ctx, models.FoodTypeLunch)
+		lunchFoods, err := c.repo.GetAllFoods(ctx, m.GuildID, models.FoodTypeLunch)
 		if err != nil {
 			c.log.Error("Failed to get all lunch foods", zap.Error(err))
 		} else {
@@ -156,7 +291,7 @@ func (c *FoodCommand) handleListFoodArgs(s *discordgo.Session, m *discordgo.Mess
 		}
 
 		// Get dinner foods
-		dinnerFoods, err := c.repo.GetAllFoods(ctx, models.FoodTypeDinner)
+		dinnerFoods, err := c.repo.GetAllFoods(ctx, m.GuildID, models.FoodTypeDinner)
 		if err != nil {
 			c.log.Error("Failed to get all dinner foods", zap.Error(err))
 		} else {
@@ -178,15 +313,15 @@ func (c *FoodCommand) handleListFoodArgs(s *discordgo.Session, m *discordgo.Mess
 			Timestamp: time.Now().Format(time.RFC3339),
 		}
 
-		s.ChannelMessageSendEmbed(m.ChannelID, embed)
+		sendEmbed(s, m.ChannelID, embed)
 		return
 	}
 
 	// Get foods of specific type
-	foods, err := c.repo.GetAllFoods(ctx, foodType)
+	foods, err := c.repo.GetAllFoods(ctx, m.GuildID, foodType)
 	if err != nil {
 		c.log.Error("Failed to get all foods", zap.Error(err), zap.String("type", string(foodType)))
-		s.ChannelMessageSend(m.ChannelID, "메뉴 목록을 가져오는 중 오류가 발생했습니다.")
+		sendError(s, m.ChannelID, "메뉴 목록을 가져오는 중 오류가 발생했습니다.")
 		return
 	}
 
@@ -207,33 +342,31 @@ func (c *FoodCommand) handleListFoodArgs(s *discordgo.Session, m *discordgo.Mess
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
 }
 
 // handleRegisterFoodArgs handles food registration with arguments
 func (c *FoodCommand) handleRegisterFoodArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	args, ok := parseFoodArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
 	if len(args) < 2 {
-		s.ChannelMessageSend(m.ChannelID, "사용법: "+c.prefix+"food add [lunch/dinner] [food name]")
+		sendError(s, m.ChannelID, "사용법: "+c.prefixes.Prefix(m.GuildID)+"food add [lunch/dinner] [food name]")
 		return
 	}
 
-	var foodType models.FoodType
-	typeArg := args[0]
 	foodName := strings.Join(args[1:], " ")
 
 	// Determine food type
-	switch typeArg {
-	case "lunch", "점심":
-		foodType = models.FoodTypeLunch
-	case "dinner", "저녁":
-		foodType = models.FoodTypeDinner
-	default:
-		s.ChannelMessageSend(m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
+	foodType, ok := parseFoodType(args[0])
+	if !ok {
+		sendError(s, m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
 		return
 	}
 
 	if foodName == "" {
-		s.ChannelMessageSend(m.ChannelID, "등록할 메뉴 이름을 입력해주세요.")
+		sendError(s, m.ChannelID, "등록할 메뉴 이름을 입력해주세요.")
 		return
 	}
 
@@ -242,15 +375,16 @@ func (c *FoodCommand) handleRegisterFoodArgs(s *discordgo.Session, m *discordgo.
 
 	// Create food
 	food := models.NewFood(foodName, foodType, m.Author.Username)
+	food.GuildID = m.GuildID
 
 	// Save to database
 	err := c.repo.SaveFood(ctx, food)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("'%s' 메뉴는 이미 등록되어 있습니다.", foodName))
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 메뉴는 이미 등록되어 있습니다.", foodName))
 		} else {
 			c.log.Error("Failed to save food", zap.Error(err), zap.String("name", foodName))
-			s.ChannelMessageSend(m.ChannelID, "메뉴를 등록하는 중 오류가 발생했습니다.")
+			sendError(s, m.ChannelID, "메뉴를 등록하는 중 오류가 발생했습니다.")
 		}
 		return
 	}
@@ -263,7 +397,7 @@ func (c *FoodCommand) handleRegisterFoodArgs(s *discordgo.Session, m *discordgo.
 
 	embed := &discordgo.MessageEmbed{
 		Title:       "메뉴 등록 완료",
-		Description: fmt.Sprintf("'%s' 메뉴가 %s 목록에 등록되었습니다.", foodName, typeStr),
+		Description: fmt.Sprintf("'%s' 메뉴가 %s 목록에 등록되었습니다.", food.Name, typeStr),
 		Color:       0x00FF00, // Green
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: fmt.Sprintf("Added by %s", m.Author.Username),
@@ -271,33 +405,31 @@ func (c *FoodCommand) handleRegisterFoodArgs(s *discordgo.Session, m *discordgo.
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
 }
 
 // handleDeleteFoodArgs handles food deletion with arguments
 func (c *FoodCommand) handleDeleteFoodArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	args, ok := parseFoodArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
 	if len(args) < 2 {
-		s.ChannelMessageSend(m.ChannelID, "사용법: "+c.prefix+"food remove [lunch/dinner] [food name]")
+		sendError(s, m.ChannelID, "사용법: "+c.prefixes.Prefix(m.GuildID)+"food remove [lunch/dinner] [food name]")
 		return
 	}
 
-	var foodType models.FoodType
-	typeArg := args[0]
 	foodName := strings.Join(args[1:], " ")
 
 	// Determine food type
-	switch typeArg {
-	case "lunch", "점심":
-		foodType = models.FoodTypeLunch
-	case "dinner", "저녁":
-		foodType = models.FoodTypeDinner
-	default:
-		s.ChannelMessageSend(m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
+	foodType, ok := parseFoodType(args[0])
+	if !ok {
+		sendError(s, m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
 		return
 	}
 
 	if foodName == "" {
-		s.ChannelMessageSend(m.ChannelID, "삭제할 메뉴 이름을 입력해주세요.")
+		sendError(s, m.ChannelID, "삭제할 메뉴 이름을 입력해주세요.")
 		return
 	}
 
@@ -305,17 +437,23 @@ func (c *FoodCommand) handleDeleteFoodArgs(s *discordgo.Session, m *discordgo.Me
 	defer cancel()
 
 	// Delete from database
-	err := c.repo.DeleteFood(ctx, foodName, foodType)
+	err := c.repo.DeleteFood(ctx, m.GuildID, foodName, foodType)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("'%s' 메뉴를 찾을 수 없습니다.", foodName))
-		} else {
+		switch {
+		case errors.Is(err, storage.ErrSharedFood):
+			sendError(s, m.ChannelID, sharedFoodMessage(foodName))
+		case errors.Is(err, storage.ErrNotFound):
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 메뉴를 찾을 수 없습니다.", foodName))
+		default:
 			c.log.Error("Failed to delete food", zap.Error(err), zap.String("name", foodName))
-			s.ChannelMessageSend(m.ChannelID, "메뉴를 삭제하는 중 오류가 발생했습니다.")
+			sendError(s, m.ChannelID, "메뉴를 삭제하는 중 오류가 발생했습니다.")
 		}
 		return
 	}
 
+	// Remember the deletion so it can be undone
+	c.deletions.push(m.ChannelID, foodDeletion{name: foodName, foodType: foodType})
+
 	// Create success embed
 	typeStr := "점심"
 	if foodType == models.FoodTypeDinner {
@@ -332,20 +470,195 @@ func (c *FoodCommand) handleDeleteFoodArgs(s *discordgo.Session, m *discordgo.Me
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// handleRestoreFoodArgs handles restoring a removed food with arguments
+func (c *FoodCommand) handleRestoreFoodArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	args, ok := parseFoodArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
+	if len(args) < 2 {
+		sendError(s, m.ChannelID, "사용법: "+c.prefixes.Prefix(m.GuildID)+"food restore [lunch/dinner] [food name]")
+		return
+	}
+
+	foodType, ok := parseFoodType(args[0])
+	if !ok {
+		sendError(s, m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
+		return
+	}
+
+	c.restoreFood(s, m, strings.Join(args[1:], " "), foodType)
+}
+
+// handleEditFoodArgs handles renaming a food with arguments
+func (c *FoodCommand) handleEditFoodArgs(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	usage := "사용법: " + c.prefixes.Prefix(m.GuildID) + "food edit [lunch/dinner] [기존 이름] [새 이름] (이름에 공백이 있으면 \"기존 이름\" \"새 이름\" 또는 기존 이름 -> 새 이름)"
+	args, ok := parseFoodArgs(s, m.ChannelID, args)
+	if !ok {
+		return
+	}
+	if len(args) < 3 {
+		sendError(s, m.ChannelID, usage)
+		return
+	}
+
+	foodType, ok := parseFoodType(args[0])
+	if !ok {
+		sendError(s, m.ChannelID, "유효한 메뉴 유형(lunch/점심 또는 dinner/저녁)을 입력해주세요.")
+		return
+	}
+
+	oldName, newName, ok := splitRenameArgs(args[1:])
+	if !ok {
+		sendError(s, m.ChannelID, usage)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	food, err := c.repo.RenameFood(ctx, m.GuildID, oldName, newName, foodType)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrSharedFood):
+			sendError(s, m.ChannelID, sharedFoodMessage(oldName))
+		case errors.Is(err, storage.ErrNotFound):
+			sendError(s, m.ChannelID, fmt.Sprintf("%s 메뉴 중 '%s'을(를) 찾을 수 없습니다.", foodTypeLabel(foodType), oldName))
+		case errors.Is(err, storage.ErrAlreadyExists):
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 메뉴는 이미 %s 목록에 있습니다. 다른 이름을 입력하거나 기존 메뉴를 삭제해주세요.", newName, foodTypeLabel(foodType)))
+		default:
+			c.log.Error("Failed to rename food", zap.Error(err), zap.String("name", oldName))
+			sendError(s, m.ChannelID, "메뉴 이름을 수정하는 중 오류가 발생했습니다.")
+		}
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "메뉴 수정 완료",
+		Description: fmt.Sprintf("%s 메뉴 '%s'의 이름이 '%s'(으)로 바뀌었습니다.", foodTypeLabel(foodType), oldName, food.Name),
+		Color:       0x00FF00, // Green
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Edited by %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// sharedFoodMessage explains that a food in the shared list can't be changed
+// from a server, since every server sees the same shared list
+func sharedFoodMessage(name string) string {
+	return fmt.Sprintf("'%s'은(는) 모든 서버가 함께 쓰는 공용 메뉴라 서버에서 수정하거나 삭제할 수 없습니다.", name)
+}
+
+// splitRenameArgs splits the arguments of a rename into the old and new name.
+// Names with spaces are quoted or separated by "->"; otherwise exactly two
+// names are expected.
+func splitRenameArgs(args []string) (oldName, newName string, ok bool) {
+	for i, arg := range args {
+		if arg == "->" || arg == "→" {
+			oldName = strings.Join(args[:i], " ")
+			newName = strings.Join(args[i+1:], " ")
+			return oldName, newName, oldName != "" && newName != ""
+		}
+	}
+	if len(args) != 2 {
+		return "", "", false
+	}
+	return args[0], args[1], true
+}
+
+// handleUndoDelete restores the most recently removed food in the channel
+func (c *FoodCommand) handleUndoDelete(s *discordgo.Session, m *discordgo.MessageCreate) {
+	deletion, ok := c.deletions.pop(m.ChannelID)
+	if !ok {
+		sendError(s, m.ChannelID, "되돌릴 메뉴 삭제 기록이 없습니다.")
+		return
+	}
+
+	c.restoreFood(s, m, deletion.name, deletion.foodType)
+}
+
+// restoreFood restores a soft-deleted food and reports the result
+func (c *FoodCommand) restoreFood(s *discordgo.Session, m *discordgo.MessageCreate, foodName string, foodType models.FoodType) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	food, err := c.repo.RestoreFood(ctx, m.GuildID, foodName, foodType)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrSharedFood):
+			sendError(s, m.ChannelID, sharedFoodMessage(foodName))
+		case errors.Is(err, storage.ErrNotFound):
+			sendError(s, m.ChannelID, fmt.Sprintf("삭제된 %s 메뉴 중 '%s'을(를) 찾을 수 없습니다.", foodTypeLabel(foodType), foodName))
+		default:
+			c.log.Error("Failed to restore food", zap.Error(err), zap.String("name", foodName))
+			sendError(s, m.ChannelID, "메뉴를 복구하는 중 오류가 발생했습니다.")
+		}
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "메뉴 복구 완료",
+		Description: fmt.Sprintf("'%s' 메뉴가 %s 목록에 다시 추가되었습니다.", food.Name, foodTypeLabel(foodType)),
+		Color:       0x00FF00, // Green
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Restored by %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// parseFoodArgs parses food command arguments, so names with spaces can be
+// quoted. Food commands take no flags; use -- or quotes for a name starting
+// with a dash.
+func parseFoodArgs(s *discordgo.Session, channelID string, args []string) ([]string, bool) {
+	parsed, ok := parseCommandArgs(s, channelID, args, nil, nil)
+	if !ok {
+		return nil, false
+	}
+	return parsed.Positional, true
+}
+
+// parseFoodType converts a lunch/dinner argument into a food type
+func parseFoodType(arg string) (models.FoodType, bool) {
+	switch strings.ToLower(arg) {
+	case "lunch", "점심":
+		return models.FoodTypeLunch, true
+	case "dinner", "저녁":
+		return models.FoodTypeDinner, true
+	default:
+		return "", false
+	}
+}
+
+// foodTypeLabel returns the Korean label for a food type
+func foodTypeLabel(foodType models.FoodType) string {
+	if foodType == models.FoodTypeDinner {
+		return "저녁"
+	}
+	return "점심"
 }
 
 // NewFoodCommand는 새로운 음식 명령어 핸들러를 생성합니다
-func NewFoodCommand(log *zap.Logger, db *storage.MongoDB, prefix string) *FoodCommand {
+func NewFoodCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *FoodCommand {
 	return &FoodCommand{
-		log:      log.Named("food-command"),
-		db:       db,
-		prefix:   prefix,
-		repo:     storage.NewFoodRepository(db, log),
+		log:       log.Named("food-command"),
+		db:        db,
+		prefixes:  prefixes,
+		repo:      storage.NewFoodRepository(db, log),
+		deletions: newFoodDeletionLog(),
+		requests:  storage.NewFoodRequestRepository(db, log),
 	}
 }
 
 // Register는 더 이상 사용되지 않습니다. 대신 Command 인터페이스를 통해 명령어가 처리됩니다.
 func (c *FoodCommand) Register(session *discordgo.Session) {
 	// 빈 구현 - 하위 호환성 유지용
-}
\ No newline at end of file
+}
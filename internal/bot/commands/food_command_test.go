@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// foodMessage is a message from a user in guild-1's channel-1
+func foodMessage() *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Author:    &discordgo.User{ID: "user-1", Username: "user"},
+	}}
+}
+
+func TestFoodCommandMatchesSentinelErrors(t *testing.T) {
+	mt := newMockTest(t)
+
+	tests := []struct {
+		name      string
+		responses []bson.D
+		run       func(c *FoodCommand, s *discordgo.Session)
+		want      string
+	}{
+		{
+			name:      "add existing",
+			responses: []bson.D{cursorResponse(bson.D{{Key: "name", Value: "김치찌개"}, {Key: "food_type", Value: "lunch"}, {Key: "is_active", Value: true}})},
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleRegisterFoodArgs(s, foodMessage(), []string{"lunch", "김치찌개"})
+			},
+			want: "'김치찌개' 메뉴는 이미 등록되어 있습니다.",
+		},
+		{
+			name:      "add fails",
+			responses: []bson.D{mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad query"})},
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleRegisterFoodArgs(s, foodMessage(), []string{"lunch", "김치찌개"})
+			},
+			want: "메뉴를 등록하는 중 오류가 발생했습니다.",
+		},
+		{
+			name:      "remove missing",
+			responses: []bson.D{mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}), cursorResponse()},
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleDeleteFoodArgs(s, foodMessage(), []string{"lunch", "김치찌개"})
+			},
+			want: "'김치찌개' 메뉴를 찾을 수 없습니다.",
+		},
+		{
+			name: "remove shared",
+			responses: []bson.D{
+				mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+				cursorResponse(bson.D{{Key: "name", Value: "김치찌개"}, {Key: "guild_id", Value: ""}}),
+			},
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleDeleteFoodArgs(s, foodMessage(), []string{"lunch", "김치찌개"})
+			},
+			want: "'김치찌개'은(는) 모든 서버가 함께 쓰는 공용 메뉴라 서버에서 수정하거나 삭제할 수 없습니다.",
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tt.responses...)
+			session := newRecordingSession(mt.T)
+			c := NewFoodCommand(zap.NewNop(), newMockDB(mt), staticPrefixes(nil))
+
+			tt.run(c, session.Session)
+
+			messages := session.messages()
+			if len(messages) != 1 || !strings.Contains(messages[0], tt.want) {
+				mt.Errorf("replied %q, want %q", messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoodRecommendationWithNoFoods(t *testing.T) {
+	mt := newMockTest(t)
+
+	tests := []struct {
+		name     string
+		response bson.D
+		run      func(c *FoodCommand, s *discordgo.Session)
+		want     string
+	}{
+		{
+			name:     "no lunch",
+			response: cursorResponse(),
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleLunchRecommendArgs(s, foodMessage(), nil)
+			},
+			want: "등록된 점심 메뉴가 없습니다. 점심 메뉴를 먼저 등록해주세요: `!food add 점심 [메뉴 이름]`",
+		},
+		{
+			name:     "no dinner",
+			response: cursorResponse(),
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleDinnerRecommendArgs(s, foodMessage(), nil)
+			},
+			want: "등록된 저녁 메뉴가 없습니다. 저녁 메뉴를 먼저 등록해주세요: `!food add 저녁 [메뉴 이름]`",
+		},
+		{
+			name:     "database error",
+			response: mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad pipeline"}),
+			run: func(c *FoodCommand, s *discordgo.Session) {
+				c.handleLunchRecommendArgs(s, foodMessage(), nil)
+			},
+			want: "점심 추천을 가져오는 중 오류가 발생했습니다.",
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(tt.response)
+			session := newRecordingSession(mt.T)
+			c := NewFoodCommand(zap.NewNop(), newMockDB(mt), staticPrefixes(nil))
+
+			tt.run(c, session.Session)
+
+			if messages := session.messages(); len(messages) != 1 || messages[0] != tt.want {
+				mt.Errorf("replied %q, want %q", messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoodDeletionLog(t *testing.T) {
+	log := newFoodDeletionLog()
+	if _, ok := log.pop("channel-1"); ok {
+		t.Fatal("pop() on an empty log returned a deletion")
+	}
+
+	for i := 0; i < foodDeletionLogSize+2; i++ {
+		log.push("channel-1", foodDeletion{name: fmt.Sprintf("메뉴%d", i), foodType: models.FoodTypeLunch})
+	}
+	log.push("channel-2", foodDeletion{name: "된장찌개", foodType: models.FoodTypeDinner})
+
+	if got, ok := log.pop("channel-2"); !ok || got.name != "된장찌개" {
+		t.Errorf("pop(channel-2) = %+v, %v, want 된장찌개", got, ok)
+	}
+	for i := foodDeletionLogSize + 1; i >= 2; i-- {
+		got, ok := log.pop("channel-1")
+		if want := fmt.Sprintf("메뉴%d", i); !ok || got.name != want {
+			t.Fatalf("pop(channel-1) = %+v, %v, want %s", got, ok, want)
+		}
+	}
+	if got, ok := log.pop("channel-1"); ok {
+		t.Errorf("pop(channel-1) = %+v, want the oldest deletions dropped", got)
+	}
+}
+
+func TestParseFoodType(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want models.FoodType
+		ok   bool
+	}{
+		{"lunch", models.FoodTypeLunch, true},
+		{"LUNCH", models.FoodTypeLunch, true},
+		{"Dinner", models.FoodTypeDinner, true},
+		{"점심", models.FoodTypeLunch, true},
+		{"저녁", models.FoodTypeDinner, true},
+		{"brunch", "", false},
+	}
+
+	for _, tt := range tests {
+		if got, ok := parseFoodType(tt.arg); got != tt.want || ok != tt.ok {
+			t.Errorf("parseFoodType(%q) = %q, %v, want %q, %v", tt.arg, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFoodCommandMixedCaseSubcommand(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("add", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			cursorResponse(),              // no active food with the name
+			cursorResponse(),              // no deleted food to reuse
+			mtest.CreateSuccessResponse(), // insert
+		)
+		session := newRecordingSession(mt.T)
+		c := NewFoodCommand(zap.NewNop(), newMockDB(mt), staticPrefixes(nil))
+
+		c.Execute(session.Session, foodMessage(), []string{"ADD", "Lunch", "Kimchi", "Jjigae"})
+
+		inserts := startedCommands(mt, "insert")
+		if len(inserts) != 1 {
+			mt.Fatalf("sent %d inserts, want 1", len(inserts))
+		}
+		food := inserts[0].Lookup("documents").Array().Index(0).Value().Document()
+		if name := food.Lookup("name").StringValue(); name != "Kimchi Jjigae" {
+			mt.Errorf("saved name %q, want the name's case kept", name)
+		}
+		if foodType := food.Lookup("food_type").StringValue(); foodType != "lunch" {
+			mt.Errorf("saved food_type %q, want lunch", foodType)
+		}
+		sends := session.sends()
+		if len(sends) != 1 || len(sends[0].Embeds) != 1 || sends[0].Embeds[0].Title != "메뉴 등록 완료" {
+			mt.Errorf("replied %+v, want the registration embed", sends)
+		}
+	})
+}
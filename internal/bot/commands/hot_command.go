@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// hotDefaultCount는 기본으로 보여줄 인기 상품 수입니다
+	hotDefaultCount = 10
+	// hotMaxCount는 한 번에 볼 수 있는 최대 인기 상품 수입니다
+	hotMaxCount = 50
+	// hotPageSize는 한 페이지에 보여줄 인기 상품 수입니다
+	hotPageSize = 5
+	// hotDefaultWindow는 기본 집계 기간입니다
+	hotDefaultWindow = 24 * time.Hour
+	// hotMaxWindow는 집계할 수 있는 최대 기간입니다
+	hotMaxWindow = 7 * 24 * time.Hour
+)
+
+var (
+	errInvalidHotArg  = errors.New("invalid hot argument")
+	errHotCountRange  = errors.New("hot count out of range")
+	errHotWindowRange = errors.New("hot window out of range")
+)
+
+// HotCommand는 최근 댓글/조회수가 가장 많은 특가 상품을 보여줍니다
+type HotCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	repo     *storage.ProductRepository
+}
+
+// Execute implements the Command interface
+func (c *HotCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 1 && (strings.ToLower(args[0]) == "help" || args[0] == "도움말") {
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	count, window, err := parseHotArgs(args)
+	switch {
+	case errors.Is(err, errHotCountRange):
+		sendError(s, m.ChannelID, fmt.Sprintf("상품 수는 1에서 %d 사이로 입력해주세요.", hotMaxCount))
+		return
+	case errors.Is(err, errHotWindowRange):
+		sendError(s, m.ChannelID, "기간은 1시간에서 7일 사이로 입력해주세요. (예: 48h, 3d)")
+		return
+	case err != nil:
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	products, err := c.repo.GetTopByEngagement(ctx, now.Add(-window), now, count)
+	if err != nil {
+		c.log.Error("Failed to get hot products", zap.Error(err), zap.Duration("window", window))
+		sendError(s, m.ChannelID, "인기 상품을 불러오는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if len(products) == 0 {
+		sendMessage(s, m.ChannelID, fmt.Sprintf("최근 %s 동안 수집된 상품이 없습니다.", formatHotWindow(window)))
+		return
+	}
+
+	embeds := c.createHotEmbeds(products, window, m.Author.Username)
+	if err := NewPaginator(embeds, m.Author.ID).Send(s, m.ChannelID); err != nil {
+		c.log.Error("Failed to send hot products", zap.Error(err))
+	}
+}
+
+// Help implements the Command interface
+func (c *HotCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Hot Command Usage**\n"+
+		"%s hot - Show the %d deals with the most comments and views in the last 24h\n"+
+		"%s hot [N] - Show the top N deals (max %d)\n"+
+		"%s hot [N] [window] - Use a different time window (e.g. 48h, 3d, max 7d)",
+		prefix, hotDefaultCount, prefix, hotMaxCount, prefix)
+}
+
+// createHotEmbeds renders the products as pages of hotPageSize entries
+func (c *HotCommand) createHotEmbeds(products []models.Product, window time.Duration, requester string) []*discordgo.MessageEmbed {
+	title := fmt.Sprintf("🔥 최근 %s 인기 특가", formatHotWindow(window))
+
+	var embeds []*discordgo.MessageEmbed
+	for start := 0; start < len(products); start += hotPageSize {
+		end := start + hotPageSize
+		if end > len(products) {
+			end = len(products)
+		}
+
+		var description strings.Builder
+		for i, product := range products[start:end] {
+			fmt.Fprintf(&description, "**%d.** [%s](%s)\n💬 %d · 👀 %d · %s · %s\n",
+				start+i+1,
+				models.SanitizeTitle(product.Title),
+				product.URL,
+				product.Comments,
+				product.Views,
+				product.GetPriceString(),
+				product.Source)
+		}
+
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title:       title,
+			Description: description.String(),
+			Color:       0xFF4500,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Requested by %s", requester),
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	return embeds
+}
+
+// parseHotArgs parses the optional count and time window, in either order.
+// The window accepts Go durations plus a "d" (day) unit, like reminders.
+func parseHotArgs(args []string) (int, time.Duration, error) {
+	count, window := hotDefaultCount, hotDefaultWindow
+	countSet, windowSet := false, false
+
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if countSet {
+				return 0, 0, errInvalidHotArg
+			}
+			if n < 1 || n > hotMaxCount {
+				return 0, 0, errHotCountRange
+			}
+			count, countSet = n, true
+			continue
+		}
+
+		d, err := parseReminderDuration(strings.ToLower(arg))
+		if err != nil || windowSet {
+			return 0, 0, errInvalidHotArg
+		}
+		if d < time.Hour || d > hotMaxWindow {
+			return 0, 0, errHotWindowRange
+		}
+		window, windowSet = d, true
+	}
+
+	return count, window, nil
+}
+
+// formatHotWindow formats the window in whole days or hours for display
+func formatHotWindow(window time.Duration) string {
+	if window > 24*time.Hour && window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d일", int(window/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%d시간", int(window/time.Hour))
+}
+
+// NewHotCommand는 새로운 인기 상품 명령어 핸들러를 생성합니다
+func NewHotCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *HotCommand {
+	return &HotCommand{
+		log:      log.Named("hot-command"),
+		prefixes: prefixes,
+		repo:     storage.NewProductRepository(db, log),
+	}
+}
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseHotArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		count  int
+		window time.Duration
+		err    error
+	}{
+		{name: "defaults", count: hotDefaultCount, window: hotDefaultWindow},
+		{name: "count", args: []string{"5"}, count: 5, window: hotDefaultWindow},
+		{name: "window", args: []string{"48h"}, count: hotDefaultCount, window: 48 * time.Hour},
+		{name: "either order", args: []string{"2d", "20"}, count: 20, window: 48 * time.Hour},
+		{name: "count out of range", args: []string{"51"}, err: errHotCountRange},
+		{name: "window too short", args: []string{"30m"}, err: errHotWindowRange},
+		{name: "window too long", args: []string{"8d"}, err: errHotWindowRange},
+		{name: "two counts", args: []string{"5", "10"}, err: errInvalidHotArg},
+		{name: "not a window", args: []string{"yesterday"}, err: errInvalidHotArg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, window, err := parseHotArgs(tt.args)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("parseHotArgs() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if count != tt.count || window != tt.window {
+				t.Errorf("parseHotArgs() = %d, %v, want %d, %v", count, window, tt.count, tt.window)
+			}
+		})
+	}
+}
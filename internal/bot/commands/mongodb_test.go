@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// newMockTest creates a test using a mock deployment instead of a server
+func newMockTest(t *testing.T) *mtest.T {
+	return mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+}
+
+// newMockDB wraps the mock deployment of mt, which answers commands with the
+// responses queued by mt.AddMockResponses
+func newMockDB(mt *mtest.T) *storage.MongoDB {
+	cfg := &config.Config{MongoDBName: "test", DBOperationTimeoutSeconds: 10}
+	return storage.NewMongoDBFromClient(mt.Client, cfg, zap.NewNop())
+}
+
+// startedCommands returns the commands named name that were sent, in order
+func startedCommands(mt *mtest.T, name string) []bson.Raw {
+	var commands []bson.Raw
+	for _, event := range mt.GetAllStartedEvents() {
+		if event.CommandName == name {
+			commands = append(commands, event.Command)
+		}
+	}
+	return commands
+}
+
+// cursorResponse is a find or aggregate reply returning docs in one batch
+func cursorResponse(docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, "test.collection", mtest.FirstBatch, docs...)
+}
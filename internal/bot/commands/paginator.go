@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// paginatorTimeout는 페이지 넘김 리액션을 받는 기본 시간입니다
+	paginatorTimeout = 2 * time.Minute
+
+	paginatorPrev = "◀️"
+	paginatorNext = "▶️"
+)
+
+// Paginator는 여러 페이지의 임베드를 하나의 메시지로 보여주고
+// ◀/▶ 리액션으로 페이지를 넘길 수 있게 합니다.
+// 만료되면 리액션 핸들러를 제거하고 더 이상 페이지를 넘기지 않습니다.
+type Paginator struct {
+	embeds  []*discordgo.MessageEmbed
+	userID  string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	page int
+}
+
+// NewPaginator creates a paginator over embeds that only userID can page
+// through. An empty userID lets anyone page. Each embed's footer gets a page
+// indicator appended.
+func NewPaginator(embeds []*discordgo.MessageEmbed, userID string) *Paginator {
+	if len(embeds) > 1 {
+		for i, embed := range embeds {
+			indicator := fmt.Sprintf("%d/%d", i+1, len(embeds))
+			if embed.Footer == nil {
+				embed.Footer = &discordgo.MessageEmbedFooter{Text: indicator}
+			} else {
+				embed.Footer.Text = indicator + " · " + embed.Footer.Text
+			}
+		}
+	}
+
+	return &Paginator{
+		embeds:  embeds,
+		userID:  userID,
+		timeout: paginatorTimeout,
+	}
+}
+
+// Send posts the first page to the channel and, when there is more than one
+// page, listens for navigation reactions until the paginator expires
+func (p *Paginator) Send(s *discordgo.Session, channelID string) error {
+	if len(p.embeds) == 0 {
+		return nil
+	}
+
+	msg, err := sendEmbed(s, channelID, p.embeds[0])
+	if err != nil {
+		return fmt.Errorf("failed to send paginated embed: %w", err)
+	}
+
+	if len(p.embeds) == 1 {
+		return nil
+	}
+
+	if err := addReactions(s, channelID, msg.ID, []string{paginatorPrev, paginatorNext}); err != nil {
+		return err
+	}
+
+	removeHandler := s.AddHandler(func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		if r.MessageID == msg.ID {
+			p.onReaction(s, r)
+		}
+	})
+
+	time.AfterFunc(p.timeout, func() {
+		removeHandler()
+		// 만료된 페이지에는 넘김 버튼을 남기지 않음 (권한이 없으면 무시)
+		s.MessageReactionsRemoveAll(channelID, msg.ID)
+	})
+
+	return nil
+}
+
+// onReaction moves to the previous or next page in response to a reaction
+func (p *Paginator) onReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if s.State != nil && s.State.User != nil && r.UserID == s.State.User.ID {
+		return
+	}
+
+	// 다시 누를 수 있도록 사용자의 리액션 제거 (권한이 없으면 무시)
+	defer s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.APIName(), r.UserID)
+
+	if p.userID != "" && r.UserID != p.userID {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	page := p.page
+	switch r.Emoji.Name {
+	case paginatorPrev:
+		page--
+	case paginatorNext:
+		page++
+	default:
+		return
+	}
+	if page < 0 || page >= len(p.embeds) {
+		return
+	}
+
+	edit := discordgo.NewMessageEdit(r.ChannelID, r.MessageID).SetEmbed(p.embeds[page])
+	edit.AllowedMentions = noMentions
+	if _, err := s.ChannelMessageEditComplex(edit); err != nil {
+		return
+	}
+	p.page = page
+}
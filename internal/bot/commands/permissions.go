@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// isServerAdmin은 사용자가 채널에서 서버 관리 권한(또는 관리자 권한)을 가지고 있는지 확인합니다
+func isServerAdmin(s *discordgo.Session, channelID, userID string) (bool, error) {
+	perms, err := s.UserChannelPermissions(userID, channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+
+	return perms&discordgo.PermissionAdministrator != 0 || perms&discordgo.PermissionManageServer != 0, nil
+}
+
+// botSendPermissions는 상품 알림을 보내는 데 필요한 권한입니다
+var botSendPermissions = []struct {
+	permission int64
+	name       string
+}{
+	{discordgo.PermissionViewChannel, "채널 보기"},
+	{discordgo.PermissionSendMessages, "메시지 보내기"},
+	{discordgo.PermissionEmbedLinks, "링크 첨부"},
+}
+
+// missingBotSendPermissions는 봇이 채널에서 상품 알림을 보내는 데 부족한 권한의 이름을 반환합니다
+func missingBotSendPermissions(s *discordgo.Session, channelID string) ([]string, error) {
+	perms, err := s.UserChannelPermissions(s.State.User.ID, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot permissions: %w", err)
+	}
+	if perms&discordgo.PermissionAdministrator != 0 {
+		return nil, nil
+	}
+
+	var missing []string
+	for _, p := range botSendPermissions {
+		if perms&p.permission == 0 {
+			missing = append(missing, p.name)
+		}
+	}
+	return missing, nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // PingCommand는 "pong"으로 응답하는 간단한 명령어입니다
-type PingCommand struct{
+type PingCommand struct {
 	prefix string
 }
 
@@ -15,24 +15,24 @@ type PingCommand struct{
 func (c *PingCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
 	// 응답 시간 계산
 	start := time.Now()
-	msg, err := s.ChannelMessageSend(m.ChannelID, "Pinging...")
+	msg, err := sendMessage(s, m.ChannelID, "Pinging...")
 	if err != nil {
 		return
 	}
 
 	elapsed := time.Since(start)
-	
+
 	// 지연 시간 정보로 메시지 수정
-	_, err = s.ChannelMessageEdit(m.ChannelID, msg.ID, 
-		"Pong! Latency: " + elapsed.Round(time.Millisecond).String())
+	_, err = s.ChannelMessageEdit(m.ChannelID, msg.ID,
+		"Pong! Latency: "+elapsed.Round(time.Millisecond).String())
 	if err != nil {
-		s.ChannelMessageSend(m.ChannelID, 
-			"Pong! Latency: " + elapsed.Round(time.Millisecond).String())
+		sendMessage(s, m.ChannelID,
+			"Pong! Latency: "+elapsed.Round(time.Millisecond).String())
 	}
 }
 
 // Help implements the Command interface
-func (c *PingCommand) Help() string {
+func (c *PingCommand) Help(prefix string) string {
 	return "Responds with pong to verify the bot is running"
 }
 
@@ -47,4 +47,4 @@ func NewPingCommand(prefix string) *PingCommand {
 // Keeping this for backward compatibility but it's not used anymore
 func (c *PingCommand) Register(session *discordgo.Session) {
 	// Implementation removed as it's now done through the Command interface
-}
\ No newline at end of file
+}
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// pollMinOptions는 투표에 필요한 최소 선택지 수입니다
+const pollMinOptions = 2
+
+// PollCommand는 리액션 투표 명령어를 처리합니다
+type PollCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	repo     *storage.PollRepository
+}
+
+// Execute implements the Command interface
+func (c *PollCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "results", "결과":
+		if len(args) < 2 {
+			sendError(s, m.ChannelID, fmt.Sprintf("사용법: %s poll results [메시지ID]", c.prefixes.Prefix(m.GuildID)))
+			return
+		}
+		c.handleResults(s, m, args[1])
+	default:
+		c.handleCreate(s, m, args)
+	}
+}
+
+// Help implements the Command interface
+func (c *PollCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Poll Command Usage**\n"+
+		"%s poll \"question\" option1 option2 ... - Start a poll (up to %d options)\n"+
+		"%s poll results [messageID] - Tally the votes of a poll\n"+
+		"Use quotes for multi-word questions or options",
+		prefix, len(numberEmojis), prefix)
+}
+
+// handleCreate posts a new poll and adds a numbered reaction for each option
+func (c *PollCommand) handleCreate(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	parts, err := parseQuotedArgs(strings.Join(args, " "))
+	if err != nil {
+		sendError(s, m.ChannelID, "따옴표가 닫히지 않았습니다. 질문과 선택지를 다시 확인해주세요.")
+		return
+	}
+
+	if len(parts) < 1+pollMinOptions {
+		sendError(s, m.ChannelID, fmt.Sprintf("질문과 최소 %d개의 선택지를 입력해주세요.\n%s", pollMinOptions, c.Help(c.prefixes.Prefix(m.GuildID))))
+		return
+	}
+
+	question, options := parts[0], parts[1:]
+	if len(options) > len(numberEmojis) {
+		sendError(s, m.ChannelID, fmt.Sprintf("선택지는 최대 %d개까지 가능합니다.", len(numberEmojis)))
+		return
+	}
+
+	var description strings.Builder
+	for i, option := range options {
+		fmt.Fprintf(&description, "%s %s\n", numberEmojis[i], models.EscapeDiscord(option))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📊 " + models.EscapeDiscord(question),
+		Description: description.String(),
+		Color:       0xFFA500, // Orange
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	msg, err := sendEmbed(s, m.ChannelID, embed)
+	if err != nil {
+		c.log.Error("Failed to send poll", zap.Error(err))
+		return
+	}
+
+	if err := addReactions(s, m.ChannelID, msg.ID, numberEmojis[:len(options)]); err != nil {
+		c.log.Error("Failed to add poll reactions", zap.Error(err), zap.String("message_id", msg.ID))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	poll := models.NewPoll(msg.ID, m.ChannelID, m.GuildID, m.Author.ID, question, options)
+	if err := c.repo.SavePoll(ctx, poll); err != nil {
+		c.log.Error("Failed to save poll", zap.Error(err))
+		sendError(s, m.ChannelID, "투표를 저장하는 중 오류가 발생했습니다. 결과 집계가 불가능할 수 있습니다.")
+		return
+	}
+
+	c.log.Info("Poll created",
+		zap.String("message_id", msg.ID),
+		zap.Int("options", len(options)))
+}
+
+// handleResults tallies the reactions on a stored poll
+func (c *PollCommand) handleResults(s *discordgo.Session, m *discordgo.MessageCreate, messageID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	poll, err := c.repo.GetPollByMessageID(ctx, messageID)
+	if err != nil {
+		c.log.Error("Failed to get poll", zap.Error(err))
+		sendError(s, m.ChannelID, "투표를 불러오는 중 오류가 발생했습니다.")
+		return
+	}
+	if poll == nil || poll.GuildID != m.GuildID {
+		sendError(s, m.ChannelID, "해당 메시지 ID의 투표를 찾을 수 없습니다.")
+		return
+	}
+
+	counts, err := countReactions(s, poll.ChannelID, poll.MessageID, numberEmojis[:len(poll.Options)])
+	if err != nil {
+		c.log.Error("Failed to count poll reactions", zap.Error(err))
+		sendError(s, m.ChannelID, "투표 메시지를 찾을 수 없습니다. 삭제되었을 수 있습니다.")
+		return
+	}
+
+	sendEmbed(s, m.ChannelID, c.createResultsEmbed(poll, counts, m.Author.Username))
+}
+
+// createResultsEmbed creates an embed with the vote count of every option
+func (c *PollCommand) createResultsEmbed(poll *models.Poll, counts []int, requester string) *discordgo.MessageEmbed {
+	total, best := 0, 0
+	for _, count := range counts {
+		total += count
+		if count > best {
+			best = count
+		}
+	}
+
+	var description strings.Builder
+	for i, option := range poll.Options {
+		percent := 0
+		if total > 0 {
+			percent = counts[i] * 100 / total
+		}
+		marker := ""
+		if best > 0 && counts[i] == best {
+			marker = " 🏆"
+		}
+		fmt.Fprintf(&description, "%s %s — **%d표** (%d%%)%s\n",
+			numberEmojis[i], models.EscapeDiscord(option), counts[i], percent, marker)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "📊 투표 결과: " + models.EscapeDiscord(poll.Question),
+		Description: description.String(),
+		Color:       0xFFA500, // Orange
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "총 투표 수",
+				Value:  fmt.Sprintf("%d", total),
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", requester),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// NewPollCommand는 새로운 투표 명령어 핸들러를 생성합니다
+func NewPollCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *PollCommand {
+	return &PollCommand{
+		log:      log.Named("poll-command"),
+		prefixes: prefixes,
+		repo:     storage.NewPollRepository(db, log),
+	}
+}
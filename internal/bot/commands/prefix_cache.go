@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"go.uber.org/zap"
+)
+
+// prefixLookupTimeout은 서버 접두사 조회 제한 시간입니다
+const prefixLookupTimeout = 2 * time.Second
+
+// prefixLoader는 서버에 설정된 접두사를 조회합니다 (설정이 없으면 빈 문자열)
+type prefixLoader func(ctx context.Context, guildID string) (string, error)
+
+// PrefixCache는 서버별 명령어 접두사를 조회하고 캐시합니다.
+// 서버 밖(DM)이나 접두사를 설정하지 않은 서버는 기본 접두사를 사용합니다.
+type PrefixCache struct {
+	load          prefixLoader
+	defaultPrefix string
+	log           *zap.Logger
+
+	mu       sync.RWMutex
+	prefixes map[string]string // guildID -> 설정된 접두사 ("" = 기본값)
+}
+
+// NewPrefixCache는 guild_settings 컬렉션을 사용하는 접두사 캐시를 생성합니다
+func NewPrefixCache(repo *storage.GuildSettingsRepository, defaultPrefix string, log *zap.Logger) *PrefixCache {
+	return newPrefixCache(func(ctx context.Context, guildID string) (string, error) {
+		settings, err := repo.GetSettings(ctx, guildID)
+		if err != nil || settings == nil {
+			return "", err
+		}
+		return settings.Prefix, nil
+	}, defaultPrefix, log)
+}
+
+// newPrefixCache는 주어진 조회 함수로 접두사 캐시를 생성합니다
+func newPrefixCache(load prefixLoader, defaultPrefix string, log *zap.Logger) *PrefixCache {
+	return &PrefixCache{
+		load:          load,
+		defaultPrefix: defaultPrefix,
+		log:           log.Named("prefix-cache"),
+		prefixes:      make(map[string]string),
+	}
+}
+
+// Prefix는 서버의 명령어 접두사를 반환합니다.
+// 조회에 실패하면 기본 접두사를 반환하고 결과를 캐시하지 않아 다음 메시지에서 다시 조회합니다.
+func (c *PrefixCache) Prefix(guildID string) string {
+	if guildID == "" {
+		return c.defaultPrefix
+	}
+
+	c.mu.RLock()
+	prefix, ok := c.prefixes[guildID]
+	c.mu.RUnlock()
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), prefixLookupTimeout)
+		defer cancel()
+
+		var err error
+		prefix, err = c.load(ctx, guildID)
+		if err != nil {
+			c.log.Warn("Failed to load guild prefix", zap.Error(err), zap.String("guild_id", guildID))
+			return c.defaultPrefix
+		}
+
+		c.mu.Lock()
+		c.prefixes[guildID] = prefix
+		c.mu.Unlock()
+	}
+
+	if prefix == "" {
+		return c.defaultPrefix
+	}
+	return prefix
+}
+
+// Default는 기본 접두사를 반환합니다
+func (c *PrefixCache) Default() string {
+	return c.defaultPrefix
+}
+
+// Invalidate는 서버의 캐시된 접두사를 지워 다음 조회 때 다시 읽도록 합니다
+func (c *PrefixCache) Invalidate(guildID string) {
+	c.mu.Lock()
+	delete(c.prefixes, guildID)
+	c.mu.Unlock()
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// staticPrefixes returns a prefix cache serving the given guild prefixes
+func staticPrefixes(prefixes map[string]string) *PrefixCache {
+	return newPrefixCache(func(ctx context.Context, guildID string) (string, error) {
+		return prefixes[guildID], nil
+	}, "!", zap.NewNop())
+}
+
+func TestPrefixCache(t *testing.T) {
+	loads := 0
+	fail := false
+	cache := newPrefixCache(func(ctx context.Context, guildID string) (string, error) {
+		loads++
+		if fail {
+			return "", errors.New("unavailable")
+		}
+		if guildID == "guild-1" {
+			return "?", nil
+		}
+		return "", nil
+	}, "!", zap.NewNop())
+
+	if got := cache.Prefix(""); got != "!" {
+		t.Errorf("Prefix(DM) = %q, want the default", got)
+	}
+	if got := cache.Prefix("guild-1"); got != "?" {
+		t.Errorf("Prefix(guild-1) = %q, want ?", got)
+	}
+	if got := cache.Prefix("guild-2"); got != "!" {
+		t.Errorf("Prefix(guild-2) = %q, want the default for a guild without its own", got)
+	}
+	cache.Prefix("guild-1")
+	if loads != 2 {
+		t.Errorf("loaded %d times, want 2 with the second lookup cached", loads)
+	}
+
+	cache.Invalidate("guild-1")
+	fail = true
+	if got := cache.Prefix("guild-1"); got != "!" {
+		t.Errorf("Prefix() on a failed load = %q, want the default", got)
+	}
+	fail = false
+	if got := cache.Prefix("guild-1"); got != "?" {
+		t.Errorf("Prefix() after a failed load = %q, want it loaded again", got)
+	}
+}
+
+func TestUsageUsesGuildPrefix(t *testing.T) {
+	prefixes := staticPrefixes(map[string]string{"guild-1": "?"})
+	log := zap.NewNop()
+
+	tests := []struct {
+		name string
+		cmd  Command
+		args []string
+		want string
+	}{
+		{"alert help", NewAlertCommand(log, nil, prefixes, 2), nil, "? alert add"},
+		{"food help", NewFoodCommand(log, nil, prefixes), nil, "? food lunch"},
+		{"poll usage", NewPollCommand(log, nil, prefixes), []string{"results"}, "? poll results"},
+		{"roll help", NewRollCommand(log, prefixes), []string{"zz"}, "? roll [NdM+K]"},
+		{"stats help", NewStatsCommand(log, nil, prefixes), nil, "? stats"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for guildID, want := range map[string]string{"guild-1": tt.want, "": strings.Replace(tt.want, "?", "!", 1)} {
+				session := newRecordingSession(t)
+				m := &discordgo.MessageCreate{Message: &discordgo.Message{
+					ChannelID: "channel-1",
+					GuildID:   guildID,
+					Author:    &discordgo.User{ID: "user-1"},
+				}}
+
+				tt.cmd.Execute(session.Session, m, tt.args)
+
+				messages := session.messages()
+				if len(messages) != 1 || !strings.Contains(messages[0], want) {
+					t.Errorf("guild %q: sent %q, want a usage containing %q", guildID, messages, want)
+				}
+			}
+		})
+	}
+}
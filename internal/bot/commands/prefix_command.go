@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// maxPrefixLength는 서버 접두사의 최대 글자 수입니다
+const maxPrefixLength = 5
+
+var errInvalidPrefix = errors.New("invalid prefix")
+
+// PrefixCommand는 서버별 명령어 접두사를 조회하고 변경합니다
+type PrefixCommand struct {
+	log      *zap.Logger
+	repo     *storage.GuildSettingsRepository
+	prefixes *PrefixCache
+}
+
+// Execute implements the Command interface
+func (c *PrefixCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if m.GuildID == "" {
+		sendMessage(s, m.ChannelID, fmt.Sprintf("DM에서는 기본 접두사 `%s`를 사용합니다. 접두사는 서버에서만 변경할 수 있습니다.", c.prefixes.Default()))
+		return
+	}
+
+	if len(args) == 0 {
+		sendMessage(s, m.ChannelID, fmt.Sprintf("이 서버의 명령어 접두사는 `%s`입니다.", c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	admin, err := isServerAdmin(s, m.ChannelID, m.Author.ID)
+	if err != nil {
+		c.log.Error("권한 확인 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "권한을 확인하는 중 오류가 발생했습니다.")
+		return
+	}
+	if !admin {
+		sendError(s, m.ChannelID, "접두사는 서버 관리 권한이 있는 사용자만 변경할 수 있습니다.")
+		return
+	}
+
+	prefix := args[0]
+	if strings.ToLower(prefix) == "reset" || prefix == "초기화" {
+		prefix = ""
+	} else if err := validatePrefix(prefix); err != nil {
+		sendError(s, m.ChannelID, fmt.Sprintf("접두사는 공백 없이 1~%d자로 입력해주세요.", maxPrefixLength))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.repo.SetPrefix(ctx, m.GuildID, prefix, m.Author.ID); err != nil {
+		c.log.Error("Failed to set guild prefix", zap.Error(err), zap.String("guild_id", m.GuildID))
+		sendError(s, m.ChannelID, "접두사를 변경하는 중 오류가 발생했습니다.")
+		return
+	}
+	c.prefixes.Invalidate(m.GuildID)
+
+	if prefix == "" {
+		prefix = c.prefixes.Default()
+	}
+	sendMessage(s, m.ChannelID, fmt.Sprintf("이 서버의 명령어 접두사가 `%s`(으)로 변경되었습니다. 예: `%sping`", prefix, prefix))
+}
+
+// Help implements the Command interface
+func (c *PrefixCommand) Help(prefix string) string {
+	return "**Prefix Command Usage**\n" +
+		"prefix - Show this server's command prefix\n" +
+		"prefix [newPrefix] - Change the prefix (server admins only)\n" +
+		"prefix reset/초기화 - Restore the default prefix"
+}
+
+// validatePrefix는 접두사가 공백 없는 1~maxPrefixLength자인지 확인합니다
+func validatePrefix(prefix string) error {
+	if prefix == "" || utf8.RuneCountInString(prefix) > maxPrefixLength {
+		return errInvalidPrefix
+	}
+	if strings.IndexFunc(prefix, unicode.IsSpace) >= 0 || strings.Contains(prefix, "`") {
+		return errInvalidPrefix
+	}
+	return nil
+}
+
+// NewPrefixCommand는 새로운 접두사 명령어를 생성합니다
+func NewPrefixCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *PrefixCommand {
+	return &PrefixCommand{
+		log:      log.Named("prefix-command"),
+		repo:     storage.NewGuildSettingsRepository(db, log),
+		prefixes: prefixes,
+	}
+}
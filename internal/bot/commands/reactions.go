@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// numberEmojis는 번호 선택지에 사용하는 리액션 이모지입니다 (1️⃣ ~ 🔟)
+var numberEmojis = []string{
+	"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣",
+	"6️⃣", "7️⃣", "8️⃣", "9️⃣", "\U0001F51F",
+}
+
+// addReactions는 메시지에 주어진 이모지를 순서대로 추가합니다
+func addReactions(s *discordgo.Session, channelID, messageID string, emojis []string) error {
+	for _, emoji := range emojis {
+		if err := s.MessageReactionAdd(channelID, messageID, emoji); err != nil {
+			return fmt.Errorf("failed to add reaction %s: %w", emoji, err)
+		}
+	}
+	return nil
+}
+
+// countReactions는 메시지에서 각 이모지의 리액션 수를 셉니다.
+// 봇이 선택지로 추가한 자신의 리액션은 제외합니다.
+func countReactions(s *discordgo.Session, channelID, messageID string, emojis []string) ([]int, error) {
+	msg, err := s.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch message: %w", err)
+	}
+
+	byEmoji := make(map[string]int, len(msg.Reactions))
+	for _, reaction := range msg.Reactions {
+		if reaction.Emoji == nil {
+			continue
+		}
+		count := reaction.Count
+		if reaction.Me {
+			count--
+		}
+		byEmoji[reaction.Emoji.Name] = count
+	}
+
+	counts := make([]int, len(emojis))
+	for i, emoji := range emojis {
+		counts[i] = byEmoji[emoji]
+	}
+	return counts, nil
+}
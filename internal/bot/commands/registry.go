@@ -1,22 +1,39 @@
 package commands
 
 import (
+	"context"
+	"runtime/debug"
 	"strings"
+	"time"
 
+	"github.com/bradykim7/gbot/internal/models"
 	"github.com/bwmarrin/discordgo"
 	"go.uber.org/zap"
 )
 
+// usageRecordTimeout bounds how long recording one command invocation may take
+const usageRecordTimeout = 5 * time.Second
+
 // Command represents a bot command
 type Command interface {
 	Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string)
-	Help() string
+	// Help returns the command's usage, written with prefix, the command
+	// prefix of the guild the help is shown in
+	Help(prefix string) string
+}
+
+// UsageRecorder stores command invocations for usage stats
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, usage *models.CommandUsage) error
 }
 
 // Registry manages all bot commands
 type Registry struct {
 	prefix   string
+	prefixes *PrefixCache // nil이면 모든 서버에서 prefix 사용
 	commands map[string]Command
+	names    map[Command]string // 명령어 -> 대표 이름 (처음 등록한 이름, 별칭 제외)
+	usage    UsageRecorder      // nil이면 사용량을 기록하지 않음
 	log      *zap.Logger
 }
 
@@ -25,46 +42,138 @@ func NewRegistry(prefix string, log *zap.Logger) *Registry {
 	return &Registry{
 		prefix:   prefix,
 		commands: make(map[string]Command),
+		names:    make(map[Command]string),
 		log:      log.Named("commands"),
 	}
 }
 
-// Register registers a command with the registry
+// SetPrefixCache enables per-guild prefixes resolved through the cache
+func (r *Registry) SetPrefixCache(prefixes *PrefixCache) {
+	r.prefixes = prefixes
+}
+
+// SetUsageRecorder enables recording every executed command for usage stats
+func (r *Registry) SetUsageRecorder(usage UsageRecorder) {
+	r.usage = usage
+}
+
+// prefixFor returns the command prefix used in a guild. DMs and guilds
+// without their own prefix use the default prefix.
+func (r *Registry) prefixFor(guildID string) string {
+	if r.prefixes == nil {
+		return r.prefix
+	}
+	return r.prefixes.Prefix(guildID)
+}
+
+// Register registers a command with the registry. Names are matched
+// case-insensitively.
 func (r *Registry) Register(name string, cmd Command) {
+	name = strings.ToLower(name)
 	r.commands[name] = cmd
+	if _, ok := r.names[cmd]; !ok {
+		r.names[cmd] = name
+	}
 	r.log.Info("Registered command", zap.String("name", name))
 }
 
+// IsCommand reports whether the message invokes a registered command
+func (r *Registry) IsCommand(m *discordgo.MessageCreate) bool {
+	prefix := r.prefixFor(m.GuildID)
+	if !strings.HasPrefix(m.Content, prefix) {
+		return false
+	}
+
+	parts := strings.Fields(strings.TrimPrefix(m.Content, prefix))
+	if len(parts) == 0 {
+		return false
+	}
+
+	_, ok := r.commands[strings.ToLower(parts[0])]
+	return ok
+}
+
+// HandleNotReady replies to a command received before the bot is ready.
+// Messages that aren't commands are ignored.
+func (r *Registry) HandleNotReady(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !r.IsCommand(m) {
+		return
+	}
+
+	r.log.Debug("Command received before ready", zap.String("content", m.Content))
+	sendError(s, m.ChannelID, "아직 준비 중입니다. 잠시 후 다시 시도해주세요.")
+}
+
 // Handle processes a message and executes the appropriate command
 func (r *Registry) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
-	// Check if the message starts with the command prefix
-	if !strings.HasPrefix(m.Content, r.prefix) {
+	// Check if the message starts with the guild's command prefix
+	prefix := r.prefixFor(m.GuildID)
+	if !strings.HasPrefix(m.Content, prefix) {
 		return
 	}
-	
+
 	// Split the message into command and arguments
-	content := strings.TrimPrefix(m.Content, r.prefix)
+	content := strings.TrimPrefix(m.Content, prefix)
 	parts := strings.Fields(content)
 	if len(parts) == 0 {
 		return
 	}
-	
-	// Extract command name and arguments
-	cmdName := parts[0]
+
+	// Extract command name and arguments. The name is lowercased so !Food
+	// works like !food; the arguments keep their case.
+	cmdName := strings.ToLower(parts[0])
 	args := parts[1:]
-	
+
 	// Find the command
 	cmd, ok := r.commands[cmdName]
 	if !ok {
 		return
 	}
-	
+
 	// Execute the command
 	r.log.Info("Executing command", zap.String("command", cmdName))
+	if r.execute(s, m, cmdName, cmd, args) {
+		r.recordUsage(r.names[cmd], m)
+	}
+}
+
+// execute runs a command, recovering from a panic in it so one broken command
+// can't take down the bot. It reports whether the command completed.
+func (r *Registry) execute(s *discordgo.Session, m *discordgo.MessageCreate, cmdName string, cmd Command, args []string) (completed bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			r.log.Error("Command panicked",
+				zap.String("command", cmdName),
+				zap.String("content", m.Content),
+				zap.Any("panic", recovered),
+				zap.ByteString("stack", debug.Stack()))
+			sendError(s, m.ChannelID, "명령어를 처리하는 중 오류가 발생했습니다.")
+		}
+	}()
+
 	cmd.Execute(s, m, args)
+	return true
+}
+
+// recordUsage records a command invocation in the background so a slow or
+// unavailable database never delays command handling. Failures are only logged.
+func (r *Registry) recordUsage(command string, m *discordgo.MessageCreate) {
+	if r.usage == nil {
+		return
+	}
+
+	usage := models.NewCommandUsage(command, m.Author.ID, m.GuildID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), usageRecordTimeout)
+		defer cancel()
+
+		if err := r.usage.RecordUsage(ctx, usage); err != nil {
+			r.log.Warn("Failed to record command usage", zap.Error(err), zap.String("command", command))
+		}
+	}()
 }
 
 // GetCommands returns all registered commands
 func (r *Registry) GetCommands() map[string]Command {
 	return r.commands
-}
\ No newline at end of file
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// funcCommand runs execute for every invocation
+type funcCommand struct {
+	execute func(args []string)
+}
+
+func (c *funcCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	c.execute(args)
+}
+
+func (c *funcCommand) Help(prefix string) string {
+	return prefix + "test"
+}
+
+// usageSpy passes every recorded invocation to the test
+type usageSpy chan *models.CommandUsage
+
+func (s usageSpy) RecordUsage(ctx context.Context, usage *models.CommandUsage) error {
+	s <- usage
+	return nil
+}
+
+// nextUsage waits for the registry's background recording
+func (s usageSpy) nextUsage(t *testing.T) *models.CommandUsage {
+	t.Helper()
+	select {
+	case usage := <-s:
+		return usage
+	case <-time.After(5 * time.Second):
+		t.Fatal("command usage was never recorded")
+		return nil
+	}
+}
+
+func registryMessage(content string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		ChannelID: "channel-1",
+		GuildID:   "guild-1",
+		Content:   content,
+		Author:    &discordgo.User{ID: "user-1", Username: "user"},
+	}}
+}
+
+func TestHandleRecordsUsage(t *testing.T) {
+	registry := NewRegistry("!", zap.NewNop())
+	spy := make(usageSpy, 10)
+	registry.SetUsageRecorder(spy)
+	food := &funcCommand{execute: func(args []string) {}}
+	registry.Register("food", food)
+	registry.Register("밥", food)
+	session := newRecordingSession(t)
+
+	registry.Handle(session.Session, registryMessage("!밥 추천"))
+
+	usage := spy.nextUsage(t)
+	if usage.Command != "food" || usage.UserID != "user-1" || usage.GuildID != "guild-1" {
+		t.Errorf("recorded %+v, want food by user-1 in guild-1", usage)
+	}
+	if usage.Timestamp.IsZero() {
+		t.Error("recorded usage has no timestamp")
+	}
+
+	registry.Handle(session.Session, registryMessage("!unknown"))
+	registry.Handle(session.Session, registryMessage("밥 추천"))
+	select {
+	case usage := <-spy:
+		t.Errorf("recorded %+v for a message that isn't a command", usage)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandleRecoversFromPanic(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	registry := NewRegistry("!", zap.New(core))
+	spy := make(usageSpy, 10)
+	registry.SetUsageRecorder(spy)
+	registry.Register("broken", &funcCommand{execute: func(args []string) {
+		var selection map[string]string
+		selection[args[0]] = "boom"
+	}})
+	session := newRecordingSession(t)
+
+	registry.Handle(session.Session, registryMessage("!broken now"))
+
+	entries := logs.FilterMessage("Command panicked").All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d panics, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["command"] != "broken" {
+		t.Errorf("logged command = %v, want broken", fields["command"])
+	}
+	if stack, _ := fields["stack"].(string); !strings.Contains(stack, "registry_test.go") {
+		t.Error("logged panic has no stack trace of the command")
+	}
+	if got := session.messages(); len(got) != 1 || got[0] != "명령어를 처리하는 중 오류가 발생했습니다." {
+		t.Errorf("replied %q, want the generic error", got)
+	}
+	select {
+	case usage := <-spy:
+		t.Errorf("recorded %+v for a command that panicked", usage)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The registry keeps handling commands after the panic
+	ran := false
+	registry.Register("ok", &funcCommand{execute: func(args []string) { ran = true }})
+	registry.Handle(session.Session, registryMessage("!ok"))
+	if !ran {
+		t.Error("command after the panic didn't run")
+	}
+}
+
+func TestHandleMatchesCommandNameCaseInsensitively(t *testing.T) {
+	registry := NewRegistry("!", zap.NewNop())
+	calls := make(chan []string, 10)
+	food := &funcCommand{execute: func(args []string) { calls <- args }}
+	registry.Register("food", food)
+	registry.Register("Hot", &funcCommand{execute: func(args []string) { calls <- args }})
+	session := newRecordingSession(t)
+
+	tests := []struct {
+		content string
+		args    []string
+	}{
+		{"!food lunch", []string{"lunch"}},
+		{"!Food Lunch", []string{"Lunch"}},
+		{"!FOOD ADD Lunch Kimchi", []string{"ADD", "Lunch", "Kimchi"}},
+		{"!hot", []string{}},
+	}
+
+	for _, tt := range tests {
+		if !registry.IsCommand(registryMessage(tt.content)) {
+			t.Errorf("IsCommand(%q) = false, want true", tt.content)
+			continue
+		}
+		registry.Handle(session.Session, registryMessage(tt.content))
+		select {
+		case args := <-calls:
+			if strings.Join(args, " ") != strings.Join(tt.args, " ") {
+				t.Errorf("Handle(%q) ran with args %q, want %q with their case kept", tt.content, args, tt.args)
+			}
+		default:
+			t.Errorf("Handle(%q) didn't run the command", tt.content)
+		}
+	}
+}
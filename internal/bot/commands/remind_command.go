@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// maxReminderDelay는 예약할 수 있는 가장 먼 리마인더 시간입니다
+const maxReminderDelay = 365 * 24 * time.Hour
+
+var (
+	errInvalidReminderTime = errors.New("invalid reminder time")
+	errReminderInPast      = errors.New("reminder time is in the past")
+	errReminderTooFar      = errors.New("reminder time is too far in the future")
+)
+
+// RemindCommand는 리마인더 예약 명령어를 처리합니다
+type RemindCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	repo     *storage.ReminderRepository
+}
+
+// Execute implements the Command interface
+func (c *RemindCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) < 2 {
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	now := time.Now()
+	fireAt, rest, err := ParseReminderTime(args, now)
+	if err != nil {
+		switch {
+		case errors.Is(err, errReminderInPast):
+			sendError(s, m.ChannelID, "이미 지난 시간입니다. 미래의 시간을 입력해주세요.")
+		case errors.Is(err, errReminderTooFar):
+			sendError(s, m.ChannelID, "리마인더는 최대 1년 이내로만 예약할 수 있습니다.")
+		default:
+			sendError(s, m.ChannelID, "시간 형식이 올바르지 않습니다. 예: 30m, 2h, 1d, 15:30, 2024-05-01 09:00")
+		}
+		return
+	}
+
+	message := strings.TrimSpace(strings.Join(rest, " "))
+	if message == "" {
+		sendError(s, m.ChannelID, "리마인더 내용을 입력해주세요.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	reminder := models.NewReminder(m.Author.ID, m.ChannelID, m.GuildID, message, fireAt)
+	if err := c.repo.SaveReminder(ctx, reminder); err != nil {
+		c.log.Error("Failed to save reminder", zap.Error(err))
+		sendError(s, m.ChannelID, "리마인더를 저장하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "리마인더 예약됨",
+		Description: fmt.Sprintf("**%s**에 알려드릴게요: %s", fireAt.Format("2006-01-02 15:04"), message),
+		Color:       0x9966FF, // Purple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", m.Author.Username),
+		},
+		Timestamp: now.Format(time.RFC3339),
+	}
+
+	c.log.Info("Reminder scheduled",
+		zap.String("user_id", m.Author.ID),
+		zap.Time("fire_at", fireAt))
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// Help implements the Command interface
+func (c *RemindCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Remind Command Usage**\n"+
+		"%s remind [time] [message] - Get pinged later\n"+
+		"time: relative (30m, 2h, 1h30m, 1d) or absolute (15:30, 2024-05-01 09:00)",
+		prefix)
+}
+
+// ParseReminderTime parses the time at the start of args relative to now and
+// returns the fire time and the remaining arguments.
+//
+// Supported formats:
+//   - relative durations: "30m", "2h", "1h30m", "1d"
+//   - time of day: "15:30" (today, or tomorrow if already past)
+//   - date and time: "2024-05-01 09:00" or "2024-05-01T09:00"
+func ParseReminderTime(args []string, now time.Time) (time.Time, []string, error) {
+	if len(args) == 0 {
+		return time.Time{}, nil, errInvalidReminderTime
+	}
+
+	var fireAt time.Time
+	rest := args[1:]
+
+	switch {
+	case len(args) >= 2 && isDate(args[0]):
+		t, err := time.ParseInLocation("2006-01-02 15:04", args[0]+" "+args[1], now.Location())
+		if err != nil {
+			return time.Time{}, nil, errInvalidReminderTime
+		}
+		fireAt = t
+		rest = args[2:]
+	case strings.Contains(args[0], "T") && isDate(strings.SplitN(args[0], "T", 2)[0]):
+		t, err := time.ParseInLocation("2006-01-02T15:04", args[0], now.Location())
+		if err != nil {
+			return time.Time{}, nil, errInvalidReminderTime
+		}
+		fireAt = t
+	case strings.Contains(args[0], ":"):
+		t, err := time.ParseInLocation("15:04", args[0], now.Location())
+		if err != nil {
+			return time.Time{}, nil, errInvalidReminderTime
+		}
+		fireAt = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !fireAt.After(now) {
+			fireAt = fireAt.AddDate(0, 0, 1)
+		}
+	default:
+		d, err := parseReminderDuration(args[0])
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		fireAt = now.Add(d)
+	}
+
+	if !fireAt.After(now) {
+		return time.Time{}, nil, errReminderInPast
+	}
+	if fireAt.Sub(now) > maxReminderDelay {
+		return time.Time{}, nil, errReminderTooFar
+	}
+
+	return fireAt, rest, nil
+}
+
+// parseReminderDuration parses a Go duration, additionally accepting a "d" (day) unit
+func parseReminderDuration(s string) (time.Duration, error) {
+	var days time.Duration
+	if i := strings.Index(s, "d"); i >= 0 {
+		n, err := strconv.Atoi(s[:i])
+		if err != nil || n < 0 {
+			return 0, errInvalidReminderTime
+		}
+		days = time.Duration(n) * 24 * time.Hour
+		s = s[i+1:]
+		if s == "" {
+			return days, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, errInvalidReminderTime
+	}
+
+	return days + d, nil
+}
+
+// isDate reports whether s looks like a YYYY-MM-DD date
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+// NewRemindCommand는 새로운 리마인더 명령어 핸들러를 생성합니다
+func NewRemindCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *RemindCommand {
+	return &RemindCommand{
+		log:      log.Named("remind-command"),
+		prefixes: prefixes,
+		repo:     storage.NewReminderRepository(db, log),
+	}
+}
@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// 주사위 굴리기 제한 (남용 방지)
+const (
+	maxDiceCount    = 100
+	maxDiceSides    = 1000
+	maxDiceModifier = 10000
+)
+
+var (
+	errInvalidDice    = errors.New("invalid dice notation")
+	errDiceOutOfRange = errors.New("dice out of range")
+)
+
+// Dice는 NdM+K 주사위 표기법을 나타냅니다
+type Dice struct {
+	Count    int
+	Sides    int
+	Modifier int
+}
+
+// String returns the dice in NdM+K notation
+func (d Dice) String() string {
+	switch {
+	case d.Modifier > 0:
+		return fmt.Sprintf("%dd%d+%d", d.Count, d.Sides, d.Modifier)
+	case d.Modifier < 0:
+		return fmt.Sprintf("%dd%d%d", d.Count, d.Sides, d.Modifier)
+	default:
+		return fmt.Sprintf("%dd%d", d.Count, d.Sides)
+	}
+}
+
+// ParseDice parses standard dice notation such as "2d6", "d20", "3d8+2" or "1d10-1".
+// The count defaults to 1 when omitted. Counts, sides and modifiers outside the
+// allowed bounds are rejected with errDiceOutOfRange.
+func ParseDice(notation string) (Dice, error) {
+	notation = strings.ToLower(strings.TrimSpace(notation))
+
+	countPart, rest, ok := strings.Cut(notation, "d")
+	if !ok {
+		return Dice{}, errInvalidDice
+	}
+
+	var dice Dice
+
+	if countPart == "" {
+		dice.Count = 1
+	} else {
+		count, err := parseDiceNumber(countPart)
+		if err != nil {
+			return Dice{}, err
+		}
+		dice.Count = count
+	}
+
+	sidesPart := rest
+	if i := strings.IndexAny(rest, "+-"); i >= 0 {
+		sidesPart = rest[:i]
+		modifier, err := parseDiceNumber(rest[i+1:])
+		if err != nil {
+			return Dice{}, err
+		}
+		if rest[i] == '-' {
+			modifier = -modifier
+		}
+		dice.Modifier = modifier
+	}
+
+	sides, err := parseDiceNumber(sidesPart)
+	if err != nil {
+		return Dice{}, err
+	}
+	dice.Sides = sides
+
+	if dice.Count < 1 || dice.Count > maxDiceCount ||
+		dice.Sides < 2 || dice.Sides > maxDiceSides ||
+		dice.Modifier < -maxDiceModifier || dice.Modifier > maxDiceModifier {
+		return Dice{}, errDiceOutOfRange
+	}
+
+	return dice, nil
+}
+
+// parseDiceNumber parses an unsigned decimal number within a dice expression
+func parseDiceNumber(s string) (int, error) {
+	if s == "" || len(s) > 6 {
+		return 0, errInvalidDice
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, errInvalidDice
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errInvalidDice
+	}
+	return n, nil
+}
+
+// RollCommand는 주사위 굴리기 명령어를 처리합니다
+type RollCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	mu       sync.Mutex
+	random   *rand.Rand
+}
+
+// Execute implements the Command interface
+func (c *RollCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	dice := Dice{Count: 1, Sides: 6}
+	if len(args) > 0 {
+		parsed, err := ParseDice(args[0])
+		if err != nil {
+			if errors.Is(err, errDiceOutOfRange) {
+				sendError(s, m.ChannelID, fmt.Sprintf("주사위는 1~%d개, 면은 2~%d까지만 가능합니다.", maxDiceCount, maxDiceSides))
+				return
+			}
+			sendError(s, m.ChannelID, fmt.Sprintf("주사위 표기법이 올바르지 않습니다. 예: 2d6, d20, 3d8+2\n%s", c.Help(c.prefixes.Prefix(m.GuildID))))
+			return
+		}
+		dice = parsed
+	}
+
+	rolls, total := c.roll(dice)
+
+	values := make([]string, len(rolls))
+	for i, roll := range rolls {
+		values[i] = strconv.Itoa(roll)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎲 %s", dice),
+		Description: fmt.Sprintf("**%d**", total),
+		Color:       0x8B4513, // Brown
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Rolls",
+				Value: strings.Join(values, ", "),
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if dice.Modifier != 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Modifier",
+			Value:  fmt.Sprintf("%+d", dice.Modifier),
+			Inline: true,
+		})
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// Help implements the Command interface
+func (c *RollCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Roll Command Usage**\n"+
+		"%s roll - Roll 1d6\n"+
+		"%s roll [NdM+K] - Roll N dice with M sides plus K (e.g. 2d6, d20, 3d8+2)",
+		prefix, prefix)
+}
+
+// roll rolls the dice and returns the individual rolls and the total including the modifier
+func (c *RollCommand) roll(dice Dice) ([]int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rolls := make([]int, dice.Count)
+	total := dice.Modifier
+	for i := range rolls {
+		rolls[i] = c.random.Intn(dice.Sides) + 1
+		total += rolls[i]
+	}
+	return rolls, total
+}
+
+// NewRollCommand는 새로운 주사위 명령어 핸들러를 생성합니다
+func NewRollCommand(log *zap.Logger, prefixes *PrefixCache) *RollCommand {
+	// 시드가 있는 난수 생성기 생성
+	source := rand.NewSource(time.Now().UnixNano())
+
+	return &RollCommand{
+		log:      log.Named("roll-command"),
+		prefixes: prefixes,
+		random:   rand.New(source),
+	}
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+func TestParseDice(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     Dice
+		err      error
+	}{
+		{"2d6", Dice{Count: 2, Sides: 6}, nil},
+		{"d20", Dice{Count: 1, Sides: 20}, nil},
+		{" 3D8+2 ", Dice{Count: 3, Sides: 8, Modifier: 2}, nil},
+		{"1d10-1", Dice{Count: 1, Sides: 10, Modifier: -1}, nil},
+		{"0d6", Dice{}, errDiceOutOfRange},
+		{"101d6", Dice{}, errDiceOutOfRange},
+		{"1d1", Dice{}, errDiceOutOfRange},
+		{"1d1001", Dice{}, errDiceOutOfRange},
+		{"1d6+10001", Dice{}, errDiceOutOfRange},
+		{"d", Dice{}, errInvalidDice},
+		{"6", Dice{}, errInvalidDice},
+		{"2d", Dice{}, errInvalidDice},
+		{"xd6", Dice{}, errInvalidDice},
+		{"2d6+", Dice{}, errInvalidDice},
+		{"-2d6", Dice{}, errInvalidDice},
+		{"9999999d6", Dice{}, errInvalidDice},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDice(tt.notation)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseDice(%q) error = %v, want %v", tt.notation, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDice(%q) = %+v, want %+v", tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestDiceString(t *testing.T) {
+	for _, notation := range []string{"2d6", "1d20", "3d8+2", "1d10-1"} {
+		dice, err := ParseDice(notation)
+		if err != nil {
+			t.Fatalf("ParseDice(%q) error = %v", notation, err)
+		}
+		if got := dice.String(); got != notation {
+			t.Errorf("String() = %q, want %q", got, notation)
+		}
+	}
+}
+
+func TestRollCommandRoll(t *testing.T) {
+	cmd := NewRollCommand(zap.NewNop(), staticPrefixes(nil))
+	cmd.random = rand.New(rand.NewSource(1))
+	dice := Dice{Count: 50, Sides: 6, Modifier: 3}
+
+	rolls, total := cmd.roll(dice)
+
+	if len(rolls) != dice.Count {
+		t.Fatalf("rolled %d dice, want %d", len(rolls), dice.Count)
+	}
+	sum := dice.Modifier
+	for _, roll := range rolls {
+		if roll < 1 || roll > dice.Sides {
+			t.Errorf("rolled %d, want 1 to %d", roll, dice.Sides)
+		}
+		sum += roll
+	}
+	if total != sum {
+		t.Errorf("total = %d, want the rolls plus the modifier (%d)", total, sum)
+	}
+}
+
+func TestRollCommandDefaultsTo1d6(t *testing.T) {
+	session := newRecordingSession(t)
+	cmd := NewRollCommand(zap.NewNop(), staticPrefixes(nil))
+	m := &discordgo.MessageCreate{Message: &discordgo.Message{ChannelID: "channel-1", Author: &discordgo.User{ID: "user-1", Username: "tester"}}}
+
+	cmd.Execute(session.Session, m, nil)
+
+	sends := session.sends()
+	if len(sends) != 1 || len(sends[0].Embeds) != 1 || sends[0].Embeds[0].Title != "🎲 1d6" {
+		t.Errorf("sent %+v, want a 1d6 roll", sends)
+	}
+}
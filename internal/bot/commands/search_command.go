@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// searchMinQueryLength는 검색어의 최소 글자 수입니다
+	searchMinQueryLength = 2
+	// searchPageSize는 한 번에 보여줄 검색 결과 수입니다
+	searchPageSize = 5
+	// searchMaxResults는 "더보기"로 볼 수 있는 최대 결과 수입니다
+	searchMaxResults = 50
+	// searchSessionTTL은 "더보기"를 위해 마지막 검색을 기억하는 시간입니다
+	searchSessionTTL = 10 * time.Minute
+)
+
+// searchSession은 사용자의 마지막 검색 상태입니다
+type searchSession struct {
+	query     string
+	source    string
+	offset    int
+	expiresAt time.Time
+}
+
+// SearchCommand는 저장된 특가 상품 검색 명령어를 처리합니다
+type SearchCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	repo     *storage.ProductRepository
+
+	mu       sync.Mutex
+	sessions map[string]searchSession // channelID:userID -> last search
+}
+
+// Execute implements the Command interface
+func (c *SearchCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	key := m.ChannelID + ":" + m.Author.ID
+
+	if len(args) == 1 && (strings.ToLower(args[0]) == "more" || args[0] == "더보기") {
+		session, ok := c.session(key)
+		if !ok {
+			sendError(s, m.ChannelID, fmt.Sprintf("최근 검색 기록이 없습니다. %s search [검색어]로 먼저 검색해주세요.", c.prefixes.Prefix(m.GuildID)))
+			return
+		}
+		c.search(s, m, key, session)
+		return
+	}
+
+	query, source := parseSearchArgs(args)
+	if utf8.RuneCountInString(query) < searchMinQueryLength {
+		sendError(s, m.ChannelID, fmt.Sprintf("검색어는 %d글자 이상 입력해주세요.", searchMinQueryLength))
+		return
+	}
+
+	c.search(s, m, key, searchSession{query: query, source: source})
+}
+
+// Help implements the Command interface
+func (c *SearchCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Search Command Usage**\n"+
+		"%s search [query] - Search stored deals\n"+
+		"%s search [query] source:[name] - Search deals from one source (e.g. source:ppomppu)\n"+
+		"%s search more - Show more results of your last search",
+		prefix, prefix, prefix)
+}
+
+// search runs the search from session.offset, replies with one page of
+// results and remembers the next offset for "more"
+func (c *SearchCommand) search(s *discordgo.Session, m *discordgo.MessageCreate, key string, session searchSession) {
+	if session.offset >= searchMaxResults {
+		c.clearSession(key)
+		sendMessage(s, m.ChannelID, "더 이상 표시할 결과가 없습니다. 검색어를 더 구체적으로 입력해주세요.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 다음 페이지가 있는지 확인하기 위해 하나 더 조회
+	products, err := c.repo.SearchProducts(ctx, session.query, session.source, session.offset, searchPageSize+1)
+	if err != nil {
+		c.log.Error("Failed to search products", zap.Error(err), zap.String("query", session.query))
+		sendError(s, m.ChannelID, "상품을 검색하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if len(products) == 0 {
+		c.clearSession(key)
+		if session.offset == 0 {
+			sendMessage(s, m.ChannelID, fmt.Sprintf("'%s'에 대한 검색 결과가 없습니다.", session.query))
+		} else {
+			sendMessage(s, m.ChannelID, "더 이상 표시할 결과가 없습니다.")
+		}
+		return
+	}
+
+	hasMore := len(products) > searchPageSize && session.offset+searchPageSize < searchMaxResults
+	if len(products) > searchPageSize {
+		products = products[:searchPageSize]
+	}
+
+	sendEmbed(s, m.ChannelID, c.createResultsEmbed(session, products, hasMore, m.Author.Username, c.prefixes.Prefix(m.GuildID)))
+
+	if hasMore {
+		session.offset += searchPageSize
+		session.expiresAt = time.Now().Add(searchSessionTTL)
+		c.saveSession(key, session)
+	} else {
+		c.clearSession(key)
+	}
+}
+
+// createResultsEmbed renders one page of search results. prefix is the
+// command prefix shown in the footer.
+func (c *SearchCommand) createResultsEmbed(session searchSession, products []models.Product, hasMore bool, requester, prefix string) *discordgo.MessageEmbed {
+	var description strings.Builder
+	for i, product := range products {
+		fmt.Fprintf(&description, "**%d.** [%s](%s)\n%s · %s\n",
+			session.offset+i+1,
+			models.SanitizeTitle(product.Title),
+			product.URL,
+			product.GetPriceString(),
+			product.Source)
+	}
+
+	title := fmt.Sprintf("🔍 '%s' 검색 결과", models.EscapeDiscord(session.query))
+	if session.source != "" {
+		title += fmt.Sprintf(" (%s)", models.EscapeDiscord(session.source))
+	}
+
+	footer := fmt.Sprintf("Requested by %s", requester)
+	if hasMore {
+		footer = fmt.Sprintf("%s search more 로 더 보기 · %s", prefix, footer)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description.String(),
+		Color:       0x1E90FF, // Dodger blue
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: footer,
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// session returns the unexpired last search for key
+func (c *SearchCommand) session(key string) (searchSession, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, ok := c.sessions[key]
+	if !ok || time.Now().After(session.expiresAt) {
+		delete(c.sessions, key)
+		return searchSession{}, false
+	}
+	return session, true
+}
+
+// saveSession remembers the last search for key and drops expired sessions
+func (c *SearchCommand) saveSession(key string, session searchSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, existing := range c.sessions {
+		if now.After(existing.expiresAt) {
+			delete(c.sessions, k)
+		}
+	}
+	c.sessions[key] = session
+}
+
+// clearSession forgets the last search for key
+func (c *SearchCommand) clearSession(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sessions, key)
+}
+
+// parseSearchArgs splits the search arguments into the query text and an
+// optional "source:name" filter
+func parseSearchArgs(args []string) (query, source string) {
+	var terms []string
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(strings.ToLower(arg), "source:"); ok {
+			source = value
+			continue
+		}
+		terms = append(terms, arg)
+	}
+	return strings.TrimSpace(strings.Join(terms, " ")), source
+}
+
+// NewSearchCommand는 새로운 상품 검색 명령어 핸들러를 생성합니다
+func NewSearchCommand(log *zap.Logger, db *storage.MongoDB, prefixes *PrefixCache) *SearchCommand {
+	return &SearchCommand{
+		log:      log.Named("search-command"),
+		prefixes: prefixes,
+		repo:     storage.NewProductRepository(db, log),
+		sessions: make(map[string]searchSession),
+	}
+}
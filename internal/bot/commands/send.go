@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// noMentions는 어떤 멘션도 알림을 보내지 않도록 하는 AllowedMentions 정책입니다.
+// 명령어 응답에는 사용자 입력(키워드, 메뉴 이름)과 크롤링한 상품 제목이 포함되므로
+// @everyone/@here나 역할 멘션이 실제로 알림을 보내지 않도록 모든 전송에 적용합니다.
+var noMentions = &discordgo.MessageAllowedMentions{
+	Parse: []discordgo.AllowedMentionType{},
+}
+
+// sendMessage는 멘션 알림 없이 텍스트 메시지를 보냅니다
+func sendMessage(s *discordgo.Session, channelID, content string) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content:         content,
+		AllowedMentions: noMentions,
+	})
+}
+
+// sendEmbed는 멘션 알림 없이 임베드 메시지를 보냅니다
+func sendEmbed(s *discordgo.Session, channelID string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	return s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embed:           embed,
+		AllowedMentions: noMentions,
+	})
+}
+
+// temporaryReplyTTL는 도움말과 오류 응답을 지우기까지의 시간입니다 (0이면 지우지 않음)
+var temporaryReplyTTL atomic.Int64
+
+// scheduleDelete는 ttl 후에 삭제를 실행합니다. 테스트에서 타이머 대신 쓸 수 있도록 변수로 둡니다.
+var scheduleDelete = func(ttl time.Duration, del func()) {
+	time.AfterFunc(ttl, del)
+}
+
+// SetTemporaryReplyTTL sets how long help and error replies stay in the
+// channel before the bot deletes them, so they don't pile up in busy
+// channels. Zero keeps them.
+func SetTemporaryReplyTTL(ttl time.Duration) {
+	temporaryReplyTTL.Store(int64(ttl))
+}
+
+// sendHelp는 명령어 도움말을 임시 응답으로 보냅니다
+func sendHelp(s *discordgo.Session, channelID, content string) (*discordgo.Message, error) {
+	return sendTemporary(s, channelID, content, time.Duration(temporaryReplyTTL.Load()))
+}
+
+// sendError는 입력 오류나 처리 실패 안내를 임시 응답으로 보냅니다
+func sendError(s *discordgo.Session, channelID, content string) (*discordgo.Message, error) {
+	return sendTemporary(s, channelID, content, time.Duration(temporaryReplyTTL.Load()))
+}
+
+// sendTemporary는 텍스트 메시지를 보내고 ttl 후에 삭제합니다 (ttl이 0 이하면 남겨둡니다).
+// 삭제 실패(이미 삭제된 메시지, 메시지 관리 권한 없음 등)는 무시합니다.
+func sendTemporary(s *discordgo.Session, channelID, content string, ttl time.Duration) (*discordgo.Message, error) {
+	msg, err := sendMessage(s, channelID, content)
+	if err != nil || ttl <= 0 {
+		return msg, err
+	}
+
+	scheduleDelete(ttl, func() {
+		// 봇이 보낸 메시지라 보통 권한 문제는 없지만, 사용자가 먼저 지웠을 수 있습니다
+		_ = s.ChannelMessageDelete(channelID, msg.ID)
+	})
+	return msg, nil
+}
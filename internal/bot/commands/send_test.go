@@ -0,0 +1,237 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// sentMessage is a message the recording session was asked to send
+type sentMessage struct {
+	ChannelID       string
+	Content         string
+	Embeds          []*discordgo.MessageEmbed
+	AllowedMentions *discordgo.MessageAllowedMentions
+}
+
+// recordingSession answers Discord REST calls locally and records the
+// messages sent and deleted through it. A DM channel with a user has the ID
+// "dm-" followed by the user's ID.
+type recordingSession struct {
+	*discordgo.Session
+
+	mu      sync.Mutex
+	sent    []sentMessage
+	deleted []string
+}
+
+func newRecordingSession(t *testing.T) *recordingSession {
+	t.Helper()
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	rs := &recordingSession{Session: session}
+	session.Client = &http.Client{Transport: recordingTransport(func(r *http.Request) (*http.Response, error) {
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		channelID := ""
+		for i, part := range parts {
+			if part == "channels" && i+1 < len(parts) {
+				channelID = parts[i+1]
+			}
+		}
+
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/messages"):
+			var msg discordgo.MessageSend
+			if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+				t.Errorf("failed to decode message: %v", err)
+			}
+			rs.mu.Lock()
+			rs.sent = append(rs.sent, sentMessage{ChannelID: channelID, Content: msg.Content, Embeds: msg.Embeds, AllowedMentions: msg.AllowedMentions})
+			id := len(rs.sent)
+			rs.mu.Unlock()
+			return jsonResponse(discordgo.Message{ID: "message-" + strconv.Itoa(id), ChannelID: channelID, Content: msg.Content}), nil
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/users/@me/channels"):
+			var body struct {
+				RecipientID string `json:"recipient_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("failed to decode DM channel request: %v", err)
+			}
+			return jsonResponse(discordgo.Channel{ID: "dm-" + body.RecipientID, Type: discordgo.ChannelTypeDM}), nil
+		case r.Method == http.MethodDelete:
+			rs.mu.Lock()
+			rs.deleted = append(rs.deleted, parts[len(parts)-1])
+			rs.mu.Unlock()
+			return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+		}
+		t.Errorf("unexpected Discord API call: %s %s", r.Method, r.URL.Path)
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}}, nil
+	})}
+	return rs
+}
+
+// messages returns the contents of the messages sent so far
+func (rs *recordingSession) messages() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	var contents []string
+	for _, msg := range rs.sent {
+		contents = append(contents, msg.Content)
+	}
+	return contents
+}
+
+// sends returns the messages sent so far
+func (rs *recordingSession) sends() []sentMessage {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]sentMessage(nil), rs.sent...)
+}
+
+// recordingTransport adapts a function to http.RoundTripper
+type recordingTransport func(*http.Request) (*http.Response, error)
+
+func (f recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func jsonResponse(v interface{}) *http.Response {
+	body, _ := json.Marshal(v)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+	}
+}
+
+func TestSendMentionsNoOne(t *testing.T) {
+	session := newRecordingSession(t)
+
+	sendMessage(session.Session, "channel-1", "@everyone <@&123> <@456> 27인치 모니터")
+	sendEmbed(session.Session, "channel-1", &discordgo.MessageEmbed{Title: "@here 27인치 모니터"})
+
+	sends := session.sends()
+	if len(sends) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sends))
+	}
+	for _, msg := range sends {
+		allowed := msg.AllowedMentions
+		if allowed == nil {
+			t.Fatalf("message %q sent without allowed mentions, so @everyone would ping", msg.Content)
+		}
+		if len(allowed.Parse) != 0 || len(allowed.Users) != 0 || len(allowed.Roles) != 0 {
+			t.Errorf("allowed mentions = %+v, want none", allowed)
+		}
+	}
+}
+
+// scheduledDelete is a delete sendTemporary scheduled
+type scheduledDelete struct {
+	ttl time.Duration
+	del func()
+}
+
+// captureDeletes replaces the delete timer for the test, returning the deletes
+// scheduled so far instead of running them
+func captureDeletes(t *testing.T) func() []scheduledDelete {
+	t.Helper()
+	var mu sync.Mutex
+	var scheduled []scheduledDelete
+	original := scheduleDelete
+	scheduleDelete = func(ttl time.Duration, del func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		scheduled = append(scheduled, scheduledDelete{ttl: ttl, del: del})
+	}
+	t.Cleanup(func() { scheduleDelete = original })
+	return func() []scheduledDelete {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]scheduledDelete(nil), scheduled...)
+	}
+}
+
+func TestSendTemporarySchedulesDelete(t *testing.T) {
+	scheduled := captureDeletes(t)
+	session := newRecordingSession(t)
+
+	if _, err := sendTemporary(session.Session, "channel-1", "사용법: !food add [lunch/dinner] [food name]", 30*time.Second); err != nil {
+		t.Fatalf("sendTemporary() error = %v", err)
+	}
+
+	deletes := scheduled()
+	if len(deletes) != 1 || deletes[0].ttl != 30*time.Second {
+		t.Fatalf("scheduled %+v, want one delete after 30s", deletes)
+	}
+	if len(session.deleted) != 0 {
+		t.Fatal("deleted the reply before the TTL passed")
+	}
+	deletes[0].del()
+	if len(session.deleted) != 1 || session.deleted[0] != "message-1" {
+		t.Errorf("deleted %v, want the reply message-1", session.deleted)
+	}
+
+	if _, err := sendTemporary(session.Session, "channel-1", "결과", 0); err != nil {
+		t.Fatalf("sendTemporary() error = %v", err)
+	}
+	if got := len(scheduled()); got != 1 {
+		t.Errorf("scheduled %d deletes, want none for a zero TTL", got-1)
+	}
+}
+
+func TestSendErrorUsesTemporaryReplyTTL(t *testing.T) {
+	scheduled := captureDeletes(t)
+	session := newRecordingSession(t)
+	SetTemporaryReplyTTL(45 * time.Second)
+	t.Cleanup(func() { SetTemporaryReplyTTL(0) })
+
+	sendError(session.Session, "channel-1", "유효한 메뉴 유형을 입력해주세요.")
+	sendHelp(session.Session, "channel-1", "**Food Command Usage**")
+
+	deletes := scheduled()
+	if len(deletes) != 2 {
+		t.Fatalf("scheduled %d deletes, want one per reply", len(deletes))
+	}
+	for _, d := range deletes {
+		if d.ttl != 45*time.Second {
+			t.Errorf("scheduled a delete after %v, want the configured 45s", d.ttl)
+		}
+	}
+}
+
+func TestSendTemporaryIgnoresDeleteFailure(t *testing.T) {
+	scheduled := captureDeletes(t)
+	session := newRecordingSession(t)
+	recording := session.Client.Transport
+	session.Client.Transport = recordingTransport(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodDelete {
+			// The user deleted the reply first
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader(`{"message": "Unknown Message", "code": 10008}`)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}
+		return recording.RoundTrip(r)
+	})
+
+	if _, err := sendTemporary(session.Session, "channel-1", "오류가 발생했습니다.", time.Minute); err != nil {
+		t.Fatalf("sendTemporary() error = %v", err)
+	}
+	deletes := scheduled()
+	if len(deletes) != 1 {
+		t.Fatalf("scheduled %d deletes, want 1", len(deletes))
+	}
+	deletes[0].del()
+}
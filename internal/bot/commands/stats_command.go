@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// statsDefaultWindow는 명령어 사용량의 기본 집계 기간입니다
+	statsDefaultWindow = 7 * 24 * time.Hour
+	// statsMaxWindow는 집계할 수 있는 최대 기간입니다 (사용 기록 보관 기간)
+	statsMaxWindow = 90 * 24 * time.Hour
+)
+
+// StatsCommand는 서버의 봇 사용 통계를 보여줍니다
+type StatsCommand struct {
+	log      *zap.Logger
+	prefixes *PrefixCache
+	repo     *storage.CommandUsageRepository
+}
+
+// Execute implements the Command interface
+func (c *StatsCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if len(args) == 0 {
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "commands", "명령어":
+		c.handleCommandStats(s, m, args[1:])
+	default:
+		sendHelp(s, m.ChannelID, c.Help(c.prefixes.Prefix(m.GuildID)))
+	}
+}
+
+// Help implements the Command interface
+func (c *StatsCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Stats Command Usage**\n"+
+		"%s stats commands [window] - (Admin) Show how often each command was used in this server (default 7d, max 90d)",
+		prefix)
+}
+
+// handleCommandStats는 기간 동안 서버에서 실행된 명령어별 횟수를 보여줍니다
+func (c *StatsCommand) handleCommandStats(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if m.GuildID == "" {
+		sendError(s, m.ChannelID, "사용 통계는 서버 채널에서만 볼 수 있습니다.")
+		return
+	}
+
+	admin, err := isServerAdmin(s, m.ChannelID, m.Author.ID)
+	if err != nil {
+		c.log.Error("권한 확인 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "권한을 확인하는 중 오류가 발생했습니다.")
+		return
+	}
+	if !admin {
+		sendError(s, m.ChannelID, "사용 통계는 서버 관리 권한이 있는 사용자만 볼 수 있습니다.")
+		return
+	}
+
+	window := statsDefaultWindow
+	if len(args) > 0 {
+		d, err := parseReminderDuration(strings.ToLower(args[0]))
+		if err != nil || d < time.Hour || d > statsMaxWindow {
+			sendError(s, m.ChannelID, "기간은 1시간에서 90일 사이로 입력해주세요. (예: 24h, 30d)")
+			return
+		}
+		window = d
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := c.repo.CountByCommand(ctx, m.GuildID, time.Now().Add(-window))
+	if err != nil {
+		c.log.Error("명령어 사용량 조회 실패", zap.Error(err))
+		sendError(s, m.ChannelID, "명령어 사용량을 조회하는 중 오류가 발생했습니다.")
+		return
+	}
+
+	if len(counts) == 0 {
+		sendMessage(s, m.ChannelID, fmt.Sprintf("최근 %s 동안 실행된 명령어가 없습니다.", formatHotWindow(window)))
+		return
+	}
+
+	total := 0
+	var description strings.Builder
+	for i, count := range counts {
+		total += count.Count
+		fmt.Fprintf(&description, "**%d.** `%s` - %d회 · 사용자 %d명\n", i+1, count.Command, count.Count, count.Users)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("최근 %s 명령어 사용량 (총 %d회)", formatHotWindow(window), total),
+		Description: description.String(),
+		Color:       0x0000ff, // 파란색
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("요청자: %s", m.Author.Username),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	sendEmbed(s, m.ChannelID, embed)
+}
+
+// NewStatsCommand는 새로운 사용 통계 명령어 핸들러를 생성합니다
+func NewStatsCommand(log *zap.Logger, usage *storage.CommandUsageRepository, prefixes *PrefixCache) *StatsCommand {
+	return &StatsCommand{
+		log:      log.Named("stats-command"),
+		prefixes: prefixes,
+		repo:     usage,
+	}
+}
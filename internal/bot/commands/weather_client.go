@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bradykim7/gbot/pkg/config"
+)
+
+// ErrCityNotFound는 날씨 API가 도시를 찾지 못했을 때 반환됩니다
+var ErrCityNotFound = errors.New("city not found")
+
+// Weather는 도시의 현재 날씨를 나타냅니다
+type Weather struct {
+	City        string
+	Country     string
+	Temperature float64 // 섭씨
+	FeelsLike   float64 // 섭씨
+	Humidity    int     // %
+	Condition   string
+	Icon        string
+}
+
+// WeatherClient는 현재 날씨를 조회하는 인터페이스입니다
+type WeatherClient interface {
+	CurrentWeather(ctx context.Context, city string) (*Weather, error)
+}
+
+// OpenWeatherClient는 OpenWeatherMap 호환 API를 사용하는 WeatherClient 구현입니다
+type OpenWeatherClient struct {
+	apiURL string
+	apiKey string
+	client *http.Client
+}
+
+// NewOpenWeatherClient는 새로운 OpenWeatherMap 클라이언트를 생성합니다
+func NewOpenWeatherClient(apiURL, apiKey string) *OpenWeatherClient {
+	return &OpenWeatherClient{
+		apiURL: apiURL,
+		apiKey: apiKey,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// openWeatherResponse는 OpenWeatherMap 현재 날씨 응답입니다
+type openWeatherResponse struct {
+	Name string `json:"name"`
+	Sys  struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Main struct {
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		Humidity  int     `json:"humidity"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+		Icon        string `json:"icon"`
+	} `json:"weather"`
+}
+
+// CurrentWeather implements the WeatherClient interface
+func (c *OpenWeatherClient) CurrentWeather(ctx context.Context, city string) (*Weather, error) {
+	query := url.Values{}
+	query.Set("q", city)
+	query.Set("appid", c.apiKey)
+	query.Set("units", "metric")
+	query.Set("lang", "kr")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create weather request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The request URL carries the API key, and the client error includes it
+		return nil, fmt.Errorf("failed to fetch weather: %w", config.RedactError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrCityNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("weather API returned status code %d", resp.StatusCode)
+	}
+
+	var body openWeatherResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode weather response: %w", err)
+	}
+
+	weather := &Weather{
+		City:        body.Name,
+		Country:     body.Sys.Country,
+		Temperature: body.Main.Temp,
+		FeelsLike:   body.Main.FeelsLike,
+		Humidity:    body.Main.Humidity,
+	}
+	if len(body.Weather) > 0 {
+		weather.Condition = body.Weather[0].Description
+		weather.Icon = body.Weather[0].Icon
+	}
+
+	return weather, nil
+}
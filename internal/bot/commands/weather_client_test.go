@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenWeatherClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("appid") != "key" || query.Get("units") != "metric" {
+			t.Errorf("query = %v, want the API key and metric units", query)
+		}
+		switch query.Get("q") {
+		case "Seoul":
+			w.Write([]byte(`{"name":"Seoul","sys":{"country":"KR"},"main":{"temp":21.5,"feels_like":20.1,"humidity":40},"weather":[{"description":"맑음","icon":"01d"}]}`))
+		case "Nowhere":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOpenWeatherClient(server.URL, "key")
+
+	weather, err := client.CurrentWeather(context.Background(), "Seoul")
+	if err != nil {
+		t.Fatalf("CurrentWeather() error = %v", err)
+	}
+	want := Weather{City: "Seoul", Country: "KR", Temperature: 21.5, FeelsLike: 20.1, Humidity: 40, Condition: "맑음", Icon: "01d"}
+	if *weather != want {
+		t.Errorf("CurrentWeather() = %+v, want %+v", *weather, want)
+	}
+
+	if _, err := client.CurrentWeather(context.Background(), "Nowhere"); !errors.Is(err, ErrCityNotFound) {
+		t.Errorf("CurrentWeather(unknown city) error = %v, want ErrCityNotFound", err)
+	}
+	if _, err := client.CurrentWeather(context.Background(), "Broken"); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("CurrentWeather() error = %v, want the status code", err)
+	}
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// WeatherCommand는 날씨 조회 명령어를 처리합니다
+type WeatherCommand struct {
+	log         *zap.Logger
+	client      WeatherClient
+	defaultCity string
+	prefixes    *PrefixCache
+}
+
+// Execute implements the Command interface
+func (c *WeatherCommand) Execute(s *discordgo.Session, m *discordgo.MessageCreate, args []string) {
+	if c.client == nil {
+		sendError(s, m.ChannelID, "날씨 기능이 설정되지 않았습니다. 관리자에게 문의해주세요.")
+		return
+	}
+
+	city := strings.TrimSpace(strings.Join(args, " "))
+	if city == "" {
+		city = c.defaultCity
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	weather, err := c.client.CurrentWeather(ctx, city)
+	if err != nil {
+		if errors.Is(err, ErrCityNotFound) {
+			sendError(s, m.ChannelID, fmt.Sprintf("'%s' 도시를 찾을 수 없습니다. 영문 도시 이름(예: Seoul)으로 다시 시도해주세요.", city))
+			return
+		}
+		c.log.Error("Failed to get weather", zap.Error(err), zap.String("city", city))
+		sendError(s, m.ChannelID, "날씨 정보를 가져오는 중 오류가 발생했습니다. 잠시 후 다시 시도해주세요.")
+		return
+	}
+
+	sendEmbed(s, m.ChannelID, c.createWeatherEmbed(weather, m.Author.Username))
+}
+
+// Help implements the Command interface
+func (c *WeatherCommand) Help(prefix string) string {
+	return fmt.Sprintf("**Weather Command Usage**\n"+
+		"%s weather [city] - Show the current weather (default: %s)",
+		prefix, c.defaultCity)
+}
+
+// createWeatherEmbed creates an embed describing the weather
+func (c *WeatherCommand) createWeatherEmbed(weather *Weather, requester string) *discordgo.MessageEmbed {
+	location := weather.City
+	if weather.Country != "" {
+		location += ", " + weather.Country
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s 현재 날씨", location),
+		Description: weather.Condition,
+		Color:       0x00BFFF, // Sky blue
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "기온",
+				Value:  fmt.Sprintf("%.1f°C (체감 %.1f°C)", weather.Temperature, weather.FeelsLike),
+				Inline: true,
+			},
+			{
+				Name:   "습도",
+				Value:  fmt.Sprintf("%d%%", weather.Humidity),
+				Inline: true,
+			},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Requested by %s", requester),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if weather.Icon != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{
+			URL: fmt.Sprintf("https://openweathermap.org/img/wn/%s@2x.png", weather.Icon),
+		}
+	}
+
+	return embed
+}
+
+// NewWeatherCommand는 새로운 날씨 명령어 핸들러를 생성합니다.
+// client가 nil이면 명령어는 설정 안내 메시지로 응답합니다.
+func NewWeatherCommand(log *zap.Logger, client WeatherClient, defaultCity string, prefixes *PrefixCache) *WeatherCommand {
+	return &WeatherCommand{
+		log:         log.Named("weather-command"),
+		client:      client,
+		defaultCity: defaultCity,
+		prefixes:    prefixes,
+	}
+}
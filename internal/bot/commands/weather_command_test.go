@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// stubWeatherClient answers with fixed weather and records the cities asked for
+type stubWeatherClient struct {
+	weather *Weather
+	err     error
+	cities  []string
+}
+
+func (c *stubWeatherClient) CurrentWeather(ctx context.Context, city string) (*Weather, error) {
+	c.cities = append(c.cities, city)
+	return c.weather, c.err
+}
+
+func TestWeatherCommand(t *testing.T) {
+	seoul := &Weather{City: "Seoul", Country: "KR", Temperature: 21.5, FeelsLike: 20.1, Humidity: 40, Condition: "맑음"}
+
+	tests := []struct {
+		name      string
+		client    *stubWeatherClient
+		args      []string
+		wantCity  string
+		wantEmbed string
+		wantText  string
+	}{
+		{"default city", &stubWeatherClient{weather: seoul}, nil, "Seoul", "Seoul, KR 현재 날씨", ""},
+		{"given city", &stubWeatherClient{weather: seoul}, []string{"New", "York"}, "New York", "Seoul, KR 현재 날씨", ""},
+		{"unknown city", &stubWeatherClient{err: ErrCityNotFound}, []string{"Nowhere"}, "Nowhere", "", "'Nowhere' 도시를 찾을 수 없습니다"},
+		{"API error", &stubWeatherClient{err: errors.New("status 500")}, nil, "Seoul", "", "날씨 정보를 가져오는 중 오류"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newRecordingSession(t)
+			cmd := NewWeatherCommand(zap.NewNop(), tt.client, "Seoul", staticPrefixes(nil))
+			m := &discordgo.MessageCreate{Message: &discordgo.Message{
+				ChannelID: "channel-1",
+				Author:    &discordgo.User{ID: "user-1", Username: "tester"},
+			}}
+
+			cmd.Execute(session.Session, m, tt.args)
+
+			if len(tt.client.cities) != 1 || tt.client.cities[0] != tt.wantCity {
+				t.Errorf("asked for %q, want %q", tt.client.cities, tt.wantCity)
+			}
+			sends := session.sends()
+			if len(sends) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(sends))
+			}
+			if tt.wantEmbed != "" {
+				if len(sends[0].Embeds) != 1 || sends[0].Embeds[0].Title != tt.wantEmbed {
+					t.Errorf("embeds = %+v, want one titled %q", sends[0].Embeds, tt.wantEmbed)
+				}
+			}
+			if !strings.Contains(sends[0].Content, tt.wantText) {
+				t.Errorf("sent %q, want it to contain %q", sends[0].Content, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestWeatherCommandWithoutClient(t *testing.T) {
+	session := newRecordingSession(t)
+	cmd := NewWeatherCommand(zap.NewNop(), nil, "Seoul", staticPrefixes(nil))
+	m := &discordgo.MessageCreate{Message: &discordgo.Message{ChannelID: "channel-1", Author: &discordgo.User{ID: "user-1"}}}
+
+	cmd.Execute(session.Session, m, nil)
+
+	if messages := session.messages(); len(messages) != 1 || !strings.Contains(messages[0], "설정되지 않았습니다") {
+		t.Errorf("sent %q, want the not configured notice", messages)
+	}
+}
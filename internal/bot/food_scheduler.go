@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// foodScheduler는 매일 정해진 시간에 점심 메뉴 추천을 채널에 게시합니다
+type foodScheduler struct {
+	session      *discordgo.Session
+	repo         *storage.FoodRepository
+	log          *zap.Logger
+	channelID    string
+	hour         int
+	minute       int
+	skipWeekends bool
+}
+
+// newFoodScheduler는 새로운 점심 추천 스케줄러를 생성합니다.
+// at은 "15:04" 형식의 게시 시간입니다.
+func newFoodScheduler(session *discordgo.Session, db *storage.MongoDB, log *zap.Logger, channelID string, at time.Time, skipWeekends bool) *foodScheduler {
+	return &foodScheduler{
+		session:      session,
+		repo:         storage.NewFoodRepository(db, log),
+		log:          log.Named("food-scheduler"),
+		channelID:    channelID,
+		hour:         at.Hour(),
+		minute:       at.Minute(),
+		skipWeekends: skipWeekends,
+	}
+}
+
+// Run은 컨텍스트가 취소될 때까지 예약된 시간마다 점심 추천을 게시합니다
+func (f *foodScheduler) Run(ctx context.Context) {
+	for {
+		next := nextDailyFireTime(time.Now(), f.hour, f.minute, f.skipWeekends)
+		f.log.Info("다음 점심 추천 예약", zap.Time("at", next))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			f.post(ctx)
+		case <-ctx.Done():
+			timer.Stop()
+			f.log.Info("점심 추천 스케줄러 종료")
+			return
+		}
+	}
+}
+
+// post는 무작위 점심 메뉴를 골라 채널에 게시합니다
+func (f *foodScheduler) post(ctx context.Context) {
+	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	food, err := f.repo.GetRandomFood(queryCtx, f.guildID(), models.FoodTypeLunch)
+	if errors.Is(err, storage.ErrNoFoods) {
+		f.log.Info("등록된 점심 메뉴가 없어 자동 추천을 건너뜁니다")
+		return
+	}
+	if err != nil {
+		f.log.Error("점심 추천 조회 실패", zap.Error(err))
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "오늘의 점심 메뉴 추천",
+		Description: "오늘 점심은 **" + models.EscapeDiscord(food.Name) + "** 어떠세요?",
+		Color:       0xFF9900, // Orange
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	_, err = f.session.ChannelMessageSendComplex(f.channelID, &discordgo.MessageSend{
+		Embed: embed,
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Parse: []discordgo.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		f.log.Error("점심 추천 게시 실패", zap.Error(err), zap.String("channel_id", f.channelID))
+	}
+}
+
+// guildID는 게시 채널이 속한 서버의 ID를 반환합니다. 채널을 찾을 수 없거나
+// DM 채널이면 공용 메뉴 목록을 쓰도록 빈 문자열을 반환합니다.
+func (f *foodScheduler) guildID() string {
+	channel, err := f.session.State.Channel(f.channelID)
+	if err != nil {
+		channel, err = f.session.Channel(f.channelID)
+	}
+	if err != nil {
+		f.log.Warn("점심 추천 채널 조회 실패, 공용 메뉴 목록을 사용합니다", zap.Error(err), zap.String("channel_id", f.channelID))
+		return ""
+	}
+	return channel.GuildID
+}
+
+// nextDailyFireTime returns the first time strictly after now that falls on
+// hour:minute in now's location. When skipWeekends is set, Saturdays and
+// Sundays are skipped.
+func nextDailyFireTime(now time.Time, hour, minute int, skipWeekends bool) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	for skipWeekends && (next.Weekday() == time.Saturday || next.Weekday() == time.Sunday) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
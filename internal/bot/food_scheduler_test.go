@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextDailyFireTime(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		skipWeekends bool
+		want         time.Time
+	}{
+		{"later today", at(14, 9, 0), false, at(14, 11, 30)},
+		{"exactly at the time", at(14, 11, 30), false, at(15, 11, 30)},
+		{"after the time", at(14, 12, 0), false, at(15, 11, 30)},
+		{"friday evening keeps saturday", at(16, 18, 0), false, at(17, 11, 30)},
+		{"friday evening skips the weekend", at(16, 18, 0), true, at(19, 11, 30)},
+		{"saturday skips to monday", at(17, 9, 0), true, at(19, 11, 30)},
+		{"friday morning", at(16, 9, 0), true, at(16, 11, 30)},
+		{"end of month", time.Date(2026, time.October, 31, 12, 0, 0, 0, kst), false, time.Date(2026, time.November, 1, 11, 30, 0, 0, kst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextDailyFireTime(tt.now, 11, 30, tt.skipWeekends)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextDailyFireTime(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// reminderPollInterval는 만기된 리마인더를 확인하는 주기입니다
+	reminderPollInterval = 30 * time.Second
+	// reminderBatchSize는 한 번에 처리할 최대 리마인더 수입니다
+	reminderBatchSize = 50
+)
+
+// reminderScheduler는 만기된 리마인더를 주기적으로 찾아 사용자에게 알립니다.
+// 리마인더는 MongoDB에 저장되어 있으므로 재시작 후에도 다음 폴링에서 그대로 발송됩니다.
+type reminderScheduler struct {
+	session *discordgo.Session
+	db      *storage.MongoDB
+	repo    *storage.ReminderRepository
+	log     *zap.Logger
+}
+
+// newReminderScheduler는 새로운 리마인더 스케줄러를 생성합니다
+func newReminderScheduler(session *discordgo.Session, db *storage.MongoDB, log *zap.Logger) *reminderScheduler {
+	return &reminderScheduler{
+		session: session,
+		db:      db,
+		repo:    storage.NewReminderRepository(db, log),
+		log:     log.Named("reminder-scheduler"),
+	}
+}
+
+// Run은 컨텍스트가 취소될 때까지 리마인더를 폴링합니다
+func (r *reminderScheduler) Run(ctx context.Context) {
+	indexCtx, cancel := r.db.OperationContext(ctx)
+	if err := r.repo.EnsureIndexes(indexCtx); err != nil {
+		r.log.Warn("리마인더 인덱스 생성 실패", zap.Error(err))
+	}
+	cancel()
+
+	ticker := time.NewTicker(reminderPollInterval)
+	defer ticker.Stop()
+
+	// 시작 직후 재시작 동안 밀린 리마인더부터 처리
+	r.fireDue(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			r.fireDue(ctx)
+		case <-ctx.Done():
+			r.log.Info("리마인더 스케줄러 종료")
+			return
+		}
+	}
+}
+
+// fireDue는 만기된 리마인더를 발송하고 발송 완료로 표시합니다.
+// 발송에 실패한 리마인더는 간격을 늘려 가며 다시 시도하고, ReminderMaxAttempts번 실패하면 포기합니다.
+func (r *reminderScheduler) fireDue(ctx context.Context) {
+	queryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	reminders, err := r.repo.GetDueReminders(queryCtx, time.Now(), reminderBatchSize)
+	if err != nil {
+		r.log.Error("만기된 리마인더 조회 실패", zap.Error(err))
+		return
+	}
+
+	for _, reminder := range reminders {
+		_, err := r.session.ChannelMessageSendComplex(reminder.ChannelID, &discordgo.MessageSend{
+			Content: fmt.Sprintf("⏰ <@%s> 리마인더: %s", reminder.UserID, reminder.Message),
+			AllowedMentions: &discordgo.MessageAllowedMentions{
+				Parse: []discordgo.AllowedMentionType{},
+				Users: []string{reminder.UserID},
+			},
+		})
+		if err != nil {
+			r.log.Error("리마인더 발송 실패",
+				zap.Error(err),
+				zap.String("reminder_id", reminder.ID.Hex()),
+				zap.String("channel_id", reminder.ChannelID),
+				zap.Int("attempt", reminder.Attempts+1))
+			r.recordFailure(queryCtx, reminder)
+			continue
+		}
+
+		if err := r.repo.MarkFired(queryCtx, reminder.ID); err != nil {
+			r.log.Error("리마인더 발송 완료 표시 실패",
+				zap.Error(err),
+				zap.String("reminder_id", reminder.ID.Hex()))
+		}
+	}
+}
+
+// recordFailure는 리마인더의 발송 실패를 기록합니다. 재시도는 fire_at을 뒤로 미뤄
+// 계속 실패하는 리마인더가 새로 만기된 리마인더의 발송을 막지 않도록 합니다.
+func (r *reminderScheduler) recordFailure(ctx context.Context, reminder models.Reminder) {
+	attempts := reminder.Attempts + 1
+	if attempts >= models.ReminderMaxAttempts {
+		r.log.Warn("리마인더 발송 포기",
+			zap.String("reminder_id", reminder.ID.Hex()),
+			zap.Int("attempts", attempts))
+		if err := r.repo.MarkFailed(ctx, reminder.ID, attempts); err != nil {
+			r.log.Error("리마인더 발송 실패 표시 실패",
+				zap.Error(err),
+				zap.String("reminder_id", reminder.ID.Hex()))
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(models.ReminderRetryDelay(attempts))
+	if err := r.repo.ScheduleRetry(ctx, reminder.ID, attempts, retryAt); err != nil {
+		r.log.Error("리마인더 재시도 예약 실패",
+			zap.Error(err),
+			zap.String("reminder_id", reminder.ID.Hex()))
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// channelSession returns a Discord session whose message sends succeed,
+// except in the channel named broken
+func channelSession(t *testing.T) *discordgo.Session {
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.Contains(r.URL.Path, "/channels/broken/") {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader(`{"code": 50001, "message": "Missing Access"}`)),
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"id": "message-1"}`))),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+		}, nil
+	})}
+	return session
+}
+
+func TestFireDueRetriesFailedReminders(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("fire", func(mt *mtest.T) {
+		db := storage.NewMongoDBFromClient(mt.Client, &config.Config{MongoDBName: "test", DBOperationTimeoutSeconds: 10}, zap.NewNop())
+		r := newReminderScheduler(channelSession(mt.T), db, zap.NewNop())
+
+		reminder := func(channelID string, attempts int) bson.D {
+			return bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "user_id", Value: "user-1"},
+				{Key: "channel_id", Value: channelID},
+				{Key: "message", Value: "회의"},
+				{Key: "fire_at", Value: time.Now().Add(-time.Minute)},
+				{Key: "fired", Value: false},
+				{Key: "attempts", Value: attempts},
+			}
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.reminders", mtest.FirstBatch,
+				reminder("broken", 0),
+				reminder("channel-1", 0),
+				reminder("broken", 4),
+			),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		start := time.Now()
+		r.fireDue(context.Background())
+
+		var sets []bson.Raw
+		for _, event := range mt.GetAllStartedEvents() {
+			if event.CommandName == "update" {
+				sets = append(sets, event.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document())
+			}
+		}
+		if len(sets) != 3 {
+			mt.Fatalf("sent %d updates, want 3", len(sets))
+		}
+
+		// The first failure is retried later, behind the reminders due now
+		if got := sets[0].Lookup("attempts").AsInt64(); got != 1 {
+			mt.Errorf("attempts = %d, want 1", got)
+		}
+		if fireAt := sets[0].Lookup("fire_at").Time(); !fireAt.After(start) {
+			mt.Errorf("retry fire_at = %v, want after %v", fireAt, start)
+		}
+		// A failure doesn't hold up the next reminder
+		if fired, ok := sets[1].Lookup("fired").BooleanOK(); !ok || !fired {
+			mt.Errorf("second reminder update = %v, want fired", sets[1])
+		}
+		// The last allowed attempt gives up
+		if failed, ok := sets[2].Lookup("failed").BooleanOK(); !ok || !failed {
+			mt.Errorf("third reminder update = %v, want failed", sets[2])
+		}
+	})
+}
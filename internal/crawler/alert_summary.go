@@ -0,0 +1,192 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// alertSummaryMatchLimit는 사용자 한 명의 요약에 포함할 최대 일치 수입니다
+	alertSummaryMatchLimit = 50
+	// alertSummaryMaxEmbeds는 요약 DM 한 건의 최대 임베드 수입니다
+	alertSummaryMaxEmbeds = 3
+)
+
+// AlertSummary DMs each user with daily or weekly summary mode alerts the
+// deals their alerts matched since the last summary, instead of real-time pings
+type AlertSummary struct {
+	sender  DMSender
+	repo    *storage.AlertMatchRepository
+	log     *zap.Logger
+	weekday time.Weekday
+	hour    int
+	minute  int
+}
+
+// NewAlertSummary creates a summary job sending daily summaries every day at
+// the given time of day, and weekly summaries on weekday at the same time
+func NewAlertSummary(sender DMSender, db *storage.MongoDB, log *zap.Logger, weekday time.Weekday, at time.Time) *AlertSummary {
+	return &AlertSummary{
+		sender:  sender,
+		repo:    storage.NewAlertMatchRepository(db, log),
+		log:     log.Named("alert-summary"),
+		weekday: weekday,
+		hour:    at.Hour(),
+		minute:  at.Minute(),
+	}
+}
+
+// Run sends the summaries on schedule until the context is canceled
+func (a *AlertSummary) Run(ctx context.Context) {
+	for {
+		next := nextDailyFireTime(time.Now(), a.hour, a.minute)
+		a.log.Info("Next alert summary scheduled", zap.Time("at", next))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			// Windows end at the scheduled time, so consecutive summaries tile exactly
+			if err := a.Send(ctx, models.SummaryDaily, next); err != nil {
+				a.log.Error("Failed to send daily alert summaries", zap.Error(err))
+			}
+			if next.Weekday() == a.weekday {
+				if err := a.Send(ctx, models.SummaryWeekly, next); err != nil {
+					a.log.Error("Failed to send weekly alert summaries", zap.Error(err))
+				}
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			a.log.Info("Stopping alert summaries")
+			return
+		}
+	}
+}
+
+// Send DMs every user with matches of the given summary mode in the window
+// ending at end. A user whose DM fails is logged and skipped.
+func (a *AlertSummary) Send(ctx context.Context, mode string, end time.Time) error {
+	start := end.Add(-summaryWindow(mode))
+
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	summaries, err := a.repo.SummaryMatches(queryCtx, mode, start, end, alertSummaryMatchLimit)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, summary := range summaries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		summaryEmbeds := buildAlertSummaryEmbeds(summary, mode, start, end)
+		if err := a.sender.SendDMEmbeds(ctx, summary.UserID, summaryEmbeds); err != nil {
+			a.log.Warn("Failed to send alert summary",
+				zap.Error(err),
+				zap.String("user_id", summary.UserID),
+				zap.String("mode", mode))
+			continue
+		}
+		sent++
+	}
+
+	a.log.Info("Sent alert summaries",
+		zap.String("mode", mode),
+		zap.Int("users", len(summaries)),
+		zap.Int("sent", sent))
+	return nil
+}
+
+// summaryWindow returns how far back a summary of mode looks
+func summaryWindow(mode string) time.Duration {
+	if mode == models.SummaryWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// buildAlertSummaryEmbeds renders a user's matches as summary pages. A deal
+// matched by several of the user's alerts is listed once with every keyword.
+func buildAlertSummaryEmbeds(summary storage.UserMatchSummary, mode string, from, to time.Time) []*discordgo.MessageEmbed {
+	var urls []string
+	titles := make(map[string]string)
+	keywords := make(map[string][]string)
+	for _, match := range summary.Matches {
+		if _, ok := titles[match.ProductURL]; !ok {
+			urls = append(urls, match.ProductURL)
+			titles[match.ProductURL] = match.ProductTitle
+		}
+		if !containsString(keywords[match.ProductURL], match.Keyword) {
+			keywords[match.ProductURL] = append(keywords[match.ProductURL], match.Keyword)
+		}
+	}
+
+	lines := make([]string, 0, len(urls))
+	for i, url := range urls {
+		escaped := make([]string, 0, len(keywords[url]))
+		for _, keyword := range keywords[url] {
+			escaped = append(escaped, models.EscapeDiscord(keyword))
+		}
+		lines = append(lines, fmt.Sprintf("**%d.** [%s](%s)\n🔔 %s",
+			i+1,
+			embeds.Truncate(models.SanitizeTitle(titles[url]), 100),
+			url,
+			strings.Join(escaped, ", ")))
+	}
+
+	title := "📬 오늘의 키워드 알림 요약"
+	if mode == models.SummaryWeekly {
+		title = "📬 이번 주 키워드 알림 요약"
+	}
+	footer := fmt.Sprintf("%s ~ %s · 일치 %d건", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"), summary.Total)
+	if summary.Total > len(summary.Matches) {
+		footer += fmt.Sprintf(" (최근 %d건만 표시)", len(summary.Matches))
+	}
+
+	pages := embeds.ChunkWithLimit(lines, "\n", embeds.DescriptionLimit, alertSummaryMaxEmbeds)
+	summaryEmbeds := make([]*discordgo.MessageEmbed, 0, len(pages))
+	for i, page := range pages {
+		pageTitle := title
+		if len(pages) > 1 {
+			pageTitle = fmt.Sprintf("%s (%d/%d)", title, i+1, len(pages))
+		}
+		summaryEmbeds = append(summaryEmbeds, &discordgo.MessageEmbed{
+			Title:       pageTitle,
+			Description: page,
+			Color:       0x3498DB, // Blue
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: footer,
+			},
+			Timestamp: to.Format(time.RFC3339),
+		})
+	}
+	return summaryEmbeds
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDailyFireTime returns the first time strictly after now at hour:minute in now's location
+func nextDailyFireTime(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
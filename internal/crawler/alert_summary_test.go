@@ -0,0 +1,189 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// dmRecorder records the summary DMs sent to each user, failing for users in fail
+type dmRecorder struct {
+	mu   sync.Mutex
+	fail map[string]bool
+	sent map[string][]*discordgo.MessageEmbed
+}
+
+func (r *dmRecorder) SendDMEmbeds(ctx context.Context, userID string, embeds []*discordgo.MessageEmbed) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail[userID] {
+		return errors.New("cannot send messages to this user")
+	}
+	if r.sent == nil {
+		r.sent = make(map[string][]*discordgo.MessageEmbed)
+	}
+	r.sent[userID] = embeds
+	return nil
+}
+
+func TestBuildAlertSummaryEmbeds(t *testing.T) {
+	to := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	from := to.Add(-summaryWindow(models.SummaryDaily))
+
+	summary := storage.UserMatchSummary{
+		UserID: "user-1",
+		Total:  60,
+		Matches: []models.AlertMatch{
+			{Keyword: "rtx", ProductURL: "https://example.com/deal/1", ProductTitle: "RTX 4090 특가"},
+			{Keyword: "4090", ProductURL: "https://example.com/deal/1", ProductTitle: "RTX 4090 특가"},
+			{Keyword: "rtx", ProductURL: "https://example.com/deal/1", ProductTitle: "RTX 4090 특가"},
+			{Keyword: "*모니터*", ProductURL: "https://example.com/deal/2", ProductTitle: "27인치 모니터"},
+		},
+	}
+
+	pages := buildAlertSummaryEmbeds(summary, models.SummaryDaily, from, to)
+
+	if len(pages) != 1 {
+		t.Fatalf("got %d pages, want 1", len(pages))
+	}
+	page := pages[0]
+	if page.Title != "📬 오늘의 키워드 알림 요약" {
+		t.Errorf("title = %q, want the daily title", page.Title)
+	}
+	want := "**1.** [RTX 4090 특가](https://example.com/deal/1)\n🔔 rtx, 4090\n" +
+		"**2.** [27인치 모니터](https://example.com/deal/2)\n🔔 \\*모니터\\*"
+	if page.Description != want {
+		t.Errorf("description = %q, want %q", page.Description, want)
+	}
+	if page.Footer.Text != "2026-10-15 09:00 ~ 2026-10-16 09:00 · 일치 60건 (최근 4건만 표시)" {
+		t.Errorf("footer = %q, want the window and the truncated total", page.Footer.Text)
+	}
+
+	weekly := buildAlertSummaryEmbeds(summary, models.SummaryWeekly, from, to)
+	if len(weekly) != 1 || weekly[0].Title != "📬 이번 주 키워드 알림 요약" {
+		t.Errorf("weekly summary = %+v, want the weekly title", weekly)
+	}
+}
+
+func TestBuildAlertSummaryEmbedsPages(t *testing.T) {
+	to := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	summary := storage.UserMatchSummary{UserID: "user-1", Total: alertSummaryMatchLimit}
+	for i := 0; i < alertSummaryMatchLimit; i++ {
+		summary.Matches = append(summary.Matches, models.AlertMatch{
+			Keyword:      "모니터",
+			ProductURL:   fmt.Sprintf("https://example.com/deal/%d", i),
+			ProductTitle: strings.Repeat("아주 긴 모니터 특가 제목 ", 6),
+		})
+	}
+
+	pages := buildAlertSummaryEmbeds(summary, models.SummaryDaily, to.Add(-24*time.Hour), to)
+
+	if len(pages) < 2 || len(pages) > alertSummaryMaxEmbeds {
+		t.Fatalf("got %d pages, want between 2 and %d", len(pages), alertSummaryMaxEmbeds)
+	}
+	for i, page := range pages {
+		if n := utf8.RuneCountInString(page.Description); n > embeds.DescriptionLimit {
+			t.Errorf("page %d description is %d characters, want at most %d", i+1, n, embeds.DescriptionLimit)
+		}
+	}
+	if want := fmt.Sprintf("📬 오늘의 키워드 알림 요약 (1/%d)", len(pages)); pages[0].Title != want {
+		t.Errorf("first title = %q, want the page number", pages[0].Title)
+	}
+}
+
+func TestAlertSummarySend(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("sends each user their digest", func(mt *mtest.T) {
+		sender := &dmRecorder{fail: map[string]bool{"user-blocked": true}}
+		summary := &AlertSummary{
+			sender: sender,
+			repo:   storage.NewAlertMatchRepository(newMockDB(mt, &config.Config{}), zap.NewNop()),
+			log:    zap.NewNop(),
+		}
+		match := func(keyword, url string) bson.D {
+			return bson.D{
+				{Key: "keyword", Value: keyword},
+				{Key: "product_url", Value: url},
+				{Key: "product_title", Value: "특가 " + keyword},
+				{Key: "summary_mode", Value: models.SummaryDaily},
+			}
+		}
+		mt.AddMockResponses(cursorResponse(
+			bson.D{
+				{Key: "_id", Value: "user-blocked"},
+				{Key: "total", Value: 1},
+				{Key: "matches", Value: bson.A{match("ssd", "https://example.com/deal/3")}},
+			},
+			bson.D{
+				{Key: "_id", Value: "user-1"},
+				{Key: "total", Value: 2},
+				{Key: "matches", Value: bson.A{match("rtx", "https://example.com/deal/1"), match("모니터", "https://example.com/deal/2")}},
+			},
+		))
+
+		end := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+		if err := summary.Send(context.Background(), models.SummaryDaily, end); err != nil {
+			mt.Fatalf("Send() error = %v", err)
+		}
+
+		aggregates := startedCommands(mt, "aggregate")
+		if len(aggregates) != 1 {
+			mt.Fatalf("sent %d aggregates, want 1", len(aggregates))
+		}
+		filter := aggregates[0].Lookup("pipeline", "0", "$match")
+		if mode := filter.Document().Lookup("summary_mode").StringValue(); mode != models.SummaryDaily {
+			mt.Errorf("matched summary_mode %q, want daily", mode)
+		}
+		if start := filter.Document().Lookup("matched_at", "$gte").Time(); !start.Equal(end.Add(-24 * time.Hour)) {
+			mt.Errorf("window starts at %v, want a day before %v", start, end)
+		}
+
+		if len(sender.sent) != 1 {
+			mt.Fatalf("sent digests to %d users, want 1", len(sender.sent))
+		}
+		digest := sender.sent["user-1"]
+		if len(digest) != 1 || !strings.Contains(digest[0].Description, "**2.** [특가 모니터](https://example.com/deal/2)") {
+			mt.Errorf("user-1 digest = %+v, want both matches", digest)
+		}
+	})
+}
+
+func TestNextDailyFireTime(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"before", at(16, 8, 0), at(16, 9, 0)},
+		{"at the time", at(16, 9, 0), at(17, 9, 0)},
+		{"after", at(16, 21, 30), at(17, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextDailyFireTime(tt.now, 9, 0); !got.Equal(tt.want) {
+				t.Errorf("nextDailyFireTime(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
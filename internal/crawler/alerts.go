@@ -3,32 +3,106 @@ package crawler
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
+// alertIndexTTL is how long BuildIndex reuses the index it built. Alerts
+// rarely change during a run, so a run loads them about once; alerts added
+// in the meantime are matched from the next run on.
+const alertIndexTTL = time.Minute
+
 // AlertMatcher handles matching products with user alerts
 type AlertMatcher struct {
 	logger *zap.Logger
-	db     *MongoDB
+	db     *storage.MongoDB
+
+	// index caches the last built keyword index for alertIndexTTL
+	index     *KeywordIndex
+	indexedAt time.Time
+	indexMu   sync.Mutex
+
+	// lastNotified is when each alert last passed its cooldown in this process.
+	// The cached index can hold a last_notified older than this. cooldownHeld
+	// is the value lastNotified had before a match still being delivered.
+	defaultCooldown time.Duration
+	lastNotified    map[string]time.Time
+	cooldownHeld    map[string]time.Time
+	cooldownMu      sync.Mutex
+
+	// bestDeals is the run's best candidate for each best deal alert, and
+	// bestDealSent the day each one's deal of the day was sent in this process.
+	// Days are counted in location.
+	location     *time.Location
+	bestDeals    map[string]bestDealCandidate
+	bestDealSent map[string]string
+	bestDealMu   sync.Mutex
 }
 
 // NewAlertMatcher creates a new AlertMatcher
-func NewAlertMatcher(db *MongoDB, logger *zap.Logger) *AlertMatcher {
+func NewAlertMatcher(db *storage.MongoDB, logger *zap.Logger) *AlertMatcher {
 	return &AlertMatcher{
-		logger: logger.Named("alert-matcher"),
-		db:     db,
+		logger:       logger.Named("alert-matcher"),
+		db:           db,
+		lastNotified: make(map[string]time.Time),
+		cooldownHeld: make(map[string]time.Time),
+		location:     time.Local,
+		bestDeals:    make(map[string]bestDealCandidate),
+		bestDealSent: make(map[string]string),
 	}
 }
 
-// FindMatchingAlerts finds all alerts matching the given product
-func (m *AlertMatcher) FindMatchingAlerts(ctx context.Context, product models.Product) ([]models.KeywordAlert, error) {
-	// Get all active alerts
+// SetLocation sets the timezone whose days best deal alerts are counted in
+func (m *AlertMatcher) SetLocation(location *time.Location) {
+	m.bestDealMu.Lock()
+	defer m.bestDealMu.Unlock()
+	m.location = location
+}
+
+// SetDefaultCooldown sets the cooldown used by alerts that don't set their own
+func (m *AlertMatcher) SetDefaultCooldown(cooldown time.Duration) {
+	m.cooldownMu.Lock()
+	defer m.cooldownMu.Unlock()
+	m.defaultCooldown = cooldown
+}
+
+// BuildIndex returns a KeywordIndex over all active alerts. The index is
+// reused for alertIndexTTL, so calling it for every batch of a run loads the
+// alerts from the database only once.
+func (m *AlertMatcher) BuildIndex(ctx context.Context) (*KeywordIndex, error) {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+
+	if m.index != nil && time.Since(m.indexedAt) < alertIndexTTL {
+		return m.index, nil
+	}
+
+	index, err := m.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.index = index
+	m.indexedAt = time.Now()
+	return index, nil
+}
+
+// Invalidate drops the cached index, so the next BuildIndex reloads the
+// alerts. Call it after changing alerts in this process.
+func (m *AlertMatcher) Invalidate() {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	m.index = nil
+}
+
+// loadIndex loads all active alerts and builds a KeywordIndex over them
+func (m *AlertMatcher) loadIndex(ctx context.Context) (*KeywordIndex, error) {
 	collection := m.db.Collection("keyword_alerts")
 	filter := bson.M{"is_active": true}
 
@@ -45,63 +119,116 @@ func (m *AlertMatcher) FindMatchingAlerts(ctx context.Context, product models.Pr
 
 	m.logger.Debug("Retrieved active alerts", zap.Int("count", len(alerts)))
 
-	// Find matching alerts
-	var matches []models.KeywordAlert
-	var matchedKeywords []string
+	return NewKeywordIndex(alerts), nil
+}
 
-	// Create a normalized product title for case-insensitive search
-	normalizedTitle := strings.ToLower(product.Title)
-	
-	// Include other searchable fields
-	searchText := normalizedTitle
-	if product.Product != "" && product.Product != product.Title {
-		searchText += " " + strings.ToLower(product.Product)
-	}
-	if product.Category != "" {
-		searchText += " " + strings.ToLower(product.Category)
-	}
+// MatchProduct finds the alerts in index matching the given product and
+// records the matched keywords on the product. Alerts still in their cooldown
+// are left out, so a burst of deals for one keyword pings once, and best deal
+// alerts are held until TakeBestDeals at the end of the run. The cooldown of
+// the returned alerts is only reserved; SettleCooldowns must be called once
+// the product's notifications are done.
+func (m *AlertMatcher) MatchProduct(ctx context.Context, index *KeywordIndex, product models.Product) []models.KeywordAlert {
+	now := time.Now()
+	matches := m.applyCooldown(filterByMaxPrice(index.Match(product.SearchText()), &product), now)
+	matches = m.holdBestDeals(matches, product, now)
 
-	// Check each alert against the search text
-	for _, alert := range alerts {
-		// Normalize keyword for case-insensitive comparison
-		keyword := strings.ToLower(alert.Keyword)
-		
-		// Check if keyword is in search text
-		if strings.Contains(searchText, keyword) {
-			matches = append(matches, alert)
-			matchedKeywords = append(matchedKeywords, alert.Keyword)
-			
-			// Update the alert's last notification time
-			if err := m.updateAlertNotification(ctx, alert.ID); err != nil {
-				m.logger.Warn("Failed to update alert notification metadata",
-					zap.Error(err),
-					zap.String("alert_id", alert.ID))
-			}
-		}
+	var matchedKeywords []string
+	for _, alert := range matches {
+		matchedKeywords = append(matchedKeywords, alert.Keyword)
 	}
-	
+
 	// Update product with matched keywords
 	if len(matchedKeywords) > 0 {
 		productCollection := m.db.Collection("products")
-		if product.ID != "" {
-			_, err = productCollection.UpdateByID(ctx, 
-				product.ID, 
-				bson.M{"$set": bson.M{"keywords": matchedKeywords}})
-			
-			if err != nil {
-				m.logger.Warn("Failed to update product keywords",
-					zap.Error(err),
-					zap.String("product_id", product.ID))
-			}
+		_, err := productCollection.UpdateOne(ctx,
+			productDocumentFilter(product),
+			bson.M{"$set": bson.M{"keywords": matchedKeywords}})
+
+		if err != nil {
+			m.logger.Warn("Failed to update product keywords",
+				zap.Error(err),
+				zap.String("product_id", product.ID))
 		}
 	}
-	
+
 	m.logger.Info("Found matching alerts",
 		zap.Int("matches", len(matches)),
 		zap.Strings("keywords", matchedKeywords),
 		zap.String("product_title", product.Title))
-		
-	return matches, nil
+
+	return matches
+}
+
+// applyCooldown drops the alerts notified less than their cooldown before now
+// and reserves now as the last notification of the rest. Checking and
+// reserving under one lock lets only one of several concurrent matches through.
+func (m *AlertMatcher) applyCooldown(alerts []models.KeywordAlert, now time.Time) []models.KeywordAlert {
+	m.cooldownMu.Lock()
+	defer m.cooldownMu.Unlock()
+
+	var kept []models.KeywordAlert
+	for _, alert := range alerts {
+		// Summary mode alerts don't ping, so all their matches go into the summary,
+		// and best deal alerts already send once a day
+		cooldown := alert.Cooldown(m.defaultCooldown)
+		if cooldown <= 0 || alert.IsSummarized() || alert.IsBestDeal() {
+			kept = append(kept, alert)
+			continue
+		}
+
+		last := m.lastNotified[alert.ID]
+		if notified := time.Unix(alert.LastNotified, 0); alert.LastNotified > 0 && notified.After(last) {
+			last = notified
+		}
+		if now.Sub(last) < cooldown {
+			m.logger.Debug("Alert in cooldown, skipping",
+				zap.String("alert_id", alert.ID),
+				zap.String("keyword", alert.Keyword),
+				zap.Duration("cooldown", cooldown))
+			continue
+		}
+
+		m.cooldownHeld[alert.ID] = m.lastNotified[alert.ID]
+		m.lastNotified[alert.ID] = now
+		kept = append(kept, alert)
+	}
+	return kept
+}
+
+// SettleCooldowns ends the cooldowns reserved for a product's alerts once its
+// notifications are done. The alerts in delivered keep theirs and have their
+// notification metadata updated; the others get theirs back, so a failed send
+// doesn't silence the next match.
+func (m *AlertMatcher) SettleCooldowns(ctx context.Context, alerts []models.KeywordAlert, delivered map[string]bool) {
+	m.cooldownMu.Lock()
+	for _, alert := range alerts {
+		previous, held := m.cooldownHeld[alert.ID]
+		if !held {
+			continue
+		}
+		delete(m.cooldownHeld, alert.ID)
+		if delivered[alert.ID] {
+			continue
+		}
+		if previous.IsZero() {
+			delete(m.lastNotified, alert.ID)
+		} else {
+			m.lastNotified[alert.ID] = previous
+		}
+	}
+	m.cooldownMu.Unlock()
+
+	for _, alert := range alerts {
+		if !delivered[alert.ID] {
+			continue
+		}
+		if err := m.updateAlertNotification(ctx, alert.ID); err != nil {
+			m.logger.Warn("Failed to update alert notification metadata",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID))
+		}
+	}
 }
 
 // UpdateAlertNotification updates the alert's notification metadata
@@ -110,18 +237,9 @@ func (m *AlertMatcher) updateAlertNotification(ctx context.Context, alertID stri
 	if alertID == "" {
 		return nil
 	}
-	
+
 	collection := m.db.Collection("keyword_alerts")
-	
-	// Convert string ID to ObjectID if needed
-	var objectID interface{}
-	if primitive.IsValidObjectID(alertID) {
-		objID, _ := primitive.ObjectIDFromHex(alertID)
-		objectID = objID
-	} else {
-		objectID = alertID
-	}
-	
+
 	// Update LastNotified and increment NotifyCount
 	update := bson.M{
 		"$set": bson.M{
@@ -131,34 +249,78 @@ func (m *AlertMatcher) updateAlertNotification(ctx context.Context, alertID stri
 			"notify_count": 1,
 		},
 	}
-	
-	_, err := collection.UpdateByID(ctx, objectID, update)
+
+	_, err := collection.UpdateByID(ctx, alertDocumentID(alertID), update)
 	if err != nil {
 		return fmt.Errorf("failed to update alert notification: %w", err)
 	}
-	
+
 	return nil
 }
 
+// alertDocumentID converts an alert ID to the _id stored in keyword_alerts,
+// an ObjectID if it is valid hex and the string otherwise
+func alertDocumentID(alertID string) interface{} {
+	if objID, err := primitive.ObjectIDFromHex(alertID); err == nil {
+		return objID
+	}
+	return alertID
+}
+
 // GetAlertsByUser retrieves all active alerts for the specified user
 func (m *AlertMatcher) GetAlertsByUser(ctx context.Context, userID string) ([]models.KeywordAlert, error) {
 	collection := m.db.Collection("keyword_alerts")
 	filter := bson.M{
-		"user_id": userID,
+		"user_id":   userID,
 		"is_active": true,
 	}
-	
+
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get alerts for user: %w", err)
 	}
 	defer cursor.Close(ctx)
-	
+
 	var alerts []models.KeywordAlert
 	if err := cursor.All(ctx, &alerts); err != nil {
 		return nil, fmt.Errorf("failed to decode user alerts: %w", err)
 	}
-	
+
+	return alerts, nil
+}
+
+// GetAlertsByIDs retrieves the active alerts with the given IDs
+func (m *AlertMatcher) GetAlertsByIDs(ctx context.Context, alertIDs []string) ([]models.KeywordAlert, error) {
+	if len(alertIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]interface{}, 0, len(alertIDs))
+	for _, alertID := range alertIDs {
+		if objID, err := primitive.ObjectIDFromHex(alertID); err == nil {
+			ids = append(ids, objID)
+		} else {
+			ids = append(ids, alertID)
+		}
+	}
+
+	collection := m.db.Collection("keyword_alerts")
+	filter := bson.M{
+		"_id":       bson.M{"$in": ids},
+		"is_active": true,
+	}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts by ID: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []models.KeywordAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode alerts: %w", err)
+	}
+
 	return alerts, nil
 }
 
@@ -167,27 +329,27 @@ func (m *AlertMatcher) GetPopularAlerts(ctx context.Context, limit int) ([]model
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
-	
+
 	collection := m.db.Collection("keyword_alerts")
-	
+
 	// Find alerts with the highest notify_count
 	opts := options.Find().
-		SetSort(bson.D{{"notify_count", -1}}).
+		SetSort(bson.D{{Key: "notify_count", Value: -1}, {Key: "last_notified", Value: -1}}).
 		SetLimit(int64(limit))
-	
-	cursor, err := collection.Find(ctx, 
+
+	cursor, err := collection.Find(ctx,
 		bson.M{"is_active": true, "notify_count": bson.M{"$gt": 0}},
 		opts)
-	
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to find popular alerts: %w", err)
 	}
 	defer cursor.Close(ctx)
-	
+
 	var alerts []models.KeywordAlert
 	if err := cursor.All(ctx, &alerts); err != nil {
 		return nil, fmt.Errorf("failed to decode popular alerts: %w", err)
 	}
-	
+
 	return alerts, nil
-}
\ No newline at end of file
+}
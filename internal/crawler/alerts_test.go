@@ -0,0 +1,190 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func cooldownAlert() models.KeywordAlert {
+	return models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true}
+}
+
+func TestSettleCooldowns(t *testing.T) {
+	mt := newMockTest(t)
+	alerts := []models.KeywordAlert{cooldownAlert()}
+	now := time.Now()
+
+	mt.Run("undelivered", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		m.SetDefaultCooldown(time.Hour)
+
+		if got := m.applyCooldown(alerts, now); len(got) != 1 {
+			mt.Fatalf("applyCooldown() kept %d alerts, want 1", len(got))
+		}
+		if got := m.applyCooldown(alerts, now.Add(time.Minute)); len(got) != 0 {
+			mt.Errorf("applyCooldown() let a second match through while the first is being delivered")
+		}
+
+		m.SettleCooldowns(context.Background(), alerts, map[string]bool{})
+
+		if got := m.applyCooldown(alerts, now.Add(2*time.Minute)); len(got) != 1 {
+			mt.Errorf("applyCooldown() after a failed delivery kept %d alerts, want 1", len(got))
+		}
+		if got := len(startedCommands(mt, "update")); got != 0 {
+			mt.Errorf("sent %d alert updates for an undelivered match, want 0", got)
+		}
+	})
+
+	mt.Run("restores previous", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		m.SetDefaultCooldown(time.Hour)
+		previous := now.Add(-2 * time.Hour)
+		m.lastNotified["alert-1"] = previous
+
+		m.applyCooldown(alerts, now)
+		m.SettleCooldowns(context.Background(), alerts, nil)
+
+		if got := m.lastNotified["alert-1"]; !got.Equal(previous) {
+			mt.Errorf("lastNotified = %v, want the previous %v", got, previous)
+		}
+	})
+
+	mt.Run("delivered", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		m.SetDefaultCooldown(time.Hour)
+		mt.AddMockResponses(writeResponse(1))
+
+		m.applyCooldown(alerts, now)
+		m.SettleCooldowns(context.Background(), alerts, map[string]bool{"alert-1": true})
+
+		if got := m.applyCooldown(alerts, now.Add(time.Minute)); len(got) != 0 {
+			mt.Errorf("applyCooldown() after a delivery kept %d alerts, want 0", len(got))
+		}
+		updates := startedCommands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d alert updates, want 1", len(updates))
+		}
+		update := updates[0].Lookup("updates").Array().Index(0).Value().Document().Lookup("u").Document()
+		if _, err := update.LookupErr("$inc", "notify_count"); err != nil {
+			mt.Errorf("alert update %v doesn't count the notification", update)
+		}
+	})
+}
+
+func TestSendProductNotificationsReleasesCooldownOnFailure(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("send fails", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusInternalServerError)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		n.alertMatcher.SetDefaultCooldown(time.Hour)
+		mt.AddMockResponses(cursorResponse()) // channel snoozes
+
+		alerts := n.alertMatcher.applyCooldown([]models.KeywordAlert{cooldownAlert()}, time.Now())
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+		if err := n.sendProductNotifications(context.Background(), product, alerts); err == nil {
+			mt.Fatal("sendProductNotifications() error = nil, want the failed send")
+		}
+
+		if got := n.alertMatcher.applyCooldown(alerts, time.Now()); len(got) != 1 {
+			mt.Error("failed send left the alert in its cooldown")
+		}
+		if got := len(startedCommands(mt, "update")); got != 0 {
+			mt.Errorf("sent %d updates after a failed send, want 0", got)
+		}
+	})
+}
+
+func TestGetPopularAlerts(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("sorted and limited", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		mt.AddMockResponses(cursorResponse(
+			bson.D{{Key: "keyword", Value: "모니터"}, {Key: "notify_count", Value: 9}},
+			bson.D{{Key: "keyword", Value: "ssd"}, {Key: "notify_count", Value: 4}},
+		))
+
+		alerts, err := m.GetPopularAlerts(context.Background(), 2)
+		if err != nil {
+			mt.Fatalf("GetPopularAlerts() error = %v", err)
+		}
+		if len(alerts) != 2 || alerts[0].NotifyCount != 9 {
+			mt.Errorf("GetPopularAlerts() = %+v, want both alerts with their counts", alerts)
+		}
+
+		find := startedCommands(mt, "find")[0]
+		sort := find.Lookup("sort").Document()
+		first, _ := sort.IndexErr(0)
+		if first.Key() != "notify_count" || first.Value().AsInt64() != -1 {
+			mt.Errorf("sort = %v, want notify_count descending first", sort)
+		}
+		if got := find.Lookup("limit").AsInt64(); got != 2 {
+			mt.Errorf("limit = %d, want 2", got)
+		}
+		if _, err := find.Lookup("filter").Document().LookupErr("notify_count", "$gt"); err != nil {
+			mt.Error("filter includes alerts that never fired")
+		}
+	})
+}
+
+func TestBuildIndexReusesAlerts(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("cached within the TTL", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		alert := bson.D{{Key: "_id", Value: "alert-1"}, {Key: "keyword", Value: "모니터"}, {Key: "is_active", Value: true}}
+		mt.AddMockResponses(cursorResponse(alert), cursorResponse(alert))
+
+		for i := 0; i < 50; i++ {
+			index, err := m.BuildIndex(context.Background())
+			if err != nil {
+				mt.Fatalf("BuildIndex() error = %v", err)
+			}
+			if got := len(index.Match("27인치 모니터")); got != 1 {
+				mt.Fatalf("index matched %d alerts, want 1", got)
+			}
+		}
+		if got := len(startedCommands(mt, "find")); got != 1 {
+			mt.Errorf("50 builds sent %d alert queries, want 1", got)
+		}
+
+		// Changing alerts in this process reloads them on the next build
+		m.Invalidate()
+		if _, err := m.BuildIndex(context.Background()); err != nil {
+			mt.Fatalf("BuildIndex() error = %v", err)
+		}
+		if got := len(startedCommands(mt, "find")); got != 2 {
+			mt.Errorf("build after Invalidate sent %d alert queries in total, want 2", got)
+		}
+	})
+
+	mt.Run("reloaded after the TTL", func(mt *mtest.T) {
+		m := NewAlertMatcher(newMockDB(mt, &config.Config{}), zap.NewNop())
+		mt.AddMockResponses(cursorResponse(), cursorResponse())
+
+		if _, err := m.BuildIndex(context.Background()); err != nil {
+			mt.Fatalf("BuildIndex() error = %v", err)
+		}
+		m.indexedAt = time.Now().Add(-alertIndexTTL)
+		if _, err := m.BuildIndex(context.Background()); err != nil {
+			mt.Fatalf("BuildIndex() error = %v", err)
+		}
+		if got := len(startedCommands(mt, "find")); got != 2 {
+			mt.Errorf("sent %d alert queries, want a reload once the index expired", got)
+		}
+	})
+}
@@ -0,0 +1,246 @@
+package crawler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// apiDefaultLimit is the page size used when a request doesn't set limit
+	apiDefaultLimit = 20
+	// apiMaxLimit is the largest page size a request may ask for
+	apiMaxLimit = 100
+)
+
+// ProductLister lists crawled products for the API
+type ProductLister interface {
+	ListProducts(ctx context.Context, opts storage.ProductListOptions) ([]models.Product, error)
+}
+
+// AlertLister lists keyword alerts for the API
+type AlertLister interface {
+	ListAlerts(ctx context.Context, opts storage.AlertListOptions) ([]models.KeywordAlert, error)
+}
+
+// APIHandler serves the read-only JSON API under /api. Every request must
+// carry the configured API key in an X-API-Key or "Authorization: Bearer" header.
+type APIHandler struct {
+	products ProductLister
+	alerts   AlertLister
+	stats    func() CrawlerStats
+	apiKey   string
+	log      *zap.Logger
+}
+
+// NewAPIHandler creates an API handler reading from the given repositories
+func NewAPIHandler(products ProductLister, alerts AlertLister, stats func() CrawlerStats, apiKey string, log *zap.Logger) *APIHandler {
+	return &APIHandler{
+		products: products,
+		alerts:   alerts,
+		stats:    stats,
+		apiKey:   apiKey,
+		log:      log.Named("api"),
+	}
+}
+
+// Register adds the API routes to mux
+func (a *APIHandler) Register(mux *http.ServeMux) {
+	mux.Handle("GET /api/products", a.authorize(a.handleProducts))
+	mux.Handle("GET /api/alerts", a.authorize(a.handleAlerts))
+	mux.Handle("GET /api/stats", a.authorize(a.handleStats))
+}
+
+// authorize rejects requests without the API key
+func (a *APIHandler) authorize(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); key == "" && ok {
+			key = token
+		}
+
+		if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(a.apiKey)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, apiError{Error: "unauthorized"})
+			return
+		}
+		next(w, r)
+	})
+}
+
+// apiError is the body of an API error response
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// apiPage is the body of a paginated API response
+type apiPage struct {
+	Items  interface{} `json:"items"`
+	Count  int         `json:"count"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// apiProduct is the API representation of a product
+type apiProduct struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	Source       string    `json:"source"`
+	Category     string    `json:"category,omitempty"`
+	Price        int       `json:"price,omitempty"`
+	PriceString  string    `json:"price_string,omitempty"`
+	DiscountRate int       `json:"discount_rate,omitempty"`
+	Comments     int       `json:"comments"`
+	Views        int       `json:"views"`
+	ImageURL     string    `json:"image_url,omitempty"`
+	IsHot        bool      `json:"is_hot"`
+	CrawledAt    time.Time `json:"crawled_at"`
+}
+
+// apiAlert is the API representation of a keyword alert
+type apiAlert struct {
+	ID           string `json:"id"`
+	Keyword      string `json:"keyword"`
+	UserID       string `json:"user_id"`
+	ChannelID    string `json:"channel_id"`
+	GuildID      string `json:"guild_id"`
+	Scope        string `json:"scope"`
+	IsActive     bool   `json:"is_active"`
+	WholeWord    bool   `json:"whole_word"`
+	NotifyCount  int    `json:"notify_count"`
+	CreatedAt    int64  `json:"created_at"`
+	LastNotified int64  `json:"last_notified,omitempty"`
+}
+
+// handleProducts lists products, filtered by source and title query
+func (a *APIHandler) handleProducts(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	query := r.URL.Query()
+	products, err := a.products.ListProducts(r.Context(), storage.ProductListOptions{
+		Source: query.Get("source"),
+		Query:  query.Get("q"),
+		Sort:   query.Get("sort"),
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		a.writeListError(w, err)
+		return
+	}
+
+	items := make([]apiProduct, 0, len(products))
+	for _, p := range products {
+		items = append(items, apiProduct{
+			ID:           p.ID,
+			Title:        p.Title,
+			URL:          p.URL,
+			Source:       p.Source,
+			Category:     p.Category,
+			Price:        p.KOPrice,
+			PriceString:  p.PriceString,
+			DiscountRate: p.DiscountRate,
+			Comments:     p.Comments,
+			Views:        p.Views,
+			ImageURL:     p.ImageURL,
+			IsHot:        p.IsHot,
+			CrawledAt:    p.CrawledAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, apiPage{Items: items, Count: len(items), Limit: limit, Offset: offset})
+}
+
+// handleAlerts lists keyword alerts, optionally for a single user
+func (a *APIHandler) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := parsePage(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	query := r.URL.Query()
+	alerts, err := a.alerts.ListAlerts(r.Context(), storage.AlertListOptions{
+		UserID: query.Get("user_id"),
+		Sort:   query.Get("sort"),
+		Offset: offset,
+		Limit:  limit,
+	})
+	if err != nil {
+		a.writeListError(w, err)
+		return
+	}
+
+	items := make([]apiAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		scope := alert.Scope
+		if scope == "" {
+			scope = models.AlertScopeUser
+		}
+		items = append(items, apiAlert{
+			ID:           alert.ID,
+			Keyword:      alert.Keyword,
+			UserID:       alert.UserID,
+			ChannelID:    alert.ChannelID,
+			GuildID:      alert.GuildID,
+			Scope:        scope,
+			IsActive:     alert.IsActive,
+			WholeWord:    alert.WholeWord,
+			NotifyCount:  alert.NotifyCount,
+			CreatedAt:    alert.CreatedAt,
+			LastNotified: alert.LastNotified,
+		})
+	}
+	writeJSON(w, http.StatusOK, apiPage{Items: items, Count: len(items), Limit: limit, Offset: offset})
+}
+
+// handleStats reports crawler statistics
+func (a *APIHandler) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.stats())
+}
+
+// writeListError reports a repository error: a bad sort is the client's fault,
+// anything else is logged and hidden behind a 500
+func (a *APIHandler) writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, storage.ErrInvalidSort) {
+		writeJSON(w, http.StatusBadRequest, apiError{Error: err.Error()})
+		return
+	}
+
+	a.log.Error("API query failed", zap.Error(err))
+	writeJSON(w, http.StatusInternalServerError, apiError{Error: "internal server error"})
+}
+
+// parsePage reads the limit and offset query parameters
+func parsePage(r *http.Request) (limit, offset int, err error) {
+	query := r.URL.Query()
+
+	limit = apiDefaultLimit
+	if s := query.Get("limit"); s != "" {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > apiMaxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", apiMaxLimit)
+		}
+	}
+
+	if s := query.Get("offset"); s != "" {
+		offset, err = strconv.Atoi(s)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
@@ -0,0 +1,208 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"go.uber.org/zap"
+)
+
+const testAPIKey = "secret"
+
+// fakeProducts is a ProductLister returning fixed products
+type fakeProducts struct {
+	products []models.Product
+	err      error
+	got      storage.ProductListOptions
+}
+
+func (f *fakeProducts) ListProducts(ctx context.Context, opts storage.ProductListOptions) ([]models.Product, error) {
+	f.got = opts
+	return f.products, f.err
+}
+
+// fakeAlerts is an AlertLister returning fixed alerts
+type fakeAlerts struct {
+	alerts []models.KeywordAlert
+	err    error
+	got    storage.AlertListOptions
+}
+
+func (f *fakeAlerts) ListAlerts(ctx context.Context, opts storage.AlertListOptions) ([]models.KeywordAlert, error) {
+	f.got = opts
+	return f.alerts, f.err
+}
+
+// serveAPI sends a request with the API key to a mux serving api
+func serveAPI(api *APIHandler, target string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	api.Register(mux)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAPIAuthorize(t *testing.T) {
+	api := NewAPIHandler(&fakeProducts{}, &fakeAlerts{}, func() CrawlerStats { return CrawlerStats{} }, testAPIKey, zap.NewNop())
+	mux := http.NewServeMux()
+	api.Register(mux)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   int
+	}{
+		{name: "no key", want: http.StatusUnauthorized},
+		{name: "wrong key", header: "X-API-Key", value: "nope", want: http.StatusUnauthorized},
+		{name: "header key", header: "X-API-Key", value: testAPIKey, want: http.StatusOK},
+		{name: "bearer token", header: "Authorization", value: "Bearer " + testAPIKey, want: http.StatusOK},
+		{name: "bearer without prefix", header: "Authorization", value: testAPIKey, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIProducts(t *testing.T) {
+	products := &fakeProducts{products: []models.Product{
+		{ID: "1", Title: "모니터", URL: "https://example.com/1", Source: "ppomppu", KOPrice: 199000},
+	}}
+	api := NewAPIHandler(products, &fakeAlerts{}, nil, testAPIKey, zap.NewNop())
+
+	rec := serveAPI(api, "/api/products?source=ppomppu&q=%EB%AA%A8%EB%8B%88%ED%84%B0&sort=-views&limit=5&offset=10")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	want := storage.ProductListOptions{Source: "ppomppu", Query: "모니터", Sort: "-views", Offset: 10, Limit: 5}
+	if products.got != want {
+		t.Errorf("ListProducts options = %+v, want %+v", products.got, want)
+	}
+
+	var page struct {
+		Items  []apiProduct `json:"items"`
+		Count  int          `json:"count"`
+		Limit  int          `json:"limit"`
+		Offset int          `json:"offset"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if page.Count != 1 || page.Limit != 5 || page.Offset != 10 {
+		t.Errorf("page = count %d limit %d offset %d, want 1 5 10", page.Count, page.Limit, page.Offset)
+	}
+	if len(page.Items) != 1 || page.Items[0].Price != 199000 || page.Items[0].Title != "모니터" {
+		t.Errorf("items = %+v", page.Items)
+	}
+}
+
+func TestAPIProductsDefaultPage(t *testing.T) {
+	products := &fakeProducts{}
+	api := NewAPIHandler(products, &fakeAlerts{}, nil, testAPIKey, zap.NewNop())
+
+	rec := serveAPI(api, "/api/products")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if products.got.Limit != apiDefaultLimit || products.got.Offset != 0 {
+		t.Errorf("page = limit %d offset %d, want %d 0", products.got.Limit, products.got.Offset, apiDefaultLimit)
+	}
+	// An empty result is an empty list, not null
+	if !strings.Contains(rec.Body.String(), `"items":[]`) {
+		t.Errorf("body = %s, want an empty items list", rec.Body)
+	}
+}
+
+func TestAPIInvalidPage(t *testing.T) {
+	api := NewAPIHandler(&fakeProducts{}, &fakeAlerts{}, nil, testAPIKey, zap.NewNop())
+
+	for _, query := range []string{"limit=0", fmt.Sprintf("limit=%d", apiMaxLimit+1), "limit=abc", "offset=-1"} {
+		t.Run(query, func(t *testing.T) {
+			if rec := serveAPI(api, "/api/products?"+query); rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+		})
+	}
+}
+
+func TestAPIListErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		want     int
+		wantBody string
+	}{
+		{name: "invalid sort", err: fmt.Errorf("sort %q: %w", "price", storage.ErrInvalidSort), want: http.StatusBadRequest, wantBody: "price"},
+		{name: "database error", err: errors.New("connection refused by 10.0.0.5"), want: http.StatusInternalServerError, wantBody: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewAPIHandler(&fakeProducts{}, &fakeAlerts{err: tt.err}, nil, testAPIKey, zap.NewNop())
+
+			rec := serveAPI(api, "/api/alerts")
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want it to contain %q", rec.Body, tt.wantBody)
+			}
+			if strings.Contains(rec.Body.String(), "10.0.0.5") {
+				t.Errorf("body = %s leaks the database error", rec.Body)
+			}
+		})
+	}
+}
+
+func TestAPIAlerts(t *testing.T) {
+	alerts := &fakeAlerts{alerts: []models.KeywordAlert{
+		{ID: "a1", Keyword: "모니터", UserID: "user-1", IsActive: true},
+		{ID: "a2", Keyword: "키보드", UserID: "user-1", Scope: models.AlertScopeServer},
+	}}
+	api := NewAPIHandler(&fakeProducts{}, alerts, nil, testAPIKey, zap.NewNop())
+
+	rec := serveAPI(api, "/api/alerts?user_id=user-1&sort=keyword")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if alerts.got.UserID != "user-1" || alerts.got.Sort != "keyword" {
+		t.Errorf("ListAlerts options = %+v", alerts.got)
+	}
+
+	var page struct {
+		Items []apiAlert `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(page.Items))
+	}
+	if page.Items[0].Scope != models.AlertScopeUser || page.Items[1].Scope != models.AlertScopeServer {
+		t.Errorf("scopes = %q, %q, want the default user scope filled in", page.Items[0].Scope, page.Items[1].Scope)
+	}
+}
@@ -0,0 +1,131 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// BestDeal is the best product of a run for one or more best deal alerts
+type BestDeal struct {
+	Product models.Product
+	Alerts  []models.KeywordAlert
+}
+
+// bestDealCandidate is the best product seen so far in a run for one alert
+type bestDealCandidate struct {
+	alert   models.KeywordAlert
+	product models.Product
+}
+
+// filterByMaxPrice drops the alerts whose maximum price the product exceeds
+func filterByMaxPrice(alerts []models.KeywordAlert, product *models.Product) []models.KeywordAlert {
+	var kept []models.KeywordAlert
+	for _, alert := range alerts {
+		if alert.WithinMaxPrice(product) {
+			kept = append(kept, alert)
+		}
+	}
+	return kept
+}
+
+// holdBestDeals takes the best deal alerts out of alerts. Each is kept as a
+// candidate if product beats its current best of the run, unless its deal of
+// the day was already sent. The other alerts are returned.
+func (m *AlertMatcher) holdBestDeals(alerts []models.KeywordAlert, product models.Product, now time.Time) []models.KeywordAlert {
+	day := now.In(m.location).Format(models.BestDealDateLayout)
+
+	m.bestDealMu.Lock()
+	defer m.bestDealMu.Unlock()
+
+	var rest []models.KeywordAlert
+	for _, alert := range alerts {
+		if !alert.IsBestDeal() {
+			rest = append(rest, alert)
+			continue
+		}
+		if alert.BestDealDate == day || m.bestDealSent[alert.ID] == day {
+			continue
+		}
+		if best, ok := m.bestDeals[alert.ID]; ok && !alert.BetterDeal(&product, &best.product) {
+			continue
+		}
+		m.bestDeals[alert.ID] = bestDealCandidate{alert: alert, product: product}
+	}
+	return rest
+}
+
+// TakeBestDeals returns the best product of the run for each best deal alert
+// and starts a new run. Alerts sharing a best product are grouped, so each
+// product is sent once.
+func (m *AlertMatcher) TakeBestDeals() []BestDeal {
+	m.bestDealMu.Lock()
+	candidates := m.bestDeals
+	m.bestDeals = make(map[string]bestDealCandidate)
+	m.bestDealMu.Unlock()
+
+	byURL := make(map[string]*BestDeal)
+	var urls []string
+	for _, candidate := range candidates {
+		deal, ok := byURL[candidate.product.URL]
+		if !ok {
+			deal = &BestDeal{Product: candidate.product}
+			byURL[candidate.product.URL] = deal
+			urls = append(urls, candidate.product.URL)
+		}
+		deal.Alerts = append(deal.Alerts, candidate.alert)
+	}
+	sort.Strings(urls)
+
+	deals := make([]BestDeal, 0, len(urls))
+	for _, url := range urls {
+		deal := byURL[url]
+		sort.Slice(deal.Alerts, func(i, j int) bool { return deal.Alerts[i].ID < deal.Alerts[j].ID })
+		deals = append(deals, *deal)
+	}
+	return deals
+}
+
+// RecordBestDeal marks the deal of the day as sent for the deal's alerts, so
+// they match nothing else until the next day
+func (m *AlertMatcher) RecordBestDeal(ctx context.Context, deal BestDeal, now time.Time) {
+	day := now.In(m.location).Format(models.BestDealDateLayout)
+
+	m.bestDealMu.Lock()
+	for _, alert := range deal.Alerts {
+		m.bestDealSent[alert.ID] = day
+	}
+	m.bestDealMu.Unlock()
+
+	for _, alert := range deal.Alerts {
+		if err := m.updateBestDealDate(ctx, alert.ID, day); err != nil {
+			m.logger.Warn("Failed to record best deal date",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID))
+		}
+	}
+
+	m.logger.Info("Best deal of the day",
+		zap.String("product_title", deal.Product.Title),
+		zap.Int("price", deal.Product.KOPrice),
+		zap.Int("alerts", len(deal.Alerts)))
+}
+
+// updateBestDealDate stores the day the alert's deal of the day was sent
+func (m *AlertMatcher) updateBestDealDate(ctx context.Context, alertID, day string) error {
+	if alertID == "" {
+		return nil
+	}
+
+	collection := m.db.Collection("keyword_alerts")
+	_, err := collection.UpdateByID(ctx, alertDocumentID(alertID), bson.M{"$set": bson.M{"best_deal_date": day}})
+	if err != nil {
+		return fmt.Errorf("failed to update best deal date: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// channelPermissionTTL is how long a channel permission lookup is reused.
+// Each lookup costs several REST calls, so it is not repeated per product.
+const channelPermissionTTL = 10 * time.Minute
+
+// requiredSendPermissions are the permissions the bot needs to post a deal notification
+const requiredSendPermissions = discordgo.PermissionViewChannel |
+	discordgo.PermissionSendMessages |
+	discordgo.PermissionEmbedLinks
+
+// channelPermission is a cached result of a channel permission lookup
+type channelPermission struct {
+	canSend   bool
+	checkedAt time.Time
+}
+
+// hasSendPermissions reports whether perms allow posting deal notifications
+func hasSendPermissions(perms int64) bool {
+	return perms&discordgo.PermissionAdministrator != 0 ||
+		perms&requiredSendPermissions == requiredSendPermissions
+}
+
+// splitSendableChannels splits channel IDs into those the bot can send to and
+// those it can't. Channels whose permissions can't be determined are treated as
+// sendable, so a failed lookup never drops a notification that might succeed.
+func splitSendableChannels(channelIDs []string, canSend func(channelID string) (bool, error)) (sendable, denied []string) {
+	for _, channelID := range channelIDs {
+		ok, err := canSend(channelID)
+		if err == nil && !ok {
+			denied = append(denied, channelID)
+			continue
+		}
+		sendable = append(sendable, channelID)
+	}
+	return sendable, denied
+}
+
+// canSendToChannel reports whether the bot may post in channelID, caching the
+// result for channelPermissionTTL. Webhook delivery doesn't post as the bot, so
+// a channel is sendable if it has a webhook configured. DM channels have no
+// permissions and are always sendable.
+func (n *NotificationService) canSendToChannel(channelID string) (bool, error) {
+	if n.usesWebhooks() {
+		_, ok := n.config.WebhookURLs[channelID]
+		return ok, nil
+	}
+
+	guildID, err := n.channelGuildID(channelID)
+	if err != nil {
+		return false, err
+	}
+	if guildID == "" {
+		return true, nil
+	}
+
+	n.channelPermsMu.Lock()
+	cached, ok := n.channelPerms[channelID]
+	n.channelPermsMu.Unlock()
+	if ok && time.Since(cached.checkedAt) < channelPermissionTTL {
+		return cached.canSend, nil
+	}
+
+	botUserID, err := n.botUserID()
+	if err != nil {
+		return false, err
+	}
+
+	perms, err := n.session.UserChannelPermissions(botUserID, channelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get bot permissions in channel %s: %w", channelID, err)
+	}
+
+	canSend := hasSendPermissions(perms)
+	n.channelPermsMu.Lock()
+	n.channelPerms[channelID] = channelPermission{canSend: canSend, checkedAt: time.Now()}
+	n.channelPermsMu.Unlock()
+
+	return canSend, nil
+}
+
+// botUserID returns the bot's own user ID, fetching it once. The notification
+// session is never opened, so its state has no user.
+func (n *NotificationService) botUserID() (string, error) {
+	n.channelPermsMu.Lock()
+	defer n.channelPermsMu.Unlock()
+
+	if n.botID != "" {
+		return n.botID, nil
+	}
+
+	user, err := n.session.User("@me")
+	if err != nil {
+		return "", fmt.Errorf("failed to get bot user: %w", err)
+	}
+	n.botID = user.ID
+	return n.botID, nil
+}
+
+// sendableChannels drops the channels the bot can't post in. A channel is
+// logged only when it first becomes unsendable and again when it recovers,
+// not on every run, and its alerts are deactivated if configured.
+func (n *NotificationService) sendableChannels(ctx context.Context, channelIDs []string) []string {
+	sendable, denied := splitSendableChannels(channelIDs, n.canSendToChannel)
+
+	for _, channelID := range sendable {
+		if n.setChannelDenied(channelID, false) {
+			n.logger.Info("Bot can send to alert channel again", zap.String("channel_id", channelID))
+		}
+	}
+
+	for _, channelID := range denied {
+		if !n.setChannelDenied(channelID, true) {
+			continue
+		}
+		// A missing webhook is a configuration gap, not a reason to drop the alerts
+		if n.usesWebhooks() {
+			n.logger.Warn("No webhook configured for alert channel; skipping its notifications",
+				zap.String("channel_id", channelID))
+			continue
+		}
+		n.logger.Warn("Bot lacks permission to send to alert channel; skipping its notifications",
+			zap.String("channel_id", channelID))
+		if n.config.DeactivateUnsendableAlerts {
+			n.deactivateChannelAlerts(ctx, channelID)
+		}
+	}
+
+	return sendable
+}
+
+// setChannelDenied records whether a channel is unsendable and reports
+// whether that changed
+func (n *NotificationService) setChannelDenied(channelID string, denied bool) bool {
+	n.channelPermsMu.Lock()
+	defer n.channelPermsMu.Unlock()
+
+	if n.deniedChannels[channelID] == denied {
+		return false
+	}
+	if denied {
+		n.deniedChannels[channelID] = true
+	} else {
+		delete(n.deniedChannels, channelID)
+	}
+	return true
+}
+
+// deactivateChannelAlerts deactivates the alerts of a channel the bot can't
+// post in and tells each owner by DM, since they would otherwise never hear
+// about deals again without knowing why
+func (n *NotificationService) deactivateChannelAlerts(ctx context.Context, channelID string) {
+	alerts, err := n.alertRepo.DeactivateByChannel(ctx, channelID)
+	if err != nil {
+		n.logger.Error("Failed to deactivate alerts of unsendable channel",
+			zap.Error(err),
+			zap.String("channel_id", channelID))
+		return
+	}
+	n.alertMatcher.Invalidate()
+
+	n.logger.Warn("Deactivated alerts of unsendable channel",
+		zap.String("channel_id", channelID),
+		zap.Int("alerts", len(alerts)))
+
+	for userID, keywords := range keywordsByOwner(alerts) {
+		content := fmt.Sprintf("봇에 <#%s> 채널에 메시지를 보낼 권한이 없어 다음 키워드 알림을 비활성화했습니다: %s\n"+
+			"채널 권한을 확인한 뒤 알림을 다시 추가해주세요.",
+			channelID, strings.Join(keywords, ", "))
+		n.sendDM(userID, content)
+	}
+}
+
+// keywordsByOwner groups the keywords of alerts by the user who created them
+func keywordsByOwner(alerts []models.KeywordAlert) map[string][]string {
+	keywords := make(map[string][]string)
+	for _, alert := range alerts {
+		if alert.UserID == "" {
+			continue
+		}
+		keywords[alert.UserID] = append(keywords[alert.UserID], "**"+models.EscapeDiscord(alert.Keyword)+"**")
+	}
+	return keywords
+}
+
+// sendDM sends a direct message to a user. Users who block DMs are only logged.
+func (n *NotificationService) sendDM(userID, content string) {
+	channel, err := n.session.UserChannelCreate(userID)
+	if err != nil {
+		n.logger.Warn("Failed to open DM channel", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+
+	_, err = n.session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content: content,
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Parse: []discordgo.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		n.logger.Warn("Failed to send DM", zap.Error(err), zap.String("user_id", userID))
+	}
+}
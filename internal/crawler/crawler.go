@@ -20,12 +20,12 @@ type SourceInterface interface {
 
 // Crawler represents the web crawler
 type Crawler struct {
-	config    *config.Config
-	log       *zap.Logger
-	db        *storage.MongoDB
-	sources   []SourceInterface
-	notifier  *DiscordNotifier
-	client    *DiscordClient  // Legacy client for backward compatibility
+	config   *config.Config
+	log      *zap.Logger
+	db       *storage.MongoDB
+	sources  []SourceInterface
+	notifier Notifier
+	client   *DiscordClient // Legacy client for backward compatibility
 }
 
 // New creates a new crawler instance
@@ -35,38 +35,39 @@ func New(cfg *config.Config, log *zap.Logger) (*Crawler, error) {
 	if err != nil {
 		return nil, err
 	}
-	
-	// Create Discord notifier
-	notifier, err := NewDiscordNotifier(cfg, db, log)
+
+	// Create notifier
+	notifier, err := newNotifier(cfg, db, log)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Create legacy Discord client
 	client, err := NewDiscordClient(cfg.DiscordToken, cfg.ProductChannelID)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Create sources
 	ppomppu := sources.NewPpomppuCrawler(log)
-	
+	ppomppu.SetFetchLimits(time.Duration(cfg.FetchTimeoutSeconds)*time.Second, cfg.FetchMaxBodyBytes)
+
 	// TODO: Implement other sources
 	// quasarzone := sources.NewQuasarzoneCrawler(log)
-	
+
 	// Create crawler
 	crawler := &Crawler{
-		config:    cfg,
-		log:       log.Named("crawler"),
-		db:        db,
-		notifier:  notifier,
-		client:    client,
+		config:   cfg,
+		log:      log.Named("crawler"),
+		db:       db,
+		notifier: notifier,
+		client:   client,
 		sources: []SourceInterface{
 			ppomppu,
 			// quasarzone,
 		},
 	}
-	
+
 	return crawler, nil
 }
 
@@ -74,34 +75,38 @@ func New(cfg *config.Config, log *zap.Logger) (*Crawler, error) {
 // Fix for the race condition in the Run method
 func (c *Crawler) Run(ctx context.Context) error {
 	c.log.Info("Starting crawler run")
-	
+
 	// Create WaitGroup for parallelization
 	var wg sync.WaitGroup
-	
+
 	// Channel for products with sufficient buffer
 	productChan := make(chan models.Product, 1000)
-	
+
 	// Crawl all sources in parallel
 	for _, src := range c.sources {
 		wg.Add(1)
 		go func(source SourceInterface) {
 			defer wg.Done()
-			
+
 			c.log.Info("Crawling source", zap.String("source", source.Name()))
-			products, err := source.Crawl(ctx)
+			products, err := crawlSource(ctx, source, c.log)
 			if err != nil {
-				c.log.Error("Failed to crawl source", 
-					zap.String("source", source.Name()), 
+				c.log.Error("Failed to crawl source",
+					zap.String("source", source.Name()),
 					zap.Error(err))
 				return
 			}
-			
-			c.log.Info("Crawled source successfully", 
-				zap.String("source", source.Name()), 
+
+			c.log.Info("Crawled source successfully",
+				zap.String("source", source.Name()),
 				zap.Int("products", len(products)))
-			
+
 			// Send products to channel without mutex
 			for _, product := range products {
+				if !product.IsHot {
+					product.IsHot = product.MeetsHotThreshold(c.config.HotCommentThreshold, c.config.HotViewThreshold)
+				}
+
 				select {
 				case productChan <- product:
 					// Successfully sent product to channel
@@ -110,22 +115,22 @@ func (c *Crawler) Run(ctx context.Context) error {
 					return
 				}
 			}
-			
+
 		}(src)
 	}
-	
+
 	// Close channel when all sources are done
 	go func() {
 		wg.Wait()
 		close(productChan)
 	}()
-	
+
 	// Process products
 	var newProducts []models.Product
-	
+
 	// Create product collection
 	collection := c.db.Collection("products")
-	
+
 	// Process each product
 	for product := range productChan {
 		select {
@@ -137,83 +142,77 @@ func (c *Crawler) Run(ctx context.Context) error {
 			filter := map[string]interface{}{
 				"url": product.URL,
 			}
-			
+
 			count, err := collection.CountDocuments(ctx, filter)
 			if err != nil {
-				c.log.Error("Failed to check product existence", 
-					zap.Error(err), 
+				c.log.Error("Failed to check product existence",
+					zap.Error(err),
 					zap.String("url", product.URL))
 				continue
 			}
-			
+
 			if count > 0 {
 				c.log.Debug("Product already exists", zap.String("url", product.URL))
 				continue
 			}
-			
-			// Insert new product
-			_, err = collection.InsertOne(ctx, product)
+
+			// Derive the ID from the URL if not set, so the same deal always has the same ID
+			if product.ID == "" {
+				product.ID = models.ProductIDFromURL(product.URL)
+			}
+
+			// Insert new product, retrying transient errors
+			err = storage.WithRetry(ctx, func(ctx context.Context) error {
+				_, err := collection.InsertOne(ctx, product)
+				return err
+			})
 			if err != nil {
-				c.log.Error("Failed to insert product", 
-					zap.Error(err), 
+				c.log.Error("Failed to insert product",
+					zap.Error(err),
 					zap.String("title", product.Title))
 				continue
 			}
-			
-			c.log.Info("New product found", 
-				zap.String("title", product.Title), 
+
+			c.log.Info("New product found",
+				zap.String("title", product.Title),
 				zap.String("source", product.Source))
-			
+
 			newProducts = append(newProducts, product)
 		}
 	}
-	
+
 	// Send notifications for new products
 	if len(newProducts) > 0 {
 		c.log.Info("Sending notifications for new products", zap.Int("count", len(newProducts)))
-		
-		if err := c.notifier.SendProductNotifications(ctx, newProducts); err != nil {
+
+		if err := c.notifier.NotifyNewProducts(ctx, newProducts); err != nil {
 			c.log.Error("Failed to send notifications", zap.Error(err))
 		}
 	}
-	
+
+	// Best deal alerts are sent once, after the run's products
+	if sender, ok := c.notifier.(BestDealSender); ok {
+		if err := sender.SendBestDeals(ctx); err != nil {
+			c.log.Error("Failed to send best deal notifications", zap.Error(err))
+		}
+	}
+
 	c.log.Info("Crawler run completed", zap.Int("new_products", len(newProducts)))
 	return nil
 }
 
 // StartScheduledRuns starts periodic crawler runs
 func (c *Crawler) StartScheduledRuns(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-	
-	c.log.Info("Starting scheduled crawler runs", zap.Duration("interval", interval))
-	
-	// Run immediately
-	if err := c.Run(ctx); err != nil {
-		c.log.Error("Initial crawler run failed", zap.Error(err))
-	}
-	
-	// Then run on schedule
-	for {
-		select {
-		case <-ticker.C:
-			if err := c.Run(ctx); err != nil {
-				c.log.Error("Scheduled crawler run failed", zap.Error(err))
-			}
-		case <-ctx.Done():
-			c.log.Info("Stopping scheduled crawler runs")
-			return
-		}
-	}
+	runScheduled(ctx, c.log.With(zap.Duration("interval", interval)), interval, c.config.CrawlJitterPercent, c.Run)
 }
 
 // Close cleans up resources
 func (c *Crawler) Close() error {
 	c.notifier.Close()
-	
+
 	if err := c.db.Disconnect(); err != nil {
 		return err
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
@@ -2,16 +2,18 @@ package crawler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
 
+	"github.com/bradykim7/gbot/internal/crawler/fetch"
 	"github.com/bradykim7/gbot/internal/crawler/sources"
 	"github.com/bradykim7/gbot/internal/models"
 	"github.com/bradykim7/gbot/internal/storage"
 	"github.com/bradykim7/gbot/pkg/config"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
@@ -22,23 +24,30 @@ type ImprovedCrawler struct {
 	config       *config.Config
 	log          *zap.Logger
 	db           *storage.MongoDB
-	notifier     *NotificationService
+	notifier     Notifier
+	alerts       *AlertMatcher // StoreOnlyMatched일 때 저장할 상품을 거르는 데 사용
 	sources      []sources.Source
 	healthStatus map[string]bool
 	lastRun      time.Time
 	stats        CrawlerStats
 	statsMutex   sync.RWMutex
+	random       *lockedRand   // 스케줄 지터와 소스 분산에 사용
+	yields       *yieldTracker // statsMutex로 보호
 }
 
 // CrawlerStats tracks statistics about crawler operation
 type CrawlerStats struct {
-	TotalProducts      int       `json:"total_products"`
-	NewProducts        int       `json:"new_products"`
-	NotifiedProducts   int       `json:"notified_products"`
-	LastRun            time.Time `json:"last_run"`
-	RunCount           int       `json:"run_count"`
-	LastError          string    `json:"last_error,omitempty"`
-	SourceStats        map[string]SourceStats `json:"source_stats"`
+	TotalProducts       int                    `json:"total_products"`
+	NewProducts         int                    `json:"new_products"`
+	NotifiedProducts    int                    `json:"notified_products"`
+	LastRun             time.Time              `json:"last_run"`
+	RunCount            int                    `json:"run_count"`
+	SkippedRuns         int                    `json:"skipped_runs"` // 이전 실행이 끝나지 않아 건너뛴 횟수
+	DryRun              bool                   `json:"dry_run"`
+	WouldInsertProducts int                    `json:"would_insert_products,omitempty"` // dry-run: 저장했을 신규 상품 수
+	WouldNotifyProducts int                    `json:"would_notify_products,omitempty"` // dry-run: 알림을 보냈을 상품 수
+	LastError           string                 `json:"last_error,omitempty"`
+	SourceStats         map[string]SourceStats `json:"source_stats"`
 }
 
 // SourceStats tracks statistics for individual sources
@@ -47,7 +56,10 @@ type SourceStats struct {
 	LastRun         time.Time `json:"last_run"`
 	LastRunDuration string    `json:"last_run_duration"`
 	LastError       string    `json:"last_error,omitempty"`
-	SuccessRate     float64   `json:"success_rate"` // 0-1
+	SuccessRate     float64   `json:"success_rate"`  // 0-1
+	YieldAverage    float64   `json:"yield_average"` // 최근 실행의 평균 상품 수
+	LowYield        bool      `json:"low_yield"`     // 마지막 실행의 수집량이 평균보다 크게 적음
+	Capped          bool      `json:"capped"`        // 마지막 실행의 상품 수가 MaxProductsPerSource를 넘어 잘림
 }
 
 // NewImprovedCrawler creates a new crawler instance
@@ -57,137 +69,267 @@ func NewImprovedCrawler(cfg *config.Config, log *zap.Logger) (*ImprovedCrawler,
 	if err != nil {
 		return nil, err
 	}
-	
-	// Create notification service
-	notifier, err := NewNotificationService(cfg, db, log)
+
+	// Create notifier
+	notifier, err := newNotifier(cfg, db, log)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	// Initialize sources
 	ppomppu := sources.NewPpomppuCrawler(log)
-	
+	configureFetch(ppomppu, cfg, log)
+	crawlSources := []sources.Source{
+		ppomppu,
+		// quasarzone,
+	}
+
+	// Feed-based sources need no parser, only a name and URL in RSS_FEEDS
+	for _, feed := range cfg.RSSFeeds {
+		rss := sources.NewRSSSource(feed.Name, feed.URL, log)
+		configureFetch(rss, cfg, log)
+		crawlSources = append(crawlSources, rss)
+	}
+
+	// Selector sources scrape list pages with CSS selectors from SELECTOR_SOURCES_FILE
+	for _, selectorCfg := range cfg.SelectorSources {
+		selector, err := sources.NewSelectorSource(selectorCfg, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create selector source: %w", err)
+		}
+		configureFetch(selector, cfg, log)
+		crawlSources = append(crawlSources, selector)
+	}
+
 	// TODO: Add other sources
 	// quasarzone := sources.NewQuasarzoneCrawler(log)
-	
+
 	// Create crawler
 	crawler := &ImprovedCrawler{
-		config:   cfg,
-		log:      log.Named("improved-crawler"),
-		db:       db,
-		notifier: notifier,
-		sources: []sources.Source{
-			ppomppu,
-			// quasarzone,
-		},
+		config:       cfg,
+		log:          log.Named("improved-crawler"),
+		db:           db,
+		notifier:     notifier,
+		alerts:       NewAlertMatcher(db, log),
+		sources:      crawlSources,
 		healthStatus: make(map[string]bool),
+		random:       newLockedRand(time.Now().UnixNano()),
+		yields:       newYieldTracker(cfg.YieldWindowRuns, cfg.YieldDropPercent),
 		stats: CrawlerStats{
+			DryRun:      cfg.DryRun,
 			SourceStats: make(map[string]SourceStats),
 		},
 	}
-	
-	// Initialize database indices
-	if err := crawler.setupDatabaseIndices(context.Background()); err != nil {
-		log.Warn("Failed to set up database indices", zap.Error(err))
+
+	// Initialize database indices (dry-run never modifies the database)
+	if cfg.DryRun {
+		crawler.log.Warn("Dry-run mode: no products will be stored and no notifications sent")
+	} else {
+		indexCtx, cancel := db.OperationContext(context.Background())
+		if err := SetupDatabaseIndices(indexCtx, db, cfg, crawler.log); err != nil {
+			log.Warn("Failed to set up database indices", zap.Error(err))
+		}
+		cancel()
 	}
-	
+
 	return crawler, nil
 }
 
-// setupDatabaseIndices ensures necessary database indices exist for performance
-func (c *ImprovedCrawler) setupDatabaseIndices(ctx context.Context) error {
+// fetchConfigurer is implemented by sources that fetch through fetch.BaseCrawler
+type fetchConfigurer interface {
+	SetFetchLimits(timeout time.Duration, maxBodyBytes int64)
+	EnableSnapshots(store *fetch.SnapshotStore)
+}
+
+// configureFetch applies the configured fetch limits and snapshotting to a source
+func configureFetch(source sources.Source, cfg *config.Config, log *zap.Logger) {
+	base, ok := source.(fetchConfigurer)
+	if !ok {
+		return
+	}
+
+	base.SetFetchLimits(time.Duration(cfg.FetchTimeoutSeconds)*time.Second, cfg.FetchMaxBodyBytes)
+	if cfg.SaveSnapshots {
+		base.EnableSnapshots(fetch.NewSnapshotStore(cfg.SnapshotDir, source.Name(), cfg.SnapshotKeep, log))
+	}
+}
+
+// SetupDatabaseIndices ensures the indices the crawler relies on exist. It is
+// idempotent, so it runs on every crawler start and from the migrate command.
+// Individual index failures are only logged; it returns the context's error
+// if the context ends before all indices are set up.
+func SetupDatabaseIndices(ctx context.Context, db *storage.MongoDB, cfg *config.Config, log *zap.Logger) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// Products collection indices
-	productsCollection := c.db.Collection("products")
-	
+	productsCollection := db.Collection("products")
+
 	// URL index (must be unique)
 	_, err := productsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"url", 1}},
+		Keys:    bson.D{{Key: "url", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	})
 	if err != nil {
-		c.log.Warn("Failed to create URL index on products collection", zap.Error(err))
+		log.Warn("Failed to create URL index on products collection", zap.Error(err))
+	}
+
+	// Last seen index for expiring products that dropped off their source
+	_, err = productsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "last_seen_at", Value: 1}},
+	})
+	if err != nil {
+		log.Warn("Failed to create last seen index on products collection", zap.Error(err))
 	}
-	
+
 	// Title text index for searching
 	_, err = productsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"title", "text"}, {"product", "text"}},
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "product", Value: "text"}},
 	})
 	if err != nil {
-		c.log.Warn("Failed to create text index on products collection", zap.Error(err))
+		log.Warn("Failed to create text index on products collection", zap.Error(err))
 	}
-	
+
 	// Keyword alerts collection indices
-	alertsCollection := c.db.Collection("keyword_alerts")
-	
-	// User ID + Keyword compound index (must be unique per user)
+	alertsCollection := db.Collection("keyword_alerts")
+
+	// Normalized keyword indices, unique per user for personal alerts and per guild for server alerts
+	if err := storage.NewAlertRepository(db, log).EnsureIndexes(ctx); err != nil {
+		log.Warn("Failed to create keyword indices on keyword_alerts collection", zap.Error(err))
+	}
+
+	// Guild ID + User ID index for per-guild alert lists
 	_, err = alertsCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"user_id", 1}, {"keyword", 1}},
-		Options: options.Index().SetUnique(true),
+		Keys: bson.D{{Key: "guild_id", Value: 1}, {Key: "user_id", Value: 1}},
 	})
 	if err != nil {
-		c.log.Warn("Failed to create compound index on keyword_alerts collection", zap.Error(err))
+		log.Warn("Failed to create guild index on keyword_alerts collection", zap.Error(err))
 	}
-	
+
+	// Alert matches collection indices
+	matchesCollection := db.Collection("alert_matches")
+
+	// Alert ID + match time index for history queries
+	_, err = matchesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "alert_id", Value: 1}, {Key: "matched_at", Value: -1}},
+	})
+	if err != nil {
+		log.Warn("Failed to create index on alert_matches collection", zap.Error(err))
+	}
+
+	// Summary mode + match time index for the summary DM query
+	_, err = matchesCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "summary_mode", Value: 1}, {Key: "matched_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"summary_mode": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		log.Warn("Failed to create summary index on alert_matches collection", zap.Error(err))
+	}
+
+	// Pending notifications collection indices
+	pendingCollection := db.Collection("pending_notifications")
+
+	// Delivery time index for the due-notification query
+	_, err = pendingCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "deliver_at", Value: 1}},
+	})
+	if err != nil {
+		log.Warn("Failed to create index on pending_notifications collection", zap.Error(err))
+	}
+
+	// Short links are unique per product URL
+	if cfg.ClickTrackingEnabled {
+		if err := storage.NewShortLinkRepository(db, log).EnsureIndexes(ctx); err != nil {
+			log.Warn("Failed to create URL index on short_links collection", zap.Error(err))
+		}
+	}
+
+	// Deal messages expire once they are too old to react to
+	if err := storage.NewDealMessageRepository(db, log).EnsureIndexes(ctx); err != nil {
+		log.Warn("Failed to create TTL index on deal_messages collection", zap.Error(err))
+	}
+
 	// Notified products collection indices
-	notifiedCollection := c.db.Collection("notified_products")
-	
+	notifiedCollection := db.Collection("notified_products")
+
 	// URL index (must be unique)
 	_, err = notifiedCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{"url", 1}},
+		Keys:    bson.D{{Key: "url", Value: 1}},
 		Options: options.Index().SetUnique(true),
 	})
 	if err != nil {
-		c.log.Warn("Failed to create URL index on notified_products collection", zap.Error(err))
+		log.Warn("Failed to create URL index on notified_products collection", zap.Error(err))
 	}
-	
-	return nil
+
+	// Notified time index, for refreshing the notified filter
+	_, err = notifiedCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "notified_at", Value: 1}},
+	})
+	if err != nil {
+		log.Warn("Failed to create notified_at index on notified_products collection", zap.Error(err))
+	}
+
+	return ctx.Err()
 }
 
 // Run executes a single crawl of all sources
 func (c *ImprovedCrawler) Run(ctx context.Context) error {
 	c.log.Info("Starting crawler run")
-	
+
 	// Update stats
 	c.statsMutex.Lock()
 	c.stats.LastRun = time.Now()
 	c.stats.RunCount++
-	c.stats.NewProducts = 0 // Reset for this run
+	c.stats.NewProducts = 0      // Reset for this run
 	c.stats.NotifiedProducts = 0 // Reset for this run
+	c.stats.WouldInsertProducts = 0
+	c.stats.WouldNotifyProducts = 0
 	c.statsMutex.Unlock()
-	
+
 	startTime := time.Now()
-	
-	// Create channels for product collection and processing
+
+	// Sources stream products to the consumer below. Every send also selects on
+	// ctx.Done, so a source never blocks on a full channel once the consumer
+	// stops. Errors are collected without a channel so reporting one can't block.
 	productChan := make(chan models.Product, 1000)
-	errorChan := make(chan error, len(c.sources))
-	
+	var sourceErrors errorCollector
+
 	// Create WaitGroup for source crawlers
 	var wg sync.WaitGroup
 	wg.Add(len(c.sources))
-	
+
 	// Crawl all sources in parallel
 	for _, src := range c.sources {
 		go func(source sources.Source) {
 			defer wg.Done()
-			
+
 			sourceName := source.Name()
+
+			// Stagger sources so they don't all hit their sites at the same instant
+			stagger := staggerDelay(time.Duration(c.config.CrawlSourceStaggerSeconds)*time.Second, c.random)
+			if err := sleepContext(ctx, stagger); err != nil {
+				sourceErrors.add(err)
+				return
+			}
+
 			sourceStartTime := time.Now()
-			
+
 			c.log.Info("Crawling source", zap.String("source", sourceName))
-			
-			products, err := source.Crawl(ctx)
+
+			products, err := crawlSource(ctx, source, c.log)
 			if err != nil {
-				c.log.Error("Failed to crawl source", 
-					zap.String("source", sourceName), 
+				c.log.Error("Failed to crawl source",
+					zap.String("source", sourceName),
 					zap.Error(err))
-				
+
 				// Update source stats with error
 				c.statsMutex.Lock()
 				sourceStats := c.stats.SourceStats[sourceName]
 				sourceStats.LastError = err.Error()
 				sourceStats.LastRun = time.Now()
 				sourceStats.LastRunDuration = time.Since(sourceStartTime).String()
-				
+
 				// Calculate success rate
 				if sourceStats.SuccessRate == 0 {
 					sourceStats.SuccessRate = 0 // First run failed
@@ -195,18 +337,29 @@ func (c *ImprovedCrawler) Run(ctx context.Context) error {
 					// Weight previous success rate at 90%, new result at 10%
 					sourceStats.SuccessRate = sourceStats.SuccessRate*0.9 + 0*0.1
 				}
-				
+
 				c.stats.SourceStats[sourceName] = sourceStats
+				recordSourceHealth(c.healthStatus, sourceName, false)
 				c.statsMutex.Unlock()
-				
-				errorChan <- fmt.Errorf("failed to crawl source %s: %w", sourceName, err)
+
+				sourceErrors.add(fmt.Errorf("failed to crawl source %s: %w", sourceName, err))
 				return
 			}
-			
-			c.log.Info("Crawled source successfully", 
-				zap.String("source", sourceName), 
+
+			// A runaway parser or pagination loop can't flood the stages below
+			found := len(products)
+			products, capped := capProducts(products, c.config.MaxProductsPerSource)
+			if capped {
+				c.log.Warn("Source returned more products than allowed per run; truncating",
+					zap.String("source", sourceName),
+					zap.Int("products_found", found),
+					zap.Int("max_products", c.config.MaxProductsPerSource))
+			}
+
+			c.log.Info("Crawled source successfully",
+				zap.String("source", sourceName),
 				zap.Int("products_found", len(products)))
-			
+
 			// Update source stats with success
 			c.statsMutex.Lock()
 			sourceStats := c.stats.SourceStats[sourceName]
@@ -214,7 +367,14 @@ func (c *ImprovedCrawler) Run(ctx context.Context) error {
 			sourceStats.LastRun = time.Now()
 			sourceStats.LastRunDuration = time.Since(sourceStartTime).String()
 			sourceStats.LastError = "" // Clear any previous error
-			
+			sourceStats.Capped = capped
+
+			// Flag a sudden drop in yield; alert only when the source first turns low
+			average, low := c.yields.record(sourceName, len(products))
+			newlyLow := low && !sourceStats.LowYield
+			sourceStats.YieldAverage = average
+			sourceStats.LowYield = low
+
 			// Calculate success rate
 			if sourceStats.SuccessRate == 0 {
 				sourceStats.SuccessRate = 1 // First run succeeded
@@ -222,14 +382,21 @@ func (c *ImprovedCrawler) Run(ctx context.Context) error {
 				// Weight previous success rate at 90%, new result at 10%
 				sourceStats.SuccessRate = sourceStats.SuccessRate*0.9 + 1*0.1
 			}
-			
+
 			c.stats.SourceStats[sourceName] = sourceStats
 			c.stats.TotalProducts += len(products) // Update total found
+
+			// Update health status for this source; alert only when it just recovered
+			recovered := recordSourceHealth(c.healthStatus, sourceName, true)
 			c.statsMutex.Unlock()
-			
-			// Update health status for this source
-			c.healthStatus[sourceName] = true
-			
+
+			if recovered {
+				c.alertSourceRecovered(ctx, sourceName, len(products))
+			}
+			if newlyLow {
+				c.alertLowYield(ctx, sourceName, len(products), average)
+			}
+
 			// Send products to channel
 			for _, product := range products {
 				// Set source and crawled time if not already set
@@ -239,121 +406,179 @@ func (c *ImprovedCrawler) Run(ctx context.Context) error {
 				if product.CrawledAt.IsZero() {
 					product.CrawledAt = time.Now()
 				}
-				
+				product.FirstSeenAt = product.CrawledAt
+				product.LastSeenAt = product.CrawledAt
+				// Sources may already mark hot deals from their own markup
+				if !product.IsHot {
+					product.IsHot = product.MeetsHotThreshold(c.config.HotCommentThreshold, c.config.HotViewThreshold)
+				}
+
 				select {
 				case productChan <- product:
 					// Successfully sent product to channel
 				case <-ctx.Done():
 					// Context cancelled, stop sending
-					errorChan <- ctx.Err()
+					sourceErrors.add(ctx.Err())
 					return
 				}
 			}
 		}(src)
 	}
-	
-	// Close channels when all sources are done
+
+	// Close the product channel when all sources are done
 	go func() {
 		wg.Wait()
 		close(productChan)
-		close(errorChan)
 	}()
-	
-	// Process products
-	var newProducts []models.Product
-	
+
+	// Deliver notifications whose quiet hours have ended
+	if pending, ok := c.notifier.(PendingDeliverer); ok && !c.config.DryRun {
+		if err := pending.DeliverPendingNotifications(ctx); err != nil {
+			c.log.Error("Failed to deliver pending notifications", zap.Error(err))
+		}
+	}
+
+	// New products are notified in batches of notifyBatchSize as they are
+	// inserted, so memory stays flat however many products the sources return.
+	// Stored products that changed notably are batched apart and re-notified.
+	batch := make([]models.Product, 0, notifyBatchSize)
+	changed := make([]models.Product, 0, notifyBatchSize)
+	var totals runTotals
+
 	// Get MongoDB collection
 	collection := c.db.Collection("products")
-	
+
+	// With StoreOnlyMatched, products no alert could notify about aren't stored
+	storeIndex := c.buildStoreIndex(ctx)
+
 	// Process each product
+	received := 0
 	for product := range productChan {
+		received++
+		if len(batch) >= notifyBatchSize {
+			c.notifyBatch(ctx, batch, false, &totals)
+			batch = make([]models.Product, 0, notifyBatchSize)
+		}
+		if len(changed) >= notifyBatchSize {
+			c.notifyBatch(ctx, changed, true, &totals)
+			changed = make([]models.Product, 0, notifyBatchSize)
+		}
+
 		select {
 		case <-ctx.Done():
+			c.sendBestDeals(ctx, &totals) // drops the canceled run's candidates
 			return ctx.Err()
 		default:
+			if !shouldStore(product, storeIndex) {
+				totals.unmatchedSkipped++
+				continue
+			}
+
 			// Check if product already exists
-			var count int64
+			var existing models.Product
 			filter := bson.M{
 				"url": product.URL,
 			}
-			
-			count, err := collection.CountDocuments(ctx, filter)
-			if err != nil {
-				c.log.Error("Failed to check product existence", 
-					zap.Error(err), 
-					zap.String("url", product.URL))
+
+			err := collection.FindOne(ctx, filter).Decode(&existing)
+			if err == nil {
+				// Refresh the stored deal's mutable fields; a price or discount change
+				// is notified again although the deal was notified when first found
+				if c.updateExistingProduct(ctx, collection, &existing, product, &totals) {
+					product.ID = existing.ID
+					changed = append(changed, product)
+				}
 				continue
 			}
-			
-			if count > 0 {
-				c.log.Debug("Product already exists", zap.String("url", product.URL))
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				c.log.Error("Failed to check product existence",
+					zap.Error(err),
+					zap.String("url", product.URL))
 				continue
 			}
-			
-			// Generate ID if not set
+
+			// Derive the ID from the URL if not set, so the same deal always has the same ID
 			if product.ID == "" {
-				product.ID = primitive.NewObjectID().Hex()
+				product.ID = models.ProductIDFromURL(product.URL)
+			}
+
+			// In dry-run mode only record what would have been inserted
+			if c.config.DryRun {
+				c.log.Debug("[dry-run] Would insert product",
+					zap.String("title", product.Title),
+					zap.String("source", product.Source))
+				batch = append(batch, product)
+				totals.newProducts++
+
+				c.statsMutex.Lock()
+				c.stats.WouldInsertProducts++
+				c.statsMutex.Unlock()
+				continue
 			}
-			
-			// Insert new product
-			_, err = collection.InsertOne(ctx, product)
+
+			// Insert new product, retrying transient errors
+			err = storage.WithRetry(ctx, func(ctx context.Context) error {
+				_, err := collection.InsertOne(ctx, product)
+				return err
+			})
 			if err != nil {
-				c.log.Error("Failed to insert product", 
-					zap.Error(err), 
+				c.log.Error("Failed to insert product",
+					zap.Error(err),
 					zap.String("title", product.Title))
 				continue
 			}
-			
-			c.log.Info("New product found", 
-				zap.String("title", product.Title), 
+
+			c.log.Info("New product found",
+				zap.String("title", product.Title),
 				zap.String("source", product.Source))
-			
-			newProducts = append(newProducts, product)
-			
+
+			batch = append(batch, product)
+			totals.newProducts++
+
 			// Update stats
 			c.statsMutex.Lock()
 			c.stats.NewProducts++
 			c.statsMutex.Unlock()
 		}
 	}
-	
-	// Check for errors from goroutines
-	var crawlErrors []error
-	for err := range errorChan {
-		crawlErrors = append(crawlErrors, err)
-	}
-	
-	// Send notifications for new products
-	if len(newProducts) > 0 {
-		c.log.Info("Sending notifications for new products", zap.Int("count", len(newProducts)))
-		
-		if err := c.notifier.NotifyNewProducts(ctx, newProducts); err != nil {
-			c.log.Error("Failed to send some notifications", zap.Error(err))
-			
-			// Update stats with error
-			c.statsMutex.Lock()
-			c.stats.LastError = err.Error()
-			c.statsMutex.Unlock()
-			
-			crawlErrors = append(crawlErrors, err)
-		} else {
-			// Update stats with notification count
-			c.statsMutex.Lock()
-			c.stats.NotifiedProducts = len(newProducts)
-			c.statsMutex.Unlock()
-		}
+
+	c.notifyBatch(ctx, batch, false, &totals)
+	c.notifyBatch(ctx, changed, true, &totals)
+	c.sendBestDeals(ctx, &totals)
+
+	if !c.config.DryRun {
+		c.expireUnseenProducts(ctx, startTime)
+	}
+
+	if totals.updatedProducts > 0 {
+		c.log.Info("Updated changed products", zap.Int("updated", totals.updatedProducts))
+	}
+	if totals.unmatchedSkipped > 0 {
+		c.log.Info("Skipped storing products that match no alert",
+			zap.Int("skipped", totals.unmatchedSkipped))
 	}
-	
+	if totals.staleSkipped > 0 {
+		c.log.Info("Skipped notifications for stale deals",
+			zap.Int("skipped", totals.staleSkipped),
+			zap.Duration("max_age", time.Duration(c.config.MaxDealAgeHours)*time.Hour))
+	}
+	if c.config.DryRun {
+		c.reportDryRun(&totals)
+	}
+
+	// All sources are done once productChan is closed, so their errors are complete
+	crawlErrors := append(sourceErrors.list(), totals.errors...)
+
 	// Update last run time
 	c.lastRun = time.Now()
-	
+
 	// Log duration
 	duration := time.Since(startTime)
-	c.log.Info("Crawler run completed", 
-		zap.Int("new_products", len(newProducts)),
-		zap.Int("total_products", len(productChan)),
+	c.log.Info("Crawler run completed",
+		zap.Int("new_products", totals.newProducts),
+		zap.Int("total_products", received),
 		zap.Duration("duration", duration))
-	
+
 	// Return any errors
 	if len(crawlErrors) > 0 {
 		// Format as a single error with all the error messages
@@ -366,70 +591,437 @@ func (c *ImprovedCrawler) Run(ctx context.Context) error {
 		}
 		return fmt.Errorf("%s", errorMsg)
 	}
-	
+
 	return nil
 }
 
+// errorCollector gathers errors from concurrent source goroutines. Unlike a
+// buffered channel it never blocks, however many errors a source reports.
+type errorCollector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// add records an error
+func (e *errorCollector) add(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.errs = append(e.errs, err)
+}
+
+// list returns the recorded errors
+func (e *errorCollector) list() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]error(nil), e.errs...)
+}
+
+// crawlSource runs a source's Crawl, turning a panic in it (e.g. a parser
+// hitting unexpected markup) into an error so the other sources still finish
+// and the failure is recorded like any other crawl error
+func crawlSource(ctx context.Context, source sources.Source, log *zap.Logger) (products []models.Product, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Error("Source panicked while crawling",
+				zap.String("source", source.Name()),
+				zap.Any("panic", recovered),
+				zap.ByteString("stack", debug.Stack()))
+			products = nil
+			err = fmt.Errorf("panic while crawling: %v", recovered)
+		}
+	}()
+
+	return source.Crawl(ctx)
+}
+
+// capProducts truncates products to max, reporting whether any were dropped.
+// A max of 0 means no limit.
+func capProducts(products []models.Product, max int) ([]models.Product, bool) {
+	if max <= 0 || len(products) <= max {
+		return products, false
+	}
+	return products[:max], true
+}
+
+// freshProducts returns the products uploaded within maxAge of now.
+// Products without an upload date are kept.
+func freshProducts(products []models.Product, maxAge time.Duration, now time.Time) []models.Product {
+	cutoff := now.Add(-maxAge).Unix()
+
+	fresh := make([]models.Product, 0, len(products))
+	for _, product := range products {
+		if product.UploadDate == 0 || product.UploadDate >= cutoff {
+			fresh = append(fresh, product)
+		}
+	}
+	return fresh
+}
+
+// buildStoreIndex builds the keyword index of active alerts used to decide
+// which products are stored. It returns nil, storing every product, when
+// StoreOnlyMatched is off or the alerts can't be loaded.
+func (c *ImprovedCrawler) buildStoreIndex(ctx context.Context) *KeywordIndex {
+	if !c.config.StoreOnlyMatched {
+		return nil
+	}
+
+	index, err := c.alerts.BuildIndex(ctx)
+	if err != nil {
+		c.log.Error("Failed to load alerts; storing all products this run", zap.Error(err))
+		return nil
+	}
+	return index
+}
+
+// shouldStore reports whether a product is stored. With an index only hot
+// products and products matching at least one active alert are stored.
+func shouldStore(product models.Product, index *KeywordIndex) bool {
+	if index == nil || product.IsHot {
+		return true
+	}
+	return len(index.Match(product.SearchText())) > 0
+}
+
+// updateExistingProduct writes the mutable fields of a re-crawled product that
+// changed since it was stored, along with when it was last seen, and reports
+// whether the change warrants a notification. CrawledAt keeps the time the
+// deal was first found. In dry-run mode nothing is written.
+func (c *ImprovedCrawler) updateExistingProduct(ctx context.Context, collection *mongo.Collection, existing *models.Product, product models.Product, totals *runTotals) bool {
+	changed := product.Diff(existing)
+	notable := product.IsNotableChange(changed)
+
+	if c.config.DryRun {
+		if len(changed) > 0 {
+			c.log.Debug("[dry-run] Would update product",
+				zap.String("title", product.Title),
+				zap.Strings("changed", changed),
+				zap.Bool("notable", notable))
+		}
+		return notable
+	}
+
+	err := storage.WithRetry(ctx, func(ctx context.Context) error {
+		_, err := collection.UpdateOne(ctx, productDocumentFilter(*existing),
+			bson.M{"$set": productChangeSet(existing, product, changed, time.Now())})
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to update product",
+			zap.Error(err),
+			zap.String("url", product.URL))
+		return false
+	}
+
+	if len(changed) == 0 {
+		c.log.Debug("Product already exists", zap.String("url", product.URL))
+		return false
+	}
+
+	totals.updatedProducts++
+	c.log.Debug("Product changed",
+		zap.String("title", product.Title),
+		zap.Strings("changed", changed),
+		zap.Bool("notable", notable))
+	return notable
+}
+
+// productChangeSet builds the $set of a re-crawled product: last_seen_at, and
+// the fields behind each changed field reported by Diff. Views change on
+// almost every crawl, so they are written whenever they differ, a product
+// that became hot is marked hot, and an expired product that reappeared is
+// active again.
+func productChangeSet(existing *models.Product, product models.Product, changed []string, now time.Time) bson.M {
+	set := bson.M{"last_seen_at": now}
+	for _, field := range changed {
+		switch field {
+		case models.ProductFieldPrice:
+			set["ko_price"] = product.KOPrice
+			set["us_price"] = product.USPrice
+			set["price_string"] = product.PriceString
+		case models.ProductFieldComments:
+			set["comments"] = product.Comments
+		case models.ProductFieldSoldOut:
+			set["sold_out"] = product.SoldOut
+		case models.ProductFieldDiscount:
+			set["discount_rate"] = product.DiscountRate
+			set["original_price"] = product.OriginalPrice
+		}
+	}
+	if product.Views != existing.Views {
+		set["views"] = product.Views
+	}
+	if product.IsHot && !existing.IsHot {
+		set["is_hot"] = true
+	}
+	if existing.Expired {
+		set["expired"] = false
+	}
+	return set
+}
+
+// dryRunSampleSize is the number of product titles logged per dry run
+const dryRunSampleSize = 5
+
+// notifyBatchSize is how many new products are collected before they are notified
+const notifyBatchSize = 100
+
+// runTotals accumulates the results of one run's notification batches
+type runTotals struct {
+	newProducts      int
+	updatedProducts  int
+	staleSkipped     int
+	unmatchedSkipped int      // StoreOnlyMatched
+	wouldNotify      int      // dry-run
+	insertSamples    []string // dry-run
+	notifySamples    []string // dry-run
+	errors           []error
+}
+
+// notifyBatch notifies a batch of newly inserted products, or with renotify
+// of stored products that changed notably, and adds the outcome to totals.
+// Changed products were notified when first found, so they are only sent by
+// a ChangeNotifier. In dry-run mode it only previews which products would be
+// notified.
+func (c *ImprovedCrawler) notifyBatch(ctx context.Context, products []models.Product, renotify bool, totals *runTotals) {
+	if len(products) == 0 {
+		return
+	}
+
+	send := c.notifier.NotifyNewProducts
+	if renotify {
+		changeNotifier, ok := c.notifier.(ChangeNotifier)
+		if !ok {
+			return
+		}
+		send = changeNotifier.NotifyChangedProducts
+	}
+
+	// Don't notify about old threads that resurfaced on the list
+	notifyProducts := products
+	if c.config.MaxDealAgeHours > 0 {
+		maxAge := time.Duration(c.config.MaxDealAgeHours) * time.Hour
+		notifyProducts = freshProducts(products, maxAge, time.Now())
+		totals.staleSkipped += len(products) - len(notifyProducts)
+	}
+
+	// In dry-run mode record what would be notified instead of sending
+	if c.config.DryRun {
+		matched := notifyProducts
+		if previewer, ok := c.notifier.(NotificationPreviewer); ok {
+			var err error
+			matched, err = previewer.PreviewNotifications(ctx, notifyProducts)
+			if err != nil {
+				c.log.Error("[dry-run] Failed to preview notifications", zap.Error(err))
+			}
+		}
+		totals.wouldNotify += len(matched)
+		if !renotify {
+			totals.insertSamples = appendSamples(totals.insertSamples, products, dryRunSampleSize)
+		}
+		totals.notifySamples = appendSamples(totals.notifySamples, matched, dryRunSampleSize)
+		return
+	}
+
+	if len(notifyProducts) == 0 {
+		return
+	}
+
+	c.log.Info("Sending notifications for new products",
+		zap.Int("count", len(notifyProducts)),
+		zap.Bool("changed", renotify))
+
+	if err := send(ctx, notifyProducts); err != nil {
+		c.log.Error("Failed to send some notifications", zap.Error(err))
+
+		// Update stats with error
+		c.statsMutex.Lock()
+		c.stats.LastError = err.Error()
+		c.statsMutex.Unlock()
+
+		totals.errors = append(totals.errors, err)
+		return
+	}
+
+	// Update stats with notification count
+	c.statsMutex.Lock()
+	c.stats.NotifiedProducts += len(notifyProducts)
+	c.statsMutex.Unlock()
+}
+
+// sendBestDeals sends the best deal alerts the best product they matched in
+// the whole run. It runs once after the last batch, not per batch, so a
+// better deal found in a later batch isn't beaten by an earlier one.
+func (c *ImprovedCrawler) sendBestDeals(ctx context.Context, totals *runTotals) {
+	sender, ok := c.notifier.(BestDealSender)
+	if !ok || c.config.DryRun {
+		return
+	}
+
+	if err := sender.SendBestDeals(ctx); err != nil {
+		c.log.Error("Failed to send some best deal notifications", zap.Error(err))
+
+		c.statsMutex.Lock()
+		c.stats.LastError = err.Error()
+		c.statsMutex.Unlock()
+
+		totals.errors = append(totals.errors, err)
+	}
+}
+
+// reportDryRun logs the products a dry run would have inserted and notified
+func (c *ImprovedCrawler) reportDryRun(totals *runTotals) {
+	c.statsMutex.Lock()
+	c.stats.WouldNotifyProducts = totals.wouldNotify
+	c.statsMutex.Unlock()
+
+	c.log.Info("[dry-run] Crawl summary",
+		zap.Int("would_insert", totals.newProducts),
+		zap.Strings("insert_samples", totals.insertSamples),
+		zap.Int("would_notify", totals.wouldNotify),
+		zap.Strings("notify_samples", totals.notifySamples))
+}
+
+// appendSamples adds titles of products to samples until it holds n titles
+func appendSamples(samples []string, products []models.Product, n int) []string {
+	if len(samples) >= n {
+		return samples
+	}
+	return append(samples, sampleTitles(products, n-len(samples))...)
+}
+
+// sampleTitles returns the titles of up to n products
+func sampleTitles(products []models.Product, n int) []string {
+	if len(products) < n {
+		n = len(products)
+	}
+	titles := make([]string, 0, n)
+	for _, product := range products[:n] {
+		titles = append(titles, product.Title)
+	}
+	return titles
+}
+
 // StartScheduledRuns starts periodic crawler runs
 func (c *ImprovedCrawler) StartScheduledRuns(ctx context.Context, interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-	
-	c.log.Info("Starting scheduled crawler runs", zap.Duration("interval", interval))
-	
-	// Run immediately on startup
-	if err := c.Run(ctx); err != nil {
-		c.log.Error("Initial crawler run failed", zap.Error(err))
-		
-		// Store error in stats
+	log := c.log.With(zap.Duration("interval", interval))
+	sched := newSchedule(log, interval, c.config.CrawlJitterPercent, c.random)
+	sched.onSkip = c.recordSkippedRun
+	sched.run(ctx, c.runAndRecord)
+}
+
+// recordSkippedRun counts a scheduled run skipped because the previous one was still going
+func (c *ImprovedCrawler) recordSkippedRun() {
+	c.statsMutex.Lock()
+	c.stats.SkippedRuns++
+	c.statsMutex.Unlock()
+}
+
+// runAndRecord executes a crawler run and stores any error in stats
+func (c *ImprovedCrawler) runAndRecord(ctx context.Context) error {
+	err := c.Run(ctx)
+	if err != nil {
 		c.statsMutex.Lock()
 		c.stats.LastError = err.Error()
 		c.statsMutex.Unlock()
 	}
-	
-	// Then run on schedule
-	for {
-		select {
-		case <-ticker.C:
-			if err := c.Run(ctx); err != nil {
-				c.log.Error("Scheduled crawler run failed", zap.Error(err))
-				
-				// Store error in stats
-				c.statsMutex.Lock()
-				c.stats.LastError = err.Error()
-				c.statsMutex.Unlock()
-			}
-		case <-ctx.Done():
-			c.log.Info("Stopping scheduled crawler runs")
-			return
-		}
+	return err
+}
+
+// StartWeeklyDigest posts the weekly popular-deals digest to the product
+// channel on schedule until the context is canceled
+func (c *ImprovedCrawler) StartWeeklyDigest(ctx context.Context) {
+	if !c.config.WeeklyDigestEnabled || c.config.ProductChannelID == "" || c.config.DryRun {
+		c.log.Info("Weekly digest disabled")
+		return
 	}
+
+	sender, ok := c.notifier.(EmbedSender)
+	if !ok {
+		c.log.Info("Weekly digest disabled: notifier cannot post embeds")
+		return
+	}
+
+	at, _ := time.Parse("15:04", c.config.WeeklyDigestTime)
+	NewWeeklyDigest(sender, c.db, c.log, c.config.ProductChannelID, c.config.WeeklyDigestWeekday, at).Run(ctx)
+}
+
+// StartAlertSummaries DMs users with daily or weekly summary mode alerts the
+// deals their alerts matched, on schedule until the context is canceled
+func (c *ImprovedCrawler) StartAlertSummaries(ctx context.Context) {
+	if c.config.DryRun {
+		c.log.Info("Alert summaries disabled in dry-run mode")
+		return
+	}
+
+	sender, ok := c.notifier.(DMSender)
+	if !ok {
+		c.log.Info("Alert summaries disabled: notifier cannot send direct messages")
+		return
+	}
+
+	at, _ := time.Parse("15:04", c.config.AlertSummaryTime)
+	NewAlertSummary(sender, c.db, c.log, c.config.AlertSummaryWeekday, at).Run(ctx)
 }
 
 // GetStats returns current crawler statistics
 func (c *ImprovedCrawler) GetStats() CrawlerStats {
 	c.statsMutex.RLock()
 	defer c.statsMutex.RUnlock()
-	
-	// Return a copy of the stats
+
+	// Return a copy of the stats, including the per-source map
 	statsCopy := c.stats
+	statsCopy.SourceStats = make(map[string]SourceStats, len(c.stats.SourceStats))
+	for name, sourceStats := range c.stats.SourceStats {
+		statsCopy.SourceStats[name] = sourceStats
+	}
 	return statsCopy
 }
 
-// Health returns the health status of the crawler
+// Health returns a copy of the health status of each source
 func (c *ImprovedCrawler) Health() map[string]bool {
-	return c.healthStatus
+	c.statsMutex.RLock()
+	defer c.statsMutex.RUnlock()
+
+	health := make(map[string]bool, len(c.healthStatus))
+	for name, ok := range c.healthStatus {
+		health[name] = ok
+	}
+	return health
+}
+
+// StartHTTPServer serves health, stats, click-tracking redirects and the JSON API
+// until the context is canceled. It does nothing if no HTTP address is configured;
+// the API is only served when an API key is configured.
+func (c *ImprovedCrawler) StartHTTPServer(ctx context.Context) {
+	if c.config.HTTPAddr == "" {
+		c.log.Info("HTTP server disabled")
+		return
+	}
+
+	var api *APIHandler
+	if c.config.APIKey != "" {
+		api = NewAPIHandler(
+			storage.NewProductRepository(c.db, c.log),
+			storage.NewAlertRepository(c.db, c.log),
+			c.GetStats,
+			c.config.APIKey,
+			c.log)
+	} else {
+		c.log.Info("JSON API disabled: API_KEY not set")
+	}
+
+	NewHTTPServer(c.config.HTTPAddr, c, storage.NewShortLinkRepository(c.db, c.log), api, c.log).Run(ctx)
 }
 
 // Close cleans up resources
 func (c *ImprovedCrawler) Close() error {
 	// Close the notifier
 	c.notifier.Close()
-	
+
 	// Disconnect from MongoDB
 	if err := c.db.Disconnect(); err != nil {
 		return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
 	}
-	
+
 	return nil
-}
\ No newline at end of file
+}
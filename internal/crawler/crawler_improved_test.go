@@ -0,0 +1,591 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// staticSource is a crawl source returning fixed products, or running crawl if set
+type staticSource struct {
+	name     string
+	products []models.Product
+	crawl    func(ctx context.Context) ([]models.Product, error)
+}
+
+func (s *staticSource) Crawl(ctx context.Context) ([]models.Product, error) {
+	if s.crawl != nil {
+		return s.crawl(ctx)
+	}
+	return s.products, nil
+}
+
+func (s *staticSource) Name() string {
+	return s.name
+}
+
+// recordingNotifier records the products it is asked to notify. As a
+// NotificationPreviewer it reports every product as matched.
+type recordingNotifier struct {
+	mu       sync.Mutex
+	notified []models.Product
+	previews int
+}
+
+func (r *recordingNotifier) NotifyNewProducts(ctx context.Context, products []models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notified = append(r.notified, products...)
+	return nil
+}
+
+func (r *recordingNotifier) PreviewNotifications(ctx context.Context, products []models.Product) ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.previews++
+	return products, nil
+}
+
+func (r *recordingNotifier) Close() {}
+
+// newTestCrawler builds an ImprovedCrawler over the mock database with the given sources
+func newTestCrawler(mt *mtest.T, cfg *config.Config, notifier Notifier, srcs ...*staticSource) *ImprovedCrawler {
+	c := &ImprovedCrawler{
+		config:       cfg,
+		log:          zap.NewNop(),
+		db:           newMockDB(mt, cfg),
+		notifier:     notifier,
+		healthStatus: make(map[string]bool),
+		random:       newLockedRand(1),
+		yields:       newYieldTracker(cfg.YieldWindowRuns, cfg.YieldDropPercent),
+		stats:        CrawlerStats{DryRun: cfg.DryRun, SourceStats: make(map[string]SourceStats)},
+	}
+	c.alerts = NewAlertMatcher(c.db, c.log)
+	for _, src := range srcs {
+		c.sources = append(c.sources, src)
+	}
+	return c
+}
+
+func TestFreshProducts(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC)
+	products := []models.Product{
+		{URL: "new", UploadDate: now.Add(-time.Hour).Unix()},
+		{URL: "old", UploadDate: now.Add(-72 * time.Hour).Unix()},
+		{URL: "undated"},
+		{URL: "edge", UploadDate: now.Add(-24 * time.Hour).Unix()},
+	}
+
+	fresh := freshProducts(products, 24*time.Hour, now)
+
+	var urls []string
+	for _, product := range fresh {
+		urls = append(urls, product.URL)
+	}
+	if got := len(urls); got != 3 || urls[0] != "new" || urls[1] != "undated" || urls[2] != "edge" {
+		t.Errorf("fresh products = %v, want new, undated and edge", urls)
+	}
+}
+
+func TestDryRunWritesNothing(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("dry run", func(mt *mtest.T) {
+		notifier := &recordingNotifier{}
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1"},
+			{Title: "기계식 키보드", URL: "https://example.com/deal/2"},
+		}}
+		c := newTestCrawler(mt, &config.Config{DryRun: true}, notifier, src)
+		mt.AddMockResponses(cursorResponse(), cursorResponse()) // neither product is stored yet
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		for _, name := range []string{"insert", "update", "delete", "createIndexes"} {
+			if got := len(startedCommands(mt, name)); got != 0 {
+				mt.Errorf("dry run sent %d %s commands, want none", got, name)
+			}
+		}
+		if len(notifier.notified) != 0 {
+			mt.Errorf("dry run notified %d products, want none", len(notifier.notified))
+		}
+		if notifier.previews != 1 {
+			mt.Errorf("previewed %d batches, want 1", notifier.previews)
+		}
+		stats := c.GetStats()
+		if stats.WouldInsertProducts != 2 || stats.WouldNotifyProducts != 2 {
+			mt.Errorf("would insert %d and notify %d, want 2 and 2", stats.WouldInsertProducts, stats.WouldNotifyProducts)
+		}
+		if stats.NewProducts != 0 || stats.NotifiedProducts != 0 {
+			mt.Errorf("new %d and notified %d, want the real counts left at 0", stats.NewProducts, stats.NotifiedProducts)
+		}
+	})
+}
+
+func TestSetupDatabaseIndicesStopsWhenCanceled(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("canceled before start", func(mt *mtest.T) {
+		cfg := &config.Config{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := SetupDatabaseIndices(ctx, newMockDB(mt, cfg), cfg, zap.NewNop()); !errors.Is(err, context.Canceled) {
+			mt.Errorf("SetupDatabaseIndices() error = %v, want context.Canceled", err)
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 0 {
+			mt.Errorf("sent %d commands, want none", n)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelOnFirst := options.Client().SetMonitor(&event.CommandMonitor{
+		Started: func(context.Context, *event.CommandStartedEvent) { cancel() },
+	})
+	mt.RunOpts("canceled during setup", mtest.NewOptions().ClientOptions(cancelOnFirst), func(mt *mtest.T) {
+		cfg := &config.Config{}
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		start := time.Now()
+		err := SetupDatabaseIndices(ctx, newMockDB(mt, cfg), cfg, zap.NewNop())
+		if !errors.Is(err, context.Canceled) {
+			mt.Errorf("SetupDatabaseIndices() error = %v, want context.Canceled", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			mt.Errorf("SetupDatabaseIndices() took %v after the context was canceled", elapsed)
+		}
+		if n := len(mt.GetAllSucceededEvents()); n > 1 {
+			mt.Errorf("%d index commands succeeded, want none after the cancel", n)
+		}
+	})
+}
+
+func TestRunRecoversFromPanickingSource(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("one source panics", func(mt *mtest.T) {
+		notifier := &recordingNotifier{}
+		broken := &staticSource{name: "broken", crawl: func(ctx context.Context) ([]models.Product, error) {
+			var row *models.Product
+			return []models.Product{*row}, nil
+		}}
+		healthy := &staticSource{name: "healthy", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1"},
+		}}
+		c := newTestCrawler(mt, &config.Config{DryRun: true}, notifier, broken, healthy)
+		mt.AddMockResponses(cursorResponse()) // the healthy source's product isn't stored yet
+
+		// The run finishes and reports the panic like any other source error
+		if err := c.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "broken") {
+			mt.Errorf("Run() error = %v, want the broken source's failure", err)
+		}
+
+		stats := c.GetStats()
+		if lastError := stats.SourceStats["broken"].LastError; !strings.Contains(lastError, "panic") {
+			mt.Errorf("broken source LastError = %q, want the recovered panic", lastError)
+		}
+		if healthy := stats.SourceStats["healthy"]; healthy.LastError != "" || healthy.ProductsFound != 1 {
+			mt.Errorf("healthy source stats = %+v, want its product found without error", healthy)
+		}
+		if stats.WouldInsertProducts != 1 {
+			mt.Errorf("would insert %d products, want the healthy source's 1", stats.WouldInsertProducts)
+		}
+	})
+}
+
+func TestRunManyProductsAndErrors(t *testing.T) {
+	mt := newMockTest(t)
+	const productCount = 3000 // several times the product channel's buffer
+
+	mt.Run("stress", func(mt *mtest.T) {
+		products := make([]models.Product, productCount)
+		for i := range products {
+			products[i] = models.Product{Title: fmt.Sprintf("상품 %d", i), URL: fmt.Sprintf("https://example.com/deal/%d", i)}
+		}
+		srcs := []*staticSource{{name: "busy", products: products}}
+		for i := 0; i < 20; i++ {
+			srcs = append(srcs, &staticSource{name: fmt.Sprintf("failing-%d", i), crawl: func(ctx context.Context) ([]models.Product, error) {
+				return nil, errors.New("site is down")
+			}})
+		}
+		c := newTestCrawler(mt, &config.Config{DryRun: true}, &recordingNotifier{}, srcs...)
+		for i := 0; i < productCount; i++ {
+			mt.AddMockResponses(cursorResponse()) // no product is stored yet
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- c.Run(context.Background()) }()
+
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "20 error(s)") {
+				mt.Errorf("Run() error = %v, want all 20 source errors", err)
+			}
+		case <-time.After(30 * time.Second):
+			mt.Fatal("Run() deadlocked")
+		}
+		if got := c.GetStats().WouldInsertProducts; got != productCount {
+			mt.Errorf("would insert %d products, want %d", got, productCount)
+		}
+	})
+
+	mt.Run("canceled while sources are sending", func(mt *mtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		products := make([]models.Product, productCount)
+		for i := range products {
+			products[i] = models.Product{Title: fmt.Sprintf("상품 %d", i), URL: fmt.Sprintf("https://example.com/deal/%d", i)}
+		}
+		// The source is canceled before the consumer drains its products
+		src := &staticSource{name: "busy", crawl: func(context.Context) ([]models.Product, error) {
+			cancel()
+			return products, nil
+		}}
+		c := newTestCrawler(mt, &config.Config{DryRun: true}, &recordingNotifier{}, src)
+
+		done := make(chan error, 1)
+		go func() { done <- c.Run(ctx) }()
+
+		select {
+		case err := <-done:
+			// Depending on who sees the cancellation first, the consumer returns
+			// it directly or the source reports it as its error
+			if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+				mt.Errorf("Run() error = %v, want the cancellation", err)
+			}
+		case <-time.After(30 * time.Second):
+			mt.Fatal("Run() deadlocked after cancellation")
+		}
+	})
+}
+
+func TestErrorCollector(t *testing.T) {
+	var collector errorCollector
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				collector.add(fmt.Errorf("source %d error %d", i, j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(collector.list()); got != 500 {
+		t.Errorf("collected %d errors, want 500", got)
+	}
+}
+
+// batchNotifier records the size of every batch it is asked to notify
+type batchNotifier struct {
+	recordingNotifier
+	batches []int
+}
+
+func (b *batchNotifier) NotifyNewProducts(ctx context.Context, products []models.Product) error {
+	b.mu.Lock()
+	b.batches = append(b.batches, len(products))
+	b.mu.Unlock()
+	return b.recordingNotifier.NotifyNewProducts(ctx, products)
+}
+
+func TestRunNotifiesInBatches(t *testing.T) {
+	mt := newMockTest(t)
+	productCount := 2*notifyBatchSize + notifyBatchSize/2
+
+	mt.Run("large source", func(mt *mtest.T) {
+		products := make([]models.Product, productCount)
+		for i := range products {
+			products[i] = models.Product{Title: fmt.Sprintf("상품 %d", i), URL: fmt.Sprintf("https://example.com/deal/%d", i)}
+			mt.AddMockResponses(cursorResponse(), writeResponse(1)) // not stored yet, then inserted
+		}
+		notifier := &batchNotifier{}
+		c := newTestCrawler(mt, &config.Config{}, notifier, &staticSource{name: "large", products: products})
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		want := []int{notifyBatchSize, notifyBatchSize, notifyBatchSize / 2}
+		if !reflect.DeepEqual(notifier.batches, want) {
+			mt.Errorf("notified batches of %v, want %v", notifier.batches, want)
+		}
+		if got := len(notifier.notified); got != productCount {
+			mt.Errorf("notified %d products, want %d", got, productCount)
+		}
+		stats := c.GetStats()
+		if stats.NewProducts != productCount || stats.NotifiedProducts != productCount {
+			mt.Errorf("new %d and notified %d, want %d and %d", stats.NewProducts, stats.NotifiedProducts, productCount, productCount)
+		}
+	})
+}
+
+func TestShouldStore(t *testing.T) {
+	index := NewKeywordIndex([]models.KeywordAlert{{ID: "monitor", Keyword: "모니터"}})
+
+	tests := []struct {
+		name    string
+		product models.Product
+		index   *KeywordIndex
+		want    bool
+	}{
+		{"matches an alert", models.Product{Title: "27인치 모니터"}, index, true},
+		{"matches no alert", models.Product{Title: "기계식 키보드"}, index, false},
+		{"hot without a match", models.Product{Title: "기계식 키보드", IsHot: true}, index, true},
+		{"no index stores everything", models.Product{Title: "기계식 키보드"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldStore(tt.product, tt.index); got != tt.want {
+			t.Errorf("%s: shouldStore() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunStoresOnlyMatched(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("store only matched", func(mt *mtest.T) {
+		notifier := &recordingNotifier{}
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1"},
+			{Title: "기계식 키보드", URL: "https://example.com/deal/2"},
+			{Title: "무선 마우스", URL: "https://example.com/deal/3", IsHot: true},
+		}}
+		c := newTestCrawler(mt, &config.Config{StoreOnlyMatched: true}, notifier, src)
+		mt.AddMockResponses(
+			cursorResponse(bson.D{{Key: "_id", Value: "alert-1"}, {Key: "keyword", Value: "모니터"}, {Key: "is_active", Value: true}}),
+			cursorResponse(), writeResponse(1), // the monitor is new and inserted
+			cursorResponse(), writeResponse(1), // the hot mouse is new and inserted
+		)
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		var inserted []string
+		for _, insert := range startedCommands(mt, "insert") {
+			inserted = append(inserted, insert.Lookup("documents").Array().Index(0).Value().Document().Lookup("url").StringValue())
+		}
+		want := []string{"https://example.com/deal/1", "https://example.com/deal/3"}
+		if !reflect.DeepEqual(inserted, want) {
+			mt.Errorf("inserted %v, want only the matched and hot products %v", inserted, want)
+		}
+		if got := len(notifier.notified); got != 2 {
+			mt.Errorf("notified %d products, want 2", got)
+		}
+	})
+}
+
+func TestCapProducts(t *testing.T) {
+	products := make([]models.Product, 5)
+
+	if got, capped := capProducts(products, 3); len(got) != 3 || !capped {
+		t.Errorf("capProducts(5, 3) = %d products, capped %v, want 3 and capped", len(got), capped)
+	}
+	if got, capped := capProducts(products, 5); len(got) != 5 || capped {
+		t.Errorf("capProducts(5, 5) = %d products, capped %v, want 5 and not capped", len(got), capped)
+	}
+	if got, capped := capProducts(products, 0); len(got) != 5 || capped {
+		t.Errorf("capProducts(5, 0) = %d products, capped %v, want no limit", len(got), capped)
+	}
+}
+
+func TestRunCapsProductsPerSource(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("over the cap", func(mt *mtest.T) {
+		products := make([]models.Product, 10)
+		for i := range products {
+			products[i] = models.Product{Title: fmt.Sprintf("상품 %d", i), URL: fmt.Sprintf("https://example.com/deal/%d", i)}
+		}
+		c := newTestCrawler(mt, &config.Config{DryRun: true, MaxProductsPerSource: 4}, &recordingNotifier{}, &staticSource{name: "runaway", products: products})
+		for i := 0; i < 4; i++ {
+			mt.AddMockResponses(cursorResponse())
+		}
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		stats := c.GetStats()
+		if got := len(startedCommands(mt, "find")); got != 4 {
+			mt.Errorf("looked up %d products, want the first 4", got)
+		}
+		if stats.WouldInsertProducts != 4 {
+			mt.Errorf("would insert %d products, want 4", stats.WouldInsertProducts)
+		}
+		if source := stats.SourceStats["runaway"]; !source.Capped || source.ProductsFound != 4 {
+			mt.Errorf("source stats = %+v, want capped at 4 products", source)
+		}
+	})
+}
+
+func TestProductChangeSet(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC)
+	existing := models.Product{KOPrice: 300000, Comments: 3, Views: 120}
+
+	tests := []struct {
+		name     string
+		existing models.Product
+		change   func(p *models.Product)
+		want     bson.M
+	}{
+		{"unchanged", existing, func(p *models.Product) {}, bson.M{"last_seen_at": now}},
+		{"more comments", existing, func(p *models.Product) { p.Comments = 10 }, bson.M{"last_seen_at": now, "comments": 10}},
+		{"views only", existing, func(p *models.Product) { p.Views = 500 }, bson.M{"last_seen_at": now, "views": 500}},
+		{"sold out", existing, func(p *models.Product) { p.SoldOut = true }, bson.M{"last_seen_at": now, "sold_out": true}},
+		{"became hot", existing, func(p *models.Product) { p.IsHot = true }, bson.M{"last_seen_at": now, "is_hot": true}},
+		{"reappeared", models.Product{KOPrice: 300000, Comments: 3, Views: 120, Expired: true}, func(p *models.Product) { p.Expired = false }, bson.M{"last_seen_at": now, "expired": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawled := tt.existing
+			tt.change(&crawled)
+
+			if got := productChangeSet(&tt.existing, crawled, crawled.Diff(&tt.existing), now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("productChangeSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunUpdatesRecrawledProduct(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("more comments", func(mt *mtest.T) {
+		notifier := &recordingNotifier{}
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1", Comments: 10, Views: 120},
+		}}
+		c := newTestCrawler(mt, &config.Config{}, notifier, src)
+		firstSeen := time.Date(2026, time.October, 15, 9, 0, 0, 0, time.UTC)
+		mt.AddMockResponses(
+			cursorResponse(bson.D{
+				{Key: "_id", Value: "deal-1"},
+				{Key: "title", Value: "27인치 모니터"},
+				{Key: "url", Value: "https://example.com/deal/1"},
+				{Key: "comments", Value: 3},
+				{Key: "views", Value: 120},
+				{Key: "crawled_at", Value: firstSeen},
+				{Key: "notified", Value: true},
+			}),
+			writeResponse(1),
+		)
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		if inserts := startedCommands(mt, "insert"); len(inserts) != 0 {
+			mt.Errorf("inserted %d documents, want the existing product updated instead", len(inserts))
+		}
+		updates := startedCommands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d updates, want 1", len(updates))
+		}
+		update := updates[0].Lookup("updates").Array().Index(0).Value().Document()
+		if id := update.Lookup("q", "_id").StringValue(); id != "deal-1" {
+			mt.Errorf("updated _id %q, want the stored product", id)
+		}
+		set := update.Lookup("u", "$set").Document()
+		if comments := set.Lookup("comments").Int32(); comments != 10 {
+			mt.Errorf("set comments to %d, want 10", comments)
+		}
+		if _, err := set.LookupErr("crawled_at"); err == nil {
+			mt.Error("update reset crawled_at, want the time the deal was first found kept")
+		}
+		if _, err := set.LookupErr("views"); err == nil {
+			mt.Error("update set unchanged views")
+		}
+		if len(notifier.notified) != 0 {
+			mt.Errorf("notified %d products, want a re-crawled product not notified again", len(notifier.notified))
+		}
+	})
+}
+
+// changeNotifier is a recordingNotifier that also records the changed
+// products it is asked to notify again
+type changeNotifier struct {
+	recordingNotifier
+	changed []models.Product
+}
+
+func (c *changeNotifier) NotifyChangedProducts(ctx context.Context, products []models.Product) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changed = append(c.changed, products...)
+	return nil
+}
+
+func TestRunRenotifiesNotableChanges(t *testing.T) {
+	mt := newMockTest(t)
+	stored := func(url string, price int) bson.D {
+		return bson.D{
+			{Key: "_id", Value: models.ProductIDFromURL(url)},
+			{Key: "title", Value: "27인치 모니터"},
+			{Key: "url", Value: url},
+			{Key: "ko_price", Value: price},
+			{Key: "comments", Value: 1},
+		}
+	}
+
+	mt.Run("price drop and comments", func(mt *mtest.T) {
+		notifier := &changeNotifier{}
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1", KOPrice: 250000, Comments: 1},
+			{Title: "27인치 모니터", URL: "https://example.com/deal/2", KOPrice: 300000, Comments: 5},
+		}}
+		c := newTestCrawler(mt, &config.Config{}, notifier, src)
+		mt.AddMockResponses(
+			cursorResponse(stored("https://example.com/deal/1", 300000)), writeResponse(1),
+			cursorResponse(stored("https://example.com/deal/2", 300000)), writeResponse(1),
+		)
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		if len(notifier.notified) != 0 {
+			mt.Errorf("notified %d changed products as new, want 0", len(notifier.notified))
+		}
+		if len(notifier.changed) != 1 || notifier.changed[0].URL != "https://example.com/deal/1" {
+			mt.Errorf("re-notified %+v, want only the price drop", notifier.changed)
+		}
+	})
+
+	mt.Run("notifier can't re-notify", func(mt *mtest.T) {
+		notifier := &recordingNotifier{}
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1", KOPrice: 250000, Comments: 1},
+		}}
+		c := newTestCrawler(mt, &config.Config{}, notifier, src)
+		mt.AddMockResponses(cursorResponse(stored("https://example.com/deal/1", 300000)), writeResponse(1))
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+		if len(notifier.notified) != 0 {
+			mt.Errorf("notified %d changed products as new, want 0", len(notifier.notified))
+		}
+	})
+}
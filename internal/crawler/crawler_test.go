@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestCrawlerNotifiesNewProducts(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("spy notifier", func(mt *mtest.T) {
+		cfg := &config.Config{}
+		spy := &recordingNotifier{}
+		c := &Crawler{
+			config:   cfg,
+			log:      zap.NewNop(),
+			db:       newMockDB(mt, cfg),
+			notifier: spy,
+			sources: []SourceInterface{&staticSource{name: "test", products: []models.Product{
+				{Title: "27인치 모니터", URL: "https://example.com/deal/1"},
+				{Title: "기계식 키보드", URL: "https://example.com/deal/2"},
+			}}},
+		}
+		mt.AddMockResponses(
+			cursorResponse(bson.D{{Key: "n", Value: 0}}), // first product is new
+			writeResponse(1),
+			cursorResponse(bson.D{{Key: "n", Value: 1}}), // second is already stored
+		)
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		if len(spy.notified) != 1 || spy.notified[0].URL != "https://example.com/deal/1" {
+			mt.Fatalf("notifier received %+v, want only the new product", spy.notified)
+		}
+		if spy.notified[0].ID != models.ProductIDFromURL("https://example.com/deal/1") {
+			mt.Errorf("notified product ID = %q, want it derived from the URL", spy.notified[0].ID)
+		}
+	})
+}
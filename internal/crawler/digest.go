@@ -0,0 +1,150 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+const (
+	// digestWindow는 주간 다이제스트가 집계하는 기간입니다
+	digestWindow = 7 * 24 * time.Hour
+	// digestProductLimit는 다이제스트에 포함할 최대 상품 수입니다
+	digestProductLimit = 30
+	// digestPageSize는 임베드 한 페이지에 표시할 상품 수입니다
+	digestPageSize = 10
+)
+
+// WeeklyDigest posts a "주간 인기 특가" digest of the past week's most popular
+// deals to the broadcast channel once a week
+type WeeklyDigest struct {
+	notifier  EmbedSender
+	repo      *storage.ProductRepository
+	log       *zap.Logger
+	channelID string
+	weekday   time.Weekday
+	hour      int
+	minute    int
+}
+
+// NewWeeklyDigest creates a new weekly digest job posting to channelID at
+// the given weekday and time of day
+func NewWeeklyDigest(notifier EmbedSender, db *storage.MongoDB, log *zap.Logger, channelID string, weekday time.Weekday, at time.Time) *WeeklyDigest {
+	return &WeeklyDigest{
+		notifier:  notifier,
+		repo:      storage.NewProductRepository(db, log),
+		log:       log.Named("weekly-digest"),
+		channelID: channelID,
+		weekday:   weekday,
+		hour:      at.Hour(),
+		minute:    at.Minute(),
+	}
+}
+
+// Run posts the digest on schedule until the context is canceled
+func (d *WeeklyDigest) Run(ctx context.Context) {
+	for {
+		next := nextWeeklyFireTime(time.Now(), d.weekday, d.hour, d.minute)
+		d.log.Info("Next weekly digest scheduled", zap.Time("at", next))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			if err := d.Post(ctx, time.Now()); err != nil {
+				d.log.Error("Failed to post weekly digest", zap.Error(err))
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			d.log.Info("Stopping weekly digest")
+			return
+		}
+	}
+}
+
+// Post sends the digest for the week ending at now
+func (d *WeeklyDigest) Post(ctx context.Context, now time.Time) error {
+	from := now.Add(-digestWindow)
+
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	products, err := d.repo.GetPopularProducts(queryCtx, from, now, digestProductLimit)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if len(products) == 0 {
+		d.log.Info("No products for weekly digest")
+		return nil
+	}
+
+	embeds := buildDigestEmbeds(products, from, now)
+	if err := d.notifier.SendEmbeds(ctx, d.channelID, embeds); err != nil {
+		return fmt.Errorf("failed to send weekly digest: %w", err)
+	}
+
+	d.log.Info("Posted weekly digest",
+		zap.Int("products", len(products)),
+		zap.Int("pages", len(embeds)))
+	return nil
+}
+
+// buildDigestEmbeds renders products as numbered digest pages of digestPageSize entries
+func buildDigestEmbeds(products []models.Product, from, to time.Time) []*discordgo.MessageEmbed {
+	pages := (len(products) + digestPageSize - 1) / digestPageSize
+	digestEmbeds := make([]*discordgo.MessageEmbed, 0, pages)
+
+	for page := 0; page < pages; page++ {
+		start := page * digestPageSize
+		end := start + digestPageSize
+		if end > len(products) {
+			end = len(products)
+		}
+
+		var description strings.Builder
+		for i, product := range products[start:end] {
+			line := fmt.Sprintf("**%d.** [%s](%s)\n%s · 💬 %d · 👀 %d\n",
+				start+i+1,
+				embeds.Truncate(models.SanitizeTitle(product.Title), 100),
+				product.URL,
+				product.GetPriceString(),
+				product.Comments,
+				product.Views)
+			description.WriteString(line)
+		}
+
+		title := "🔥 주간 인기 특가"
+		if pages > 1 {
+			title = fmt.Sprintf("%s (%d/%d)", title, page+1, pages)
+		}
+
+		digestEmbeds = append(digestEmbeds, &discordgo.MessageEmbed{
+			Title:       title,
+			Description: embeds.Truncate(description.String(), embeds.DescriptionLimit),
+			Color:       0xFF4500, // Orange red
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("%s ~ %s", from.Format("2006-01-02"), to.Format("2006-01-02")),
+			},
+			Timestamp: to.Format(time.RFC3339),
+		})
+	}
+
+	return digestEmbeds
+}
+
+// nextWeeklyFireTime returns the first time strictly after now that falls on
+// weekday at hour:minute in now's location
+func nextWeeklyFireTime(now time.Time, weekday time.Weekday, hour, minute int) time.Time {
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	next := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
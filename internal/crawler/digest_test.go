@@ -0,0 +1,68 @@
+package crawler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+func TestBuildDigestEmbeds(t *testing.T) {
+	to := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	from := to.Add(-digestWindow)
+
+	var products []models.Product
+	for i := 0; i < 25; i++ {
+		products = append(products, models.Product{Title: fmt.Sprintf("특가 %d", i+1), URL: fmt.Sprintf("https://example.com/deal/%d", i+1), Comments: 100 - i})
+	}
+
+	pages := buildDigestEmbeds(products, from, to)
+
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+	if pages[0].Title != "🔥 주간 인기 특가 (1/3)" {
+		t.Errorf("first title = %q, want the page number", pages[0].Title)
+	}
+	if !strings.HasPrefix(pages[2].Description, "**21.** [특가 21]") {
+		t.Errorf("last page starts with %q, want entry 21", pages[2].Description)
+	}
+	if pages[0].Footer.Text != "2026-10-09 ~ 2026-10-16" {
+		t.Errorf("footer = %q, want the week's range", pages[0].Footer.Text)
+	}
+
+	if single := buildDigestEmbeds(products[:5], from, to); len(single) != 1 || single[0].Title != "🔥 주간 인기 특가" {
+		t.Errorf("a single page = %+v, want one page without a number", single)
+	}
+	if empty := buildDigestEmbeds(nil, from, to); len(empty) != 0 {
+		t.Errorf("no products gave %d pages, want none", len(empty))
+	}
+}
+
+func TestNextWeeklyFireTime(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, kst)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"earlier in the week", at(14, 12, 0), at(19, 9, 0)},
+		{"same day before", at(19, 8, 0), at(19, 9, 0)},
+		{"same day at the time", at(19, 9, 0), at(26, 9, 0)},
+		{"same day after", at(19, 10, 0), at(26, 9, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextWeeklyFireTime(tt.now, time.Monday, 9, 0); !got.Equal(tt.want) {
+				t.Errorf("nextWeeklyFireTime(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
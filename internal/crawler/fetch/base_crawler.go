@@ -0,0 +1,283 @@
+// Package fetch downloads pages for the crawl sources: retries, decompression,
+// charset conversion, a body size limit and optional HTML snapshots. It imports
+// nothing else from the crawler, so both the crawler and its sources can use it.
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxRetries        = 3
+	defaultTimeout    = 30 * time.Second
+	retryWaitDuration = 2 * time.Second
+)
+
+// xmlDeclEncoding matches the encoding in a leading XML declaration,
+// e.g. <?xml version="1.0" encoding="euc-kr"?>
+var xmlDeclEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*\sencoding=["']([\w.:-]+)["']`)
+
+// ErrResponseTooLarge is returned by FetchURL when a response body exceeds MaxBodyBytes
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// BaseCrawler provides common functionality for all crawlers
+type BaseCrawler struct {
+	Client       *http.Client
+	Logger       *zap.Logger
+	Headers      map[string]string
+	Snapshots    *SnapshotStore // nil이면 가져온 HTML을 저장하지 않음
+	MaxBodyBytes int64          // 응답 본문 최대 크기 (0이면 제한 없음)
+}
+
+// NewBaseCrawler creates a new base crawler with default settings
+func NewBaseCrawler(log *zap.Logger) *BaseCrawler {
+	return &BaseCrawler{
+		Client: &http.Client{
+			Timeout: defaultTimeout,
+		},
+		Logger:  log.Named("base-crawler"),
+		Headers: getDefaultHeaders(),
+	}
+}
+
+// FetchURL retrieves the content of a URL with retry logic.
+// The content is returned as UTF-8 whatever charset the page was served in.
+func (c *BaseCrawler) FetchURL(ctx context.Context, url string) ([]byte, error) {
+	var (
+		resp    *http.Response
+		content []byte
+		retries = 0
+	)
+
+	for retries < maxRetries {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			c.Logger.Error("Failed to create request", zap.Error(err), zap.String("url", url))
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Set headers
+		for key, value := range c.Headers {
+			req.Header.Set(key, value)
+		}
+
+		// Execute request
+		resp, err = c.Client.Do(req)
+		if err != nil {
+			c.Logger.Warn("HTTP request failed",
+				zap.Error(err),
+				zap.String("url", url),
+				zap.Int("attempt", retries+1))
+
+			retries++
+			if retries < maxRetries {
+				time.Sleep(retryWaitDuration)
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch URL after %d attempts: %w", maxRetries, err)
+		}
+		defer resp.Body.Close()
+
+		// Check status code
+		if resp.StatusCode != http.StatusOK {
+			c.Logger.Warn("Non-OK HTTP status",
+				zap.Int("status", resp.StatusCode),
+				zap.String("url", url),
+				zap.Int("attempt", retries+1))
+
+			retries++
+			if retries < maxRetries {
+				time.Sleep(retryWaitDuration)
+				continue
+			}
+			return nil, fmt.Errorf("failed to fetch URL after %d attempts: status code %d", maxRetries, resp.StatusCode)
+		}
+
+		// Refuse bodies over the limit up front when the size is announced
+		if c.MaxBodyBytes > 0 && resp.ContentLength > c.MaxBodyBytes {
+			return nil, fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrResponseTooLarge, resp.ContentLength, c.MaxBodyBytes)
+		}
+
+		// Read response body, decompressing it if the site sent it encoded
+		body, err := decodeBody(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response body: %w", err)
+		}
+		content, err = c.readBody(body)
+		if errors.Is(err, ErrResponseTooLarge) {
+			// The same page would be too large again, so don't retry
+			return nil, err
+		}
+		if err != nil {
+			c.Logger.Warn("Failed to read response body",
+				zap.Error(err),
+				zap.String("url", url),
+				zap.Int("attempt", retries+1))
+
+			retries++
+			if retries < maxRetries {
+				time.Sleep(retryWaitDuration)
+				continue
+			}
+			return nil, fmt.Errorf("failed to read response body after %d attempts: %w", maxRetries, err)
+		}
+
+		// Parsers expect UTF-8; some Korean sites still serve EUC-KR
+		content, err = toUTF8(content, resp.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", url, err)
+		}
+
+		c.Logger.Debug("Successfully fetched URL",
+			zap.String("url", url),
+			zap.Int("content_length", len(content)))
+
+		c.saveSnapshot(url, content)
+		return content, nil
+	}
+
+	return nil, fmt.Errorf("failed to fetch URL after %d attempts", maxRetries)
+}
+
+// FetchDocument fetches a page with FetchURL and parses it as HTML. The page
+// goes through the same retries, decompression, charset conversion, size limit
+// and snapshotting, and is parsed from the fetched bytes without copying them.
+func (c *BaseCrawler) FetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
+	content, err := c.FetchURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML from %s: %w", url, err)
+	}
+	return doc, nil
+}
+
+// decodeBody returns a reader that decompresses the response body according
+// to its Content-Encoding. Setting Accept-Encoding ourselves turns off the
+// transport's own gzip handling, so gzip and deflate are both decoded here.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return resp.Body, nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" should be zlib-wrapped, but some servers send raw deflate data
+		body := bufio.NewReader(resp.Body)
+		header, err := body.Peek(2)
+		if err == nil && isZlibHeader(header) {
+			return zlib.NewReader(body)
+		}
+		return flate.NewReader(body), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", resp.Header.Get("Content-Encoding"))
+	}
+}
+
+// isZlibHeader reports whether the first two bytes of a stream are a zlib header
+// (deflate compression method and a valid header checksum)
+func isZlibHeader(header []byte) bool {
+	return len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// toUTF8 transcodes an HTML page or XML feed to UTF-8. The charset is taken
+// from a byte order mark, the Content-Type header, an XML declaration or a
+// <meta charset> tag, in that order; undeclared pages that are valid UTF-8
+// are returned unchanged.
+func toUTF8(content []byte, contentType string) ([]byte, error) {
+	encoding, name, certain := charset.DetermineEncoding(content, contentType)
+	if !certain {
+		if match := xmlDeclEncoding.FindSubmatch(content); match != nil {
+			if declared, declaredName := charset.Lookup(string(match[1])); declared != nil {
+				encoding, name = declared, declaredName
+			}
+		}
+	}
+	if name == "utf-8" {
+		return content, nil
+	}
+
+	decoded, err := encoding.NewDecoder().Bytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcode from %s: %w", name, err)
+	}
+	return decoded, nil
+}
+
+// readBody reads a response body, failing with ErrResponseTooLarge instead of
+// reading past MaxBodyBytes
+func (c *BaseCrawler) readBody(body io.Reader) ([]byte, error) {
+	if c.MaxBodyBytes <= 0 {
+		return io.ReadAll(body)
+	}
+
+	// Read one byte past the limit to tell a body of exactly the limit from a larger one
+	content, err := io.ReadAll(io.LimitReader(body, c.MaxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > c.MaxBodyBytes {
+		return nil, fmt.Errorf("%w: exceeds limit of %d bytes", ErrResponseTooLarge, c.MaxBodyBytes)
+	}
+	return content, nil
+}
+
+// SetFetchLimits sets the HTTP client timeout and the maximum response body
+// size FetchURL will read. A non-positive timeout keeps the current one and a
+// non-positive maxBodyBytes disables the size limit.
+func (c *BaseCrawler) SetFetchLimits(timeout time.Duration, maxBodyBytes int64) {
+	if timeout > 0 {
+		c.Client.Timeout = timeout
+	}
+	c.MaxBodyBytes = maxBodyBytes
+}
+
+// EnableSnapshots saves every fetched page to store for offline debugging
+func (c *BaseCrawler) EnableSnapshots(store *SnapshotStore) {
+	c.Snapshots = store
+}
+
+// saveSnapshot stores fetched content when snapshots are enabled; failures are only logged
+func (c *BaseCrawler) saveSnapshot(url string, content []byte) {
+	if c.Snapshots == nil {
+		return
+	}
+
+	path, err := c.Snapshots.Save(content, time.Now())
+	if err != nil {
+		c.Logger.Warn("Failed to save HTML snapshot", zap.Error(err), zap.String("url", url))
+		return
+	}
+	c.Logger.Debug("Saved HTML snapshot", zap.String("url", url), zap.String("path", path))
+}
+
+// getDefaultHeaders returns common headers for HTTP requests
+func getDefaultHeaders() map[string]string {
+	return map[string]string{
+		"User-Agent":      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.5",
+		"Accept-Encoding": "gzip, deflate",
+		"Cache-Control":   "no-cache",
+		"Pragma":          "no-cache",
+	}
+}
@@ -0,0 +1,215 @@
+package fetch
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/korean"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestFetchURLEnforcesBodyLimit(t *testing.T) {
+	page := strings.Repeat("a", 2048)
+	tests := []struct {
+		name    string
+		limit   int64
+		chunked bool
+		wantErr bool
+	}{
+		{name: "announced size over the limit", limit: 1024, wantErr: true},
+		{name: "unannounced size over the limit", limit: 1024, chunked: true, wantErr: true},
+		{name: "exactly the limit", limit: 2048, chunked: true},
+		{name: "no limit", limit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				if tt.chunked {
+					// Flushing before the body is written drops the Content-Length
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(page))
+			}))
+			defer server.Close()
+
+			c := NewBaseCrawler(zap.NewNop())
+			c.SetFetchLimits(0, tt.limit)
+			content, err := c.FetchURL(context.Background(), server.URL)
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Fatalf("FetchURL() error = %v, want ErrResponseTooLarge", err)
+				}
+				if got := requests.Load(); got != 1 {
+					t.Errorf("sent %d requests, want no retry of an oversized page", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchURL() error = %v", err)
+			}
+			if string(content) != page {
+				t.Errorf("FetchURL() returned %d bytes, want the whole %d byte page", len(content), len(page))
+			}
+		})
+	}
+}
+
+func TestSetFetchLimits(t *testing.T) {
+	c := NewBaseCrawler(zap.NewNop())
+	if c.Client.Timeout != defaultTimeout || c.MaxBodyBytes != 0 {
+		t.Errorf("defaults = %v and %d bytes, want %v and no limit", c.Client.Timeout, c.MaxBodyBytes, defaultTimeout)
+	}
+
+	c.SetFetchLimits(5*time.Second, 1<<20)
+	if c.Client.Timeout != 5*time.Second || c.MaxBodyBytes != 1<<20 {
+		t.Errorf("limits = %v and %d bytes, want 5s and 1 MiB", c.Client.Timeout, c.MaxBodyBytes)
+	}
+
+	c.SetFetchLimits(0, 0)
+	if c.Client.Timeout != 5*time.Second || c.MaxBodyBytes != 0 {
+		t.Errorf("limits = %v and %d bytes, want the timeout kept and no size limit", c.Client.Timeout, c.MaxBodyBytes)
+	}
+}
+
+func TestFetchURLDecompressesBody(t *testing.T) {
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":         func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"zlib deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw deflate": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+	}
+	encodings := map[string]string{"gzip": "gzip", "zlib deflate": "deflate", "raw deflate": "deflate"}
+
+	for name, newWriter := range compress {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); !strings.Contains(got, "gzip") {
+					t.Errorf("Accept-Encoding = %q, want gzip advertised", got)
+				}
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Header().Set("Content-Encoding", encodings[name])
+				cw := newWriter(w)
+				cw.Write([]byte(snapshotPage))
+				cw.Close()
+			}))
+			defer server.Close()
+
+			content, err := NewBaseCrawler(zap.NewNop()).FetchURL(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("FetchURL() error = %v", err)
+			}
+			if string(content) != snapshotPage {
+				t.Errorf("FetchURL() = %q, want the decompressed page", content)
+			}
+		})
+	}
+}
+
+func TestFetchURLTranscodesEUCKR(t *testing.T) {
+	const title = "[11번가] 삼성 27인치 모니터 199,000원"
+	tests := []struct {
+		name        string
+		contentType string
+		page        string
+	}{
+		{
+			name:        "charset in header",
+			contentType: "text/html; charset=euc-kr",
+			page:        "<html><head><title>" + title + "</title></head></html>",
+		},
+		{
+			name:        "charset in meta tag",
+			contentType: "text/html",
+			page:        `<html><head><meta http-equiv="Content-Type" content="text/html; charset=euc-kr"><title>` + title + "</title></head></html>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := korean.EUCKR.NewEncoder().String(tt.page)
+			if err != nil {
+				t.Fatalf("failed to encode fixture: %v", err)
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(encoded))
+			}))
+			defer server.Close()
+
+			content, err := NewBaseCrawler(zap.NewNop()).FetchURL(context.Background(), server.URL)
+			if err != nil {
+				t.Fatalf("FetchURL() error = %v", err)
+			}
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("failed to parse page: %v", err)
+			}
+			if got := doc.Find("title").Text(); got != title {
+				t.Errorf("title = %q, want %q", got, title)
+			}
+		})
+	}
+}
+
+func TestFetchDocument(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write([]byte(snapshotPage))
+	gz.Close()
+
+	c := NewBaseCrawler(zap.NewNop())
+	c.Client.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.String() != "https://example.com/deals" {
+			t.Errorf("requested %s, want https://example.com/deals", r.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header: http.Header{
+				"Content-Type":     []string{"text/html; charset=utf-8"},
+				"Content-Encoding": []string{"gzip"},
+			},
+			Body:    io.NopCloser(bytes.NewReader(compressed.Bytes())),
+			Request: r,
+		}, nil
+	})
+
+	doc, err := c.FetchDocument(context.Background(), "https://example.com/deals")
+	if err != nil {
+		t.Fatalf("FetchDocument() error = %v", err)
+	}
+	if got := doc.Find("a.deal").Text(); got != "27인치 모니터" {
+		t.Errorf("deal = %q, want the parsed link text", got)
+	}
+
+	// A fetch failure is returned as is, so callers can still match it
+	c.SetFetchLimits(0, 10)
+	if _, err := c.FetchDocument(context.Background(), "https://example.com/deals"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("FetchDocument() error = %v, want ErrResponseTooLarge", err)
+	}
+}
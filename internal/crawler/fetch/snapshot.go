@@ -0,0 +1,83 @@
+package fetch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// snapshotTimeFormat names snapshot files so they sort chronologically
+const snapshotTimeFormat = "20060102T150405.000000000Z"
+
+// SnapshotStore saves fetched HTML pages for one source to disk so a broken
+// parser can be replayed offline against the exact page. Only the newest
+// snapshots are kept.
+type SnapshotStore struct {
+	dir  string
+	keep int
+	log  *zap.Logger
+	mu   sync.Mutex
+}
+
+// NewSnapshotStore creates a store keeping the last keep snapshots of source
+// under baseDir/<source>
+func NewSnapshotStore(baseDir, source string, keep int, log *zap.Logger) *SnapshotStore {
+	return &SnapshotStore{
+		dir:  filepath.Join(baseDir, strings.ToLower(source)),
+		keep: keep,
+		log:  log.Named("snapshots"),
+	}
+}
+
+// Save writes content to a new timestamped file and prunes old snapshots.
+// It returns the path of the written file.
+func (s *SnapshotStore) Save(content []byte, fetchedAt time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	path := filepath.Join(s.dir, fetchedAt.UTC().Format(snapshotTimeFormat)+".html")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := s.prune(); err != nil {
+		s.log.Warn("Failed to prune old snapshots", zap.Error(err), zap.String("dir", s.dir))
+	}
+	return path, nil
+}
+
+// prune removes all but the newest keep snapshots
+func (s *SnapshotStore) prune() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".html") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= s.keep {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-s.keep] {
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
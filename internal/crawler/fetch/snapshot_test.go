@@ -0,0 +1,86 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const snapshotPage = "<html><body><a class=\"deal\">27인치 모니터</a></body></html>"
+
+func newPageServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(snapshotPage))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchURLSavesSnapshotWhenEnabled(t *testing.T) {
+	server := newPageServer(t)
+	dir := t.TempDir()
+
+	c := NewBaseCrawler(zap.NewNop())
+	c.EnableSnapshots(NewSnapshotStore(dir, "Ppomppu", 5, zap.NewNop()))
+	if _, err := c.FetchURL(context.Background(), server.URL); err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "ppomppu", "*.html"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("snapshots = %v (%v), want one under the lowercased source name", files, err)
+	}
+	content, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+	if string(content) != snapshotPage {
+		t.Errorf("snapshot = %q, want the fetched page", content)
+	}
+}
+
+func TestFetchURLSkipsSnapshotWhenDisabled(t *testing.T) {
+	server := newPageServer(t)
+	dir := t.TempDir()
+
+	c := NewBaseCrawler(zap.NewNop())
+	if _, err := c.FetchURL(context.Background(), server.URL); err != nil {
+		t.Fatalf("FetchURL() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("wrote %d entries with snapshots disabled, want none", len(entries))
+	}
+}
+
+func TestSnapshotStoreKeepsNewest(t *testing.T) {
+	store := NewSnapshotStore(t.TempDir(), "rss", 2, zap.NewNop())
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i := 0; i < 4; i++ {
+		path, err := store.Save([]byte("page"), start.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	for i, path := range paths {
+		_, err := os.Stat(path)
+		if kept := err == nil; kept != (i >= 2) {
+			t.Errorf("snapshot %d kept = %v, want only the newest 2 kept", i, kept)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// recordSourceHealth stores whether a source's crawl succeeded and reports
+// whether it just recovered: it failed on its previous run and succeeded now.
+// A source's first run is never a recovery.
+func recordSourceHealth(health map[string]bool, source string, healthy bool) (recovered bool) {
+	was, known := health[source]
+	health[source] = healthy
+	return healthy && known && !was
+}
+
+// alertSourceRecovered tells operators that a failing source works again, so
+// they don't have to keep checking it by hand
+func (c *ImprovedCrawler) alertSourceRecovered(ctx context.Context, source string, count int) {
+	c.log.Info("Source recovered", zap.String("source", source), zap.Int("products_found", count))
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("✅ %s 복구됨", source),
+		Description: fmt.Sprintf("이전 실행에서 실패했던 소스가 다시 정상적으로 수집되었습니다 (상품 %d개).", count),
+		Color:       0x00CC66,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "크롤러 운영 알림",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if err := c.sendOpsAlert(ctx, embed); err != nil {
+		c.log.Error("Failed to send source recovery alert", zap.Error(err), zap.String("source", source))
+	}
+}
+
+// sendOpsAlert posts an embed to the ops channel. It does nothing when no ops
+// channel is configured, in dry-run mode, or when the notifier can't post embeds.
+func (c *ImprovedCrawler) sendOpsAlert(ctx context.Context, embed *discordgo.MessageEmbed) error {
+	if c.config.OpsAlertChannelID == "" || c.config.DryRun {
+		return nil
+	}
+	sender, ok := c.notifier.(EmbedSender)
+	if !ok {
+		return nil
+	}
+
+	return sender.SendEmbeds(ctx, c.config.OpsAlertChannelID, []*discordgo.MessageEmbed{embed})
+}
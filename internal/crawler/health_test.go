@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRecordSourceHealth(t *testing.T) {
+	health := make(map[string]bool)
+
+	steps := []struct {
+		healthy   bool
+		recovered bool
+	}{
+		{true, false},  // a first run is never a recovery
+		{false, false}, // failing isn't a recovery
+		{false, false},
+		{true, true}, // failed last run, works now
+		{true, false},
+	}
+	for i, step := range steps {
+		if got := recordSourceHealth(health, "ppomppu", step.healthy); got != step.recovered {
+			t.Errorf("run %d: recordSourceHealth(%v) = %v, want %v", i, step.healthy, got, step.recovered)
+		}
+	}
+
+	if recordSourceHealth(health, "new-source", true) {
+		t.Error("a new source's first healthy run counted as a recovery")
+	}
+}
+
+func TestRunAlertsOnceWhenSourceRecovers(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("unhealthy then healthy", func(mt *mtest.T) {
+		notifier := &embedNotifier{embeds: make(map[string][]*discordgo.MessageEmbed)}
+		failing := true
+		src := &staticSource{name: "ppomppu", crawl: func(ctx context.Context) ([]models.Product, error) {
+			if failing {
+				return nil, errors.New("site is down")
+			}
+			return []models.Product{}, nil
+		}}
+		c := newTestCrawler(mt, &config.Config{OpsAlertChannelID: "ops"}, notifier, src)
+
+		if err := c.Run(context.Background()); err == nil {
+			mt.Fatal("Run() error = nil, want the failing source's error")
+		}
+		failing = false
+		for i := 0; i < 3; i++ {
+			if err := c.Run(context.Background()); err != nil {
+				mt.Fatalf("Run() error = %v", err)
+			}
+		}
+
+		alerts := notifier.embeds["ops"]
+		if len(alerts) != 1 {
+			mt.Fatalf("sent %d ops alerts, want exactly one recovery alert", len(alerts))
+		}
+		if !strings.Contains(alerts[0].Title, "ppomppu 복구됨") {
+			mt.Errorf("alert title = %q, want the recovery of ppomppu", alerts[0].Title)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.uber.org/zap"
+)
+
+// httpShutdownTimeout bounds how long in-flight requests get when the server stops
+const httpShutdownTimeout = 5 * time.Second
+
+// ShortLinkStore looks up click-tracking short links and records their clicks
+type ShortLinkStore interface {
+	GetLink(ctx context.Context, token string) (*models.ShortLink, error)
+	RecordClick(ctx context.Context, click *models.Click) error
+}
+
+// HTTPServer exposes crawler health and stats, serves click-tracking redirects
+// and, when enabled, the read-only JSON API
+type HTTPServer struct {
+	server  *http.Server
+	crawler *ImprovedCrawler
+	links   ShortLinkStore
+	log     *zap.Logger
+}
+
+// NewHTTPServer creates an HTTP server for the crawler listening on addr.
+// A nil api leaves the /api routes disabled.
+func NewHTTPServer(addr string, crawler *ImprovedCrawler, links ShortLinkStore, api *APIHandler, log *zap.Logger) *HTTPServer {
+	s := &HTTPServer{
+		crawler: crawler,
+		links:   links,
+		log:     log.Named("http-server"),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /r/{token}", s.handleRedirect)
+	if api != nil {
+		api.Register(mux)
+		// Stats include error messages, so they need the key once one is set
+		mux.Handle("GET /stats", api.authorize(s.handleStats))
+	} else {
+		mux.HandleFunc("GET /stats", s.handlePublicStats)
+	}
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Run serves requests until the context is canceled
+func (s *HTTPServer) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := s.server.Shutdown(shutdownCtx); err != nil {
+			s.log.Warn("HTTP server shutdown failed", zap.Error(err))
+		}
+	}()
+
+	s.log.Info("Starting HTTP server", zap.String("addr", s.server.Addr))
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.log.Error("HTTP server failed", zap.Error(err))
+	}
+}
+
+// handleHealth reports whether each source is healthy, without error details
+func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.crawler.Health())
+}
+
+// handleStats reports crawler statistics
+func (s *HTTPServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.crawler.GetStats())
+}
+
+// handlePublicStats reports crawler statistics without error messages, for
+// when no API key is configured and anyone can read them
+func (s *HTTPServer) handlePublicStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, publicStats(s.crawler.GetStats()))
+}
+
+// publicStats clears the error messages in stats, which can name internal
+// hosts or carry query details
+func publicStats(stats CrawlerStats) CrawlerStats {
+	stats.LastError = ""
+	for name, source := range stats.SourceStats {
+		source.LastError = ""
+		stats.SourceStats[name] = source
+	}
+	return stats
+}
+
+// handleRedirect logs a click on a short link and redirects to the product URL.
+// The click is recorded in the background so the redirect isn't delayed by the write.
+func (s *HTTPServer) handleRedirect(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+
+	link, err := s.links.GetLink(r.Context(), token)
+	if err != nil {
+		s.log.Error("Failed to look up short link", zap.Error(err), zap.String("token", token))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if link == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	click := &models.Click{
+		Token:     link.Token,
+		URL:       link.URL,
+		UserAgent: r.UserAgent(),
+		Referer:   r.Referer(),
+		ClickedAt: time.Now(),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.links.RecordClick(ctx, click); err != nil {
+			s.log.Warn("Failed to record click", zap.Error(err), zap.String("token", click.Token))
+		}
+	}()
+
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
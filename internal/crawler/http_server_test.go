@@ -0,0 +1,154 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.uber.org/zap"
+)
+
+// newStatsCrawler returns a crawler whose last run failed with an error
+// message that must not be served publicly
+func newStatsCrawler() *ImprovedCrawler {
+	return &ImprovedCrawler{
+		stats: CrawlerStats{
+			RunCount:  3,
+			LastError: "dial tcp 10.0.0.5:27017: connection refused",
+			SourceStats: map[string]SourceStats{
+				"ppomppu": {ProductsFound: 0, LastError: "GET http://internal-proxy:3128: timeout"},
+			},
+		},
+		healthStatus: map[string]bool{"ppomppu": false},
+	}
+}
+
+func serveHTTP(s *HTTPServer, target, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHTTPServerStatsAuth(t *testing.T) {
+	api := NewAPIHandler(&fakeProducts{}, &fakeAlerts{}, nil, testAPIKey, zap.NewNop())
+	s := NewHTTPServer(":0", newStatsCrawler(), nil, api, zap.NewNop())
+
+	if rec := serveHTTP(s, "/stats", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("/stats without key: status = %d, want 401", rec.Code)
+	}
+
+	rec := serveHTTP(s, "/stats", testAPIKey)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/stats with key: status = %d, want 200", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "connection refused") {
+		t.Errorf("/stats with key = %s, want the error details", rec.Body)
+	}
+}
+
+func TestHTTPServerPublicEndpointsHideErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		api  *APIHandler
+		path string
+	}{
+		{name: "health with API key", api: NewAPIHandler(&fakeProducts{}, &fakeAlerts{}, nil, testAPIKey, zap.NewNop()), path: "/health"},
+		{name: "health without API key", path: "/health"},
+		{name: "stats without API key", path: "/stats"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHTTPServer(":0", newStatsCrawler(), nil, tt.api, zap.NewNop())
+
+			rec := serveHTTP(s, tt.path, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", rec.Code)
+			}
+			body := rec.Body.String()
+			if strings.Contains(body, "10.0.0.5") || strings.Contains(body, "internal-proxy") {
+				t.Errorf("body = %s, want no error messages", body)
+			}
+		})
+	}
+}
+
+func TestPublicStatsKeepsCounts(t *testing.T) {
+	crawler := newStatsCrawler()
+	stats := publicStats(crawler.GetStats())
+
+	if stats.RunCount != 3 {
+		t.Errorf("RunCount = %d, want 3", stats.RunCount)
+	}
+	if stats.LastError != "" || stats.SourceStats["ppomppu"].LastError != "" {
+		t.Errorf("publicStats kept error messages: %+v", stats)
+	}
+	if crawler.GetStats().SourceStats["ppomppu"].LastError == "" {
+		t.Error("publicStats cleared the crawler's own stats")
+	}
+}
+
+// fakeLinks serves short links from a map and sends recorded clicks on clicks
+type fakeLinks struct {
+	links  map[string]*models.ShortLink
+	err    error
+	clicks chan *models.Click
+}
+
+func (f *fakeLinks) GetLink(ctx context.Context, token string) (*models.ShortLink, error) {
+	return f.links[token], f.err
+}
+
+func (f *fakeLinks) RecordClick(ctx context.Context, click *models.Click) error {
+	f.clicks <- click
+	return nil
+}
+
+func TestHTTPServerRedirect(t *testing.T) {
+	links := &fakeLinks{
+		links:  map[string]*models.ShortLink{"Ab3dE6gH": {Token: "Ab3dE6gH", URL: "https://example.com/deal/1"}},
+		clicks: make(chan *models.Click, 1),
+	}
+	s := NewHTTPServer(":0", newStatsCrawler(), links, nil, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/r/Ab3dE6gH", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, want 302", rec.Code)
+	}
+	if got := rec.Header().Get("Location"); got != "https://example.com/deal/1" {
+		t.Errorf("Location = %q, want the product URL", got)
+	}
+	select {
+	case click := <-links.clicks:
+		if click.Token != "Ab3dE6gH" || click.URL != "https://example.com/deal/1" || click.UserAgent != "test-agent" {
+			t.Errorf("recorded click %+v, want the link's token, URL and user agent", click)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("click was never recorded")
+	}
+
+	if rec := serveHTTP(s, "/r/unknown1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown token: status = %d, want 404", rec.Code)
+	}
+
+	links.err = errors.New("connection refused")
+	if rec := serveHTTP(s, "/r/Ab3dE6gH", ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("lookup failure: status = %d, want 500", rec.Code)
+	}
+	if len(links.clicks) != 0 {
+		t.Error("recorded a click for a failed redirect")
+	}
+}
@@ -0,0 +1,165 @@
+package crawler
+
+import (
+	"strings"
+
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+// acNode is a single state of the Aho-Corasick automaton
+type acNode struct {
+	next     map[byte]int
+	fail     int
+	patterns []int // indices into KeywordIndex.patterns ending at this state
+}
+
+// KeywordIndex matches product text against every alert keyword in a single pass.
+// It is an Aho-Corasick automaton built over the lowercased keywords, so matching
+// a product costs O(len(text) + matches) instead of O(len(alerts) * len(text)).
+// A KeywordIndex is immutable once built and safe for concurrent use.
+type KeywordIndex struct {
+	alerts   []models.KeywordAlert
+	nodes    []acNode
+	patterns [][]int // pattern index -> alert indices sharing that keyword
+	lengths  []int   // pattern index -> keyword length in bytes
+	always   []int   // alerts with an empty keyword, which match any text
+}
+
+// NewKeywordIndex builds a KeywordIndex over the given alerts
+func NewKeywordIndex(alerts []models.KeywordAlert) *KeywordIndex {
+	idx := &KeywordIndex{
+		alerts: alerts,
+		nodes:  []acNode{{next: make(map[byte]int)}},
+	}
+
+	// Insert each distinct keyword into the trie
+	patternIDs := make(map[string]int)
+	for i, alert := range alerts {
+		keyword := strings.ToLower(alert.Keyword)
+		if keyword == "" {
+			idx.always = append(idx.always, i)
+			continue
+		}
+
+		id, ok := patternIDs[keyword]
+		if !ok {
+			id = len(idx.patterns)
+			patternIDs[keyword] = id
+			idx.patterns = append(idx.patterns, nil)
+			idx.lengths = append(idx.lengths, len(keyword))
+			idx.insert(keyword, id)
+		}
+		idx.patterns[id] = append(idx.patterns[id], i)
+	}
+
+	idx.buildFailureLinks()
+	return idx
+}
+
+// insert adds a keyword to the trie, marking its final state with the pattern ID
+func (idx *KeywordIndex) insert(keyword string, id int) {
+	state := 0
+	for i := 0; i < len(keyword); i++ {
+		next, ok := idx.nodes[state].next[keyword[i]]
+		if !ok {
+			next = len(idx.nodes)
+			idx.nodes = append(idx.nodes, acNode{next: make(map[byte]int)})
+			idx.nodes[state].next[keyword[i]] = next
+		}
+		state = next
+	}
+	idx.nodes[state].patterns = append(idx.nodes[state].patterns, id)
+}
+
+// buildFailureLinks computes failure links breadth-first and merges the
+// output sets so every state reports all keywords that end at it
+func (idx *KeywordIndex) buildFailureLinks() {
+	queue := make([]int, 0, len(idx.nodes))
+	for _, child := range idx.nodes[0].next {
+		idx.nodes[child].fail = 0
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for b, child := range idx.nodes[state].next {
+			queue = append(queue, child)
+
+			fail := idx.nodes[state].fail
+			for {
+				if next, ok := idx.nodes[fail].next[b]; ok {
+					idx.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					idx.nodes[child].fail = 0
+					break
+				}
+				fail = idx.nodes[fail].fail
+			}
+
+			failState := idx.nodes[child].fail
+			idx.nodes[child].patterns = append(idx.nodes[child].patterns, idx.nodes[failState].patterns...)
+		}
+	}
+}
+
+// Match returns every alert whose keyword occurs in text, case-insensitively,
+// with the same semantics as models.KeywordAlert.Matches.
+// Whole-word alerts only match occurrences delimited by word boundaries
+// (see models.IsWholeWord). Alerts are returned in the order they were given
+// to NewKeywordIndex.
+func (idx *KeywordIndex) Match(text string) []models.KeywordAlert {
+	text = strings.ToLower(text)
+
+	matched := make([]bool, len(idx.alerts))
+	found := len(idx.always)
+	for _, i := range idx.always {
+		matched[i] = true
+	}
+
+	state := 0
+	for i := 0; i < len(text); i++ {
+		for {
+			if next, ok := idx.nodes[state].next[text[i]]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = idx.nodes[state].fail
+		}
+
+		for _, id := range idx.nodes[state].patterns {
+			end := i + 1
+			wholeWord := models.IsWholeWord(text, end-idx.lengths[id], end)
+			for _, alertIndex := range idx.patterns[id] {
+				if matched[alertIndex] || (idx.alerts[alertIndex].WholeWord && !wholeWord) {
+					continue
+				}
+				matched[alertIndex] = true
+				found++
+			}
+		}
+	}
+
+	if found == 0 {
+		return nil
+	}
+
+	matches := make([]models.KeywordAlert, 0, found)
+	for i, ok := range matched {
+		if ok {
+			matches = append(matches, idx.alerts[i])
+		}
+	}
+	return matches
+}
+
+// Len returns the number of alerts in the index
+func (idx *KeywordIndex) Len() int {
+	return len(idx.alerts)
+}
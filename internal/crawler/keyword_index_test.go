@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+// scanMatch is the matching KeywordIndex replaced: every alert checked
+// against the product one by one
+func scanMatch(alerts []models.KeywordAlert, product *models.Product) []models.KeywordAlert {
+	var matches []models.KeywordAlert
+	for _, alert := range alerts {
+		if alert.Matches(product) {
+			matches = append(matches, alert)
+		}
+	}
+	return matches
+}
+
+func TestKeywordIndexMatch(t *testing.T) {
+	alerts := []models.KeywordAlert{
+		{ID: "rtx", Keyword: "RTX"},
+		{ID: "rtx-4090", Keyword: "rtx 4090"},
+		{ID: "4090", Keyword: "4090"},
+		{ID: "monitor", Keyword: "모니터"},
+		{ID: "ssd-word", Keyword: "ssd", WholeWord: true},
+		{ID: "rtx-again", Keyword: "rtx"},
+	}
+	index := NewKeywordIndex(alerts)
+
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{"[11번가] ASUS RTX 4090 그래픽카드", []string{"rtx", "rtx-4090", "4090", "rtx-again"}},
+		{"LG 27인치 게이밍모니터", []string{"monitor"}},
+		{"삼성 SSD 1TB", []string{"ssd-word"}},
+		{"NVMe SSDs bundle", nil},
+		{"아무것도 없음", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, alert := range index.Match(tt.title) {
+			got = append(got, alert.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Match(%q) = %v, want %v", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestKeywordIndexMatchesScan(t *testing.T) {
+	alerts, products := matchFixture(500, 200)
+	index := NewKeywordIndex(alerts)
+
+	matched := 0
+	for i := range products {
+		got := index.Match(products[i].SearchText())
+		want := scanMatch(alerts, &products[i])
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Match(%q) matched %d alerts, scan matched %d", products[i].Title, len(got), len(want))
+		}
+		if len(want) > 0 {
+			matched++
+		}
+	}
+	if matched == 0 {
+		t.Fatal("no product matched, the fixture doesn't exercise matching")
+	}
+}
+
+// matchFixture builds alerts and products with overlapping keywords, so
+// about one in ten products matches something
+func matchFixture(alertCount, productCount int) ([]models.KeywordAlert, []models.Product) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"rtx", "4090", "모니터", "ssd", "노트북", "iphone", "에어팟", "lg", "삼성", "갤럭시", "키보드", "마우스"}
+
+	alerts := make([]models.KeywordAlert, alertCount)
+	for i := range alerts {
+		keyword := fmt.Sprintf("%s%d", words[random.Intn(len(words))], random.Intn(alertCount))
+		alerts[i] = models.KeywordAlert{
+			ID:        fmt.Sprintf("alert-%d", i),
+			Keyword:   keyword,
+			WholeWord: i%3 == 0,
+		}
+	}
+
+	products := make([]models.Product, productCount)
+	for i := range products {
+		title := fmt.Sprintf("[특가] %s %s%d 할인 %d원", words[random.Intn(len(words))], words[random.Intn(len(words))], random.Intn(alertCount*10), random.Intn(100000))
+		products[i] = models.Product{Title: title, URL: fmt.Sprintf("https://example.com/deal/%d", i)}
+	}
+	return alerts, products
+}
+
+func BenchmarkMatchScan(b *testing.B) {
+	alerts, products := matchFixture(5000, 100)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range products {
+			scanMatch(alerts, &products[i])
+		}
+	}
+}
+
+func BenchmarkMatchKeywordIndex(b *testing.B) {
+	alerts, products := matchFixture(5000, 100)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		index := NewKeywordIndex(alerts)
+		for i := range products {
+			index.Match(products[i].SearchText())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// newMockTest creates a test using a mock deployment instead of a server
+func newMockTest(t *testing.T) *mtest.T {
+	return mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+}
+
+// newMockDB wraps the mock deployment of mt, which answers commands with the
+// responses queued by mt.AddMockResponses
+func newMockDB(mt *mtest.T, cfg *config.Config) *storage.MongoDB {
+	if cfg.MongoDBName == "" {
+		cfg.MongoDBName = "test"
+	}
+	if cfg.DBOperationTimeoutSeconds == 0 {
+		cfg.DBOperationTimeoutSeconds = 10
+	}
+	return storage.NewMongoDBFromClient(mt.Client, cfg, zap.NewNop())
+}
+
+// startedCommands returns the commands named name that were sent, in order
+func startedCommands(mt *mtest.T, name string) []bson.Raw {
+	var commands []bson.Raw
+	for _, event := range mt.GetAllStartedEvents() {
+		if event.CommandName == name {
+			commands = append(commands, event.Command)
+		}
+	}
+	return commands
+}
+
+// cursorResponse is a find or aggregate reply returning docs in one batch
+func cursorResponse(docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, "test.collection", mtest.FirstBatch, docs...)
+}
+
+// writeResponse is a successful reply to a write affecting n documents
+func writeResponse(n int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// offlineSession returns a Discord session whose REST calls fail the test,
+// for code that must not use the bot
+func offlineSession(t *testing.T) *discordgo.Session {
+	t.Helper()
+
+	session, err := discordgo.New("Bot ")
+	if err != nil {
+		t.Fatalf("discordgo.New() error = %v", err)
+	}
+	session.Client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected Discord API call: %s %s", r.Method, r.URL.Path)
+		return nil, errors.New("offline")
+	})}
+	return session
+}
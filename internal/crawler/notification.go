@@ -7,22 +7,60 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bradykim7/gbot/internal/embeds"
 	"github.com/bradykim7/gbot/internal/models"
 	"github.com/bradykim7/gbot/internal/storage"
 	"github.com/bradykim7/gbot/pkg/config"
 	"github.com/bwmarrin/discordgo"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
+// pendingDeliveryBatchSize is the maximum number of queued notifications delivered per run
+const pendingDeliveryBatchSize = 200
+
 // NotificationService handles sending notifications to Discord users
 type NotificationService struct {
-	session     *discordgo.Session
-	config      *config.Config
-	db          *storage.MongoDB
-	logger      *zap.Logger
-	rateLimiter *time.Ticker
+	session      *discordgo.Session
+	sender       MessageSender // 알림 전송 방식 (봇 세션 또는 웹훅)
+	config       *config.Config
+	db           *storage.MongoDB
+	logger       *zap.Logger
+	rateLimiter  *time.Ticker
 	alertMatcher *AlertMatcher
+	matchRepo    *storage.AlertMatchRepository
+
+	pendingRepo *storage.PendingNotificationRepository
+	snoozeRepo  *storage.ChannelSnoozeRepository
+	dealRepo    *storage.DealMessageRepository
+	linkRepo    *storage.ShortLinkRepository // nil이면 클릭 추적 비활성화
+	location    *time.Location               // timezone quiet hours are interpreted in
+
+	// notified holds every URL in notified_products, loaded at startup,
+	// refreshed before each run and added to on each mark, so most unnotified
+	// products skip the DB query. nil if loading failed, in which case every
+	// check queries the DB.
+	notified *notifiedFilter
+
+	// channelGuilds caches the guild each notification channel belongs to
+	channelGuilds   map[string]string
+	channelGuildsMu sync.Mutex
+
+	// Bot permissions per channel, and channels already reported as unsendable
+	alertRepo      *storage.AlertRepository
+	botID          string
+	channelPerms   map[string]channelPermission
+	deniedChannels map[string]bool
+	channelPermsMu sync.Mutex
+
+	// Shutdown drain: sends already in flight outlive the run context by drainGrace
+	drainGrace time.Duration
+	inflight   sync.WaitGroup
+	lifetime   context.Context
+	shutdown   context.CancelFunc
 }
 
 // NewNotificationService creates a new notification service
@@ -34,75 +72,178 @@ func NewNotificationService(cfg *config.Config, db *storage.MongoDB, log *zap.Lo
 
 	// Set up rate limiter to avoid Discord API limits (1 message per 2 seconds)
 	rateLimiter := time.NewTicker(2 * time.Second)
-	
+
 	alertMatcher := NewAlertMatcher(db, log)
+	alertMatcher.SetDefaultCooldown(time.Duration(cfg.AlertCooldownMinutes) * time.Minute)
+
+	location, err := time.LoadLocation(cfg.NotificationTimezone)
+	if err != nil {
+		log.Warn("Failed to load notification timezone, using KST",
+			zap.String("timezone", cfg.NotificationTimezone),
+			zap.Error(err))
+		location = time.FixedZone("KST", 9*60*60)
+	}
+	alertMatcher.SetLocation(location)
+
+	lifetime, shutdown := context.WithCancel(context.Background())
+
+	// Choose how notifications are delivered
+	var sender MessageSender = sessionSender{session: session}
+	if cfg.NotificationTransport == "webhook" {
+		sender = NewWebhookNotifier(cfg.WebhookURLs, log)
+	}
+
+	var linkRepo *storage.ShortLinkRepository
+	if cfg.ClickTrackingEnabled {
+		linkRepo = storage.NewShortLinkRepository(db, log)
+	}
+
+	loadCtx, cancel := db.OperationContext(context.Background())
+	notified, err := loadNotifiedFilter(loadCtx, db)
+	cancel()
+	if err != nil {
+		log.Warn("Failed to load notified product filter, checking every product in the database", zap.Error(err))
+	}
 
 	return &NotificationService{
-		session:      session,
-		config:       cfg,
-		db:           db,
-		logger:       log.Named("notification-service"),
-		rateLimiter:  rateLimiter,
-		alertMatcher: alertMatcher,
+		session:        session,
+		sender:         sender,
+		config:         cfg,
+		db:             db,
+		logger:         log.Named("notification-service"),
+		rateLimiter:    rateLimiter,
+		alertMatcher:   alertMatcher,
+		matchRepo:      storage.NewAlertMatchRepository(db, log),
+		pendingRepo:    storage.NewPendingNotificationRepository(db, log),
+		snoozeRepo:     storage.NewChannelSnoozeRepository(db, log),
+		dealRepo:       storage.NewDealMessageRepository(db, log),
+		linkRepo:       linkRepo,
+		location:       location,
+		notified:       notified,
+		channelGuilds:  make(map[string]string),
+		alertRepo:      storage.NewAlertRepository(db, log),
+		channelPerms:   make(map[string]channelPermission),
+		deniedChannels: make(map[string]bool),
+		drainGrace:     time.Duration(cfg.ShutdownGraceSeconds) * time.Second,
+		lifetime:       lifetime,
+		shutdown:       shutdown,
 	}, nil
 }
 
-// NotifyNewProducts sends notifications for newly found products
+// NotifyNewProducts sends notifications for newly found products.
+//
+// Delivery on shutdown: once a product's notification has started, it keeps
+// going after ctx is canceled for up to the shutdown grace period, so its
+// messages are sent and the product is marked notified together. Products are
+// only marked after sending, so one cut off by the grace deadline between the
+// two may be sent again later (at-least-once). Products whose notification had
+// not started when ctx was canceled are skipped and not retried (at-most-once).
 func (n *NotificationService) NotifyNewProducts(ctx context.Context, products []models.Product) error {
+	return n.notifyProducts(ctx, products, false)
+}
+
+// NotifyChangedProducts notifies again about stored products whose price or
+// discount changed, even if they were already notified. Delivery on shutdown
+// is the same as for NotifyNewProducts.
+func (n *NotificationService) NotifyChangedProducts(ctx context.Context, products []models.Product) error {
+	return n.notifyProducts(ctx, products, true)
+}
+
+// notifyProducts sends notifications for products, skipping those already
+// notified unless renotify is set
+func (n *NotificationService) notifyProducts(ctx context.Context, products []models.Product, renotify bool) error {
 	if len(products) == 0 {
 		n.logger.Info("No products to notify about")
 		return nil
 	}
 
-	n.logger.Info("Processing products for notifications", zap.Int("count", len(products)))
+	n.logger.Info("Processing products for notifications",
+		zap.Int("count", len(products)),
+		zap.Bool("renotify", renotify))
+
+	// Pick up the products other processes marked since the last run
+	if n.notified != nil && !renotify {
+		if err := n.notified.refresh(ctx, n.db); err != nil {
+			n.logger.Warn("Failed to refresh notified product filter, checking every product in the database", zap.Error(err))
+		}
+	}
+
+	// Build the keyword index once for the whole run
+	index, err := n.alertMatcher.BuildIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to build keyword index: %w", err)
+	}
+
+	// In-flight sends use a context that survives ctx for the grace period
+	sendCtx, release := n.drainContext(ctx)
+	defer release()
 
 	// Process each product
 	var wg sync.WaitGroup
-	wg.Add(len(products))
-	
+
 	// Create semaphore to limit concurrency to 5 at a time
 	sem := make(chan struct{}, 5)
-	
+
 	// Collect errors
 	var notificationErrors []error
 	var errorMutex sync.Mutex
-	
-	for _, product := range products {
+
+	// Track products handled in this run so each is processed by exactly one goroutine
+	seenURLs := make(map[string]bool)
+
+products:
+	for i, product := range products {
+		// Don't start new notifications once the run is canceled
+		if ctx.Err() != nil {
+			n.logger.Warn("Run canceled, skipping remaining notifications",
+				zap.Int("skipped", len(products)-i))
+			break
+		}
+
+		// Skip duplicates of a product already handled in this run
+		if seenURLs[product.URL] {
+			n.logger.Debug("Duplicate product in run", zap.String("url", product.URL))
+			continue
+		}
+		seenURLs[product.URL] = true
+
 		// Skip products that were already notified
-		if n.isProductNotified(ctx, product.URL) {
+		if !renotify && n.isProductNotified(ctx, product.URL) {
 			n.logger.Debug("Product already notified", zap.String("url", product.URL))
-			wg.Done()
 			continue
 		}
-		
+
 		// Process each product concurrently but with controlled concurrency
-		sem <- struct{}{} // Acquire semaphore
-		
+		select {
+		case sem <- struct{}{}: // Acquire semaphore
+		case <-ctx.Done():
+			n.logger.Warn("Run canceled, skipping remaining notifications",
+				zap.Int("skipped", len(products)-i))
+			break products
+		}
+
+		wg.Add(1)
+		n.inflight.Add(1)
 		go func(p models.Product) {
 			defer func() {
 				<-sem // Release semaphore
 				wg.Done()
+				n.inflight.Done()
 			}()
-			
+
 			// Find matching alerts
-			matchingAlerts, err := n.alertMatcher.FindMatchingAlerts(ctx, p)
-			if err != nil {
-				errorMutex.Lock()
-				notificationErrors = append(notificationErrors, fmt.Errorf("failed to find matching alerts: %w", err))
-				errorMutex.Unlock()
-				return
-			}
-			
+			matchingAlerts := n.alertMatcher.MatchProduct(sendCtx, index, p)
+
 			if len(matchingAlerts) == 0 {
 				return // No matching alerts, nothing to notify
 			}
-			
-			n.logger.Debug("Found matching alerts", 
-				zap.String("product", p.Title), 
+
+			n.logger.Debug("Found matching alerts",
+				zap.String("product", p.Title),
 				zap.Int("matches", len(matchingAlerts)))
-			
+
 			// Send notifications
-			err = n.sendProductNotifications(ctx, p, matchingAlerts)
+			err := n.sendProductNotifications(sendCtx, p, matchingAlerts)
 			if err != nil {
 				errorMutex.Lock()
 				notificationErrors = append(notificationErrors, err)
@@ -113,78 +254,230 @@ func (n *NotificationService) NotifyNewProducts(ctx context.Context, products []
 
 	// Wait for all notifications to finish
 	wg.Wait()
-	
+
 	// If there were errors, log them and return a combined error
 	if len(notificationErrors) > 0 {
-		n.logger.Error("Some notifications failed", 
-			zap.Int("failure_count", len(notificationErrors)), 
+		n.logger.Error("Some notifications failed",
+			zap.Int("failure_count", len(notificationErrors)),
 			zap.Int("total_products", len(products)))
-		
+
 		return fmt.Errorf("some notifications failed: %v", notificationErrors)
 	}
-	
-	n.logger.Info("All notifications processed successfully", 
+
+	n.logger.Info("All notifications processed successfully",
 		zap.Int("product_count", len(products)))
 	return nil
 }
 
-// sendProductNotifications sends notifications for a single product to all matching alert channels
+// SendBestDeals sends the best deal alerts the best product they matched since
+// the last call, that is over the whole crawl run. If ctx is already canceled
+// the run's candidates are dropped instead.
+func (n *NotificationService) SendBestDeals(ctx context.Context) error {
+	bestDeals := n.alertMatcher.TakeBestDeals()
+	if ctx.Err() != nil || len(bestDeals) == 0 {
+		return nil
+	}
+
+	sendCtx, release := n.drainContext(ctx)
+	defer release()
+
+	var notificationErrors []error
+	for _, deal := range bestDeals {
+		n.alertMatcher.RecordBestDeal(sendCtx, deal, time.Now())
+		if err := n.sendProductNotifications(sendCtx, deal.Product, deal.Alerts); err != nil {
+			notificationErrors = append(notificationErrors, err)
+		}
+	}
+
+	if len(notificationErrors) > 0 {
+		return fmt.Errorf("some best deal notifications failed: %v", notificationErrors)
+	}
+	return nil
+}
+
+// PreviewNotifications returns the products that would trigger at least one
+// alert, without sending anything or updating alert and product metadata
+func (n *NotificationService) PreviewNotifications(ctx context.Context, products []models.Product) ([]models.Product, error) {
+	if len(products) == 0 {
+		return nil, nil
+	}
+
+	index, err := n.alertMatcher.BuildIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build keyword index: %w", err)
+	}
+
+	var matched []models.Product
+	for _, product := range products {
+		if len(filterByMaxPrice(index.Match(product.SearchText()), &product)) > 0 {
+			matched = append(matched, product)
+		}
+	}
+	return matched, nil
+}
+
+// sendProductNotifications sends notifications for a single product to all matching alert channels.
+// Alerts are grouped by channel first so each channel receives exactly one embed
+// mentioning every user whose alert matched there.
 func (n *NotificationService) sendProductNotifications(ctx context.Context, product models.Product, alerts []models.KeywordAlert) error {
 	if len(alerts) == 0 {
 		return nil
 	}
 
-	// Create notification embed
-	embed := n.createProductEmbed(product, alerts)
+	// Alerts whose match reached its channel, or was held back or suppressed
+	// on purpose, keep their cooldown; the others get it back
+	delivered := make(map[string]bool)
+	defer n.alertMatcher.SettleCooldowns(ctx, alerts, delivered)
+
+	// Summary mode alerts are only recorded now and DMed with the next summary
+	alerts, summarized := splitSummaryAlerts(alerts)
+	n.recordSummaryMatches(ctx, product, summarized)
+	for _, alert := range summarized {
+		delivered[alert.ID] = true
+	}
+	if len(alerts) == 0 {
+		if err := n.markProductNotified(ctx, product); err != nil {
+			return fmt.Errorf("failed to mark product as notified: %w", err)
+		}
+		return nil
+	}
+
+	// Only deliver alerts to channels in the guild the alert was created in.
+	// Webhook channels are chosen by the operator and can't be looked up
+	// without the bot, so they are trusted as configured.
+	if !n.usesWebhooks() {
+		var dropped []models.KeywordAlert
+		alerts, dropped = filterAlertsByChannelGuild(alerts, n.channelGuildID)
+		for _, alert := range dropped {
+			n.logger.Warn("Skipping alert whose channel is outside its guild",
+				zap.String("alert_id", alert.ID),
+				zap.String("guild_id", alert.GuildID),
+				zap.String("channel_id", alert.ChannelID))
+		}
+		if len(alerts) == 0 {
+			return nil
+		}
+	}
+
+	// Hold back alerts in their owner's quiet hours until the window ends
+	now := time.Now().In(n.location)
+	alerts, held := splitQuietAlerts(alerts, now)
+	for _, alert := range held {
+		pending := models.NewPendingNotification(alert, product, alert.QuietHoursEnd(now))
+		if err := n.pendingRepo.Enqueue(ctx, pending); err != nil {
+			n.logger.Error("Failed to queue notification for quiet hours",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID))
+			continue
+		}
+		delivered[alert.ID] = true
+		n.logger.Debug("Queued notification until quiet hours end",
+			zap.String("alert_id", alert.ID),
+			zap.Time("deliver_at", pending.DeliverAt))
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	channelIDs, alertsByChannel := groupAlertsByChannel(alerts)
+
+	// Skip channels the bot can't post in instead of failing and retrying every run
+	sendable := n.sendableChannels(ctx, channelIDs)
+	deniedChannels := len(channelIDs) - len(sendable)
+	channelIDs = sendable
+
+	// Look up snoozed channels; on failure, send as usual
+	snoozed, err := n.snoozeRepo.GetSnoozedChannels(ctx, channelIDs, time.Now())
+	if err != nil {
+		n.logger.Warn("Failed to check channel snoozes", zap.Error(err))
+	}
+
+	// Link embeds through the click-tracking redirect when enabled
+	display := n.withTrackedURL(ctx, product)
 
 	// Send notification to each unique channel
 	sentChannels := make(map[string]bool)
+	suppressedChannels := 0
 	channelErrors := make(map[string]error)
 	var notificationErrors []error
-	
+
+	for _, channelID := range channelIDs {
+		// Snoozed channels get nothing now; optionally queue until the snooze ends
+		if until, ok := snoozed[channelID]; ok {
+			n.suppressSnoozed(ctx, product, alertsByChannel[channelID], until)
+			suppressedChannels++
+			for _, alert := range alertsByChannel[channelID] {
+				delivered[alert.ID] = true
+			}
+			continue
+		}
+
+		// Wait for rate limiter to avoid rate limits
+		select {
+		case <-n.rateLimiter.C:
+			// Continue with sending
+		case <-ctx.Done():
+			// Context canceled, stop sending
+			return ctx.Err()
+		}
+
+		// Create notification message for the users in this channel
+		message := n.createProductMessage(display, alertsByChannel[channelID])
+
+		sent, err := n.sender.SendMessage(channelID, message)
+		if err != nil {
+			n.logger.Error("Failed to send Discord message",
+				zap.Error(err),
+				zap.String("channel_id", channelID))
+			channelErrors[channelID] = err
+			notificationErrors = append(notificationErrors, fmt.Errorf("failed to send notification to channel %s: %w", channelID, err))
+			continue
+		}
+
+		n.logger.Info("Sent notification",
+			zap.String("channel_id", channelID),
+			zap.String("product", product.Title),
+			zap.Int("alerts", len(alertsByChannel[channelID])))
+
+		sentChannels[channelID] = true
+		n.trackDealMessage(ctx, sent, product)
+	}
+
+	// Record a match for every alert whose channel received the notification
 	for _, alert := range alerts {
 		if !sentChannels[alert.ChannelID] {
-			// Wait for rate limiter to avoid rate limits
-			select {
-			case <-n.rateLimiter.C:
-				// Continue with sending
-			case <-ctx.Done():
-				// Context canceled, stop sending
-				return ctx.Err()
-			}
-			
-			_, err := n.session.ChannelMessageSendEmbed(alert.ChannelID, embed)
-			if err != nil {
-				n.logger.Error("Failed to send Discord message", 
-					zap.Error(err), 
-					zap.String("channel_id", alert.ChannelID))
-				channelErrors[alert.ChannelID] = err
-				notificationErrors = append(notificationErrors, fmt.Errorf("failed to send notification to channel %s: %w", alert.ChannelID, err))
-				continue
-			}
-			
-			n.logger.Info("Sent notification", 
-				zap.String("channel_id", alert.ChannelID),
-				zap.String("product", product.Title))
-			
-			sentChannels[alert.ChannelID] = true
+			continue
+		}
+		delivered[alert.ID] = true
+		if err := n.matchRepo.RecordMatch(ctx, models.NewAlertMatch(alert, product)); err != nil {
+			n.logger.Warn("Failed to record alert match",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID),
+				zap.String("product_url", product.URL))
 		}
 	}
 
-	// Only mark product as notified if at least one notification was sent
-	if len(sentChannels) > 0 {
+	// Only mark product as notified if at least one notification was sent or
+	// suppressed by a snooze, so those channels aren't flooded afterwards.
+	// Channels skipped for missing permissions count only when no send failed,
+	// or the failed channels would never be retried.
+	handled := len(sentChannels) > 0 || suppressedChannels > 0
+	if deniedChannels > 0 && len(channelErrors) == 0 {
+		handled = true
+	}
+	if handled {
 		if err := n.markProductNotified(ctx, product); err != nil {
-			n.logger.Error("Failed to mark product as notified", 
-				zap.Error(err), 
+			n.logger.Error("Failed to mark product as notified",
+				zap.Error(err),
 				zap.String("product_url", product.URL))
-			
+
 			notificationErrors = append(notificationErrors, fmt.Errorf("failed to mark product as notified: %w", err))
 		}
 	}
-	
+
 	// If there were errors, log them and return a combined error
 	if len(notificationErrors) > 0 {
-		if len(notificationErrors) == len(alerts) {
+		if len(channelErrors) == len(channelIDs) {
 			// All notifications failed
 			return fmt.Errorf("all notifications failed: %v", notificationErrors)
 		} else {
@@ -192,177 +485,546 @@ func (n *NotificationService) sendProductNotifications(ctx context.Context, prod
 			n.logger.Warn("Some channel notifications failed but others succeeded",
 				zap.Int("successful", len(sentChannels)),
 				zap.Int("failed", len(channelErrors)))
-			
+
 			// Return a summary error but don't fail the whole process
-			return fmt.Errorf("%d of %d notifications failed", len(channelErrors), len(alerts))
+			return fmt.Errorf("%d of %d notifications failed", len(channelErrors), len(channelIDs))
 		}
 	}
-	
+
 	return nil
 }
 
-// createProductEmbed creates a rich embed for product notification
-func (n *NotificationService) createProductEmbed(product models.Product, alerts []models.KeywordAlert) *discordgo.MessageEmbed {
-	// Collect unique keywords that matched
-	keywords := make(map[string]bool)
+// SendDMEmbeds sends embeds to a user by direct message, honoring the rate
+// limiter. DMs always go through the bot session, whatever the transport.
+func (n *NotificationService) SendDMEmbeds(ctx context.Context, userID string, embeds []*discordgo.MessageEmbed) error {
+	channel, err := n.session.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel for user %s: %w", userID, err)
+	}
+
+	select {
+	case <-n.rateLimiter.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	_, err = n.session.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Embeds: embeds,
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Parse: []discordgo.AllowedMentionType{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send DM to user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// recordSummaryMatches records the matches of summary mode alerts, which are
+// not sent now but included in their owner's next summary DM
+func (n *NotificationService) recordSummaryMatches(ctx context.Context, product models.Product, alerts []models.KeywordAlert) {
 	for _, alert := range alerts {
-		keywords[alert.Keyword] = true
-	}
-	
-	// Join keywords into comma-separated string
-	var keywordList []string
-	for k := range keywords {
-		keywordList = append(keywordList, k)
-	}
-	
-	// Collect unique usernames to mention
-	var usernames []string
-	mentionedUsers := make(map[string]bool)
+		if err := n.matchRepo.RecordMatch(ctx, models.NewAlertMatch(alert, product)); err != nil {
+			n.logger.Warn("Failed to record alert match for summary",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID),
+				zap.String("product_url", product.URL))
+		}
+	}
+}
+
+// splitSummaryAlerts splits alerts into those notified in real time and those
+// collected for a summary DM
+func splitSummaryAlerts(alerts []models.KeywordAlert) (realtime, summarized []models.KeywordAlert) {
 	for _, alert := range alerts {
-		// Check if Username is set
-		if alert.Username != "" && !mentionedUsers[alert.Username] {
-			usernames = append(usernames, "@"+alert.Username)
-			mentionedUsers[alert.Username] = true
-		} else if !mentionedUsers[alert.UserID] {
-			// Fallback to user ID if username not available
-			usernames = append(usernames, "<@"+alert.UserID+">")
-			mentionedUsers[alert.UserID] = true
-		}
-	}
-
-	// Create embed fields
-	fields := []*discordgo.MessageEmbedField{
-		{
-			Name:   "Source",
-			Value:  product.Source,
-			Inline: true,
-		},
+		if alert.IsSummarized() {
+			summarized = append(summarized, alert)
+		} else {
+			realtime = append(realtime, alert)
+		}
 	}
+	return realtime, summarized
+}
 
-	// Add price field if available
-	priceStr := product.GetPriceString()
-	if priceStr != "Price unknown" {
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   "Price",
-			Value:  priceStr,
-			Inline: true,
-		})
+// withTrackedURL returns a copy of the product whose URL points at its
+// click-tracking short link. Without click tracking, or if the link can't be
+// created, the product is returned unchanged.
+func (n *NotificationService) withTrackedURL(ctx context.Context, product models.Product) models.Product {
+	if n.linkRepo == nil {
+		return product
 	}
-	
-	// Add discount rate if available
-	if product.DiscountRate > 0 {
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   "Discount",
-			Value:  fmt.Sprintf("%d%%", product.DiscountRate),
-			Inline: true,
-		})
+
+	link, err := n.linkRepo.GetOrCreateLink(ctx, product.URL, product.Title)
+	if err != nil {
+		n.logger.Warn("Failed to create short link, using product URL",
+			zap.Error(err),
+			zap.String("url", product.URL))
+		return product
 	}
 
-	// Add comments/views if available
-	if product.Comments > 0 || product.Views > 0 {
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   "Stats",
-			Value:  fmt.Sprintf("Comments: %d | Views: %d", product.Comments, product.Views),
-			Inline: true,
-		})
+	product.URL = strings.TrimRight(n.config.PublicBaseURL, "/") + "/r/" + link.Token
+	return product
+}
+
+// trackDealMessage remembers which product a sent message is about and adds the
+// bell reaction users can click to create an alert from it. With webhook
+// delivery there may be no bot token, so users add the reaction themselves.
+// Failures are only logged since the notification itself was delivered.
+func (n *NotificationService) trackDealMessage(ctx context.Context, message *discordgo.Message, product models.Product) {
+	if message.ID == "" {
+		return
 	}
 
-	// Add matched keywords field
-	fields = append(fields, &discordgo.MessageEmbedField{
-		Name:   "Matched Keywords",
-		Value:  strings.Join(keywordList, ", "),
-		Inline: false,
-	})
+	if err := n.dealRepo.RecordMessage(ctx, models.NewDealMessage(message.ID, message.ChannelID, product)); err != nil {
+		n.logger.Warn("Failed to record deal message",
+			zap.Error(err),
+			zap.String("message_id", message.ID))
+		return
+	}
+	if n.usesWebhooks() {
+		return
+	}
 
-	// Create description with mentions
-	description := fmt.Sprintf("새로운 특가 상품을 발견했습니다! %s", strings.Join(usernames, " "))
-
-	// Create embed color based on hotness or discount rate
-	color := 0x00ff00 // Default green
-	if product.IsHot {
-		color = 0xff0000 // Hot item = red
-	} else if product.DiscountRate >= 50 {
-		color = 0xff6600 // Big discount = orange
-	}
-
-	// Create embed
-	embed := &discordgo.MessageEmbed{
-		Title:       product.Title,
-		URL:         product.URL,
-		Description: description,
-		Color:       color,
-		Fields:      fields,
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Crawled at %s", product.CrawledAt.Format("2006-01-02 15:04:05")),
-		},
+	if err := n.session.MessageReactionAdd(message.ChannelID, message.ID, models.DealAlertEmoji); err != nil {
+		n.logger.Warn("Failed to add alert reaction",
+			zap.Error(err),
+			zap.String("message_id", message.ID))
+	}
+}
+
+// suppressSnoozed handles a notification for a snoozed channel. It is dropped
+// unless SnoozeQueueNotifications is set, in which case it is queued until
+// the snooze ends.
+func (n *NotificationService) suppressSnoozed(ctx context.Context, product models.Product, alerts []models.KeywordAlert, until time.Time) {
+	if !n.config.SnoozeQueueNotifications {
+		n.logger.Debug("Skipping notification for snoozed channel",
+			zap.String("channel_id", alerts[0].ChannelID),
+			zap.String("product_url", product.URL))
+		return
+	}
+
+	for _, alert := range alerts {
+		if err := n.pendingRepo.Enqueue(ctx, models.NewPendingNotification(alert, product, until)); err != nil {
+			n.logger.Error("Failed to queue notification for snoozed channel",
+				zap.Error(err),
+				zap.String("alert_id", alert.ID))
+		}
+	}
+}
+
+// splitQuietAlerts splits alerts into those that can be delivered at now and
+// those whose quiet hours are in effect
+func splitQuietAlerts(alerts []models.KeywordAlert, now time.Time) (deliver, held []models.KeywordAlert) {
+	for _, alert := range alerts {
+		if alert.InQuietHours(now) {
+			held = append(held, alert)
+		} else {
+			deliver = append(deliver, alert)
+		}
 	}
-	
-	// Add image if available
-	if product.ImageURL != "" {
-		embed.Image = &discordgo.MessageEmbedImage{
-			URL: product.ImageURL,
+	return deliver, held
+}
+
+// DeliverPendingNotifications sends notifications that were held back by
+// quiet hours and are now due. Alerts deleted or deactivated in the
+// meantime are dropped.
+func (n *NotificationService) DeliverPendingNotifications(ctx context.Context) error {
+	due, err := n.pendingRepo.GetDue(ctx, time.Now(), pendingDeliveryBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	var alertIDs []string
+	for _, pending := range due {
+		alertIDs = append(alertIDs, pending.AlertID)
+	}
+	alerts, err := n.alertMatcher.GetAlertsByIDs(ctx, alertIDs)
+	if err != nil {
+		return err
+	}
+	alertsByID := make(map[string]models.KeywordAlert, len(alerts))
+	for _, alert := range alerts {
+		alertsByID[alert.ID] = alert
+	}
+
+	// Group the due notifications by product, preserving queue order
+	var productURLs []string
+	products := make(map[string]models.Product)
+	alertsByProduct := make(map[string][]models.KeywordAlert)
+	ids := make([]primitive.ObjectID, 0, len(due))
+	for _, pending := range due {
+		ids = append(ids, pending.ID)
+
+		alert, ok := alertsByID[pending.AlertID]
+		if !ok {
+			continue
 		}
+		url := pending.Product.URL
+		if _, ok := products[url]; !ok {
+			productURLs = append(productURLs, url)
+			products[url] = pending.Product
+		}
+		alertsByProduct[url] = append(alertsByProduct[url], alert)
+	}
+
+	// Remove from the queue first so a failing channel is not retried forever
+	if err := n.pendingRepo.Delete(ctx, ids); err != nil {
+		return err
 	}
 
-	return embed
+	var deliveryErrors []error
+	for _, url := range productURLs {
+		if err := n.sendProductNotifications(ctx, products[url], alertsByProduct[url]); err != nil {
+			deliveryErrors = append(deliveryErrors, err)
+		}
+	}
+
+	n.logger.Info("Delivered pending notifications",
+		zap.Int("queued", len(due)),
+		zap.Int("products", len(productURLs)))
+
+	if len(deliveryErrors) > 0 {
+		return fmt.Errorf("failed to deliver %d pending notification(s): %v", len(deliveryErrors), deliveryErrors)
+	}
+	return nil
+}
+
+// usesWebhooks reports whether channel notifications go through webhooks
+// instead of the bot session, which then may have no token
+func (n *NotificationService) usesWebhooks() bool {
+	return n.config.NotificationTransport == "webhook"
+}
+
+// channelGuildID returns the guild a channel belongs to, caching the lookup.
+// Direct message channels have an empty guild ID.
+func (n *NotificationService) channelGuildID(channelID string) (string, error) {
+	n.channelGuildsMu.Lock()
+	guildID, ok := n.channelGuilds[channelID]
+	n.channelGuildsMu.Unlock()
+	if ok {
+		return guildID, nil
+	}
+
+	channel, err := n.session.Channel(channelID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up channel %s: %w", channelID, err)
+	}
+
+	n.channelGuildsMu.Lock()
+	n.channelGuilds[channelID] = channel.GuildID
+	n.channelGuildsMu.Unlock()
+
+	return channel.GuildID, nil
+}
+
+// filterAlertsByChannelGuild splits alerts into those whose channel belongs
+// to the alert's guild and those that would notify a channel elsewhere.
+// Alerts without a guild (created in DMs) are always kept. Alerts whose
+// channel cannot be resolved are dropped, since delivery would fail anyway.
+func filterAlertsByChannelGuild(alerts []models.KeywordAlert, channelGuildID func(channelID string) (string, error)) (kept, dropped []models.KeywordAlert) {
+	for _, alert := range alerts {
+		if alert.GuildID == "" {
+			kept = append(kept, alert)
+			continue
+		}
+
+		guildID, err := channelGuildID(alert.ChannelID)
+		if err != nil || guildID != alert.GuildID {
+			dropped = append(dropped, alert)
+			continue
+		}
+
+		kept = append(kept, alert)
+	}
+
+	return kept, dropped
+}
+
+// groupAlertsByChannel groups alerts by their target channel, returning the
+// channel IDs in the order they first appear
+func groupAlertsByChannel(alerts []models.KeywordAlert) ([]string, map[string][]models.KeywordAlert) {
+	var channelIDs []string
+	alertsByChannel := make(map[string][]models.KeywordAlert)
+
+	for _, alert := range alerts {
+		if _, ok := alertsByChannel[alert.ChannelID]; !ok {
+			channelIDs = append(channelIDs, alert.ChannelID)
+		}
+		alertsByChannel[alert.ChannelID] = append(alertsByChannel[alert.ChannelID], alert)
+	}
+
+	return channelIDs, alertsByChannel
+}
+
+// createProductMessage creates the notification message for a channel in the channel's language
+func (n *NotificationService) createProductMessage(product models.Product, alerts []models.KeywordAlert) *discordgo.MessageSend {
+	return embeds.ProductMessage(product, alerts, embeds.MessagesFor(notificationLanguage(n.config, alerts)))
+}
+
+// notificationLanguage returns the language override of the first alert that sets one,
+// or the configured default
+func notificationLanguage(cfg *config.Config, alerts []models.KeywordAlert) string {
+	for _, alert := range alerts {
+		if alert.Language != "" {
+			return alert.Language
+		}
+	}
+	return cfg.NotificationLanguage
 }
 
-// isProductNotified checks if a product has already been notified
+// isProductNotified checks if a product has already been notified. URLs the
+// notified filter has never seen are not notified without asking the database;
+// only possible hits are confirmed there.
 func (n *NotificationService) isProductNotified(ctx context.Context, url string) bool {
-	collection := n.db.Collection("notified_products")
-	
-	count, err := collection.CountDocuments(ctx, bson.M{"url": url})
+	if n.notified.skip(url) {
+		return false
+	}
+
+	notified, err := productNotified(ctx, n.db, url)
 	if err != nil {
 		n.logger.Error("Failed to check if product was notified", zap.Error(err), zap.String("url", url))
 		return false
 	}
-	
-	return count > 0
+
+	return notified
 }
 
 // markProductNotified marks a product as notified in the database
 func (n *NotificationService) markProductNotified(ctx context.Context, product models.Product) error {
-	collection := n.db.Collection("notified_products")
-	
-	notifiedProduct := struct {
-		URL        string    `bson:"url"`
-		Title      string    `bson:"title"`
-		NotifiedAt time.Time `bson:"notified_at"`
-		ProductID  string    `bson:"product_id,omitempty"`
-	}{
-		URL:        product.URL,
-		Title:      product.Title,
-		NotifiedAt: time.Now(),
-		ProductID:  product.ID,
-	}
-	
-	_, err := collection.InsertOne(ctx, notifiedProduct)
+	return markNotified(ctx, n.db, n.logger, n.notified, product)
+}
+
+// markNotified marks a product as notified in the database and adds it to
+// filter, which may be nil. Every notifier marks through it, so its filter
+// never misses a product it notified itself.
+func markNotified(ctx context.Context, db *storage.MongoDB, log *zap.Logger, filter *notifiedFilter, product models.Product) error {
+	if err := recordProductNotified(ctx, db, log, product); err != nil {
+		return err
+	}
+
+	if filter != nil {
+		filter.add(product.URL)
+	}
+	return nil
+}
+
+// productNotified reports whether any notifier has already notified the product URL
+func productNotified(ctx context.Context, db *storage.MongoDB, url string) (bool, error) {
+	count, err := db.Collection("notified_products").CountDocuments(ctx, bson.M{"url": url})
 	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// recordProductNotified marks a product as notified in the database.
+// It upserts on URL so concurrent or repeated marks are idempotent;
+// notified_at keeps the time of the first mark.
+func recordProductNotified(ctx context.Context, db *storage.MongoDB, log *zap.Logger, product models.Product) error {
+	collection := db.Collection("notified_products")
+
+	doc := notifiedProductDocument(product, time.Now())
+	err := storage.WithRetry(ctx, func(ctx context.Context) error {
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"url": product.URL},
+			bson.M{"$setOnInsert": doc},
+			options.Update().SetUpsert(true))
+		return err
+	})
+	// Two concurrent upserts can race on the unique URL index; the loser
+	// fails with a duplicate key error, but the product is marked either way
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
 		return fmt.Errorf("failed to mark product as notified: %w", err)
 	}
-	
-	// Also update the product's notified status if it has an ID
-	if product.ID != "" {
-		productCollection := n.db.Collection("products")
-		_, err = productCollection.UpdateByID(ctx, 
-			product.ID, 
+
+	// Also update the product's notified status
+	result, err := db.Collection("products").UpdateOne(ctx,
+		productDocumentFilter(product),
+		bson.M{"$set": bson.M{"notified": true}})
+	if err != nil {
+		log.Warn("Failed to update product notification status",
+			zap.Error(err),
+			zap.String("product_id", product.ID),
+			zap.String("url", product.URL))
+		// Don't fail the whole process for this
+	} else if result.MatchedCount == 0 {
+		log.Debug("No stored product to mark as notified",
+			zap.String("product_id", product.ID),
+			zap.String("url", product.URL))
+	}
+
+	return nil
+}
+
+// productDocumentFilter matches the stored document of product. Products are
+// stored with a string _id, but older documents may use an ObjectID with the
+// same hex, so a hex ID matches either form. Products without an ID are
+// matched by URL.
+func productDocumentFilter(product models.Product) bson.M {
+	if product.ID == "" {
+		return bson.M{"url": product.URL}
+	}
+
+	if objectID, err := primitive.ObjectIDFromHex(product.ID); err == nil {
+		return bson.M{"_id": bson.M{"$in": bson.A{product.ID, objectID}}}
+	}
+	return bson.M{"_id": product.ID}
+}
+
+// reconcileBatchSize is the number of product IDs updated per reconcile write
+const reconcileBatchSize = 500
+
+// ReconcileNotifiedProducts sets notified on products that were recorded in
+// notified_products but whose own flag was never updated, for example because
+// the update used an ID in a different form than the stored _id.
+// It returns the number of products updated.
+func ReconcileNotifiedProducts(ctx context.Context, db *storage.MongoDB) (int64, error) {
+	products := db.Collection("products")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"notified": bson.M{"$ne": true}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "notified_products",
+			"localField":   "url",
+			"foreignField": "url",
+			"as":           "notifications",
+		}}},
+		{{Key: "$match", Value: bson.M{"notifications.0": bson.M{"$exists": true}}}},
+		{{Key: "$project", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := products.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find unmarked notified products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	flush := func(ids bson.A) error {
+		result, err := products.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}},
 			bson.M{"$set": bson.M{"notified": true}})
-			
 		if err != nil {
-			n.logger.Warn("Failed to update product notification status",
-				zap.Error(err),
-				zap.String("product_id", product.ID))
-			// Don't fail the whole process for this
+			return fmt.Errorf("failed to mark products as notified: %w", err)
 		}
+		updated += result.ModifiedCount
+		return nil
+	}
+
+	var ids bson.A
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, fmt.Errorf("failed to decode product: %w", err)
+		}
+
+		ids = append(ids, doc.ID)
+		if len(ids) >= reconcileBatchSize {
+			if err := flush(ids); err != nil {
+				return updated, err
+			}
+			ids = nil
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return updated, fmt.Errorf("failed to iterate products: %w", err)
+	}
+
+	if len(ids) > 0 {
+		if err := flush(ids); err != nil {
+			return updated, err
+		}
+	}
+
+	return updated, nil
+}
+
+// notifiedProductDocument builds the notified_products document written on first mark
+func notifiedProductDocument(product models.Product, notifiedAt time.Time) bson.M {
+	doc := bson.M{
+		"url":         product.URL,
+		"title":       product.Title,
+		"notified_at": notifiedAt,
+	}
+	if product.ID != "" {
+		doc["product_id"] = product.ID
 	}
-	
+	return doc
+}
+
+// SendEmbeds sends embeds to a channel one message at a time, honoring the rate limiter
+func (n *NotificationService) SendEmbeds(ctx context.Context, channelID string, embeds []*discordgo.MessageEmbed) error {
+	for _, embed := range embeds {
+		select {
+		case <-n.rateLimiter.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		_, err := n.sender.SendMessage(channelID, &discordgo.MessageSend{
+			Embed: embed,
+			AllowedMentions: &discordgo.MessageAllowedMentions{
+				Parse: []discordgo.AllowedMentionType{},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send embed to channel %s: %w", channelID, err)
+		}
+	}
+
 	return nil
 }
 
-// Close cleans up resources
+// drainContext returns a context for in-flight sends. It is not canceled with
+// ctx; instead it is canceled drainGrace after ctx ends, or when the service shuts down.
+func (n *NotificationService) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	sendCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	stopGrace := context.AfterFunc(ctx, func() {
+		time.AfterFunc(n.drainGrace, cancel)
+	})
+	stopShutdown := context.AfterFunc(n.lifetime, cancel)
+
+	return sendCtx, func() {
+		stopGrace()
+		stopShutdown()
+		cancel()
+	}
+}
+
+// Drain waits up to timeout for in-flight notifications to be sent and marked.
+// Sends still running at the deadline are canceled. It reports whether all
+// in-flight notifications finished in time.
+func (n *NotificationService) Drain(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		n.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		n.logger.Warn("Shutdown grace period expired, canceling in-flight notifications",
+			zap.Duration("grace", timeout))
+		n.shutdown()
+		return false
+	}
+}
+
+// Close drains in-flight notifications and cleans up resources
 func (n *NotificationService) Close() {
+	n.Drain(n.drainGrace)
+	n.shutdown()
 	n.rateLimiter.Stop()
 	if n.session != nil {
 		n.session.Close()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,606 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestSendProductNotificationsDeniedChannels(t *testing.T) {
+	mt := newMockTest(t)
+	product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+	configured := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true}
+	denied := models.KeywordAlert{ID: "alert-2", Keyword: "모니터", UserID: "user-2", GuildID: "guild-1", ChannelID: "channel-2", IsActive: true}
+
+	tests := []struct {
+		name       string
+		alerts     []models.KeywordAlert
+		wantMarked bool
+	}{
+		{"denied with a failed send", []models.KeywordAlert{configured, denied}, false},
+		{"only denied", []models.KeywordAlert{denied}, true},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			server := newWebhookServer(mt.T, http.StatusInternalServerError)
+			cfg := &config.Config{
+				NotificationTransport: "webhook",
+				WebhookURLs:           map[string]string{"channel-1": server.URL},
+				NotificationLanguage:  "ko",
+			}
+			n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+			mt.AddMockResponses(
+				cursorResponse(), // channel snoozes
+				writeResponse(1), // notified_products
+				writeResponse(1), // products
+			)
+
+			n.sendProductNotifications(context.Background(), product, tt.alerts)
+
+			marked := len(startedCommands(mt, "update")) > 0
+			if marked != tt.wantMarked {
+				mt.Errorf("product marked notified = %v, want %v", marked, tt.wantMarked)
+			}
+		})
+	}
+}
+
+func TestBestDealsSentOncePerRun(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("two batches", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true, BestDeal: models.BestDealPrice}
+		n.alertMatcher.index = NewKeywordIndex([]models.KeywordAlert{alert})
+		n.alertMatcher.indexedAt = time.Now()
+
+		notNotified := cursorResponse(bson.D{{Key: "n", Value: 0}})
+		mt.AddMockResponses(notNotified)
+		first := models.Product{Title: "27인치 모니터 A", URL: "https://example.com/deal/1", KOPrice: 300000}
+		if err := n.NotifyNewProducts(context.Background(), []models.Product{first}); err != nil {
+			mt.Fatalf("NotifyNewProducts() error = %v", err)
+		}
+		mt.AddMockResponses(notNotified)
+		second := models.Product{Title: "27인치 모니터 B", URL: "https://example.com/deal/2", KOPrice: 250000}
+		if err := n.NotifyNewProducts(context.Background(), []models.Product{second}); err != nil {
+			mt.Fatalf("NotifyNewProducts() error = %v", err)
+		}
+		if got := len(server.received()); got != 0 {
+			mt.Fatalf("best deal sent %d messages before the run ended, want 0", got)
+		}
+
+		mt.AddMockResponses(
+			writeResponse(1), // best deal date
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(1), // alert match
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+			writeResponse(1), // alert notification
+		)
+		if err := n.SendBestDeals(context.Background()); err != nil {
+			mt.Fatalf("SendBestDeals() error = %v", err)
+		}
+
+		payloads := server.received()
+		if len(payloads) != 1 {
+			mt.Fatalf("best deal sent %d messages, want 1", len(payloads))
+		}
+		if title := payloads[0].Embeds[0].Title; !strings.Contains(title, second.Title) {
+			mt.Errorf("best deal sent %q, want the cheaper %q from the later batch", title, second.Title)
+		}
+	})
+}
+
+func TestFilterAlertsByChannelGuild(t *testing.T) {
+	channelGuilds := map[string]string{"channel-a": "guild-a", "channel-b": "guild-b"}
+	lookup := func(channelID string) (string, error) {
+		guildID, ok := channelGuilds[channelID]
+		if !ok {
+			return "", errors.New("unknown channel")
+		}
+		return guildID, nil
+	}
+
+	alerts := []models.KeywordAlert{
+		{ID: "same-guild", GuildID: "guild-a", ChannelID: "channel-a"},
+		{ID: "other-guild", GuildID: "guild-a", ChannelID: "channel-b"},
+		{ID: "unknown-channel", GuildID: "guild-a", ChannelID: "channel-x"},
+		{ID: "legacy", ChannelID: "channel-b"},
+	}
+
+	kept, dropped := filterAlertsByChannelGuild(alerts, lookup)
+
+	ids := func(alerts []models.KeywordAlert) []string {
+		var ids []string
+		for _, alert := range alerts {
+			ids = append(ids, alert.ID)
+		}
+		return ids
+	}
+	if got := strings.Join(ids(kept), ","); got != "same-guild,legacy" {
+		t.Errorf("kept = %s, want same-guild,legacy", got)
+	}
+	if got := strings.Join(ids(dropped), ","); got != "other-guild,unknown-channel" {
+		t.Errorf("dropped = %s, want other-guild,unknown-channel", got)
+	}
+}
+
+func TestSendProductNotificationsOneMessagePerChannel(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("two users", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		mt.AddMockResponses(
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(2), // alert matches
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+		)
+
+		alerts := []models.KeywordAlert{
+			{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true},
+			{ID: "alert-2", Keyword: "27인치", UserID: "user-2", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true},
+		}
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+		if err := n.sendProductNotifications(context.Background(), product, alerts); err != nil {
+			mt.Fatalf("sendProductNotifications() error = %v", err)
+		}
+
+		payloads := server.received()
+		if len(payloads) != 1 {
+			mt.Fatalf("channel received %d messages, want 1", len(payloads))
+		}
+		if payloads[0].Content != "<@user-1> <@user-2>" {
+			mt.Errorf("content = %q, want both users mentioned", payloads[0].Content)
+		}
+		if got := strings.Join(payloads[0].AllowedMentions.Users, ","); got != "user-1,user-2" {
+			mt.Errorf("allowed mentions = %s, want user-1,user-2", got)
+		}
+	})
+}
+
+func TestSendProductNotificationsQuietHours(t *testing.T) {
+	mt := newMockTest(t)
+	product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+	now := time.Now().UTC()
+	clock := func(t time.Time) string { return t.Format("15:04") }
+
+	mt.Run("inside the window", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		mt.AddMockResponses(
+			writeResponse(1), // pending notification
+			writeResponse(1), // alert notification
+		)
+		alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", ChannelID: "channel-1", IsActive: true,
+			QuietStart: clock(now.Add(-time.Hour)), QuietEnd: clock(now.Add(time.Hour))}
+
+		if err := n.sendProductNotifications(context.Background(), product, []models.KeywordAlert{alert}); err != nil {
+			mt.Fatalf("sendProductNotifications() error = %v", err)
+		}
+
+		if got := len(server.received()); got != 0 {
+			mt.Errorf("sent %d messages during quiet hours, want 0", got)
+		}
+		inserts := startedCommands(mt, "insert")
+		if len(inserts) != 1 || inserts[0].Lookup("insert").StringValue() != "pending_notifications" {
+			mt.Fatalf("inserts = %v, want the match queued in pending_notifications", inserts)
+		}
+		deliverAt := inserts[0].Lookup("documents").Array().Index(0).Value().Document().Lookup("deliver_at").Time()
+		if want := alert.QuietHoursEnd(now); !deliverAt.Equal(want) {
+			mt.Errorf("queued until %v, want the end of quiet hours %v", deliverAt, want)
+		}
+	})
+
+	mt.Run("outside the window", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		mt.AddMockResponses(
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(1), // alert match
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+			writeResponse(1), // alert notification
+		)
+		alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", ChannelID: "channel-1", IsActive: true,
+			QuietStart: clock(now.Add(2 * time.Hour)), QuietEnd: clock(now.Add(3 * time.Hour))}
+
+		if err := n.sendProductNotifications(context.Background(), product, []models.KeywordAlert{alert}); err != nil {
+			mt.Fatalf("sendProductNotifications() error = %v", err)
+		}
+
+		if got := len(server.received()); got != 1 {
+			mt.Errorf("sent %d messages outside quiet hours, want 1", got)
+		}
+		for _, insert := range startedCommands(mt, "insert") {
+			if insert.Lookup("insert").StringValue() == "pending_notifications" {
+				mt.Error("match outside quiet hours was queued")
+			}
+		}
+	})
+}
+
+func TestSendProductNotificationsSnoozedChannel(t *testing.T) {
+	mt := newMockTest(t)
+	product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+	alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", ChannelID: "channel-1", IsActive: true}
+	snooze := cursorResponse(bson.D{
+		{Key: "_id", Value: "channel-1"},
+		{Key: "until", Value: time.Now().Add(time.Hour)},
+	})
+
+	tests := []struct {
+		name       string
+		queue      bool
+		wantQueued bool
+	}{
+		{"dropped", false, false},
+		{"queued", true, true},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			server := newWebhookServer(mt.T, http.StatusOK)
+			cfg := &config.Config{
+				NotificationTransport:    "webhook",
+				WebhookURLs:              map[string]string{"channel-1": server.URL},
+				NotificationLanguage:     "ko",
+				SnoozeQueueNotifications: tt.queue,
+			}
+			n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+			mt.AddMockResponses(snooze)
+			if tt.queue {
+				mt.AddMockResponses(writeResponse(1)) // pending notification
+			}
+			mt.AddMockResponses(
+				writeResponse(1), // notified_products
+				writeResponse(1), // products
+				writeResponse(1), // alert notification
+			)
+
+			if err := n.sendProductNotifications(context.Background(), product, []models.KeywordAlert{alert}); err != nil {
+				mt.Fatalf("sendProductNotifications() error = %v", err)
+			}
+
+			if got := len(server.received()); got != 0 {
+				mt.Errorf("snoozed channel received %d messages, want 0", got)
+			}
+			queued := false
+			for _, insert := range startedCommands(mt, "insert") {
+				if insert.Lookup("insert").StringValue() == "pending_notifications" {
+					queued = true
+				}
+			}
+			if queued != tt.wantQueued {
+				mt.Errorf("queued = %v, want %v", queued, tt.wantQueued)
+			}
+			if len(startedCommands(mt, "update")) == 0 {
+				mt.Error("product wasn't marked notified, the channel would be flooded when the snooze ends")
+			}
+		})
+	}
+}
+
+func TestDrainContext(t *testing.T) {
+	lifetime, shutdown := context.WithCancel(context.Background())
+	n := &NotificationService{drainGrace: 50 * time.Millisecond, lifetime: lifetime, shutdown: shutdown}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sendCtx, release := n.drainContext(ctx)
+	defer release()
+
+	cancel()
+	if sendCtx.Err() != nil {
+		t.Fatal("send context canceled with the run, want it kept for the grace period")
+	}
+	select {
+	case <-sendCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("send context not canceled after the grace period")
+	}
+
+	sendCtx, release = n.drainContext(context.Background())
+	defer release()
+	shutdown()
+	select {
+	case <-sendCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("send context not canceled on shutdown")
+	}
+}
+
+func TestNotifyNewProductsCanceledMidSend(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("cancel", func(mt *mtest.T) {
+		hit := make(chan struct{})
+		release := make(chan struct{})
+		server := newWebhookServer(mt.T, http.StatusOK)
+		handler := server.Config.Handler
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(hit)
+			<-release
+			handler.ServeHTTP(w, r)
+		})
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		lifetime, shutdown := context.WithCancel(context.Background())
+		n.lifetime, n.shutdown, n.drainGrace = lifetime, shutdown, 5*time.Second
+		alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", ChannelID: "channel-1", IsActive: true}
+		n.alertMatcher.index = NewKeywordIndex([]models.KeywordAlert{alert})
+		n.alertMatcher.indexedAt = time.Now()
+		mt.AddMockResponses(
+			cursorResponse(bson.D{{Key: "n", Value: 0}}), // already notified check
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(1), // alert match
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+			writeResponse(1), // alert notification
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		go func() {
+			result <- n.NotifyNewProducts(ctx, []models.Product{{Title: "27인치 모니터", URL: "https://example.com/deal/1"}})
+		}()
+
+		<-hit
+		cancel()
+		close(release)
+		if err := <-result; err != nil {
+			mt.Fatalf("NotifyNewProducts() error = %v", err)
+		}
+
+		if got := len(server.received()); got != 1 {
+			mt.Errorf("sent %d messages, want the in-flight one finished", got)
+		}
+		marked := false
+		for _, update := range startedCommands(mt, "update") {
+			if update.Lookup("update").StringValue() == "notified_products" {
+				marked = true
+			}
+		}
+		if !marked {
+			mt.Error("in-flight product wasn't marked notified after the run was canceled")
+		}
+		if !n.Drain(time.Second) {
+			mt.Error("Drain() = false with nothing in flight")
+		}
+	})
+}
+
+func TestMarkNotifiedConcurrently(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("same url", func(mt *mtest.T) {
+		db := newMockDB(mt, &config.Config{})
+		// Each mark upserts notified_products and then updates products; the
+		// loser of the upsert race hits the unique URL index
+		mt.AddMockResponses(
+			writeResponse(1),
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}),
+			writeResponse(1),
+			writeResponse(1),
+		)
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				errs <- recordProductNotified(context.Background(), db, zap.NewNop(), product)
+			}()
+		}
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				mt.Errorf("recordProductNotified() error = %v, want both marks to succeed", err)
+			}
+		}
+
+		marks := 0
+		for _, update := range startedCommands(mt, "update") {
+			if update.Lookup("update").StringValue() != "notified_products" {
+				continue
+			}
+			marks++
+			u := update.Lookup("updates").Array().Index(0).Value().Document()
+			if upsert, _ := u.Lookup("upsert").BooleanOK(); !upsert {
+				mt.Error("mark is not an upsert")
+			}
+			if _, ok := u.Lookup("u", "$setOnInsert", "notified_at").TimeOK(); !ok {
+				mt.Errorf("update = %v, want notified_at only set on insert", u.Lookup("u"))
+			}
+			if _, err := u.LookupErr("u", "$set"); err == nil {
+				mt.Errorf("update = %v, want no $set overwriting the first mark", u.Lookup("u"))
+			}
+		}
+		if marks != 2 {
+			mt.Errorf("sent %d marks, want 2", marks)
+		}
+	})
+}
+
+func TestRecordProductNotifiedSetsProductFlag(t *testing.T) {
+	mt := newMockTest(t)
+
+	hexID := models.ProductIDFromURL("https://example.com/deal/1")
+	objectID, _ := primitive.ObjectIDFromHex(hexID)
+	tests := []struct {
+		name    string
+		product models.Product
+		filter  bson.D
+	}{
+		{
+			name:    "hex ID matches either _id form",
+			product: models.Product{ID: hexID, URL: "https://example.com/deal/1"},
+			filter:  bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: bson.A{hexID, objectID}}}}},
+		},
+		{
+			name:    "other ID",
+			product: models.Product{ID: "legacy-1", URL: "https://example.com/deal/1"},
+			filter:  bson.D{{Key: "_id", Value: "legacy-1"}},
+		},
+		{
+			name:    "no ID matches by URL",
+			product: models.Product{URL: "https://example.com/deal/1"},
+			filter:  bson.D{{Key: "url", Value: "https://example.com/deal/1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			db := newMockDB(mt, &config.Config{})
+			mt.AddMockResponses(writeResponse(1), writeResponse(1))
+
+			if err := recordProductNotified(context.Background(), db, zap.NewNop(), tt.product); err != nil {
+				mt.Fatalf("recordProductNotified() error = %v", err)
+			}
+
+			var found bool
+			for _, update := range startedCommands(mt, "update") {
+				if update.Lookup("update").StringValue() != "products" {
+					continue
+				}
+				found = true
+				u := update.Lookup("updates").Array().Index(0).Value().Document()
+				want, _ := bson.Marshal(tt.filter)
+				if !bytes.Equal(u.Lookup("q").Document(), want) {
+					mt.Errorf("filter = %v, want %v", u.Lookup("q"), bson.Raw(want))
+				}
+				if notified, ok := u.Lookup("u", "$set", "notified").BooleanOK(); !ok || !notified {
+					mt.Errorf("update = %v, want notified set to true", u.Lookup("u"))
+				}
+			}
+			if !found {
+				mt.Error("product document was never updated")
+			}
+		})
+	}
+}
+
+func TestReconcileNotifiedProducts(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("unmarked products", func(mt *mtest.T) {
+		db := newMockDB(mt, &config.Config{})
+		objectID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			cursorResponse(bson.D{{Key: "_id", Value: "a1"}}, bson.D{{Key: "_id", Value: objectID}}),
+			writeResponse(2),
+		)
+
+		updated, err := ReconcileNotifiedProducts(context.Background(), db)
+		if err != nil {
+			mt.Fatalf("ReconcileNotifiedProducts() error = %v", err)
+		}
+		if updated != 2 {
+			mt.Errorf("updated %d products, want 2", updated)
+		}
+
+		updates := startedCommands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d updates, want 1", len(updates))
+		}
+		u := updates[0].Lookup("updates").Array().Index(0).Value().Document()
+		ids := u.Lookup("q", "_id", "$in").Array()
+		if id := ids.Index(0).Value().StringValue(); id != "a1" {
+			mt.Errorf("first ID = %v, want the string _id as stored", id)
+		}
+		if id := ids.Index(1).Value().ObjectID(); id != objectID {
+			mt.Errorf("second ID = %v, want the ObjectID as stored", id)
+		}
+		if notified, ok := u.Lookup("u", "$set", "notified").BooleanOK(); !ok || !notified {
+			mt.Errorf("update = %v, want notified set to true", u.Lookup("u"))
+		}
+	})
+}
+
+func TestNotifyChangedProductsSkipsNotifiedCheck(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("already notified", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		n := newWebhookTestService(mt.T, cfg, newMockDB(mt, cfg))
+		alert := models.KeywordAlert{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true}
+		n.alertMatcher.index = NewKeywordIndex([]models.KeywordAlert{alert})
+		n.alertMatcher.indexedAt = time.Now()
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1", KOPrice: 250000}
+
+		mt.AddMockResponses(cursorResponse(bson.D{{Key: "n", Value: 1}}))
+		if err := n.NotifyNewProducts(context.Background(), []models.Product{product}); err != nil {
+			mt.Fatalf("NotifyNewProducts() error = %v", err)
+		}
+		if got := len(server.received()); got != 0 {
+			mt.Fatalf("sent %d messages for a notified product, want 0", got)
+		}
+		checks := len(startedCommands(mt, "aggregate"))
+		if checks == 0 {
+			mt.Fatal("NotifyNewProducts() didn't check whether the product was notified")
+		}
+
+		mt.AddMockResponses(
+			writeResponse(1), // alert cooldown
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(1), // alert match
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+			writeResponse(1), // alert notification
+		)
+		if err := n.NotifyChangedProducts(context.Background(), []models.Product{product}); err != nil {
+			mt.Fatalf("NotifyChangedProducts() error = %v", err)
+		}
+		if got := len(startedCommands(mt, "aggregate")); got != checks {
+			mt.Errorf("sent %d notified checks for a changed product, want none", got-checks)
+		}
+		if got := len(server.received()); got != 1 {
+			mt.Errorf("sent %d messages for a changed product, want 1", got)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// notifiedFilterMinCapacity is the smallest number of URLs the filter is sized for
+	notifiedFilterMinCapacity = 100000
+	// notifiedFilterFalsePositiveRate is the target false positive rate at capacity
+	notifiedFilterFalsePositiveRate = 0.01
+	// notifiedFilterRefreshOverlap is how far before the last load a refresh
+	// starts reading, covering clock skew between processes and slow writes
+	notifiedFilterRefreshOverlap = 5 * time.Minute
+)
+
+// notifiedFilter is a Bloom filter over notified product URLs. It never
+// forgets a URL, so a miss means the URL was never added, while a hit may be
+// a false positive and must be confirmed against notified_products. Past its
+// capacity the false positive rate rises, which only costs extra queries.
+//
+// Other processes mark products too, so the filter is refreshed with the
+// URLs notified since it was last loaded before each run. Until a failed
+// refresh succeeds again, the filter is stale and every check goes to the DB.
+type notifiedFilter struct {
+	mu     sync.RWMutex
+	bits   []uint64
+	m      uint64 // number of bits
+	hashes int    // number of bit positions per URL
+
+	loadedAt time.Time // when the last successful load or refresh started
+	stale    bool
+}
+
+// newNotifiedFilter creates a filter sized for capacity URLs at falsePositiveRate
+func newNotifiedFilter(capacity int, falsePositiveRate float64) *notifiedFilter {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	// Optimal size and hash count for n items at rate p:
+	// m = -n ln p / (ln 2)^2, k = m/n ln 2
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	hashes := int(math.Round(float64(m) / float64(capacity) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	return &notifiedFilter{
+		bits:   make([]uint64, m/64),
+		m:      m,
+		hashes: hashes,
+	}
+}
+
+// add records a URL as notified
+func (f *notifiedFilter) add(url string) {
+	h1, h2 := filterHashes(url)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain reports whether url may have been added. False means it definitely wasn't.
+func (f *notifiedFilter) mayContain(url string) bool {
+	h1, h2 := filterHashes(url)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for i := 0; i < f.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// skip reports whether url is known not to be notified without asking the
+// database. It is false for a nil or stale filter.
+func (f *notifiedFilter) skip(url string) bool {
+	if f == nil {
+		return false
+	}
+	f.mu.RLock()
+	stale := f.stale
+	f.mu.RUnlock()
+	return !stale && !f.mayContain(url)
+}
+
+// refresh adds the URLs notified since the filter was last loaded. On
+// failure the filter is stale until a later refresh succeeds.
+func (f *notifiedFilter) refresh(ctx context.Context, db *storage.MongoDB) error {
+	f.mu.RLock()
+	since := f.loadedAt.Add(-notifiedFilterRefreshOverlap)
+	f.mu.RUnlock()
+
+	started := time.Now()
+	err := f.addNotified(ctx, db, bson.M{"notified_at": bson.M{"$gte": since}})
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err != nil {
+		f.stale = true
+		return err
+	}
+	f.loadedAt = started
+	f.stale = false
+	return nil
+}
+
+// addNotified adds the URLs of the notified_products documents matching query
+func (f *notifiedFilter) addNotified(ctx context.Context, db *storage.MongoDB, query bson.M) error {
+	collection := db.Collection("notified_products")
+	cursor, err := collection.Find(ctx, query, options.Find().SetProjection(bson.M{"url": 1, "_id": 0}))
+	if err != nil {
+		return fmt.Errorf("failed to load notified products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			URL string `bson:"url"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode notified product: %w", err)
+		}
+		f.add(doc.URL)
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to load notified products: %w", err)
+	}
+	return nil
+}
+
+// filterHashes returns two independent hashes of url, combined as h1 + i*h2
+// to derive each bit position (Kirsch-Mitzenmacher)
+func filterHashes(url string) (uint64, uint64) {
+	a := fnv.New64a()
+	a.Write([]byte(url))
+	b := fnv.New64()
+	b.Write([]byte(url))
+	return a.Sum64(), b.Sum64() | 1 // odd, so positions don't collapse when h2 is 0
+}
+
+// loadNotifiedFilter builds a filter holding every URL in notified_products,
+// sized for twice the current count so it has room to grow
+func loadNotifiedFilter(ctx context.Context, db *storage.MongoDB) (*notifiedFilter, error) {
+	collection := db.Collection("notified_products")
+
+	count, err := collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count notified products: %w", err)
+	}
+
+	capacity := int(count) * 2
+	if capacity < notifiedFilterMinCapacity {
+		capacity = notifiedFilterMinCapacity
+	}
+	filter := newNotifiedFilter(capacity, notifiedFilterFalsePositiveRate)
+	filter.loadedAt = time.Now()
+	if err := filter.addNotified(ctx, db, bson.M{}); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
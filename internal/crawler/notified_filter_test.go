@@ -0,0 +1,161 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestNotifiedFilter(t *testing.T) {
+	filter := newNotifiedFilter(1000, notifiedFilterFalsePositiveRate)
+	for i := 0; i < 1000; i++ {
+		filter.add(fmt.Sprintf("https://example.com/deal/%d", i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if url := fmt.Sprintf("https://example.com/deal/%d", i); !filter.mayContain(url) {
+			t.Fatalf("mayContain(%q) = false for an added URL", url)
+		}
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.mayContain(fmt.Sprintf("https://example.com/other/%d", i)) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 3*notifiedFilterFalsePositiveRate {
+		t.Errorf("false positive rate = %.3f, want about %.2f", rate, notifiedFilterFalsePositiveRate)
+	}
+}
+
+func TestNotifiedFilterSkip(t *testing.T) {
+	var missing *notifiedFilter
+	if missing.skip("https://example.com/deal/1") {
+		t.Error("nil filter skipped the database")
+	}
+
+	filter := newNotifiedFilter(100, notifiedFilterFalsePositiveRate)
+	filter.add("https://example.com/deal/1")
+	if filter.skip("https://example.com/deal/1") {
+		t.Error("skip() = true for an added URL")
+	}
+	if !filter.skip("https://example.com/deal/2") {
+		t.Error("skip() = false for a URL never added")
+	}
+
+	filter.stale = true
+	if filter.skip("https://example.com/deal/2") {
+		t.Error("stale filter skipped the database")
+	}
+}
+
+func TestNotifiedFilterRefresh(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("adds new marks", func(mt *mtest.T) {
+		db := newMockDB(mt, &config.Config{})
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			cursorResponse(bson.D{{Key: "url", Value: "https://example.com/deal/1"}}),
+		)
+		filter, err := loadNotifiedFilter(context.Background(), db)
+		if err != nil {
+			mt.Fatalf("loadNotifiedFilter() error = %v", err)
+		}
+		loadedAt := filter.loadedAt
+
+		// Marked by another process after the load
+		mt.AddMockResponses(cursorResponse(bson.D{{Key: "url", Value: "https://example.com/deal/2"}}))
+		if err := filter.refresh(context.Background(), db); err != nil {
+			mt.Fatalf("refresh() error = %v", err)
+		}
+
+		if filter.skip("https://example.com/deal/2") {
+			mt.Error("refreshed filter skipped a product marked by another process")
+		}
+		finds := startedCommands(mt, "find")
+		if len(finds) != 2 {
+			mt.Fatalf("sent %d finds, want 2", len(finds))
+		}
+		since, ok := finds[1].Lookup("filter", "notified_at", "$gte").DateTimeOK()
+		if !ok {
+			mt.Fatalf("refresh filter = %v, want notified_at $gte", finds[1].Lookup("filter"))
+		}
+		if want := loadedAt.Add(-notifiedFilterRefreshOverlap); time.UnixMilli(since).Before(want.Add(-time.Second)) || time.UnixMilli(since).After(want.Add(time.Second)) {
+			mt.Errorf("refresh read from %v, want %v", time.UnixMilli(since), want)
+		}
+		if filter.loadedAt.Before(loadedAt) {
+			mt.Error("refresh didn't move loadedAt forward")
+		}
+	})
+
+	mt.Run("failure marks stale", func(mt *mtest.T) {
+		db := newMockDB(mt, &config.Config{})
+		filter := newNotifiedFilter(100, notifiedFilterFalsePositiveRate)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad"}))
+
+		if err := filter.refresh(context.Background(), db); err == nil {
+			mt.Fatal("refresh() error = nil, want the find error")
+		}
+		if filter.skip("https://example.com/deal/1") {
+			mt.Error("filter skipped the database after a failed refresh")
+		}
+
+		mt.AddMockResponses(cursorResponse())
+		if err := filter.refresh(context.Background(), db); err != nil {
+			mt.Fatalf("refresh() error = %v", err)
+		}
+		if !filter.skip("https://example.com/deal/1") {
+			mt.Error("filter still stale after a successful refresh")
+		}
+	})
+}
+
+func TestSlackMarksNotifiedFilter(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("mark", func(mt *mtest.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		cfg := &config.Config{SlackWebhookURL: server.URL, NotificationLanguage: "ko"}
+		db := newMockDB(mt, cfg)
+		limiter := time.NewTicker(time.Millisecond)
+		defer limiter.Stop()
+		s := &SlackNotifier{
+			config:       cfg,
+			db:           db,
+			alertMatcher: NewAlertMatcher(db, zap.NewNop()),
+			client:       server.Client(),
+			rateLimiter:  limiter,
+			log:          zap.NewNop(),
+			notified:     newNotifiedFilter(100, notifiedFilterFalsePositiveRate),
+		}
+		mt.AddMockResponses(
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+			writeResponse(1), // alert notification
+		)
+
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+		alerts := []models.KeywordAlert{cooldownAlert()}
+		if err := s.sendProductNotifications(context.Background(), product, alerts); err != nil {
+			mt.Fatalf("sendProductNotifications() error = %v", err)
+		}
+		if s.notified.skip(product.URL) {
+			mt.Error("Slack mark didn't add the product to the notified filter")
+		}
+	})
+}
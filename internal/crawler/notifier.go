@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"context"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers notifications about newly found products.
+// The crawlers depend only on this interface; the concrete implementation
+// is chosen when the crawler is created.
+type Notifier interface {
+	NotifyNewProducts(ctx context.Context, products []models.Product) error
+	Close()
+}
+
+// PendingDeliverer is implemented by notifiers that hold notifications back
+// (quiet hours, snoozes) and deliver them on a later run
+type PendingDeliverer interface {
+	DeliverPendingNotifications(ctx context.Context) error
+}
+
+// BestDealSender is implemented by notifiers that hold best deal alerts back
+// during a crawl run and then send each the best product it matched. The
+// crawler calls SendBestDeals once after the run's last batch.
+type BestDealSender interface {
+	SendBestDeals(ctx context.Context) error
+}
+
+// ChangeNotifier is implemented by notifiers that can notify again about
+// stored products whose price or discount changed. Unlike NotifyNewProducts,
+// NotifyChangedProducts doesn't skip products that were already notified.
+type ChangeNotifier interface {
+	NotifyChangedProducts(ctx context.Context, products []models.Product) error
+}
+
+// NotificationPreviewer is implemented by notifiers that can report which
+// products would be notified without sending anything (used by dry-run)
+type NotificationPreviewer interface {
+	PreviewNotifications(ctx context.Context, products []models.Product) ([]models.Product, error)
+}
+
+// EmbedSender is implemented by notifiers that can post arbitrary embeds to
+// a channel (used by the weekly digest)
+type EmbedSender interface {
+	SendEmbeds(ctx context.Context, channelID string, embeds []*discordgo.MessageEmbed) error
+}
+
+// DMSender is implemented by notifiers that can send embeds to a user by
+// direct message (used by the alert summaries)
+type DMSender interface {
+	SendDMEmbeds(ctx context.Context, userID string, embeds []*discordgo.MessageEmbed) error
+}
+
+// NotificationService is the default Notifier; SlackNotifier is used for the Slack backend
+var (
+	_ Notifier              = (*NotificationService)(nil)
+	_ PendingDeliverer      = (*NotificationService)(nil)
+	_ BestDealSender        = (*NotificationService)(nil)
+	_ ChangeNotifier        = (*NotificationService)(nil)
+	_ NotificationPreviewer = (*NotificationService)(nil)
+	_ EmbedSender           = (*NotificationService)(nil)
+	_ DMSender              = (*NotificationService)(nil)
+	_ Notifier              = (*SlackNotifier)(nil)
+	_ BestDealSender        = (*SlackNotifier)(nil)
+	_ ChangeNotifier        = (*SlackNotifier)(nil)
+	_ NotificationPreviewer = (*SlackNotifier)(nil)
+)
+
+// newNotifier creates the notifier for the configured notification backend
+func newNotifier(cfg *config.Config, db *storage.MongoDB, log *zap.Logger) (Notifier, error) {
+	if cfg.NotificationBackend == "slack" {
+		return NewSlackNotifier(cfg, db, log), nil
+	}
+
+	notifier, err := NewNotificationService(cfg, db, log)
+	if err != nil {
+		return nil, err
+	}
+	return notifier, nil
+}
@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// expireUnseenProducts marks the products that haven't appeared in their
+// source for ProductExpireRuns runs as expired. Only sources crawled
+// successfully in the run that started at runStart are considered, so a
+// failing source doesn't expire its products.
+func (c *ImprovedCrawler) expireUnseenProducts(ctx context.Context, runStart time.Time) {
+	if c.config.ProductExpireRuns <= 0 {
+		return
+	}
+
+	sources := c.crawledSources(runStart)
+	if len(sources) == 0 {
+		return
+	}
+
+	interval := time.Duration(c.config.CrawlIntervalMinutes) * time.Minute
+	cutoff := runStart.Add(-time.Duration(c.config.ProductExpireRuns) * interval)
+
+	expired, err := c.expireProductsBefore(ctx, sources, cutoff)
+	if err != nil {
+		c.log.Error("Failed to expire unseen products", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		c.log.Info("Expired products no longer seen",
+			zap.Int64("expired", expired),
+			zap.Strings("sources", sources),
+			zap.Time("last_seen_before", cutoff))
+	}
+}
+
+// crawledSources returns the sources crawled without error since runStart
+func (c *ImprovedCrawler) crawledSources(runStart time.Time) []string {
+	c.statsMutex.RLock()
+	defer c.statsMutex.RUnlock()
+
+	var names []string
+	for name, stats := range c.stats.SourceStats {
+		if stats.LastError == "" && !stats.LastRun.Before(runStart) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// expireProductsBefore marks the active products of sources last seen before
+// cutoff as expired and returns how many were marked. Products stored before
+// last_seen_at was tracked have no last seen time and are left alone.
+func (c *ImprovedCrawler) expireProductsBefore(ctx context.Context, sources []string, cutoff time.Time) (int64, error) {
+	result, err := c.db.Collection("products").UpdateMany(ctx, expireProductsFilter(sources, cutoff),
+		bson.M{"$set": bson.M{"expired": true}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire products: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// expireProductsFilter matches the unexpired products of sources last seen before cutoff
+func expireProductsFilter(sources []string, cutoff time.Time) bson.M {
+	return bson.M{
+		"source":       bson.M{"$in": sources},
+		"last_seen_at": bson.M{"$lt": cutoff},
+		"expired":      bson.M{"$ne": true},
+	}
+}
@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRunSetsSeenTimestamps(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("insert and update", func(mt *mtest.T) {
+		crawledAt := time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC)
+		firstSeen := time.Date(2026, time.October, 10, 9, 0, 0, 0, time.UTC)
+		src := &staticSource{name: "test", products: []models.Product{
+			{Title: "27인치 모니터", URL: "https://example.com/deal/1", CrawledAt: crawledAt},
+			{Title: "기계식 키보드", URL: "https://example.com/deal/2", CrawledAt: crawledAt},
+		}}
+		c := newTestCrawler(mt, &config.Config{}, &recordingNotifier{}, src)
+		mt.AddMockResponses(
+			cursorResponse(), writeResponse(1), // the monitor is new and inserted
+			cursorResponse(bson.D{
+				{Key: "_id", Value: "deal-2"},
+				{Key: "title", Value: "기계식 키보드"},
+				{Key: "url", Value: "https://example.com/deal/2"},
+				{Key: "crawled_at", Value: firstSeen},
+				{Key: "first_seen_at", Value: firstSeen},
+				{Key: "last_seen_at", Value: firstSeen},
+			}), writeResponse(1), // the keyboard is stored and seen again
+		)
+
+		before := time.Now()
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		inserts := startedCommands(mt, "insert")
+		if len(inserts) != 1 {
+			mt.Fatalf("sent %d inserts, want 1", len(inserts))
+		}
+		inserted := inserts[0].Lookup("documents").Array().Index(0).Value().Document()
+		for _, field := range []string{"crawled_at", "first_seen_at", "last_seen_at"} {
+			if got := inserted.Lookup(field).Time(); !got.Equal(crawledAt) {
+				mt.Errorf("inserted %s = %v, want the crawl time %v", field, got, crawledAt)
+			}
+		}
+
+		updates := startedCommands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d updates, want 1", len(updates))
+		}
+		set := updates[0].Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+		if lastSeen := set.Lookup("last_seen_at").Time(); lastSeen.Before(before.Truncate(time.Millisecond)) {
+			mt.Errorf("updated last_seen_at = %v, want the time of this run", lastSeen)
+		}
+		for _, field := range []string{"crawled_at", "first_seen_at"} {
+			if _, err := set.LookupErr(field); err == nil {
+				mt.Errorf("update set %s, want it kept from the insert", field)
+			}
+		}
+	})
+}
+
+func TestExpireUnseenProducts(t *testing.T) {
+	mt := newMockTest(t)
+	runStart := time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC)
+	sourceStats := map[string]SourceStats{
+		"crawled": {LastRun: runStart.Add(time.Minute)},
+		"failing": {LastRun: runStart.Add(time.Minute), LastError: "timeout"},
+		"not-run": {LastRun: runStart.Add(-time.Hour)},
+	}
+
+	mt.Run("expires crawled sources", func(mt *mtest.T) {
+		c := newTestCrawler(mt, &config.Config{ProductExpireRuns: 4, CrawlIntervalMinutes: 30}, &recordingNotifier{})
+		c.stats.SourceStats = sourceStats
+		mt.AddMockResponses(writeResponse(3))
+
+		c.expireUnseenProducts(context.Background(), runStart)
+
+		updates := startedCommands(mt, "update")
+		if len(updates) != 1 {
+			mt.Fatalf("sent %d updates, want 1", len(updates))
+		}
+		update := updates[0].Lookup("updates").Array().Index(0).Value().Document()
+		if !update.Lookup("multi").Boolean() {
+			mt.Error("expired a single product, want every unseen product")
+		}
+		var sources []string
+		values, _ := update.Lookup("q", "source", "$in").Array().Values()
+		for _, value := range values {
+			sources = append(sources, value.StringValue())
+		}
+		if !reflect.DeepEqual(sources, []string{"crawled"}) {
+			mt.Errorf("expired sources %v, want only the source crawled this run", sources)
+		}
+		if cutoff := update.Lookup("q", "last_seen_at", "$lt").Time(); !cutoff.Equal(runStart.Add(-2 * time.Hour)) {
+			mt.Errorf("cutoff = %v, want 4 runs of 30 minutes before the run", cutoff)
+		}
+		if !update.Lookup("u", "$set", "expired").Boolean() {
+			mt.Error("update doesn't mark products expired")
+		}
+	})
+
+	mt.Run("disabled", func(mt *mtest.T) {
+		c := newTestCrawler(mt, &config.Config{CrawlIntervalMinutes: 30}, &recordingNotifier{})
+		c.stats.SourceStats = sourceStats
+
+		c.expireUnseenProducts(context.Background(), runStart)
+
+		if updates := startedCommands(mt, "update"); len(updates) != 0 {
+			mt.Errorf("sent %d updates with expiry disabled, want none", len(updates))
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package crawler
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// schedule runs a job immediately and then repeatedly, waiting a jittered
+// interval between runs so that instances and sources don't all fire at once
+type schedule struct {
+	log           *zap.Logger
+	interval      time.Duration
+	jitterPercent int // 0이면 정확히 interval마다 실행
+	random        *lockedRand
+
+	// after returns a channel that fires once d has elapsed; replaced by a fake clock in tests
+	after func(d time.Duration) <-chan time.Time
+
+	// onSkip is called when a tick is skipped because the previous run is still going
+	onSkip func()
+
+	running atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// newSchedule creates a schedule with the given interval and ±jitterPercent jitter
+func newSchedule(log *zap.Logger, interval time.Duration, jitterPercent int, random *lockedRand) *schedule {
+	return &schedule{
+		log:           log,
+		interval:      interval,
+		jitterPercent: jitterPercent,
+		random:        random,
+		after:         time.After,
+	}
+}
+
+// runScheduled runs job once immediately and then every interval (±jitterPercent)
+// until the context is canceled. Job errors are logged and do not stop the schedule.
+func runScheduled(ctx context.Context, log *zap.Logger, interval time.Duration, jitterPercent int, job func(context.Context) error) {
+	newSchedule(log, interval, jitterPercent, newLockedRand(time.Now().UnixNano())).run(ctx, job)
+}
+
+// run executes the schedule until the context is canceled.
+// Runs start on a fixed cadence; a tick that arrives while the previous run is
+// still going is skipped rather than overlapped. run returns once the context
+// is canceled and the in-flight run, if any, has finished.
+func (s *schedule) run(ctx context.Context, job func(context.Context) error) {
+	s.log.Info("Starting scheduled crawler runs", zap.Int("jitter_percent", s.jitterPercent))
+	defer s.wg.Wait()
+
+	// Run immediately on startup
+	s.start(ctx, job, "Initial crawler run failed")
+
+	// Then run on schedule
+	for {
+		delay := s.nextDelay()
+		s.log.Debug("Next crawler run scheduled", zap.Duration("delay", delay))
+
+		select {
+		case <-s.after(delay):
+			s.start(ctx, job, "Scheduled crawler run failed")
+		case <-ctx.Done():
+			s.log.Info("Stopping scheduled crawler runs")
+			return
+		}
+	}
+}
+
+// start launches job in the background unless a previous run is still in progress
+func (s *schedule) start(ctx context.Context, job func(context.Context) error, failMsg string) {
+	if !s.running.CompareAndSwap(false, true) {
+		s.log.Warn("Previous crawler run still in progress, skipping this run")
+		if s.onSkip != nil {
+			s.onSkip()
+		}
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.running.Store(false)
+
+		if err := job(ctx); err != nil {
+			s.log.Error(failMsg, zap.Error(err))
+		}
+	}()
+}
+
+// nextDelay returns the wait before the next run
+func (s *schedule) nextDelay() time.Duration {
+	return jitterDuration(s.interval, s.jitterPercent, s.random)
+}
+
+// jitterDuration returns d shifted by a uniformly random amount within ±percent% of d.
+// A non-positive percent returns d unchanged; percent is capped at 100.
+func jitterDuration(d time.Duration, percent int, random *lockedRand) time.Duration {
+	if percent <= 0 || d <= 0 {
+		return d
+	}
+	if percent > 100 {
+		percent = 100
+	}
+
+	spread := int64(d) * int64(percent) / 100
+	return d - time.Duration(spread) + time.Duration(random.Int63n(2*spread+1))
+}
+
+// staggerDelay returns a random delay in [0, max) used to spread sources within a run
+func staggerDelay(max time.Duration, random *lockedRand) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(random.Int63n(int64(max)))
+}
+
+// sleepContext waits for d or until the context is canceled, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lockedRand is a seeded random source that is safe for concurrent use
+type lockedRand struct {
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+// newLockedRand creates a lockedRand; the same seed yields the same sequence
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{random: rand.New(rand.NewSource(seed))}
+}
+
+// Int63n returns a random number in [0, n)
+func (r *lockedRand) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.random.Int63n(n)
+}
@@ -0,0 +1,259 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeTimer is one wait the schedule asked the fake clock for
+type fakeTimer struct {
+	delay time.Duration
+	fire  chan time.Time
+}
+
+// fakeClock hands the schedule's waits to the test, which fires them by hand
+type fakeClock struct {
+	timers chan fakeTimer
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{timers: make(chan fakeTimer)}
+}
+
+func (c *fakeClock) after(d time.Duration) <-chan time.Time {
+	timer := fakeTimer{delay: d, fire: make(chan time.Time, 1)}
+	c.timers <- timer
+	return timer.fire
+}
+
+// next waits for the schedule to start its next wait
+func (c *fakeClock) next(t *testing.T) fakeTimer {
+	t.Helper()
+	select {
+	case timer := <-c.timers:
+		return timer
+	case <-time.After(5 * time.Second):
+		t.Fatal("schedule never waited for the next run")
+		return fakeTimer{}
+	}
+}
+
+func newFakeSchedule(interval time.Duration) (*schedule, *fakeClock) {
+	clock := newFakeClock()
+	s := newSchedule(zap.NewNop(), interval, 0, newLockedRand(1))
+	s.after = clock.after
+	return s, clock
+}
+
+func TestScheduleRuns(t *testing.T) {
+	s, clock := newFakeSchedule(time.Hour)
+	runs := make(chan int, 10)
+	var count atomic.Int32
+	job := func(ctx context.Context) error {
+		n := int(count.Add(1))
+		runs <- n
+		if n == 2 {
+			return errors.New("crawl failed")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx, job)
+		close(done)
+	}()
+
+	waitRun := func(want int) {
+		t.Helper()
+		select {
+		case n := <-runs:
+			if n != want {
+				t.Fatalf("run %d, want %d", n, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d never started", want)
+		}
+	}
+
+	// The first run doesn't wait for the interval
+	waitRun(1)
+	timer := clock.next(t)
+	if timer.delay != time.Hour {
+		t.Errorf("waited %v, want the interval", timer.delay)
+	}
+
+	timer.fire <- time.Now()
+	waitRun(2)
+
+	// A failed run doesn't stop the schedule
+	clock.next(t).fire <- time.Now()
+	waitRun(3)
+
+	clock.next(t)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run() didn't return after the context was canceled")
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("job ran %d times, want 3", got)
+	}
+}
+
+func TestScheduleSkipsOverlappingRun(t *testing.T) {
+	s, clock := newFakeSchedule(time.Minute)
+	var skips atomic.Int32
+	s.onSkip = func() { skips.Add(1) }
+
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	var count atomic.Int32
+	job := func(ctx context.Context) error {
+		count.Add(1)
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx, job)
+		close(done)
+	}()
+
+	<-started
+	// The first run is still going when the tick fires
+	clock.next(t).fire <- time.Now()
+	clock.next(t)
+	if got := skips.Load(); got != 1 {
+		t.Errorf("skipped %d runs, want 1", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("run() returned before the in-flight run finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run() didn't return after the in-flight run finished")
+	}
+	if got := count.Load(); got != 1 {
+		t.Errorf("job ran %d times, want 1", got)
+	}
+}
+
+func TestJitterDuration(t *testing.T) {
+	interval := 10 * time.Minute
+
+	if got := jitterDuration(interval, 0, newLockedRand(1)); got != interval {
+		t.Errorf("jitterDuration() with no jitter = %v, want %v", got, interval)
+	}
+
+	first, second := newLockedRand(42), newLockedRand(42)
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := jitterDuration(interval, 20, first)
+		if got < 8*time.Minute || got > 12*time.Minute {
+			t.Fatalf("jitterDuration() = %v, want within ±20%% of %v", got, interval)
+		}
+		if again := jitterDuration(interval, 20, second); again != got {
+			t.Fatalf("jitterDuration() = %v and %v with the same seed", got, again)
+		}
+		if got != interval {
+			varied = true
+		}
+	}
+	if !varied {
+		t.Error("jitterDuration() never moved the interval")
+	}
+
+	for i := 0; i < 100; i++ {
+		if got := jitterDuration(interval, 500, first); got < 0 || got > 2*interval {
+			t.Fatalf("jitterDuration() with 500%% = %v, want it capped at ±100%%", got)
+		}
+	}
+}
+
+func TestScheduleWaitsJitteredInterval(t *testing.T) {
+	s, clock := newFakeSchedule(time.Hour)
+	s.jitterPercent = 10
+	s.random = newLockedRand(7)
+	want := newLockedRand(7)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx, func(ctx context.Context) error { return nil })
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		timer := clock.next(t)
+		if expected := jitterDuration(time.Hour, 10, want); timer.delay != expected {
+			t.Errorf("wait %d = %v, want %v from the seeded jitter", i, timer.delay, expected)
+		}
+		timer.fire <- time.Now()
+	}
+
+	cancel()
+	clock.next(t)
+	<-done
+}
+
+func TestStaggerDelay(t *testing.T) {
+	if got := staggerDelay(0, newLockedRand(1)); got != 0 {
+		t.Errorf("staggerDelay(0) = %v, want 0", got)
+	}
+	random := newLockedRand(1)
+	for i := 0; i < 100; i++ {
+		if got := staggerDelay(30*time.Second, random); got < 0 || got >= 30*time.Second {
+			t.Fatalf("staggerDelay() = %v, want within [0, 30s)", got)
+		}
+	}
+}
+
+func TestImprovedCrawlerCountsSkippedRuns(t *testing.T) {
+	c := &ImprovedCrawler{log: zap.NewNop()}
+	s, clock := newFakeSchedule(time.Minute)
+	s.onSkip = c.recordSkippedRun
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slowRun := func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.run(ctx, slowRun)
+		close(done)
+	}()
+
+	<-started
+	clock.next(t).fire <- time.Now()
+	clock.next(t).fire <- time.Now()
+	clock.next(t)
+	if got := c.GetStats().SkippedRuns; got != 2 {
+		t.Errorf("SkippedRuns = %d, want 2", got)
+	}
+
+	cancel()
+	close(release)
+	<-done
+}
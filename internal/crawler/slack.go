@@ -0,0 +1,368 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.uber.org/zap"
+)
+
+// slackSectionTextLimit is Slack's maximum length of a section or field text
+const slackSectionTextLimit = 2000
+
+// SlackNotifier posts deal notifications to Slack incoming webhooks as Block Kit messages.
+// Keyword alerts are routed by their channel ID through SlackChannelWebhooks;
+// alerts without a route go to SlackWebhookURL.
+type SlackNotifier struct {
+	config       *config.Config
+	db           *storage.MongoDB
+	alertMatcher *AlertMatcher
+	client       *http.Client
+	rateLimiter  *time.Ticker // Slack 웹훅은 초당 1건 정도만 허용
+	log          *zap.Logger
+
+	// notified is the notified product filter, as in NotificationService
+	notified *notifiedFilter
+}
+
+// NewSlackNotifier creates a new Slack notifier
+func NewSlackNotifier(cfg *config.Config, db *storage.MongoDB, log *zap.Logger) *SlackNotifier {
+	alertMatcher := NewAlertMatcher(db, log)
+	alertMatcher.SetDefaultCooldown(time.Duration(cfg.AlertCooldownMinutes) * time.Minute)
+	if location, err := time.LoadLocation(cfg.NotificationTimezone); err == nil {
+		alertMatcher.SetLocation(location)
+	}
+
+	loadCtx, cancel := db.OperationContext(context.Background())
+	notified, err := loadNotifiedFilter(loadCtx, db)
+	cancel()
+	if err != nil {
+		log.Warn("Failed to load notified product filter, checking every product in the database", zap.Error(err))
+	}
+
+	return &SlackNotifier{
+		config:       cfg,
+		db:           db,
+		alertMatcher: alertMatcher,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		rateLimiter: time.NewTicker(time.Second),
+		log:         log.Named("slack-notifier"),
+		notified:    notified,
+	}
+}
+
+// NotifyNewProducts posts each product matching at least one alert to the
+// Slack webhooks its alerts are routed to, then marks it notified
+func (s *SlackNotifier) NotifyNewProducts(ctx context.Context, products []models.Product) error {
+	return s.notifyProducts(ctx, products, false)
+}
+
+// NotifyChangedProducts posts stored products whose price or discount changed
+// like NotifyNewProducts, including products that were already notified
+func (s *SlackNotifier) NotifyChangedProducts(ctx context.Context, products []models.Product) error {
+	return s.notifyProducts(ctx, products, true)
+}
+
+// notifyProducts posts products, skipping those already notified unless
+// renotify is set
+func (s *SlackNotifier) notifyProducts(ctx context.Context, products []models.Product, renotify bool) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	index, err := s.alertMatcher.BuildIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to build keyword index: %w", err)
+	}
+
+	// Pick up the products other processes marked since the last run
+	if s.notified != nil && !renotify {
+		if err := s.notified.refresh(ctx, s.db); err != nil {
+			s.log.Warn("Failed to refresh notified product filter, checking every product in the database", zap.Error(err))
+		}
+	}
+
+	var notificationErrors []error
+	seenURLs := make(map[string]bool)
+	for _, product := range products {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if seenURLs[product.URL] {
+			continue
+		}
+		seenURLs[product.URL] = true
+
+		if !renotify && !s.notified.skip(product.URL) {
+			notified, err := productNotified(ctx, s.db, product.URL)
+			if err != nil {
+				s.log.Error("Failed to check if product was notified", zap.Error(err), zap.String("url", product.URL))
+			} else if notified {
+				continue
+			}
+		}
+
+		alerts := s.alertMatcher.MatchProduct(ctx, index, product)
+		if len(alerts) == 0 {
+			continue
+		}
+
+		if err := s.sendProductNotifications(ctx, product, alerts); err != nil {
+			notificationErrors = append(notificationErrors, err)
+		}
+	}
+
+	if len(notificationErrors) > 0 {
+		return fmt.Errorf("some notifications failed: %v", notificationErrors)
+	}
+	return nil
+}
+
+// SendBestDeals posts the best deal alerts the best product they matched over
+// the whole crawl run. If ctx is already canceled the candidates are dropped.
+func (s *SlackNotifier) SendBestDeals(ctx context.Context) error {
+	var notificationErrors []error
+	for _, deal := range s.alertMatcher.TakeBestDeals() {
+		if ctx.Err() != nil {
+			break
+		}
+		s.alertMatcher.RecordBestDeal(ctx, deal, time.Now())
+		if err := s.sendProductNotifications(ctx, deal.Product, deal.Alerts); err != nil {
+			notificationErrors = append(notificationErrors, err)
+		}
+	}
+
+	if len(notificationErrors) > 0 {
+		return fmt.Errorf("some best deal notifications failed: %v", notificationErrors)
+	}
+	return nil
+}
+
+// PreviewNotifications returns the products that would trigger at least one
+// alert, without sending anything
+func (s *SlackNotifier) PreviewNotifications(ctx context.Context, products []models.Product) ([]models.Product, error) {
+	if len(products) == 0 {
+		return nil, nil
+	}
+
+	index, err := s.alertMatcher.BuildIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build keyword index: %w", err)
+	}
+
+	var matched []models.Product
+	for _, product := range products {
+		if len(filterByMaxPrice(index.Match(product.SearchText()), &product)) > 0 {
+			matched = append(matched, product)
+		}
+	}
+	return matched, nil
+}
+
+// sendProductNotifications posts one message per webhook the product's alerts route to
+func (s *SlackNotifier) sendProductNotifications(ctx context.Context, product models.Product, alerts []models.KeywordAlert) error {
+	// Only alerts whose webhook accepted the message keep their cooldown
+	delivered := make(map[string]bool)
+	defer s.alertMatcher.SettleCooldowns(ctx, alerts, delivered)
+
+	var webhooks []string
+	alertsByWebhook := make(map[string][]models.KeywordAlert)
+	for _, alert := range alerts {
+		webhook := s.webhookFor(alert.ChannelID)
+		if webhook == "" {
+			s.log.Warn("No Slack webhook for alert channel",
+				zap.String("alert_id", alert.ID),
+				zap.String("channel_id", alert.ChannelID))
+			continue
+		}
+		if _, ok := alertsByWebhook[webhook]; !ok {
+			webhooks = append(webhooks, webhook)
+		}
+		alertsByWebhook[webhook] = append(alertsByWebhook[webhook], alert)
+	}
+
+	sent := 0
+	var errs []error
+	for _, webhook := range webhooks {
+		select {
+		case <-s.rateLimiter.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		matched := alertsByWebhook[webhook]
+		payload := buildSlackPayload(product, matched, embeds.MessagesFor(notificationLanguage(s.config, matched)))
+		if err := s.post(ctx, webhook, payload); err != nil {
+			s.log.Error("Failed to send Slack message", zap.Error(err), zap.String("product", product.Title))
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+		for _, alert := range matched {
+			delivered[alert.ID] = true
+		}
+	}
+
+	if sent > 0 {
+		if err := markNotified(ctx, s.db, s.log, s.notified, product); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d of %d Slack notifications failed: %v", len(errs), len(webhooks), errs)
+	}
+	return nil
+}
+
+// webhookFor returns the Slack webhook URL an alert channel is routed to
+func (s *SlackNotifier) webhookFor(channelID string) string {
+	if url, ok := s.config.SlackChannelWebhooks[channelID]; ok {
+		return url
+	}
+	return s.config.SlackWebhookURL
+}
+
+// post sends a payload to a Slack incoming webhook
+func (s *SlackNotifier) post(ctx context.Context, webhook string, payload slackPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Slack webhook: %w", config.RedactError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Slack webhook returned status %d: %s", resp.StatusCode, detail)
+	}
+	return nil
+}
+
+// Close stops the rate limiter
+func (s *SlackNotifier) Close() {
+	s.rateLimiter.Stop()
+}
+
+// slackPayload is the body of a Slack incoming webhook request
+type slackPayload struct {
+	Text   string       `json:"text"` // 알림 미리보기에 쓰이는 대체 텍스트
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackBlock is a Block Kit section or context block
+type slackBlock struct {
+	Type      string      `json:"type"`
+	Text      *slackText  `json:"text,omitempty"`
+	Fields    []slackText `json:"fields,omitempty"`
+	Accessory *slackImage `json:"accessory,omitempty"`
+	Elements  []slackText `json:"elements,omitempty"`
+}
+
+// slackText is a Block Kit text object
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackImage is a Block Kit image element
+type slackImage struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// buildSlackPayload renders a product notification as a Block Kit message with
+// the same fields as the Discord embed. Discord mentions have no Slack
+// equivalent, so matched users are not mentioned.
+func buildSlackPayload(product models.Product, alerts []models.KeywordAlert, msgs embeds.Messages) slackPayload {
+	// Collapse whitespace only; Discord escaping would show up literally in Slack
+	plainTitle := strings.Join(strings.Fields(product.Title), " ")
+	title := slackEscape(plainTitle)
+	if product.IsHot {
+		title = ":fire: " + title
+	}
+
+	header := slackBlock{
+		Type: "section",
+		Text: &slackText{
+			Type: "mrkdwn",
+			Text: embeds.Truncate(fmt.Sprintf("*<%s|%s>*\n%s", slackEscape(product.URL), title, msgs.NewDeal), slackSectionTextLimit),
+		},
+	}
+	if product.ImageURL != "" {
+		header.Accessory = &slackImage{
+			Type:     "image",
+			ImageURL: product.ImageURL,
+			AltText:  embeds.Truncate(plainTitle, slackSectionTextLimit),
+		}
+	}
+
+	fields := []slackText{slackField(msgs.Source, slackEscape(product.Source))}
+	if priceStr := product.GetPriceString(); priceStr != "Price unknown" {
+		fields = append(fields, slackField(msgs.Price, priceStr))
+	}
+	if product.DiscountRate > 0 {
+		fields = append(fields, slackField(msgs.Discount, fmt.Sprintf("%d%%", product.DiscountRate)))
+	}
+	if product.Comments > 0 || product.Views > 0 {
+		fields = append(fields, slackField(msgs.Stats, fmt.Sprintf(msgs.StatsFormat, product.Comments, product.Views)))
+	}
+
+	// Collect unique keywords that matched, in alert order
+	var keywordList []string
+	keywords := make(map[string]bool)
+	for _, alert := range alerts {
+		if !keywords[alert.Keyword] {
+			keywords[alert.Keyword] = true
+			keywordList = append(keywordList, slackEscape(alert.Keyword))
+		}
+	}
+	fields = append(fields, slackField(msgs.Keywords, embeds.JoinWithLimit(keywordList, ", ", slackSectionTextLimit/2)))
+
+	return slackPayload{
+		Text: fmt.Sprintf("%s %s", msgs.NewDeal, slackEscape(plainTitle)),
+		Blocks: []slackBlock{
+			header,
+			{Type: "section", Fields: fields},
+			{
+				Type: "context",
+				Elements: []slackText{{
+					Type: "mrkdwn",
+					Text: fmt.Sprintf(msgs.FooterFormat, product.CrawledAt.Format("2006-01-02 15:04:05")),
+				}},
+			},
+		},
+	}
+}
+
+// slackField formats a bold-labeled section field
+func slackField(name, value string) slackText {
+	return slackText{Type: "mrkdwn", Text: "*" + name + "*\n" + value}
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in mrkdwn
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
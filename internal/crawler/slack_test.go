@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/embeds"
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+func TestBuildSlackPayload(t *testing.T) {
+	product := models.Product{
+		Title:        "LG <27인치> 모니터 & 스탠드",
+		URL:          "https://example.com/deal/1",
+		Source:       "ppomppu",
+		KOPrice:      300000,
+		DiscountRate: 25,
+		Comments:     12,
+		Views:        340,
+		ImageURL:     "https://example.com/image.jpg",
+		IsHot:        true,
+		CrawledAt:    time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+	}
+	alerts := []models.KeywordAlert{
+		{Keyword: "모니터", UserID: "user-1"},
+		{Keyword: "모니터", UserID: "user-2"},
+		{Keyword: "LG", UserID: "user-1"},
+	}
+
+	payload := buildSlackPayload(product, alerts, embeds.MessagesFor(models.LanguageKorean))
+
+	want := `{
+		"text": "새로운 특가 상품을 발견했습니다! LG &lt;27인치&gt; 모니터 &amp; 스탠드",
+		"blocks": [
+			{
+				"type": "section",
+				"text": {"type": "mrkdwn", "text": "*<https://example.com/deal/1|:fire: LG &lt;27인치&gt; 모니터 &amp; 스탠드>*\n새로운 특가 상품을 발견했습니다!"},
+				"accessory": {"type": "image", "image_url": "https://example.com/image.jpg", "alt_text": "LG <27인치> 모니터 & 스탠드"}
+			},
+			{
+				"type": "section",
+				"fields": [
+					{"type": "mrkdwn", "text": "*출처*\nppomppu"},
+					{"type": "mrkdwn", "text": "*가격*\n300,000 KRW"},
+					{"type": "mrkdwn", "text": "*할인율*\n25%"},
+					{"type": "mrkdwn", "text": "*반응*\n댓글: 12 | 조회수: 340"},
+					{"type": "mrkdwn", "text": "*일치한 키워드*\n모니터, LG"}
+				]
+			},
+			{
+				"type": "context",
+				"elements": [{"type": "mrkdwn", "text": "수집 시각: 2026-10-16 09:30:00"}]
+			}
+		]
+	}`
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	// Compare decoded values, since Marshal escapes <, > and & in strings
+	var got, expected interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected payload is invalid JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		indented, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("payload =\n%s\nwant\n%s", indented, want)
+	}
+}
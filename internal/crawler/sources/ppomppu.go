@@ -9,25 +9,39 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/bradykim7/gbot/internal/crawler"
+	"github.com/bradykim7/gbot/internal/crawler/fetch"
 	"github.com/bradykim7/gbot/internal/models"
 	"go.uber.org/zap"
 )
 
+// ppomppuLocation is the timezone Ppomppu shows post dates in
+var ppomppuLocation = time.FixedZone("KST", 9*60*60)
+
 const (
 	ppomppuBaseURL     = "https://www.ppomppu.co.kr/zboard/zboard.php?id=ppomppu"
 	ppomppuItemURLBase = "https://www.ppomppu.co.kr/zboard/"
 )
 
+// ppomppuURLPolicy keeps only the zboard params that identify a post
+// (board id and post number); paging and list state params are dropped
+var ppomppuURLPolicy = models.URLParamPolicy{Keep: []string{"id", "no"}}
+
+// ppomppuHotIconMarkers are substrings of the icon images Ppomppu puts next
+// to popular and hot posts
+var ppomppuHotIconMarkers = []string{"hot", "pop"}
+
+// ppomppuEndIconMarker is a substring of the icon Ppomppu puts next to ended deals
+const ppomppuEndIconMarker = "end_icon"
+
 // PpomppuCrawler is a crawler for Ppomppu website
 type PpomppuCrawler struct {
-	*crawler.BaseCrawler
+	*fetch.BaseCrawler
 }
 
 // NewPpomppuCrawler creates a new Ppomppu crawler
 func NewPpomppuCrawler(log *zap.Logger) *PpomppuCrawler {
 	return &PpomppuCrawler{
-		BaseCrawler: crawler.NewBaseCrawler(log.Named("ppomppu-crawler")),
+		BaseCrawler: fetch.NewBaseCrawler(log.Named("ppomppu-crawler")),
 	}
 }
 
@@ -39,16 +53,10 @@ func (c *PpomppuCrawler) Name() string {
 // Crawl fetches and parses deals from Ppomppu
 func (c *PpomppuCrawler) Crawl(ctx context.Context) ([]models.Product, error) {
 	c.Logger.Info("Starting Ppomppu crawl")
-	
-	content, err := c.FetchURL(ctx, ppomppuBaseURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch Ppomppu: %w", err)
-	}
 
-	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(content)))
+	doc, err := c.FetchDocument(ctx, ppomppuBaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, fmt.Errorf("failed to fetch Ppomppu: %w", err)
 	}
 
 	var products []models.Product
@@ -75,7 +83,7 @@ func (c *PpomppuCrawler) parseProduct(s *goquery.Selection) (*models.Product, er
 	if titleEl.Length() == 0 {
 		return nil, fmt.Errorf("title element not found")
 	}
-	
+
 	title := strings.TrimSpace(titleEl.Text())
 	if title == "" {
 		return nil, fmt.Errorf("empty title")
@@ -86,11 +94,18 @@ func (c *PpomppuCrawler) parseProduct(s *goquery.Selection) (*models.Product, er
 	if !exists {
 		return nil, fmt.Errorf("URL not found")
 	}
-	
+
 	// Correct relative URL
-	url := urlPath
+	rawURL := urlPath
 	if !strings.HasPrefix(urlPath, "http") {
-		url = ppomppuItemURLBase + strings.TrimPrefix(urlPath, "./")
+		rawURL = ppomppuItemURLBase + strings.TrimPrefix(urlPath, "./")
+	}
+
+	// Canonicalize so the same post always dedups to one URL
+	url := models.CanonicalizeURLWith(rawURL, ppomppuURLPolicy)
+	originalURL := ""
+	if url != rawURL {
+		originalURL = rawURL
 	}
 
 	// Extract price with regex
@@ -98,7 +113,7 @@ func (c *PpomppuCrawler) parseProduct(s *goquery.Selection) (*models.Product, er
 	priceMatches := priceRegex.FindStringSubmatch(title)
 	var price int
 	var priceStr string
-	
+
 	if len(priceMatches) > 0 {
 		priceStr = priceMatches[0]
 		// Clean up price string and convert to integer
@@ -116,24 +131,84 @@ func (c *PpomppuCrawler) parseProduct(s *goquery.Selection) (*models.Product, er
 	viewsStr := strings.TrimSpace(s.Find("td").Eq(5).Text())
 	views, _ := strconv.Atoi(viewsStr)
 
-	// Get date
+	// Get date ("12:34:56" for today's posts, "25/05/01" for older ones)
 	dateStr := strings.TrimSpace(s.Find("td").Eq(4).Text())
-	
+
 	now := time.Now()
-	
+	uploadDate := now.Unix()
+	if parsed, err := models.ParseUploadDate(dateStr, now.In(ppomppuLocation)); err == nil {
+		uploadDate = parsed.Unix()
+	} else {
+		c.Logger.Debug("Failed to parse upload date", zap.String("date", dateStr), zap.Error(err))
+	}
+
 	return &models.Product{
-		Title:        title,
-		URL:          url,
-		KOPrice:      price,
-		PriceString:  priceStr + "원",
-		UploadDate:   now.Unix(),
-		UploadSite:   "Ppomppu",
-		Product:      title,
-		Website:      "Ppomppu",
-		Source:       "Ppomppu",
-		Comments:     comments,
-		Views:        views,
-		CrawledAt:    now,
-		Category:     "Deal",
+		Title:       title,
+		URL:         url,
+		OriginalURL: originalURL,
+		KOPrice:     price,
+		PriceString: priceStr + "원",
+		UploadDate:  uploadDate,
+		UploadSite:  "Ppomppu",
+		Product:     title,
+		Website:     "Ppomppu",
+		Source:      "Ppomppu",
+		Comments:    comments,
+		Views:       views,
+		CrawledAt:   now,
+		Category:    "Deal",
+		IsHot:       isPpomppuHot(s, titleEl),
+		SoldOut:     isPpomppuEnded(s, titleEl),
 	}, nil
-}
\ No newline at end of file
+}
+
+// isPpomppuEnded reports whether the row is marked as an ended deal: the end
+// icon, or a struck-through title
+func isPpomppuEnded(s, titleEl *goquery.Selection) bool {
+	ended := false
+	s.Find("img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		src, _ := img.Attr("src")
+		ended = strings.Contains(strings.ToLower(src), ppomppuEndIconMarker)
+		return !ended
+	})
+	if ended {
+		return true
+	}
+
+	if titleEl.Find("s, strike, del").Length() > 0 || titleEl.ParentsFiltered("s, strike, del").Length() > 0 {
+		return true
+	}
+	style, _ := titleEl.Attr("style")
+	return strings.Contains(strings.ToLower(strings.ReplaceAll(style, " ", "")), "line-through")
+}
+
+// isPpomppuHot reports whether the row carries Ppomppu's own hot markup:
+// a popular/hot icon, or a bolded or red-colored title
+func isPpomppuHot(s, titleEl *goquery.Selection) bool {
+	hot := false
+	s.Find("img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		src, _ := img.Attr("src")
+		src = strings.ToLower(src)
+		for _, marker := range ppomppuHotIconMarkers {
+			if strings.Contains(src, marker) {
+				hot = true
+				return false
+			}
+		}
+		return true
+	})
+	if hot {
+		return true
+	}
+
+	if titleEl.Find("b, strong").Length() > 0 || titleEl.ParentsFiltered("b, strong").Length() > 0 {
+		return true
+	}
+
+	color, _ := titleEl.Attr("color")
+	style, _ := titleEl.Attr("style")
+	color = strings.ToLower(color)
+	style = strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	return color == "red" || color == "#ff0000" ||
+		strings.Contains(style, "color:red") || strings.Contains(style, "color:#ff0000")
+}
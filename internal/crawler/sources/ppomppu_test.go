@@ -0,0 +1,92 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+)
+
+// ppomppuRow parses a single list row of the Ppomppu board
+func ppomppuRow(t *testing.T, cells string) *goquery.Selection {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table><tr>" + cells + "</tr></table>"))
+	if err != nil {
+		t.Fatalf("failed to parse row: %v", err)
+	}
+	return doc.Find("tr").First()
+}
+
+func TestPpomppuProductURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		href     string
+		want     string
+		original string
+	}{
+		{
+			name:     "relative link drops list state and trackers",
+			href:     "view.php?id=ppomppu&page=2&divpage=90&no=123&utm_source=x",
+			want:     "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+			original: "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&page=2&divpage=90&no=123&utm_source=x",
+		},
+		{
+			name:     "dot relative link",
+			href:     "./view.php?no=123&id=ppomppu&fbclid=abc",
+			want:     "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+			original: "https://www.ppomppu.co.kr/zboard/view.php?no=123&id=ppomppu&fbclid=abc",
+		},
+		{
+			name: "canonical link is kept as is",
+			href: "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+			want: "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+		},
+	}
+
+	c := NewPpomppuCrawler(zap.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := ppomppuRow(t, `<td><a href="`+tt.href+`"><font class="list_title">[11번가] 모니터 199,000원</font></a></td>`)
+			product, err := c.parseProduct(row)
+			if err != nil {
+				t.Fatalf("parseProduct() error = %v", err)
+			}
+			if product.URL != tt.want {
+				t.Errorf("URL = %q, want %q", product.URL, tt.want)
+			}
+			if product.OriginalURL != tt.original {
+				t.Errorf("OriginalURL = %q, want %q", product.OriginalURL, tt.original)
+			}
+		})
+	}
+}
+
+func TestPpomppuHotMarker(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		icon  string
+		want  bool
+	}{
+		{name: "normal row", title: `<font class="list_title">모니터 199,000원</font>`},
+		{name: "hot icon", title: `<font class="list_title">모니터 199,000원</font>`, icon: `<img src="/images/menu/hot_icon2.jpg">`, want: true},
+		{name: "popular icon", title: `<font class="list_title">모니터 199,000원</font>`, icon: `<img src="/images/menu/pop_icon2.jpg">`, want: true},
+		{name: "bold title", title: `<b><font class="list_title">모니터 199,000원</font></b>`, want: true},
+		{name: "red title", title: `<font class="list_title" color="red">모니터 199,000원</font>`, want: true},
+	}
+
+	c := NewPpomppuCrawler(zap.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := ppomppuRow(t, `<td>`+tt.icon+`<a href="view.php?id=ppomppu&no=1">`+tt.title+`</a></td>`)
+			product, err := c.parseProduct(row)
+			if err != nil {
+				t.Fatalf("parseProduct() error = %v", err)
+			}
+			if product.IsHot != tt.want {
+				t.Errorf("IsHot = %v, want %v", product.IsHot, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/crawler/fetch"
+	"github.com/bradykim7/gbot/internal/models"
+	"go.uber.org/zap"
+)
+
+// rssDateLayouts are the date formats seen in RSS pubDate and Atom
+// published/updated elements, tried in order
+var rssDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+// RSSSource reads deals from an RSS 2.0 or Atom feed, so a site with a feed
+// can be added as a source through configuration alone
+type RSSSource struct {
+	*fetch.BaseCrawler
+	name    string
+	feedURL string
+}
+
+// NewRSSSource creates a source named name that reads the feed at feedURL
+func NewRSSSource(name, feedURL string, log *zap.Logger) *RSSSource {
+	return &RSSSource{
+		BaseCrawler: fetch.NewBaseCrawler(log.Named("rss-crawler").With(zap.String("source", name))),
+		name:        name,
+		feedURL:     feedURL,
+	}
+}
+
+// Name returns the name of the source
+func (r *RSSSource) Name() string {
+	return r.name
+}
+
+// Crawl fetches the feed and maps its entries to products
+func (r *RSSSource) Crawl(ctx context.Context) ([]models.Product, error) {
+	content, err := r.FetchURL(ctx, r.feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s feed: %w", r.name, err)
+	}
+
+	entries, err := parseFeed(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s feed: %w", r.name, err)
+	}
+
+	now := time.Now()
+	products := make([]models.Product, 0, len(entries))
+	for _, entry := range entries {
+		if product, ok := r.entryProduct(entry, now); ok {
+			products = append(products, product)
+		}
+	}
+
+	r.Logger.Info("Parsed feed", zap.Int("entries", len(entries)), zap.Int("products", len(products)))
+	return products, nil
+}
+
+// entryProduct maps a feed entry to a product. Entries without a title or
+// link are skipped, and entries without a usable date get the crawl time.
+func (r *RSSSource) entryProduct(entry feedEntry, now time.Time) (models.Product, bool) {
+	title := strings.TrimSpace(entry.Title)
+	rawURL := strings.TrimSpace(entry.Link)
+	if title == "" || rawURL == "" {
+		return models.Product{}, false
+	}
+
+	url := models.CanonicalizeURL(rawURL)
+	originalURL := ""
+	if url != rawURL {
+		originalURL = rawURL
+	}
+
+	uploadDate := now.Unix()
+	if published, err := parseFeedDate(entry.Published); err == nil {
+		uploadDate = published.Unix()
+	} else if entry.Published != "" {
+		r.Logger.Debug("Failed to parse feed date", zap.String("date", entry.Published), zap.Error(err))
+	}
+
+	return models.Product{
+		Title:       title,
+		URL:         url,
+		OriginalURL: originalURL,
+		UploadDate:  uploadDate,
+		UploadSite:  r.name,
+		Product:     title,
+		Website:     r.name,
+		Source:      r.name,
+		CrawledAt:   now,
+		Category:    "Deal",
+	}, true
+}
+
+// feedEntry is an RSS item or Atom entry reduced to what products need
+type feedEntry struct {
+	Title     string
+	Link      string
+	Published string
+}
+
+// rssFeed is the subset of an RSS 2.0 document that products are built from
+type rssFeed struct {
+	Items []struct {
+		Title   string `xml:"title"`
+		Link    string `xml:"link"`
+		GUID    string `xml:"guid"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// atomFeed is the subset of an Atom document that products are built from
+type atomFeed struct {
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// parseFeed parses an RSS 2.0 or Atom document, telling them apart by the root element
+func parseFeed(content []byte) ([]feedEntry, error) {
+	root, err := feedRoot(content)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss":
+		var feed rssFeed
+		if err := decodeFeed(content, &feed); err != nil {
+			return nil, err
+		}
+		entries := make([]feedEntry, 0, len(feed.Items))
+		for _, item := range feed.Items {
+			link := item.Link
+			if strings.TrimSpace(link) == "" {
+				link = item.GUID // permalink GUIDs are often the only link
+			}
+			entries = append(entries, feedEntry{Title: item.Title, Link: link, Published: item.PubDate})
+		}
+		return entries, nil
+
+	case "feed":
+		var feed atomFeed
+		if err := decodeFeed(content, &feed); err != nil {
+			return nil, err
+		}
+		entries := make([]feedEntry, 0, len(feed.Entries))
+		for _, entry := range feed.Entries {
+			var link string
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			published := entry.Published
+			if published == "" {
+				published = entry.Updated
+			}
+			entries = append(entries, feedEntry{Title: entry.Title, Link: link, Published: published})
+		}
+		return entries, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported feed root element <%s>", root)
+	}
+}
+
+// feedRoot returns the local name of the document's root element
+func feedRoot(content []byte) (string, error) {
+	decoder := newFeedDecoder(content)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("feed has no root element")
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read feed: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// decodeFeed decodes the whole document into v
+func decodeFeed(content []byte, v interface{}) error {
+	if err := newFeedDecoder(content).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode feed: %w", err)
+	}
+	return nil
+}
+
+// newFeedDecoder creates a lenient XML decoder. FetchURL has already
+// converted the feed to UTF-8, so a declared encoding is not applied again.
+func newFeedDecoder(content []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	return decoder
+}
+
+// parseFeedDate parses a feed date in any of rssDateLayouts
+func parseFeedDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range rssDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized feed date %q", s)
+}
@@ -0,0 +1,144 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRSSEntryProductURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		want     string
+		original string
+	}{
+		{
+			name:     "trackers are removed",
+			link:     "https://example.com/deal?id=42&utm_source=rss&utm_medium=feed",
+			want:     "https://example.com/deal?id=42",
+			original: "https://example.com/deal?id=42&utm_source=rss&utm_medium=feed",
+		},
+		{
+			name: "other params survive",
+			link: "https://example.com/deal?id=42&page=2",
+			want: "https://example.com/deal?id=42&page=2",
+		},
+	}
+
+	source := NewRSSSource("Feed", "https://example.com/rss", zap.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, ok := source.entryProduct(feedEntry{Title: "모니터 199,000원", Link: tt.link}, time.Now())
+			if !ok {
+				t.Fatal("entryProduct() skipped the entry")
+			}
+			if product.URL != tt.want {
+				t.Errorf("URL = %q, want %q", product.URL, tt.want)
+			}
+			if product.OriginalURL != tt.original {
+				t.Errorf("OriginalURL = %q, want %q", product.OriginalURL, tt.original)
+			}
+		})
+	}
+}
+
+const sampleRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>특가 게시판</title>
+    <item>
+      <title>[11번가] 삼성 27인치 모니터 199,000원</title>
+      <link>https://deals.example.com/view/101</link>
+      <pubDate>Thu, 16 Oct 2026 09:30:00 +0900</pubDate>
+    </item>
+    <item>
+      <title>로지텍 무선 마우스 &amp; 키보드 세트</title>
+      <guid isPermaLink="true">https://deals.example.com/view/102</guid>
+      <pubDate>not a date</pubDate>
+    </item>
+    <item>
+      <title></title>
+      <link>https://deals.example.com/view/103</link>
+    </item>
+  </channel>
+</rss>`
+
+const sampleAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Deals</title>
+  <entry>
+    <title>RTX 4090 그래픽카드</title>
+    <link rel="replies" href="https://deals.example.com/view/201#comments"/>
+    <link rel="alternate" href="https://deals.example.com/view/201"/>
+    <updated>2026-10-16T00:30:00Z</updated>
+  </entry>
+</feed>`
+
+func TestRSSSourceCrawl(t *testing.T) {
+	tests := []struct {
+		name   string
+		feed   string
+		titles []string
+		urls   []string
+		dates  []time.Time
+	}{
+		{
+			name:   "rss",
+			feed:   sampleRSSFeed,
+			titles: []string{"[11번가] 삼성 27인치 모니터 199,000원", "로지텍 무선 마우스 & 키보드 세트"},
+			urls:   []string{"https://deals.example.com/view/101", "https://deals.example.com/view/102"},
+			dates:  []time.Time{time.Date(2026, time.October, 16, 0, 30, 0, 0, time.UTC), {}},
+		},
+		{
+			name:   "atom",
+			feed:   sampleAtomFeed,
+			titles: []string{"RTX 4090 그래픽카드"},
+			urls:   []string{"https://deals.example.com/view/201"},
+			dates:  []time.Time{time.Date(2026, time.October, 16, 0, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/xml")
+				w.Write([]byte(tt.feed))
+			}))
+			defer server.Close()
+
+			before := time.Now()
+			products, err := NewRSSSource("Feed", server.URL, zap.NewNop()).Crawl(context.Background())
+			if err != nil {
+				t.Fatalf("Crawl() error = %v", err)
+			}
+			if len(products) != len(tt.titles) {
+				t.Fatalf("Crawl() returned %d products, want %d", len(products), len(tt.titles))
+			}
+			for i, product := range products {
+				if product.Title != tt.titles[i] || product.URL != tt.urls[i] {
+					t.Errorf("product %d = %q %s, want %q %s", i, product.Title, product.URL, tt.titles[i], tt.urls[i])
+				}
+				if product.Source != "Feed" {
+					t.Errorf("product %d source = %q, want Feed", i, product.Source)
+				}
+				// Entries without a usable date get the crawl time
+				if want := tt.dates[i]; !want.IsZero() && product.UploadDate != want.Unix() {
+					t.Errorf("product %d uploaded %v, want %v", i, time.Unix(product.UploadDate, 0).UTC(), want)
+				} else if want.IsZero() && product.UploadDate < before.Unix() {
+					t.Errorf("undated product %d uploaded %v, want the crawl time", i, time.Unix(product.UploadDate, 0))
+				}
+			}
+		})
+	}
+}
+
+func TestParseFeedRejectsUnknownRoot(t *testing.T) {
+	if _, err := parseFeed([]byte("<html><body>not a feed</body></html>")); err == nil {
+		t.Error("parseFeed() accepted an HTML page")
+	}
+}
@@ -0,0 +1,200 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/bradykim7/gbot/internal/crawler/fetch"
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.uber.org/zap"
+)
+
+// defaultPriceRegex matches won prices such as "12,900원" in a title
+var defaultPriceRegex = regexp.MustCompile(`(\d{1,3}(,\d{3})*원|\d+원)`)
+
+// nonDigits matches everything that isn't part of a number
+var nonDigits = regexp.MustCompile(`\D`)
+
+// SelectorSource scrapes a list page with CSS selectors from configuration,
+// so a site can be added without writing a parser
+type SelectorSource struct {
+	*fetch.BaseCrawler
+	name       string
+	baseURL    *url.URL
+	row        cascadia.Selector
+	title      cascadia.Selector
+	link       cascadia.Selector
+	price      cascadia.Selector // nil이면 제목에서 가격 추출
+	comments   cascadia.Selector // nil이면 댓글 수 0
+	priceRegex *regexp.Regexp
+}
+
+// NewSelectorSource creates a source from cfg. It fails if the URL, a
+// selector or the price regex is invalid, so a broken config is caught at
+// startup instead of silently yielding no products.
+func NewSelectorSource(cfg config.SelectorSource, log *zap.Logger) (*SelectorSource, error) {
+	baseURL, err := url.Parse(cfg.URL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q for selector source %s", cfg.URL, cfg.Name)
+	}
+
+	source := &SelectorSource{
+		BaseCrawler: fetch.NewBaseCrawler(log.Named("selector-crawler").With(zap.String("source", cfg.Name))),
+		name:        cfg.Name,
+		baseURL:     baseURL,
+		priceRegex:  defaultPriceRegex,
+	}
+
+	link := cfg.Link
+	if link == "" {
+		link = "a"
+	}
+
+	selectors := []struct {
+		field    string
+		selector string
+		dst      *cascadia.Selector
+	}{
+		{"row", cfg.Row, &source.row},
+		{"title", cfg.Title, &source.title},
+		{"link", link, &source.link},
+		{"price", cfg.Price, &source.price},
+		{"comments", cfg.Comments, &source.comments},
+	}
+	for _, s := range selectors {
+		if s.selector == "" {
+			continue
+		}
+		sel, err := cascadia.Compile(s.selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s selector %q for selector source %s: %w", s.field, s.selector, cfg.Name, err)
+		}
+		*s.dst = sel
+	}
+	if source.row == nil || source.title == nil {
+		return nil, fmt.Errorf("selector source %s requires row and title selectors", cfg.Name)
+	}
+
+	if cfg.PriceRegex != "" {
+		source.priceRegex, err = regexp.Compile(cfg.PriceRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price regex for selector source %s: %w", cfg.Name, err)
+		}
+	}
+
+	return source, nil
+}
+
+// Name returns the name of the source
+func (c *SelectorSource) Name() string {
+	return c.name
+}
+
+// Crawl fetches the list page and builds a product from each row
+func (c *SelectorSource) Crawl(ctx context.Context) ([]models.Product, error) {
+	doc, err := c.FetchDocument(ctx, c.baseURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", c.name, err)
+	}
+
+	products := c.parseDocument(doc, time.Now())
+
+	c.Logger.Info("Selector crawl completed", zap.Int("products_found", len(products)))
+	return products, nil
+}
+
+// parseDocument builds products from the rows of doc. Rows without a title
+// or link (ads, notices, separators) are skipped.
+func (c *SelectorSource) parseDocument(doc *goquery.Document, now time.Time) []models.Product {
+	var products []models.Product
+	doc.FindMatcher(c.row).Each(func(_ int, row *goquery.Selection) {
+		product, err := c.parseRow(row, now)
+		if err != nil {
+			c.Logger.Debug("Skipping row", zap.Error(err))
+			return
+		}
+		products = append(products, *product)
+	})
+	return products
+}
+
+// parseRow extracts a product from a single row
+func (c *SelectorSource) parseRow(row *goquery.Selection, now time.Time) (*models.Product, error) {
+	title := strings.Join(strings.Fields(row.FindMatcher(c.title).First().Text()), " ")
+	if title == "" {
+		return nil, fmt.Errorf("title not found")
+	}
+
+	href, ok := row.FindMatcher(c.link).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return nil, fmt.Errorf("link not found")
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return nil, fmt.Errorf("invalid link %q: %w", href, err)
+	}
+
+	// Relative links are resolved against the list page
+	rawURL := c.baseURL.ResolveReference(ref).String()
+	productURL := models.CanonicalizeURL(rawURL)
+	originalURL := ""
+	if productURL != rawURL {
+		originalURL = rawURL
+	}
+
+	priceText := title
+	if c.price != nil {
+		priceText = row.FindMatcher(c.price).First().Text()
+	}
+	price, priceStr := extractPrice(priceText, c.priceRegex)
+
+	var comments int
+	if c.comments != nil {
+		comments, _ = strconv.Atoi(nonDigits.ReplaceAllString(row.FindMatcher(c.comments).First().Text(), ""))
+	}
+
+	return &models.Product{
+		Title:       title,
+		URL:         productURL,
+		OriginalURL: originalURL,
+		KOPrice:     price,
+		PriceString: priceStr,
+		UploadDate:  now.Unix(),
+		UploadSite:  c.name,
+		Product:     title,
+		Website:     c.name,
+		Source:      c.name,
+		Comments:    comments,
+		CrawledAt:   now,
+		Category:    "Deal",
+	}, nil
+}
+
+// extractPrice finds the price in text with re. The number is taken from the
+// first capture group if re has one, otherwise from the whole match, and any
+// fraction is dropped. The matched text is returned as the display string.
+func extractPrice(text string, re *regexp.Regexp) (int, string) {
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return 0, ""
+	}
+
+	number := match[0]
+	if len(match) > 1 && match[1] != "" {
+		number = match[1]
+	}
+	number, _, _ = strings.Cut(strings.ReplaceAll(number, ",", ""), ".")
+	price, err := strconv.Atoi(nonDigits.ReplaceAllString(number, ""))
+	if err != nil {
+		return 0, ""
+	}
+	return price, strings.TrimSpace(match[0])
+}
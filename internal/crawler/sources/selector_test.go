@@ -0,0 +1,136 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.uber.org/zap"
+)
+
+func TestSelectorSourceProductURL(t *testing.T) {
+	source, err := NewSelectorSource(config.SelectorSource{
+		Name:  "Board",
+		URL:   "https://example.com/board/list",
+		Row:   "li.deal",
+		Title: ".title",
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewSelectorSource() error = %v", err)
+	}
+
+	page := `<ul>
+		<li class="deal"><a href="view?no=7&page=3&gclid=abc"><span class="title">모니터 199,000원</span></a></li>
+		<li class="deal"><a href="https://example.com/board/view?no=8"><span class="title">키보드 59,000원</span></a></li>
+	</ul>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("failed to parse page: %v", err)
+	}
+
+	products := source.parseDocument(doc, time.Now())
+	if len(products) != 2 {
+		t.Fatalf("parseDocument() returned %d products, want 2", len(products))
+	}
+	if want := "https://example.com/board/view?no=7&page=3"; products[0].URL != want {
+		t.Errorf("URL = %q, want %q", products[0].URL, want)
+	}
+	if want := "https://example.com/board/view?no=7&page=3&gclid=abc"; products[0].OriginalURL != want {
+		t.Errorf("OriginalURL = %q, want %q", products[0].OriginalURL, want)
+	}
+	if want := "https://example.com/board/view?no=8"; products[1].URL != want || products[1].OriginalURL != "" {
+		t.Errorf("URL = %q, OriginalURL = %q, want %q unchanged", products[1].URL, products[1].OriginalURL, want)
+	}
+}
+
+const sampleSelectorPage = `<html><body>
+<table class="board">
+  <tr class="row notice"><td class="subject">공지사항</td></tr>
+  <tr class="row">
+    <td class="subject"><a href="/deal/11?utm_source=list">[G마켓] 삼성 SSD 1TB</a></td>
+    <td class="price">$89.99</td>
+    <td class="reply">[12]</td>
+  </tr>
+  <tr class="row">
+    <td class="subject"><a href="https://shop.example.com/item/7">  LG   27인치
+      모니터 </a></td>
+    <td class="price">가격 미정</td>
+    <td class="reply"></td>
+  </tr>
+</table>
+</body></html>`
+
+func TestSelectorSourceCrawl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(sampleSelectorPage))
+	}))
+	defer server.Close()
+
+	source, err := NewSelectorSource(config.SelectorSource{
+		Name:       "Board",
+		URL:        server.URL + "/board/list",
+		Row:        "tr.row",
+		Title:      "td.subject a",
+		Link:       "td.subject a",
+		Price:      "td.price",
+		Comments:   "td.reply",
+		PriceRegex: `\$([\d,.]+)`,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewSelectorSource() error = %v", err)
+	}
+
+	products, err := source.Crawl(context.Background())
+	if err != nil {
+		t.Fatalf("Crawl() error = %v", err)
+	}
+
+	// The notice row has no link title and is skipped
+	if len(products) != 2 {
+		t.Fatalf("Crawl() returned %d products, want 2", len(products))
+	}
+	first := products[0]
+	if first.Title != "[G마켓] 삼성 SSD 1TB" || first.URL != server.URL+"/deal/11" {
+		t.Errorf("first product = %q %s, want the SSD resolved against the page", first.Title, first.URL)
+	}
+	if first.KOPrice != 89 || first.PriceString != "$89.99" || first.Comments != 12 {
+		t.Errorf("first product price = %d %q, comments = %d, want 89 \"$89.99\" and 12", first.KOPrice, first.PriceString, first.Comments)
+	}
+	second := products[1]
+	if second.Title != "LG 27인치 모니터" || second.URL != "https://shop.example.com/item/7" {
+		t.Errorf("second product = %q %s, want the monitor with its whitespace collapsed", second.Title, second.URL)
+	}
+	if second.KOPrice != 0 || second.Comments != 0 || second.Source != "Board" {
+		t.Errorf("second product = %+v, want no price, no comments and the source name", second)
+	}
+}
+
+func TestNewSelectorSourceValidatesConfig(t *testing.T) {
+	valid := config.SelectorSource{Name: "Board", URL: "https://example.com/list", Row: "tr", Title: "a"}
+	tests := []struct {
+		name   string
+		change func(cfg *config.SelectorSource)
+	}{
+		{"relative URL", func(cfg *config.SelectorSource) { cfg.URL = "/list" }},
+		{"missing row", func(cfg *config.SelectorSource) { cfg.Row = "" }},
+		{"invalid selector", func(cfg *config.SelectorSource) { cfg.Price = "td[" }},
+		{"invalid price regex", func(cfg *config.SelectorSource) { cfg.PriceRegex = "(" }},
+	}
+
+	if _, err := NewSelectorSource(valid, zap.NewNop()); err != nil {
+		t.Fatalf("NewSelectorSource() with a valid config error = %v", err)
+	}
+	for _, tt := range tests {
+		cfg := valid
+		tt.change(&cfg)
+		if _, err := NewSelectorSource(cfg, zap.NewNop()); err == nil {
+			t.Errorf("%s: NewSelectorSource() error = nil", tt.name)
+		}
+	}
+}
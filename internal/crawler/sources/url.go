@@ -0,0 +1,25 @@
+package sources
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+// ppomppuHost is the domain of Ppomppu post URLs, with or without www
+const ppomppuHost = "ppomppu.co.kr"
+
+// CanonicalProductURL canonicalizes a stored product URL the way the source
+// that crawled it does, recognizing the source by host. It brings URLs saved
+// before canonicalization to the form the sources produce now.
+func CanonicalProductURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err == nil {
+		host := strings.ToLower(u.Hostname())
+		if host == ppomppuHost || strings.HasSuffix(host, "."+ppomppuHost) {
+			return models.CanonicalizeURLWith(rawURL, ppomppuURLPolicy)
+		}
+	}
+	return models.CanonicalizeURL(rawURL)
+}
@@ -0,0 +1,40 @@
+package sources
+
+import "testing"
+
+func TestCanonicalProductURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "ppomppu keeps only the post params",
+			raw:  "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&page=2&divpage=90&no=123",
+			want: "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+		},
+		{
+			name: "ppomppu without www",
+			raw:  "https://ppomppu.co.kr/zboard/view.php?no=123&id=ppomppu&page=2",
+			want: "https://ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123",
+		},
+		{
+			name: "other sites drop tracking params only",
+			raw:  "https://quasarzone.com/bbs/qb_saleinfo/views/1?page=2&utm_source=x",
+			want: "https://quasarzone.com/bbs/qb_saleinfo/views/1?page=2",
+		},
+		{
+			name: "lookalike host is not ppomppu",
+			raw:  "https://notppomppu.co.kr/view.php?page=2&no=1",
+			want: "https://notppomppu.co.kr/view.php?no=1&page=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalProductURL(tt.raw); got != tt.want {
+				t.Errorf("CanonicalProductURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bradykim7/gbot/internal/crawler/sources"
+	"github.com/bradykim7/gbot/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CanonicalizeStoredURLs rewrites the url of products and notified products
+// saved before URLs were canonicalized to the form the sources produce now,
+// so a deal crawled again matches its stored copy instead of being inserted
+// and announced a second time. A document whose canonical URL is already
+// stored is a duplicate and is removed; a removed product's notified flag is
+// carried over to the one kept. It returns how many documents were rewritten
+// and how many were removed.
+func CanonicalizeStoredURLs(ctx context.Context, db *storage.MongoDB) (rewritten, removed int64, err error) {
+	for _, name := range []string{"products", "notified_products"} {
+		r, d, err := canonicalizeCollectionURLs(ctx, db.Collection(name), name == "products")
+		rewritten += r
+		removed += d
+		if err != nil {
+			return rewritten, removed, err
+		}
+	}
+	return rewritten, removed, nil
+}
+
+// canonicalizeCollectionURLs canonicalizes the url of every document in
+// collection. keepOriginal records the URL as typed in original_url, as the
+// sources do for products.
+func canonicalizeCollectionURLs(ctx context.Context, collection *mongo.Collection, keepOriginal bool) (rewritten, removed int64, err error) {
+	cursor, err := collection.Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"url": 1, "original_url": 1, "notified": 1}))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID          interface{} `bson:"_id"`
+			URL         string      `bson:"url"`
+			OriginalURL string      `bson:"original_url"`
+			Notified    bool        `bson:"notified"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return rewritten, removed, fmt.Errorf("failed to decode %s document: %w", collection.Name(), err)
+		}
+
+		canonical := sources.CanonicalProductURL(doc.URL)
+		if canonical == doc.URL {
+			continue
+		}
+
+		set := bson.M{"url": canonical}
+		if keepOriginal && doc.OriginalURL == "" {
+			set["original_url"] = doc.URL
+		}
+		_, err := collection.UpdateByID(ctx, doc.ID, bson.M{"$set": set})
+		if err == nil {
+			rewritten++
+			continue
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return rewritten, removed, fmt.Errorf("failed to rewrite URL %q: %w", doc.URL, err)
+		}
+
+		// The canonical URL is already stored, so this document is a duplicate
+		if doc.Notified {
+			_, err := collection.UpdateOne(ctx, bson.M{"url": canonical}, bson.M{"$set": bson.M{"notified": true}})
+			if err != nil {
+				return rewritten, removed, fmt.Errorf("failed to carry over notified flag to %q: %w", canonical, err)
+			}
+		}
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": doc.ID}); err != nil {
+			return rewritten, removed, fmt.Errorf("failed to remove duplicate %q: %w", doc.URL, err)
+		}
+		removed++
+	}
+	if err := cursor.Err(); err != nil {
+		return rewritten, removed, fmt.Errorf("failed to iterate %s: %w", collection.Name(), err)
+	}
+
+	return rewritten, removed, nil
+}
@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bradykim7/gbot/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestCanonicalizeStoredURLs(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("rewrite and remove duplicates", func(mt *mtest.T) {
+		const canonical = "https://shop.example.com/deal/1"
+		mt.AddMockResponses(
+			cursorResponse(
+				bson.D{{Key: "_id", Value: "p1"}, {Key: "url", Value: canonical + "?utm_source=x"}},
+				bson.D{{Key: "_id", Value: "p2"}, {Key: "url", Value: "https://shop.example.com/deal/2/"}, {Key: "notified", Value: true}},
+				bson.D{{Key: "_id", Value: "p3"}, {Key: "url", Value: "https://shop.example.com/deal/3"}},
+			),
+			writeResponse(1), // p1 rewritten
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}),
+			writeResponse(1), // notified carried over to the stored copy of p2
+			writeResponse(1), // p2 removed
+			cursorResponse(), // notified_products
+		)
+
+		rewritten, removed, err := CanonicalizeStoredURLs(context.Background(), newMockDB(mt, &config.Config{}))
+		if err != nil {
+			mt.Fatalf("CanonicalizeStoredURLs() error = %v", err)
+		}
+		if rewritten != 1 || removed != 1 {
+			mt.Errorf("CanonicalizeStoredURLs() = %d rewritten, %d removed, want 1, 1", rewritten, removed)
+		}
+
+		updates := startedCommands(mt, "update")
+		if len(updates) != 3 {
+			mt.Fatalf("sent %d updates, want 3", len(updates))
+		}
+		first := updates[0].Lookup("updates").Array().Index(0).Value().Document()
+		if got := first.Lookup("u", "$set", "url").StringValue(); got != canonical {
+			mt.Errorf("rewrote url to %q, want %q", got, canonical)
+		}
+		if got := first.Lookup("u", "$set", "original_url").StringValue(); got != canonical+"?utm_source=x" {
+			mt.Errorf("original_url = %q, want the URL as stored", got)
+		}
+		carried := updates[2].Lookup("updates").Array().Index(0).Value().Document()
+		if got := carried.Lookup("q", "url").StringValue(); got != "https://shop.example.com/deal/2" {
+			mt.Errorf("carried notified flag to %q, want the canonical product", got)
+		}
+
+		deletes := startedCommands(mt, "delete")
+		if len(deletes) != 1 {
+			mt.Fatalf("sent %d deletes, want 1", len(deletes))
+		}
+		if got := deletes[0].Lookup("deletes").Array().Index(0).Value().Document().Lookup("q", "_id").StringValue(); got != "p2" {
+			mt.Errorf("removed %q, want the duplicate p2", got)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// MessageSender delivers a Discord message to a channel
+type MessageSender interface {
+	SendMessage(channelID string, message *discordgo.MessageSend) (*discordgo.Message, error)
+}
+
+// sessionSender sends messages through the bot's REST session
+type sessionSender struct {
+	session *discordgo.Session
+}
+
+// SendMessage implements MessageSender
+func (s sessionSender) SendMessage(channelID string, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	return s.session.ChannelMessageSendComplex(channelID, message)
+}
+
+// WebhookNotifier sends messages through per-channel Discord webhooks over plain HTTP,
+// so notifications don't depend on the bot's session
+type WebhookNotifier struct {
+	urls   map[string]string // channel ID -> webhook URL
+	client *http.Client
+	log    *zap.Logger
+}
+
+// NewWebhookNotifier creates a webhook notifier for the given channel webhooks
+func NewWebhookNotifier(urls map[string]string, log *zap.Logger) *WebhookNotifier {
+	return &WebhookNotifier{
+		urls: urls,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		log: log.Named("webhook-notifier"),
+	}
+}
+
+// webhookPayload is the body of a Discord webhook execute request
+type webhookPayload struct {
+	Content         string                            `json:"content,omitempty"`
+	Embeds          []*discordgo.MessageEmbed         `json:"embeds,omitempty"`
+	AllowedMentions *discordgo.MessageAllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// SendMessage implements MessageSender. It waits for Discord to create the
+// message so the returned message carries its ID.
+func (w *WebhookNotifier) SendMessage(channelID string, message *discordgo.MessageSend) (*discordgo.Message, error) {
+	url, ok := w.urls[channelID]
+	if !ok {
+		return nil, fmt.Errorf("no webhook configured for channel %s", channelID)
+	}
+
+	payload := webhookPayload{
+		Content:         message.Content,
+		Embeds:          message.Embeds,
+		AllowedMentions: message.AllowedMentions,
+	}
+	if message.Embed != nil {
+		payload.Embeds = append([]*discordgo.MessageEmbed{message.Embed}, payload.Embeds...)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	resp, err := w.client.Post(url+"?wait=true", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", config.RedactError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, detail)
+	}
+
+	sent := &discordgo.Message{ChannelID: channelID}
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(sent); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook response: %w", err)
+		}
+	}
+
+	w.log.Debug("Sent webhook message", zap.String("channel_id", channelID), zap.String("message_id", sent.ID))
+	return sent, nil
+}
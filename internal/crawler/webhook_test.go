@@ -0,0 +1,180 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/internal/storage"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// webhookServer records the payloads posted to it and answers like Discord
+// does for ?wait=true
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	payloads []webhookPayload
+	queries  []string
+}
+
+func newWebhookServer(t *testing.T, status int) *webhookServer {
+	ws := &webhookServer{}
+	ws.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode webhook payload: %v", err)
+		}
+		ws.mu.Lock()
+		ws.payloads = append(ws.payloads, payload)
+		ws.queries = append(ws.queries, r.URL.RawQuery)
+		ws.mu.Unlock()
+
+		if status != http.StatusOK {
+			http.Error(w, "bad webhook", status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(discordgo.Message{ID: "message-1", ChannelID: "channel-1"})
+	}))
+	t.Cleanup(ws.Close)
+	return ws
+}
+
+func (ws *webhookServer) received() []webhookPayload {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	return append([]webhookPayload(nil), ws.payloads...)
+}
+
+func TestWebhookNotifierSendMessage(t *testing.T) {
+	server := newWebhookServer(t, http.StatusOK)
+	notifier := NewWebhookNotifier(map[string]string{"channel-1": server.URL}, zap.NewNop())
+
+	sent, err := notifier.SendMessage("channel-1", &discordgo.MessageSend{
+		Content: "<@user>",
+		Embed:   &discordgo.MessageEmbed{Title: "deal"},
+		Embeds:  []*discordgo.MessageEmbed{{Title: "more"}},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if sent.ID != "message-1" || sent.ChannelID != "channel-1" {
+		t.Errorf("SendMessage() = %+v, want the message Discord created", sent)
+	}
+
+	payloads := server.received()
+	if len(payloads) != 1 {
+		t.Fatalf("server received %d requests, want 1", len(payloads))
+	}
+	if server.queries[0] != "wait=true" {
+		t.Errorf("query = %q, want wait=true", server.queries[0])
+	}
+	got := payloads[0]
+	if got.Content != "<@user>" {
+		t.Errorf("content = %q, want %q", got.Content, "<@user>")
+	}
+	if len(got.Embeds) != 2 || got.Embeds[0].Title != "deal" || got.Embeds[1].Title != "more" {
+		t.Errorf("embeds = %+v, want the single embed followed by the others", got.Embeds)
+	}
+}
+
+func TestWebhookNotifierErrors(t *testing.T) {
+	server := newWebhookServer(t, http.StatusBadRequest)
+	notifier := NewWebhookNotifier(map[string]string{"channel-1": server.URL}, zap.NewNop())
+
+	if _, err := notifier.SendMessage("channel-2", &discordgo.MessageSend{Content: "hi"}); err == nil {
+		t.Error("SendMessage() to a channel without a webhook succeeded")
+	}
+
+	_, err := notifier.SendMessage("channel-1", &discordgo.MessageSend{Content: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("SendMessage() error = %v, want the status code", err)
+	}
+}
+
+func TestSendProductNotificationsOverWebhookWithoutBot(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("deliver", func(mt *mtest.T) {
+		server := newWebhookServer(mt.T, http.StatusOK)
+		cfg := &config.Config{
+			NotificationTransport: "webhook",
+			WebhookURLs:           map[string]string{"channel-1": server.URL},
+			NotificationLanguage:  "ko",
+		}
+		db := newMockDB(mt, cfg)
+		n := newWebhookTestService(mt.T, cfg, db)
+
+		mt.AddMockResponses(
+			cursorResponse(), // channel snoozes
+			writeResponse(1), // deal message
+			writeResponse(1), // alert match
+			writeResponse(1), // notified_products
+			writeResponse(1), // products
+		)
+
+		alerts := []models.KeywordAlert{
+			{ID: "alert-1", Keyword: "모니터", UserID: "user-1", GuildID: "guild-1", ChannelID: "channel-1", IsActive: true},
+			{ID: "alert-2", Keyword: "모니터", UserID: "user-2", GuildID: "guild-1", ChannelID: "channel-2", IsActive: true},
+		}
+		product := models.Product{Title: "27인치 모니터", URL: "https://example.com/deal/1"}
+
+		if err := n.sendProductNotifications(context.Background(), product, alerts); err != nil {
+			mt.Fatalf("sendProductNotifications() error = %v", err)
+		}
+
+		payloads := server.received()
+		if len(payloads) != 1 {
+			mt.Fatalf("webhook received %d messages, want 1 for the configured channel", len(payloads))
+		}
+		// Only an ID mention listed in allowed_mentions pings the user
+		if payloads[0].Content != "<@user-1>" {
+			mt.Errorf("content = %q, want the user's ID mention", payloads[0].Content)
+		}
+		if users := payloads[0].AllowedMentions.Users; len(users) != 1 || users[0] != "user-1" {
+			mt.Errorf("allowed mentions = %v, want only user-1", users)
+		}
+		if len(startedCommands(mt, "update")) == 0 {
+			mt.Error("product was not marked notified")
+		}
+	})
+}
+
+// newWebhookTestService builds a notification service using webhook delivery
+// whose bot session fails the test if it is used
+func newWebhookTestService(t *testing.T, cfg *config.Config, db *storage.MongoDB) *NotificationService {
+	log := zap.NewNop()
+	limiter := time.NewTicker(time.Millisecond)
+	t.Cleanup(limiter.Stop)
+
+	return &NotificationService{
+		session:        offlineSession(t),
+		sender:         NewWebhookNotifier(cfg.WebhookURLs, log),
+		config:         cfg,
+		db:             db,
+		logger:         log,
+		rateLimiter:    limiter,
+		matchRepo:      storage.NewAlertMatchRepository(db, log),
+		pendingRepo:    storage.NewPendingNotificationRepository(db, log),
+		snoozeRepo:     storage.NewChannelSnoozeRepository(db, log),
+		dealRepo:       storage.NewDealMessageRepository(db, log),
+		alertRepo:      storage.NewAlertRepository(db, log),
+		alertMatcher:   NewAlertMatcher(db, log),
+		location:       time.UTC,
+		channelGuilds:  make(map[string]string),
+		channelPerms:   make(map[string]channelPermission),
+		deniedChannels: make(map[string]bool),
+		lifetime:       context.Background(),
+	}
+}
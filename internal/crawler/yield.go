@@ -0,0 +1,85 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/zap"
+)
+
+// yieldMinSamples is the number of previous runs needed before a source's
+// yield is judged, so a new source isn't flagged on its first quiet run
+const yieldMinSamples = 3
+
+// yieldTracker keeps a moving window of products found per run for each
+// source and flags runs that fall far below the source's recent average,
+// which usually means the site changed and its parser stopped matching.
+// It is not safe for concurrent use; ImprovedCrawler guards it with statsMutex.
+type yieldTracker struct {
+	window      int
+	dropPercent int
+	history     map[string][]int
+}
+
+// newYieldTracker creates a tracker averaging over the last window runs and
+// flagging runs below dropPercent of that average
+func newYieldTracker(window, dropPercent int) *yieldTracker {
+	return &yieldTracker{
+		window:      window,
+		dropPercent: dropPercent,
+		history:     make(map[string][]int),
+	}
+}
+
+// record adds a run's product count for source. It returns the average of the
+// previous runs and whether this run's count is unusually low compared to it.
+// Sources that have averaged zero products are never flagged.
+func (y *yieldTracker) record(source string, count int) (average float64, low bool) {
+	previous := y.history[source]
+
+	if len(previous) > 0 {
+		total := 0
+		for _, n := range previous {
+			total += n
+		}
+		average = float64(total) / float64(len(previous))
+	}
+
+	low = len(previous) >= yieldMinSamples &&
+		average > 0 &&
+		float64(count) < average*float64(y.dropPercent)/100
+
+	previous = append(previous, count)
+	if len(previous) > y.window {
+		previous = previous[len(previous)-y.window:]
+	}
+	y.history[source] = previous
+
+	return average, low
+}
+
+// alertLowYield tells operators that a source's yield dropped. The alert goes to
+// the ops channel when one is configured and the notifier can post embeds;
+// otherwise, and in dry-run mode, it is only logged.
+func (c *ImprovedCrawler) alertLowYield(ctx context.Context, source string, count int, average float64) {
+	c.log.Warn("Source yield dropped sharply, its parser may be broken",
+		zap.String("source", source),
+		zap.Int("products_found", count),
+		zap.Float64("average", average))
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("⚠️ 수집량 급감: %s", source),
+		Description: fmt.Sprintf("이번 실행에서 %d개의 상품을 찾았습니다 (최근 평균 %.1f개).\n사이트 구조가 바뀌어 파서가 동작하지 않을 수 있습니다.",
+			count, average),
+		Color: 0xFF6600,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "크롤러 운영 알림",
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if err := c.sendOpsAlert(ctx, embed); err != nil {
+		c.log.Error("Failed to send low yield alert", zap.Error(err), zap.String("source", source))
+	}
+}
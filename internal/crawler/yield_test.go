@@ -0,0 +1,120 @@
+package crawler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bradykim7/gbot/pkg/config"
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestYieldTrackerFlagsDropToZero(t *testing.T) {
+	y := newYieldTracker(5, 20)
+
+	for i := 0; i < yieldMinSamples; i++ {
+		if _, low := y.record("ppomppu", 20); low {
+			t.Fatalf("run %d flagged with a steady yield", i+1)
+		}
+	}
+
+	average, low := y.record("ppomppu", 0)
+	if !low {
+		t.Error("drop to zero wasn't flagged")
+	}
+	if average != 20 {
+		t.Errorf("average = %v, want 20", average)
+	}
+
+	// 2 is below 20% of the new average of 15
+	if _, low := y.record("ppomppu", 2); !low {
+		t.Error("yield below the threshold wasn't flagged")
+	}
+	if _, low := y.record("ppomppu", 15); low {
+		t.Error("normal yield was flagged")
+	}
+}
+
+func TestYieldTrackerNeedsHistory(t *testing.T) {
+	y := newYieldTracker(5, 20)
+
+	// A new source isn't judged on its first runs
+	y.record("rss", 10)
+	if _, low := y.record("rss", 0); low {
+		t.Error("source flagged before it had enough runs")
+	}
+
+	// A source that never returns deals isn't flagged
+	for i := 0; i < yieldMinSamples; i++ {
+		y.record("quiet", 0)
+	}
+	if _, low := y.record("quiet", 0); low {
+		t.Error("source averaging zero was flagged")
+	}
+}
+
+func TestYieldTrackerWindow(t *testing.T) {
+	y := newYieldTracker(3, 50)
+
+	for _, n := range []int{100, 10, 10, 10} {
+		y.record("ppomppu", n)
+	}
+	// The 100-product run left the window, so 6 isn't a drop against 10
+	if average, low := y.record("ppomppu", 6); average != 10 || low {
+		t.Errorf("record() = %v, %v, want the average of the last 3 runs (10) and no flag", average, low)
+	}
+}
+
+// embedNotifier records the embeds sent to channels
+type embedNotifier struct {
+	recordingNotifier
+	mu     sync.Mutex
+	embeds map[string][]*discordgo.MessageEmbed
+}
+
+func (e *embedNotifier) SendEmbeds(ctx context.Context, channelID string, embeds []*discordgo.MessageEmbed) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.embeds[channelID] = append(e.embeds[channelID], embeds...)
+	return nil
+}
+
+func TestRunFlagsSourceDroppingToZero(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("drop to zero", func(mt *mtest.T) {
+		notifier := &embedNotifier{embeds: make(map[string][]*discordgo.MessageEmbed)}
+		cfg := &config.Config{OpsAlertChannelID: "ops", YieldWindowRuns: 5, YieldDropPercent: 20}
+		c := newTestCrawler(mt, cfg, notifier, &staticSource{name: "ppomppu", products: []models.Product{}})
+		for i := 0; i < yieldMinSamples; i++ {
+			c.yields.record("ppomppu", 20)
+		}
+
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+
+		stats := c.GetStats().SourceStats["ppomppu"]
+		if !stats.LowYield || stats.YieldAverage != 20 {
+			mt.Errorf("source stats = %+v, want LowYield against an average of 20", stats)
+		}
+		alerts := notifier.embeds["ops"]
+		if len(alerts) != 1 || !strings.Contains(alerts[0].Title, "ppomppu") {
+			mt.Fatalf("ops alerts = %v, want one about ppomppu", alerts)
+		}
+
+		// Still low on the next run, but operators were already told
+		if err := c.Run(context.Background()); err != nil {
+			mt.Fatalf("Run() error = %v", err)
+		}
+		if !c.GetStats().SourceStats["ppomppu"].LowYield {
+			mt.Error("LowYield cleared while the source still returns nothing")
+		}
+		if n := len(notifier.embeds["ops"]); n != 1 {
+			mt.Errorf("sent %d ops alerts, want 1", n)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package embeds
+
+import (
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+// Messages is the message catalog used to render product notifications
+type Messages struct {
+	NewDeal      string
+	Source       string
+	Price        string
+	Discount     string
+	Stats        string
+	StatsFormat  string // comments, views
+	Keywords     string
+	MoreMentions string
+	FooterFormat string // crawl time
+}
+
+// notificationCatalog holds the notification text for every supported language
+var notificationCatalog = map[string]Messages{
+	models.LanguageKorean: {
+		NewDeal:      "새로운 특가 상품을 발견했습니다!",
+		Source:       "출처",
+		Price:        "가격",
+		Discount:     "할인율",
+		Stats:        "반응",
+		StatsFormat:  "댓글: %d | 조회수: %d",
+		Keywords:     "일치한 키워드",
+		MoreMentions: "추가 멘션",
+		FooterFormat: "수집 시각: %s",
+	},
+	models.LanguageEnglish: {
+		NewDeal:      "Found a new deal!",
+		Source:       "Source",
+		Price:        "Price",
+		Discount:     "Discount",
+		Stats:        "Stats",
+		StatsFormat:  "Comments: %d | Views: %d",
+		Keywords:     "Matched Keywords",
+		MoreMentions: "More Mentions",
+		FooterFormat: "Crawled at %s",
+	},
+}
+
+// MessagesFor returns the catalog for lang, falling back to Korean
+func MessagesFor(lang string) Messages {
+	if msgs, ok := notificationCatalog[lang]; ok {
+		return msgs
+	}
+	return notificationCatalog[models.LanguageKorean]
+}
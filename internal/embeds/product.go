@@ -0,0 +1,197 @@
+package embeds
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"github.com/bwmarrin/discordgo"
+)
+
+// MentionRoleIDs returns the unique role IDs alerts ask to ping, in alert order
+func MentionRoleIDs(alerts []models.KeywordAlert) []string {
+	var roleIDs []string
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if alert.RoleID != "" && !seen[alert.RoleID] {
+			seen[alert.RoleID] = true
+			roleIDs = append(roleIDs, alert.RoleID)
+		}
+	}
+	return roleIDs
+}
+
+// MentionUserIDs returns the unique IDs of the users owning alerts, in alert order.
+// Server alerts notify the whole channel and mention no one, and alerts with
+// a role mention the role instead of their owner.
+func MentionUserIDs(alerts []models.KeywordAlert) []string {
+	var userIDs []string
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if alert.IsServerAlert() || alert.RoleID != "" {
+			continue
+		}
+		if alert.UserID != "" && !seen[alert.UserID] {
+			seen[alert.UserID] = true
+			userIDs = append(userIDs, alert.UserID)
+		}
+	}
+	return userIDs
+}
+
+// Mentions formats role mentions followed by user mentions
+func Mentions(roleIDs, userIDs []string) []string {
+	mentions := make([]string, 0, len(roleIDs)+len(userIDs))
+	for _, id := range roleIDs {
+		mentions = append(mentions, "<@&"+id+">")
+	}
+	return append(mentions, userMentions(userIDs)...)
+}
+
+// userMentions formats user IDs as Discord user mentions
+func userMentions(userIDs []string) []string {
+	mentions := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		mentions = append(mentions, "<@"+id+">")
+	}
+	return mentions
+}
+
+// ProductEmbed renders a product notification embed using the given message catalog
+func ProductEmbed(product models.Product, alerts []models.KeywordAlert, msgs Messages) *discordgo.MessageEmbed {
+	// Collect unique keywords that matched, in alert order
+	var keywordList []string
+	keywords := make(map[string]bool)
+	for _, alert := range alerts {
+		if !keywords[alert.Keyword] {
+			keywords[alert.Keyword] = true
+			keywordList = append(keywordList, models.EscapeDiscord(alert.Keyword))
+		}
+	}
+
+	// Mention roles and users by ID; Discord renders them with their current names
+	usernames := Mentions(MentionRoleIDs(alerts), MentionUserIDs(alerts))
+
+	// Create embed fields
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   msgs.Source,
+			Value:  product.Source,
+			Inline: true,
+		},
+	}
+
+	// Add price field if available
+	priceStr := product.GetPriceString()
+	if priceStr != "Price unknown" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   msgs.Price,
+			Value:  priceStr,
+			Inline: true,
+		})
+	}
+
+	// Add discount rate if available
+	if product.DiscountRate > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   msgs.Discount,
+			Value:  fmt.Sprintf("%d%%", product.DiscountRate),
+			Inline: true,
+		})
+	}
+
+	// Add comments/views if available
+	if product.Comments > 0 || product.Views > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   msgs.Stats,
+			Value:  fmt.Sprintf(msgs.StatsFormat, product.Comments, product.Views),
+			Inline: true,
+		})
+	}
+
+	// Add matched keywords field, truncated to Discord's field limit
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   msgs.Keywords,
+		Value:  JoinWithLimit(keywordList, ", ", FieldValueLimit),
+		Inline: false,
+	})
+
+	// Create description with mentions, overflowing extra mentions into fields
+	descriptionMentions := usernames
+	var overflowMentions []string
+	if len(usernames) > maxDescriptionMentions {
+		descriptionMentions = usernames[:maxDescriptionMentions]
+		overflowMentions = usernames[maxDescriptionMentions:]
+	}
+	description := Truncate(
+		strings.TrimSpace(fmt.Sprintf("%s %s", msgs.NewDeal, strings.Join(descriptionMentions, " "))),
+		DescriptionLimit)
+
+	for _, chunk := range ChunkWithLimit(overflowMentions, " ", FieldValueLimit, maxMentionFields) {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   msgs.MoreMentions,
+			Value:  chunk,
+			Inline: false,
+		})
+	}
+
+	// Create embed color based on hotness or discount rate
+	color := 0x00ff00 // Default green
+	title := models.SanitizeTitle(product.Title)
+	if product.IsHot {
+		color = 0xff0000 // Hot item = red
+		title = "🔥 " + title
+	} else if product.DiscountRate >= 50 {
+		color = 0xff6600 // Big discount = orange
+	}
+
+	// Create embed
+	embed := &discordgo.MessageEmbed{
+		Title:       Truncate(title, TitleLimit),
+		URL:         product.URL,
+		Description: description,
+		Color:       color,
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf(msgs.FooterFormat, product.CrawledAt.Format("2006-01-02 15:04:05")),
+		},
+	}
+
+	// Add image if available
+	if product.ImageURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{
+			URL: product.ImageURL,
+		}
+	}
+
+	return embed
+}
+
+// ProductMessage creates the notification message for a channel. Mentions inside
+// an embed never ping, so the matched users are also mentioned in the message content,
+// and AllowedMentions restricts pings to exactly those users. Titles come from untrusted
+// websites, so @everyone, @here and role mentions in them must never ping.
+// Discord rejects more than MaxAllowedMentionIDs IDs per list, and the content
+// limit leaves room for fewer mentions than that, so the lists are capped.
+func ProductMessage(product models.Product, alerts []models.KeywordAlert, msgs Messages) *discordgo.MessageSend {
+	roleIDs := MentionRoleIDs(alerts)
+	userIDs := MentionUserIDs(alerts)
+
+	return &discordgo.MessageSend{
+		Content: JoinWithLimit(Mentions(roleIDs, userIDs), " ", MessageContentLimit),
+		Embed:   ProductEmbed(product, alerts, msgs),
+		AllowedMentions: &discordgo.MessageAllowedMentions{
+			Parse: []discordgo.AllowedMentionType{},
+			Roles: capIDs(roleIDs, MaxAllowedMentionIDs),
+			Users: capIDs(userIDs, MaxAllowedMentionIDs),
+		},
+	}
+}
+
+// capIDs returns at most limit IDs, keeping the first ones
+func capIDs(ids []string, limit int) []string {
+	if len(ids) > limit {
+		return ids[:limit]
+	}
+	return ids
+}
@@ -0,0 +1,151 @@
+package embeds
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bradykim7/gbot/internal/models"
+)
+
+func TestProductMessageMentions(t *testing.T) {
+	alerts := []models.KeywordAlert{
+		{Keyword: "모니터", UserID: "111", ChannelID: "channel-1"},
+		{Keyword: "모니터", UserID: "111", ChannelID: "channel-1"},
+		{Keyword: "27인치", UserID: "222", ChannelID: "channel-1", RoleID: "333"},
+	}
+
+	msg := ProductMessage(models.Product{Title: "@everyone 27인치 모니터"}, alerts, MessagesFor("ko"))
+
+	if msg.Content != "<@&333> <@111>" {
+		t.Errorf("Content = %q, want the role and the user mentioned once", msg.Content)
+	}
+	if len(msg.AllowedMentions.Parse) != 0 {
+		t.Errorf("Parse = %v, want none so @everyone in titles never pings", msg.AllowedMentions.Parse)
+	}
+	if got := strings.Join(msg.AllowedMentions.Users, ","); got != "111" {
+		t.Errorf("Users = %s, want 111", got)
+	}
+	if got := strings.Join(msg.AllowedMentions.Roles, ","); got != "333" {
+		t.Errorf("Roles = %s, want 333", got)
+	}
+}
+
+func TestProductMessageCapsAllowedMentions(t *testing.T) {
+	var alerts []models.KeywordAlert
+	for i := 0; i < 150; i++ {
+		alerts = append(alerts, models.KeywordAlert{Keyword: "모니터", UserID: fmt.Sprintf("1000000000000000%03d", i)})
+	}
+
+	msg := ProductMessage(models.Product{Title: "27인치 모니터"}, alerts, MessagesFor("ko"))
+
+	users := msg.AllowedMentions.Users
+	if len(users) != MaxAllowedMentionIDs {
+		t.Fatalf("allowed %d users, want %d", len(users), MaxAllowedMentionIDs)
+	}
+	if users[0] != alerts[0].UserID || users[len(users)-1] != alerts[MaxAllowedMentionIDs-1].UserID {
+		t.Errorf("allowed users %s..%s, want the first %d", users[0], users[len(users)-1], MaxAllowedMentionIDs)
+	}
+	if len([]rune(msg.Content)) > MessageContentLimit {
+		t.Errorf("content is %d characters, want at most %d", len([]rune(msg.Content)), MessageContentLimit)
+	}
+}
+
+func TestProductEmbedFitsDiscordLimits(t *testing.T) {
+	var alerts []models.KeywordAlert
+	for i := 0; i < 100; i++ {
+		alerts = append(alerts, models.KeywordAlert{
+			Keyword: fmt.Sprintf("아주 긴 키워드 %03d %s", i, strings.Repeat("모니터", 10)),
+			UserID:  fmt.Sprintf("1000000000000000%03d", i),
+		})
+	}
+	product := models.Product{Title: strings.Repeat("27인치 모니터 ", 40), Source: "ppomppu", KOPrice: 300000}
+
+	embed := ProductEmbed(product, alerts, MessagesFor("ko"))
+
+	total := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description) + utf8.RuneCountInString(embed.Footer.Text)
+	if n := utf8.RuneCountInString(embed.Title); n > TitleLimit {
+		t.Errorf("title is %d characters, want at most %d", n, TitleLimit)
+	}
+	if n := utf8.RuneCountInString(embed.Description); n > DescriptionLimit {
+		t.Errorf("description is %d characters, want at most %d", n, DescriptionLimit)
+	}
+	if len(embed.Fields) > 25 {
+		t.Errorf("embed has %d fields, want at most 25", len(embed.Fields))
+	}
+	mentionFields := 0
+	for _, field := range embed.Fields {
+		if n := utf8.RuneCountInString(field.Value); n > FieldValueLimit {
+			t.Errorf("field %q is %d characters, want at most %d", field.Name, n, FieldValueLimit)
+		}
+		if field.Name == MessagesFor("ko").MoreMentions {
+			mentionFields++
+		}
+		total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if mentionFields == 0 || mentionFields > maxMentionFields {
+		t.Errorf("mentions overflowed into %d fields, want 1 to %d", mentionFields, maxMentionFields)
+	}
+	// Discord also caps the combined text of an embed
+	if total > 6000 {
+		t.Errorf("embed text is %d characters, want at most 6000", total)
+	}
+}
+
+func TestProductEmbedSanitizesTitle(t *testing.T) {
+	product := models.Product{Title: "@everyone **초특가** 모니터", URL: "https://example.com/deal/1"}
+
+	embed := ProductEmbed(product, nil, MessagesFor("ko"))
+
+	if want := models.SanitizeTitle(product.Title); embed.Title != want {
+		t.Errorf("Title = %q, want %q", embed.Title, want)
+	}
+	if embed.URL != product.URL {
+		t.Errorf("URL = %q, want the product link kept as is", embed.URL)
+	}
+}
+
+func TestProductMessageServerAlert(t *testing.T) {
+	alerts := []models.KeywordAlert{
+		{Keyword: "모니터", UserID: "admin-1", ChannelID: "channel-1", Scope: models.AlertScopeServer},
+	}
+
+	msg := ProductMessage(models.Product{Title: "27인치 모니터"}, alerts, MessagesFor("ko"))
+
+	if msg.Content != "" {
+		t.Errorf("Content = %q, want no mention for a server alert", msg.Content)
+	}
+	if len(msg.AllowedMentions.Users) != 0 || len(msg.AllowedMentions.Roles) != 0 {
+		t.Errorf("allowed mentions = %+v, want none", msg.AllowedMentions)
+	}
+	if strings.Contains(msg.Embed.Description, "admin-1") {
+		t.Errorf("description = %q, want the admin who created the alert left out", msg.Embed.Description)
+	}
+	if !strings.Contains(msg.Embed.Fields[len(msg.Embed.Fields)-1].Value, "모니터") {
+		t.Error("embed doesn't list the matched keyword")
+	}
+}
+
+func TestProductMessageRoleMention(t *testing.T) {
+	alerts := []models.KeywordAlert{
+		{Keyword: "모니터", UserID: "user-1", ChannelID: "channel-1", RoleID: "role-1"},
+		{Keyword: "27인치", UserID: "admin-1", ChannelID: "channel-1", RoleID: "role-1", Scope: models.AlertScopeServer},
+		{Keyword: "LG", UserID: "user-2", ChannelID: "channel-1"},
+	}
+
+	msg := ProductMessage(models.Product{Title: "LG 27인치 모니터"}, alerts, MessagesFor("ko"))
+
+	if msg.Content != "<@&role-1> <@user-2>" {
+		t.Errorf("Content = %q, want the role once and the user without a role", msg.Content)
+	}
+	if got := strings.Join(msg.AllowedMentions.Roles, ","); got != "role-1" {
+		t.Errorf("allowed roles = %s, want role-1", got)
+	}
+	if got := strings.Join(msg.AllowedMentions.Users, ","); got != "user-2" {
+		t.Errorf("allowed users = %s, want user-2", got)
+	}
+	if !strings.Contains(msg.Embed.Description, "<@&role-1>") {
+		t.Errorf("description = %q, want the role mention", msg.Embed.Description)
+	}
+}
@@ -0,0 +1,110 @@
+// Package embeds renders deal notifications as Discord embeds. It is shared by
+// the crawler, which sends them, and the bot, which previews them.
+package embeds
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord embed limits, measured in characters
+const (
+	TitleLimit          = 256
+	DescriptionLimit    = 4096
+	FieldValueLimit     = 1024
+	MessageContentLimit = 2000
+
+	// maxDescriptionMentions caps the mentions placed in the embed description;
+	// the rest overflow into additional fields
+	maxDescriptionMentions = 20
+	// maxMentionFields caps the overflow fields used for mentions
+	maxMentionFields = 3
+)
+
+// MaxAllowedMentionIDs is the most user or role IDs Discord accepts in each
+// AllowedMentions list; a longer list fails the whole send
+const MaxAllowedMentionIDs = 100
+
+// Truncate shortens s to at most limit characters, marking the cut with an ellipsis
+func Truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:limit-1]) + "…"
+}
+
+// JoinWithLimit joins items with sep, dropping trailing items that would push
+// the result past limit characters and appending a "+N more" suffix instead
+func JoinWithLimit(items []string, sep string, limit int) string {
+	var b strings.Builder
+	length := 0
+
+	for i, item := range items {
+		remaining := len(items) - i
+
+		piece := item
+		if i > 0 {
+			piece = sep + item
+		}
+		pieceLen := utf8.RuneCountInString(piece)
+
+		// Reserve room for the suffix unless this is the last item
+		reserve := 0
+		if remaining > 1 {
+			reserve = utf8.RuneCountInString(fmt.Sprintf("%s+%d more", sep, remaining-1))
+		}
+
+		if length+pieceLen+reserve > limit {
+			if i == 0 {
+				return Truncate(fmt.Sprintf("+%d more", remaining), limit)
+			}
+			fmt.Fprintf(&b, "%s+%d more", sep, remaining)
+			return b.String()
+		}
+
+		b.WriteString(piece)
+		length += pieceLen
+	}
+
+	return b.String()
+}
+
+// ChunkWithLimit splits items into at most maxChunks strings joined by sep,
+// each no longer than limit characters. Items that do not fit are summarized
+// with a "+N more" suffix on the last chunk.
+func ChunkWithLimit(items []string, sep string, limit, maxChunks int) []string {
+	var chunks []string
+	var current []string
+	length := 0
+
+	for i, item := range items {
+		itemLen := utf8.RuneCountInString(item)
+		sepLen := 0
+		if len(current) > 0 {
+			sepLen = utf8.RuneCountInString(sep)
+		}
+
+		if len(current) > 0 && length+sepLen+itemLen > limit {
+			if len(chunks) == maxChunks-1 {
+				// Last chunk: summarize everything that is left
+				chunks = append(chunks, JoinWithLimit(append(current, items[i:]...), sep, limit))
+				return chunks
+			}
+			chunks = append(chunks, strings.Join(current, sep))
+			current = nil
+			length = 0
+			sepLen = 0
+		}
+
+		current = append(current, item)
+		length += sepLen + itemLen
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, JoinWithLimit(current, sep, limit))
+	}
+
+	return chunks
+}
@@ -0,0 +1,52 @@
+package embeds
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestJoinWithLimit(t *testing.T) {
+	items := []string{"모니터", "키보드", "마우스", "스피커"}
+
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{100, "모니터, 키보드, 마우스, 스피커"},
+		{20, "모니터, 키보드, +2 more"},
+		{3, "+4…"},
+	}
+
+	for _, tt := range tests {
+		got := JoinWithLimit(items, ", ", tt.limit)
+		if got != tt.want {
+			t.Errorf("JoinWithLimit(limit %d) = %q, want %q", tt.limit, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); n > tt.limit {
+			t.Errorf("JoinWithLimit(limit %d) is %d characters", tt.limit, n)
+		}
+	}
+}
+
+func TestChunkWithLimit(t *testing.T) {
+	var items []string
+	for i := 0; i < 100; i++ {
+		items = append(items, fmt.Sprintf("<@1000000000000000%03d>", i))
+	}
+
+	chunks := ChunkWithLimit(items, " ", 100, 3)
+
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if n := utf8.RuneCountInString(chunk); n > 100 {
+			t.Errorf("chunk is %d characters, want at most 100", n)
+		}
+	}
+	if !strings.HasSuffix(chunks[2], "more") {
+		t.Errorf("last chunk = %q, want the rest summarized", chunks[2])
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// AlertMatch는 키워드 알림이 상품과 일치하여 알림이 발송된 기록을 나타냅니다.
+// 요약 방식 알림의 일치는 발송 전에 기록되고 다음 요약 DM에 포함됩니다.
+type AlertMatch struct {
+	ID           string    `bson:"_id,omitempty"`
+	AlertID      string    `bson:"alert_id"`
+	UserID       string    `bson:"user_id"`
+	Keyword      string    `bson:"keyword"`
+	ProductURL   string    `bson:"product_url"`
+	ProductTitle string    `bson:"product_title"`
+	MatchedAt    time.Time `bson:"matched_at"`
+	SummaryMode  string    `bson:"summary_mode,omitempty"` // 요약 DM으로 전달될 일치 (실시간이면 비어 있음)
+}
+
+// NewAlertMatch는 알림과 상품으로부터 새로운 일치 기록을 생성합니다
+func NewAlertMatch(alert KeywordAlert, product Product) *AlertMatch {
+	match := &AlertMatch{
+		AlertID:      alert.ID,
+		UserID:       alert.UserID,
+		Keyword:      alert.Keyword,
+		ProductURL:   product.URL,
+		ProductTitle: product.Title,
+		MatchedAt:    time.Now(),
+	}
+	if alert.IsSummarized() {
+		match.SummaryMode = alert.SummaryMode
+	}
+	return match
+}
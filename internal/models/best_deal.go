@@ -0,0 +1,54 @@
+package models
+
+// 최저가 알림 방식
+const (
+	// BestDealPrice는 하루에 한 번, 일치한 특가 중 가장 싼 것만 알립니다
+	BestDealPrice = "price"
+	// BestDealDiscount는 하루에 한 번, 일치한 특가 중 할인율이 가장 높은 것만 알립니다
+	BestDealDiscount = "discount"
+)
+
+// BestDealDateLayout은 BestDealDate에 저장하는 날짜 형식입니다
+const BestDealDateLayout = "2006-01-02"
+
+// IsBestDeal은 일치할 때마다 알리는 대신 하루에 가장 좋은 특가 하나만 알리는 알림인지 확인합니다
+func (k *KeywordAlert) IsBestDeal() bool {
+	return k.BestDeal == BestDealPrice || k.BestDeal == BestDealDiscount
+}
+
+// WithinMaxPrice는 상품이 알림의 최대 가격 조건을 만족하는지 확인합니다.
+// 최대 가격이 설정되면 가격을 알 수 없는 상품은 일치하지 않습니다.
+func (k *KeywordAlert) WithinMaxPrice(product *Product) bool {
+	if k.MaxPrice <= 0 {
+		return true
+	}
+	return product.KOPrice > 0 && product.KOPrice <= k.MaxPrice
+}
+
+// BetterDeal은 알림의 최저가 방식으로 볼 때 a가 b보다 좋은 특가인지 확인합니다.
+// 가격 방식은 가격이 낮은 쪽(가격 미상은 가장 나쁨), 할인율 방식은 할인율이 높은 쪽이 좋으며,
+// 같으면 다른 기준으로 비교합니다.
+func (k *KeywordAlert) BetterDeal(a, b *Product) bool {
+	if k.BestDeal == BestDealDiscount {
+		if a.DiscountRate != b.DiscountRate {
+			return a.DiscountRate > b.DiscountRate
+		}
+		return lowerPrice(a, b)
+	}
+	if a.KOPrice != b.KOPrice {
+		return lowerPrice(a, b)
+	}
+	return a.DiscountRate > b.DiscountRate
+}
+
+// lowerPrice는 a의 가격이 b보다 낮은지 확인합니다. 가격 미상(0)은 어떤 가격보다도 높게 봅니다.
+func lowerPrice(a, b *Product) bool {
+	switch {
+	case a.KOPrice <= 0:
+		return false
+	case b.KOPrice <= 0:
+		return true
+	default:
+		return a.KOPrice < b.KOPrice
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// ChannelSnooze는 채널의 알림을 일시적으로 중지한 상태를 나타냅니다
+type ChannelSnooze struct {
+	ChannelID string    `bson:"_id" json:"channel_id"`
+	GuildID   string    `bson:"guild_id,omitempty" json:"guild_id,omitempty"`
+	Until     time.Time `bson:"until" json:"until"`
+	SetBy     string    `bson:"set_by" json:"set_by"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// NewChannelSnooze는 until까지 채널 알림을 중지하는 상태를 생성합니다
+func NewChannelSnooze(channelID, guildID, setBy string, until time.Time) *ChannelSnooze {
+	return &ChannelSnooze{
+		ChannelID: channelID,
+		GuildID:   guildID,
+		Until:     until,
+		SetBy:     setBy,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// CommandUsage는 명령어 한 번의 실행 기록입니다 (기능 사용량 집계용)
+type CommandUsage struct {
+	Command   string    `bson:"command"` // 별칭이 아닌 대표 명령어 이름
+	UserID    string    `bson:"user_id"`
+	GuildID   string    `bson:"guild_id"` // DM이면 빈 문자열
+	Timestamp time.Time `bson:"ts"`
+}
+
+// NewCommandUsage는 지금 실행된 명령어의 사용 기록을 생성합니다
+func NewCommandUsage(command, userID, guildID string) *CommandUsage {
+	return &CommandUsage{
+		Command:   command,
+		UserID:    userID,
+		GuildID:   guildID,
+		Timestamp: time.Now(),
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// CooldownOff는 기본 간격이 설정되어 있어도 이 알림에는 간격을 두지 않도록 하는 CooldownMinutes 값입니다
+const CooldownOff = -1
+
+// Cooldown은 이 알림을 한 번 보낸 뒤 같은 알림을 다시 보내기까지의 최소 간격을 반환합니다.
+// 알림에 간격이 설정되어 있지 않으면 defaultCooldown을 쓰며, 0이면 간격이 없습니다.
+func (k *KeywordAlert) Cooldown(defaultCooldown time.Duration) time.Duration {
+	switch {
+	case k.CooldownMinutes > 0:
+		return time.Duration(k.CooldownMinutes) * time.Minute
+	case k.CooldownMinutes < 0:
+		return 0
+	default:
+		return defaultCooldown
+	}
+}
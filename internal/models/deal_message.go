@@ -0,0 +1,60 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DealAlertEmoji는 특가 알림 메시지에 달면 해당 상품으로 키워드 알림을 만드는 반응입니다
+const DealAlertEmoji = "🔔"
+
+// maxSuggestedKeywordWords는 상품 제목에서 만드는 키워드의 최대 단어 수입니다
+const maxSuggestedKeywordWords = 3
+
+// DealMessage는 봇이 게시한 특가 알림 메시지와 상품의 연결을 나타냅니다.
+// 반응으로 알림을 만들 때 메시지 ID로 상품을 찾는 데 사용하며 일정 기간 후 자동 삭제됩니다.
+type DealMessage struct {
+	MessageID    string    `bson:"_id" json:"message_id"`
+	ChannelID    string    `bson:"channel_id" json:"channel_id"`
+	ProductURL   string    `bson:"product_url" json:"product_url"`
+	ProductTitle string    `bson:"product_title" json:"product_title"`
+	ProductName  string    `bson:"product_name,omitempty" json:"product_name,omitempty"`
+	Category     string    `bson:"category,omitempty" json:"category,omitempty"`
+	PostedAt     time.Time `bson:"posted_at" json:"posted_at"`
+}
+
+// NewDealMessage는 게시된 메시지와 상품으로부터 연결 기록을 생성합니다
+func NewDealMessage(messageID, channelID string, product Product) *DealMessage {
+	return &DealMessage{
+		MessageID:    messageID,
+		ChannelID:    channelID,
+		ProductURL:   product.URL,
+		ProductTitle: product.Title,
+		ProductName:  product.Product,
+		Category:     product.Category,
+		PostedAt:     time.Now(),
+	}
+}
+
+// bracketedText는 제목의 [쇼핑몰], (가격) 같은 괄호 구간과 매칭됩니다
+var bracketedText = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)|<[^>]*>`)
+
+// SuggestAlertKeyword는 상품으로 만들 키워드를 고릅니다.
+// 상품명이 있으면 상품명을, 없으면 제목에서 괄호 구간을 뺀 앞 단어들을 사용하고
+// 둘 다 비어 있으면 카테고리를 사용합니다.
+func (d *DealMessage) SuggestAlertKeyword() string {
+	source := d.ProductName
+	if strings.TrimSpace(source) == "" {
+		source = bracketedText.ReplaceAllString(d.ProductTitle, " ")
+	}
+
+	words := strings.Fields(source)
+	if len(words) > maxSuggestedKeywordWords {
+		words = words[:maxSuggestedKeywordWords]
+	}
+	if len(words) == 0 {
+		return strings.TrimSpace(d.Category)
+	}
+	return strings.Join(words, " ")
+}
@@ -1,7 +1,9 @@
 package models
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -19,21 +21,44 @@ const (
 
 // Food는 음식 추천을 나타냅니다
 type Food struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name      string             `bson:"name" json:"name"`
-	FoodType  FoodType           `bson:"food_type" json:"food_type"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	CreatedBy string             `bson:"created_by" json:"created_by"`
-	IsActive  bool               `bson:"is_active" json:"is_active"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name           string             `bson:"name" json:"name"`
+	NormalizedName string             `bson:"normalized_name" json:"-"` // 중복 검사용 키 (NormalizeFoodName)
+	FoodType       FoodType           `bson:"food_type" json:"food_type"`
+	GuildID        string             `bson:"guild_id" json:"guild_id"` // 메뉴 목록을 가진 서버 (비어 있으면 DM 등에서 쓰는 공용 목록)
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	CreatedBy      string             `bson:"created_by" json:"created_by"`
+	IsActive       bool               `bson:"is_active" json:"is_active"`
 }
 
 // NewFood는 새로운 음식을 생성합니다
 func NewFood(name string, foodType FoodType, createdBy string) *Food {
 	return &Food{
-		Name:      name,
-		FoodType:  foodType,
-		CreatedAt: time.Now(),
-		CreatedBy: createdBy,
-		IsActive:  true,
+		Name:           CleanFoodName(name),
+		NormalizedName: NormalizeFoodName(name),
+		FoodType:       foodType,
+		CreatedAt:      time.Now(),
+		CreatedBy:      createdBy,
+		IsActive:       true,
 	}
-}
\ No newline at end of file
+}
+
+// CleanFoodName은 표시용 이름을 정리합니다: 앞뒤 공백을 제거하고 연속된 공백을 하나로 줄입니다
+func CleanFoodName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NormalizeFoodName은 중복 검사용 키를 만듭니다.
+// 공백을 모두 제거하고 라틴 문자는 소문자로 바꾸므로
+// "김치 찌개", "김치찌개 ", "Pho"와 "pho"는 각각 같은 키가 됩니다.
+func NormalizeFoodName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
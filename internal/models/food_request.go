@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FoodRequest는 사용자가 메뉴 추천을 받은 기록을 나타냅니다
+type FoodRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      string             `bson:"user_id"`
+	ChannelID   string             `bson:"channel_id"`
+	FoodID      primitive.ObjectID `bson:"food_id"`
+	RequestedAt time.Time          `bson:"requested_at"`
+}
+
+// NewFoodRequest는 추천된 음식으로부터 새로운 요청 기록을 생성합니다
+func NewFoodRequest(userID, channelID string, food *Food) *FoodRequest {
+	return &FoodRequest{
+		UserID:      userID,
+		ChannelID:   channelID,
+		FoodID:      food.ID,
+		RequestedAt: time.Now(),
+	}
+}
+
+// FoodRequestStat은 사용자가 특정 음식을 추천받은 횟수입니다
+type FoodRequestStat struct {
+	FoodID   primitive.ObjectID `bson:"_id"`
+	Name     string             `bson:"name"`
+	FoodType FoodType           `bson:"food_type"`
+	Count    int                `bson:"count"`
+}
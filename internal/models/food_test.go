@@ -0,0 +1,56 @@
+package models
+
+import "testing"
+
+func TestNormalizeFoodNameCollapsesVariants(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"김치 찌개", "김치찌개"},
+		{"김치찌개 ", " 김치찌개"},
+		{"김치\t찌개", "김치  찌개"},
+		{"Pho", "pho"},
+		{"PAD THAI", "pad thai"},
+		{"Ｐｈｏ", "ｐｈｏ"},
+	}
+	for _, tt := range tests {
+		if NormalizeFoodName(tt.a) != NormalizeFoodName(tt.b) {
+			t.Errorf("NormalizeFoodName(%q) = %q, NormalizeFoodName(%q) = %q, want equal",
+				tt.a, NormalizeFoodName(tt.a), tt.b, NormalizeFoodName(tt.b))
+		}
+	}
+}
+
+func TestNormalizeFoodNameKeepsDistinctNames(t *testing.T) {
+	if NormalizeFoodName("김치찌개") == NormalizeFoodName("된장찌개") {
+		t.Error("different foods normalized to the same key")
+	}
+}
+
+func TestCleanFoodName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  김치   찌개 ", "김치 찌개"},
+		{"Pad\tThai", "Pad Thai"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CleanFoodName(tt.in); got != tt.want {
+			t.Errorf("CleanFoodName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewFoodNormalizesName(t *testing.T) {
+	food := NewFood("  김치  찌개 ", FoodTypeLunch, "tester")
+	if food.Name != "김치 찌개" {
+		t.Errorf("Name = %q, want %q", food.Name, "김치 찌개")
+	}
+	if food.NormalizedName != "김치찌개" {
+		t.Errorf("NormalizedName = %q, want %q", food.NormalizedName, "김치찌개")
+	}
+	if !food.IsActive {
+		t.Error("new food is not active")
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+// GuildSettings는 서버별 봇 설정을 나타냅니다
+type GuildSettings struct {
+	GuildID   string    `bson:"_id" json:"guild_id"`
+	Prefix    string    `bson:"prefix,omitempty" json:"prefix,omitempty"` // 비어 있으면 기본 접두사 사용
+	UpdatedBy string    `bson:"updated_by" json:"updated_by"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
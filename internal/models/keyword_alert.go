@@ -1,21 +1,113 @@
 package models
 
 import (
+	"errors"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+// 알림 메시지에 사용할 수 있는 언어
+const (
+	LanguageKorean  = "ko"
+	LanguageEnglish = "en"
+)
+
+// IsSupportedLanguage는 알림 메시지를 해당 언어로 보낼 수 있는지 확인합니다
+func IsSupportedLanguage(lang string) bool {
+	return lang == LanguageKorean || lang == LanguageEnglish
+}
+
+// 알림 범위
+const (
+	// AlertScopeUser는 알림을 만든 사용자를 멘션하는 개인 알림입니다 (기본값)
+	AlertScopeUser = "user"
+	// AlertScopeServer는 관리자가 만든 서버 알림으로, 채널 전체에 알리며 사용자를 멘션하지 않습니다
+	AlertScopeServer = "server"
+)
+
+// 키워드 검증 오류
+var (
+	// ErrKeywordNoLetters는 키워드가 문장부호나 공백으로만 이루어진 경우입니다
+	ErrKeywordNoLetters = errors.New("keyword has no letters or digits")
+	// ErrKeywordTooShort는 키워드의 글자 수가 최소 길이보다 짧은 경우입니다
+	ErrKeywordTooShort = errors.New("keyword too short")
+	// ErrKeywordTooGeneric은 거의 모든 특가와 일치하는 키워드인 경우입니다
+	ErrKeywordTooGeneric = errors.New("keyword too generic")
+)
+
+// genericKeywords는 거의 모든 특가 글에 들어 있어 알림 폭주를 일으키는 키워드입니다 (NormalizeKeyword 기준)
+var genericKeywords = map[string]bool{
+	"원":    true,
+	"특가":   true,
+	"핫딜":   true,
+	"할인":   true,
+	"세일":   true,
+	"무료":   true,
+	"배송":   true,
+	"무배":   true,
+	"무료배송": true,
+	"쿠폰":   true,
+	"최저가":  true,
+	"deal": true,
+	"sale": true,
+	"free": true,
+	"http": true,
+	"www":  true,
+	"com":  true,
+}
+
+// ValidateKeyword는 알림 키워드로 쓸 수 있는지 확인합니다.
+// 글자 수는 바이트가 아닌 문자(rune) 단위로, 문자와 숫자만 셉니다.
+func ValidateKeyword(keyword string, minLength int) error {
+	letters := 0
+	for _, r := range keyword {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			letters++
+		}
+	}
+
+	switch {
+	case letters == 0:
+		return ErrKeywordNoLetters
+	case letters < minLength:
+		return ErrKeywordTooShort
+	case genericKeywords[NormalizeKeyword(keyword)]:
+		return ErrKeywordTooGeneric
+	}
+	return nil
+}
+
 // KeywordAlert는 키워드 기반 상품 알림을 나타냅니다
 type KeywordAlert struct {
-	ID          string `bson:"_id,omitempty"`
-	Keyword     string `bson:"keyword"`
-	UserID      string `bson:"user_id"`
-	Username    string `bson:"username"`
-	ChannelID   string `bson:"channel_id"`
-	GuildID     string `bson:"guild_id"`
-	CreatedAt   int64  `bson:"created_at"`
-	IsActive    bool   `bson:"is_active"`
-	LastNotified int64  `bson:"last_notified,omitempty"` // 마지막 알림 시간
-	NotifyCount  int    `bson:"notify_count,omitempty"`  // 알림 횟수
+	ID                string `bson:"_id,omitempty"`
+	Keyword           string `bson:"keyword"`
+	NormalizedKeyword string `bson:"normalized_keyword"` // 중복 검사용 키 (NormalizeKeyword)
+	UserID            string `bson:"user_id"`
+	Username          string `bson:"username"`
+	ChannelID         string `bson:"channel_id"`
+	GuildID           string `bson:"guild_id"`
+	CreatedAt         int64  `bson:"created_at"`
+	IsActive          bool   `bson:"is_active"`
+	LastNotified      int64  `bson:"last_notified"`              // 마지막 알림 시간 (Unix, 알림 전에는 0)
+	NotifyCount       int    `bson:"notify_count"`               // 알림 횟수 (AlertMatcher가 증가시킴)
+	WholeWord         bool   `bson:"whole_word,omitempty"`       // 단어 단위로만 일치
+	Language          string `bson:"language,omitempty"`         // 알림 언어 (비어 있으면 기본값)
+	Scope             string `bson:"scope,omitempty"`            // 알림 범위 (비어 있으면 AlertScopeUser)
+	RoleID            string `bson:"role_id,omitempty"`          // 설정되면 사용자 대신 이 역할을 멘션
+	QuietStart        string `bson:"quiet_start,omitempty"`      // 방해 금지 시작 시각 ("23:00")
+	QuietEnd          string `bson:"quiet_end,omitempty"`        // 방해 금지 종료 시각 ("08:00")
+	CooldownMinutes   int    `bson:"cooldown_minutes,omitempty"` // 재알림 최소 간격 (분, 0이면 기본값, CooldownOff면 간격 없음)
+	SummaryMode       string `bson:"summary_mode,omitempty"`     // 전송 방식 (비어 있으면 SummaryRealtime)
+	MaxPrice          int    `bson:"max_price,omitempty"`        // 최대 가격 (원, 0이면 제한 없음)
+	BestDeal          string `bson:"best_deal,omitempty"`        // 최저가 알림 방식 (BestDealPrice/BestDealDiscount, 비어 있으면 일치할 때마다 알림)
+	BestDealDate      string `bson:"best_deal_date,omitempty"`   // 최저가 알림을 마지막으로 보낸 날짜 (BestDealDateLayout)
+}
+
+// IsServerAlert는 서버 전체 알림인지 확인합니다.
+// 서버 알림의 UserID는 알림을 만든 관리자이며 멘션 대상이 아닙니다.
+func (k *KeywordAlert) IsServerAlert() bool {
+	return k.Scope == AlertScopeServer
 }
 
 // String은 알림의 문자열 표현을 반환합니다
@@ -23,16 +115,34 @@ func (k *KeywordAlert) String() string {
 	return "Alert for '" + k.Keyword + "' by <@" + k.UserID + ">"
 }
 
+// Matches는 알림 키워드가 상품과 일치하는지 확인합니다.
+// 크롤러의 KeywordIndex와 동일한 규칙(대소문자 무시, WholeWord 옵션)을 사용합니다.
+func (k *KeywordAlert) Matches(product *Product) bool {
+	return containsKeyword(product.SearchText(), strings.ToLower(k.Keyword), k.WholeWord)
+}
+
+// CleanKeyword는 표시용 키워드를 정리합니다: 앞뒤 공백을 제거하고 연속된 공백을 하나로 줄입니다
+func CleanKeyword(keyword string) string {
+	return strings.Join(strings.Fields(keyword), " ")
+}
+
+// NormalizeKeyword는 중복 검사용 키를 만듭니다.
+// 키워드 일치는 대소문자를 구분하지 않으므로 CleanKeyword 결과를 소문자로 바꿉니다.
+// 따라서 " rtx ", "rtx"와 "RTX"는 같은 키가 됩니다.
+func NormalizeKeyword(keyword string) string {
+	return strings.ToLower(CleanKeyword(keyword))
+}
+
 // KeywordExists는 사용자의 키워드 알림이 존재하는지 확인합니다
 func KeywordExists(alerts []*KeywordAlert, keyword, userID string) bool {
-	normalizedKeyword := strings.ToLower(strings.TrimSpace(keyword))
-	
+	normalizedKeyword := NormalizeKeyword(keyword)
+
 	for _, alert := range alerts {
-		if strings.ToLower(alert.Keyword) == normalizedKeyword && alert.UserID == userID {
+		if NormalizeKeyword(alert.Keyword) == normalizedKeyword && alert.UserID == userID {
 			return true
 		}
 	}
-	
+
 	return false
 }
 
@@ -40,12 +150,59 @@ func KeywordExists(alerts []*KeywordAlert, keyword, userID string) bool {
 func GetMatchingAlerts(alerts []*KeywordAlert, title string) []*KeywordAlert {
 	normalizedTitle := strings.ToLower(title)
 	var matching []*KeywordAlert
-	
+
 	for _, alert := range alerts {
-		if alert.IsActive && strings.Contains(normalizedTitle, strings.ToLower(alert.Keyword)) {
+		if alert.IsActive && containsKeyword(normalizedTitle, strings.ToLower(alert.Keyword), alert.WholeWord) {
 			matching = append(matching, alert)
 		}
 	}
-	
+
 	return matching
 }
+
+// containsKeyword는 소문자로 정규화된 text에 keyword가 포함되어 있는지 확인합니다
+func containsKeyword(text, keyword string, wholeWord bool) bool {
+	if !wholeWord {
+		return strings.Contains(text, keyword)
+	}
+
+	for offset := 0; offset <= len(text); {
+		i := strings.Index(text[offset:], keyword)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		if IsWholeWord(text, start, start+len(keyword)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + max(size, 1)
+	}
+	return false
+}
+
+// IsWholeWord는 text[start:end]가 단어 경계로 둘러싸여 있는지 확인합니다.
+//
+// 한국어는 띄어쓰기 없이 단어가 이어지는 경우가 많아 형태소 단위로 나눌 수 없으므로,
+// 문자열의 시작/끝, 공백, 문장부호, 기호만을 경계로 봅니다. 따라서 "tv"는 "TV", "4k-tv",
+// "tv(55인치)"와는 일치하지만 "retventure"나 "삼성tv"와는 일치하지 않습니다.
+func IsWholeWord(text string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(text[:start])
+		if !isWordBoundary(r) {
+			return false
+		}
+	}
+	if end < len(text) {
+		r, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordBoundary(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordBoundary는 단어 경계로 취급하는 문자인지 확인합니다
+func isWordBoundary(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNormalizeKeywordCollapsesVariants(t *testing.T) {
+	variants := []string{"rtx 4090", " rtx 4090 ", "RTX 4090", "rtx   4090", "\tRtx 4090\n"}
+
+	want := NormalizeKeyword(variants[0])
+	for _, keyword := range variants {
+		if got := NormalizeKeyword(keyword); got != want {
+			t.Errorf("NormalizeKeyword(%q) = %q, want %q", keyword, got, want)
+		}
+	}
+}
+
+func TestNormalizeKeywordKeepsDistinctKeywords(t *testing.T) {
+	if NormalizeKeyword("rtx4090") == NormalizeKeyword("rtx 4090") {
+		t.Error("keywords differing by a word break normalize to the same key")
+	}
+}
+
+func TestCleanKeywordKeepsCase(t *testing.T) {
+	if got := CleanKeyword("  RTX   4090 "); got != "RTX 4090" {
+		t.Errorf("CleanKeyword() = %q, want %q", got, "RTX 4090")
+	}
+}
+
+func TestKeywordExistsIgnoresSpacingAndCase(t *testing.T) {
+	alerts := []*KeywordAlert{{Keyword: "RTX 4090", UserID: "user-1"}}
+
+	if !KeywordExists(alerts, " rtx  4090 ", "user-1") {
+		t.Error("KeywordExists() missed a duplicate differing in spacing and case")
+	}
+	if KeywordExists(alerts, "rtx 4090", "user-2") {
+		t.Error("KeywordExists() matched another user's alert")
+	}
+}
+
+func TestValidateKeyword(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    error
+	}{
+		{"a", ErrKeywordTooShort},
+		{"원", ErrKeywordTooShort},
+		{"  !!  ", ErrKeywordNoLetters},
+		{"특가", ErrKeywordTooGeneric},
+		{" 핫딜 ", ErrKeywordTooGeneric},
+		{"SALE", ErrKeywordTooGeneric},
+		{"모니터", nil},
+		{"rtx", nil},
+		{"s24", nil},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateKeyword(tt.keyword, 2); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateKeyword(%q) = %v, want %v", tt.keyword, err, tt.want)
+		}
+	}
+}
+
+func TestKeywordAlertMatchesWholeWord(t *testing.T) {
+	tests := []struct {
+		title     string
+		wholeWord bool
+		want      bool
+	}{
+		{"삼성 TV 55인치", true, true},
+		{"LG 4k-tv 특가", true, true},
+		{"tv(55인치) 할인", true, true},
+		{"Retventure 캠핑의자", true, false},
+		{"삼성tv 55인치", true, false},
+		{"Retventure 캠핑의자", false, true},
+		{"삼성tv 55인치", false, true},
+	}
+
+	for _, tt := range tests {
+		alert := KeywordAlert{Keyword: "tv", WholeWord: tt.wholeWord}
+		if got := alert.Matches(&Product{Title: tt.title}); got != tt.want {
+			t.Errorf("Matches(%q) with whole word %v = %v, want %v", tt.title, tt.wholeWord, got, tt.want)
+		}
+	}
+}
+
+func TestKeywordAlertDecodesNotificationStats(t *testing.T) {
+	// The document as AlertMatcher leaves it: $inc stores notify_count as int32
+	raw, err := bson.Marshal(bson.D{
+		{Key: "keyword", Value: "RTX 4090"},
+		{Key: "user_id", Value: "user-1"},
+		{Key: "created_at", Value: int64(1760000000)},
+		{Key: "is_active", Value: true},
+		{Key: "notify_count", Value: int32(7)},
+		{Key: "last_notified", Value: int64(1760600000)},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal document: %v", err)
+	}
+
+	var alert KeywordAlert
+	if err := bson.Unmarshal(raw, &alert); err != nil {
+		t.Fatalf("failed to decode alert: %v", err)
+	}
+	if alert.NotifyCount != 7 || alert.LastNotified != 1760600000 || alert.CreatedAt != 1760000000 {
+		t.Errorf("decoded NotifyCount = %d, LastNotified = %d, CreatedAt = %d, want 7, 1760600000, 1760000000",
+			alert.NotifyCount, alert.LastNotified, alert.CreatedAt)
+	}
+}
+
+func TestKeywordAlertRoundTrip(t *testing.T) {
+	alerts := []KeywordAlert{
+		{Keyword: "모니터", UserID: "user-1", CreatedAt: 1760000000, IsActive: true},
+		{Keyword: "모니터", UserID: "user-1", CreatedAt: 1760000000, IsActive: true, NotifyCount: 3, LastNotified: 1760600000},
+	}
+
+	for _, alert := range alerts {
+		raw, err := bson.Marshal(alert)
+		if err != nil {
+			t.Fatalf("failed to marshal alert: %v", err)
+		}
+		// A new alert still stores its stats, so readers never see them missing
+		for _, key := range []string{"notify_count", "last_notified"} {
+			if _, err := bson.Raw(raw).LookupErr(key); err != nil {
+				t.Errorf("marshaled alert has no %s", key)
+			}
+		}
+
+		var decoded KeywordAlert
+		if err := bson.Unmarshal(raw, &decoded); err != nil {
+			t.Fatalf("failed to decode alert: %v", err)
+		}
+		if decoded.NotifyCount != alert.NotifyCount || decoded.LastNotified != alert.LastNotified {
+			t.Errorf("round trip = %d, %d, want %d, %d", decoded.NotifyCount, decoded.LastNotified, alert.NotifyCount, alert.LastNotified)
+		}
+	}
+}
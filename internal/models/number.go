@@ -0,0 +1,67 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// koreanUnits are the Korean large number units, largest first
+var koreanUnits = []struct {
+	name  string
+	value uint64
+}{
+	{"경", 1e16},
+	{"조", 1e12},
+	{"억", 1e8},
+	{"만", 1e4},
+}
+
+// formatNumber formats a number with thousands separators, e.g. -1234567 → "-1,234,567"
+func formatNumber(n int) string {
+	sign, abs := splitSign(n)
+	return sign + groupThousands(strconv.FormatUint(abs, 10))
+}
+
+// FormatKoreanNumber formats a number with Korean 만/억/조 grouping, each
+// group with thousands separators, e.g. 123456789 → "1억 2,345만 6,789" and
+// 500000 → "50만". Empty groups are left out.
+func FormatKoreanNumber(n int) string {
+	sign, abs := splitSign(n)
+	if abs < 1e4 {
+		return sign + groupThousands(strconv.FormatUint(abs, 10))
+	}
+
+	var parts []string
+	for _, unit := range koreanUnits {
+		if abs >= unit.value {
+			parts = append(parts, groupThousands(strconv.FormatUint(abs/unit.value, 10))+unit.name)
+			abs %= unit.value
+		}
+	}
+	if abs > 0 {
+		parts = append(parts, groupThousands(strconv.FormatUint(abs, 10)))
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// splitSign returns the sign of n ("-" or "") and its absolute value.
+// The absolute value is unsigned so the smallest int doesn't overflow.
+func splitSign(n int) (string, uint64) {
+	if n < 0 {
+		return "-", -uint64(n)
+	}
+	return "", uint64(n)
+}
+
+// groupThousands inserts a comma every three digits from the right of a run of digits
+func groupThousands(digits string) string {
+	var b strings.Builder
+	b.Grow(len(digits) + (len(digits)-1)/3)
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n          int
+		want       string
+		wantKorean string
+	}{
+		{0, "0", "0"},
+		{7, "7", "7"},
+		{999, "999", "999"},
+		{1000, "1,000", "1,000"},
+		{9999, "9,999", "9,999"},
+		{10000, "10,000", "1만"},
+		{500000, "500,000", "50만"},
+		{1234567, "1,234,567", "123만 4,567"},
+		{12345678, "12,345,678", "1,234만 5,678"},
+		{100000000, "100,000,000", "1억"},
+		{123456789, "123,456,789", "1억 2,345만 6,789"},
+		{1000000001, "1,000,000,001", "10억 1"},
+		{2000000000000, "2,000,000,000,000", "2조"},
+		{-1, "-1", "-1"},
+		{-1000, "-1,000", "-1,000"},
+		{-123456, "-123,456", "-12만 3,456"},
+		{-100000000, "-100,000,000", "-1억"},
+		{math.MinInt64, "-9,223,372,036,854,775,808", "-922경 3,372조 368억 5,477만 5,808"},
+	}
+
+	for _, tt := range tests {
+		if got := formatNumber(tt.n); got != tt.want {
+			t.Errorf("formatNumber(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+		if got := FormatKoreanNumber(tt.n); got != tt.wantKorean {
+			t.Errorf("FormatKoreanNumber(%d) = %q, want %q", tt.n, got, tt.wantKorean)
+		}
+	}
+}
+
+func TestGetPriceString(t *testing.T) {
+	tests := []struct {
+		name    string
+		product Product
+		want    string
+	}{
+		{"formatted", Product{PriceString: "29,900원", KOPrice: 29900}, "29,900원"},
+		{"won", Product{KOPrice: 29900}, "29,900 KRW"},
+		{"over 100,000,000 won", Product{KOPrice: 123456789}, "123,456,789 KRW"},
+		{"dollars", Product{USPrice: 19.5}, "$19.50 USD"},
+		{"unknown", Product{}, "Price unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.product.GetPriceString(); got != tt.want {
+				t.Errorf("GetPriceString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
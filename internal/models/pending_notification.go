@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingNotification은 방해 금지 시간대 때문에 보류된 알림입니다.
+// 재시작 후에도 유실되지 않도록 MongoDB에 저장되며, DeliverAt 이후에 발송됩니다.
+type PendingNotification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	AlertID   string             `bson:"alert_id" json:"alert_id"`
+	Product   Product            `bson:"product" json:"product"`
+	DeliverAt time.Time          `bson:"deliver_at" json:"deliver_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NewPendingNotification은 새로운 보류 알림을 생성합니다
+func NewPendingNotification(alert KeywordAlert, product Product, deliverAt time.Time) *PendingNotification {
+	return &PendingNotification{
+		AlertID:   alert.ID,
+		Product:   product,
+		DeliverAt: deliverAt,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Poll은 채널에 게시된 투표를 나타냅니다.
+// 투표는 메시지 리액션으로 이루어지므로 옵션 순서가 리액션 이모지 순서와 일치합니다.
+type Poll struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	MessageID string             `bson:"message_id" json:"message_id"`
+	ChannelID string             `bson:"channel_id" json:"channel_id"`
+	GuildID   string             `bson:"guild_id,omitempty" json:"guild_id,omitempty"`
+	AuthorID  string             `bson:"author_id" json:"author_id"`
+	Question  string             `bson:"question" json:"question"`
+	Options   []string           `bson:"options" json:"options"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// NewPoll은 새로운 투표를 생성합니다
+func NewPoll(messageID, channelID, guildID, authorID, question string, options []string) *Poll {
+	return &Poll{
+		MessageID: messageID,
+		ChannelID: channelID,
+		GuildID:   guildID,
+		AuthorID:  authorID,
+		Question:  question,
+		Options:   options,
+		CreatedAt: time.Now(),
+	}
+}
@@ -1,8 +1,10 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,7 +15,8 @@ type Product struct {
 	Website       string    `bson:"website"`
 	Product       string    `bson:"product"`
 	Category      string    `bson:"category"`
-	URL           string    `bson:"url"`
+	URL           string    `bson:"url"`                    // 정규화된 URL (CanonicalizeURL)
+	OriginalURL   string    `bson:"original_url,omitempty"` // 정규화 전 URL (다를 때만)
 	KOPrice       int       `bson:"ko_price,omitempty"`
 	USPrice       float64   `bson:"us_price,omitempty"`
 	PriceString   string    `bson:"price_string,omitempty"`
@@ -21,15 +24,34 @@ type Product struct {
 	UploadSite    string    `bson:"upload_site,omitempty"`
 	Comments      int       `bson:"comments,omitempty"`
 	Views         int       `bson:"views,omitempty"`
-	CrawledAt     time.Time `bson:"crawled_at,omitempty"`
+	CrawledAt     time.Time `bson:"crawled_at,omitempty"`    // 처음 발견한 시간
+	FirstSeenAt   time.Time `bson:"first_seen_at,omitempty"` // 처음 저장한 시간 (이후 바뀌지 않음)
+	LastSeenAt    time.Time `bson:"last_seen_at,omitempty"`  // 마지막으로 크롤링에서 본 시간
+	Expired       bool      `bson:"expired,omitempty"`       // 여러 번 연속으로 크롤링에서 보이지 않아 만료됨
 	Source        string    `bson:"source,omitempty"`
-	ImageURL      string    `bson:"image_url,omitempty"`     // 상품 이미지 URL
-	IsHot         bool      `bson:"is_hot,omitempty"`        // 인기 상품 여부
-	Rating        float64   `bson:"rating,omitempty"`        // 평점 (있는 경우)
-	DiscountRate  int       `bson:"discount_rate,omitempty"` // 할인율 (%)
-	OriginalPrice int       `bson:"original_price,omitempty"`// 원래 가격
-	Notified      bool      `bson:"notified"`                // 알림 발송 여부
-	Keywords      []string  `bson:"keywords,omitempty"`      // 매칭된 키워드 목록
+	ImageURL      string    `bson:"image_url,omitempty"`      // 상품 이미지 URL
+	IsHot         bool      `bson:"is_hot,omitempty"`         // 인기 상품 여부
+	Rating        float64   `bson:"rating,omitempty"`         // 평점 (있는 경우)
+	DiscountRate  int       `bson:"discount_rate,omitempty"`  // 할인율 (%)
+	OriginalPrice int       `bson:"original_price,omitempty"` // 원래 가격
+	SoldOut       bool      `bson:"sold_out,omitempty"`       // 품절/종료 여부 (소스가 표시하는 경우)
+	Notified      bool      `bson:"notified"`                 // 알림 발송 여부
+	Keywords      []string  `bson:"keywords,omitempty"`       // 매칭된 키워드 목록
+}
+
+// ProductIDFromURL derives a product ID from the product URL, so the same deal
+// always gets the same ID and products, price history and alert matches can be
+// joined on it. The ID is 24 hex characters, the same shape as an ObjectID hex.
+func ProductIDFromURL(url string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(url)))
+	return hex.EncodeToString(sum[:12])
+}
+
+// MeetsHotThreshold reports whether the product's engagement reaches either
+// threshold. A non-positive threshold is ignored.
+func (p *Product) MeetsHotThreshold(minComments, minViews int) bool {
+	return (minComments > 0 && p.Comments >= minComments) ||
+		(minViews > 0 && p.Views >= minViews)
 }
 
 // GetPriceString returns a formatted price string
@@ -38,7 +60,7 @@ func (p *Product) GetPriceString() string {
 	if p.PriceString != "" {
 		return p.PriceString
 	}
-	
+
 	// Otherwise, format based on the price values
 	if p.KOPrice > 0 {
 		return fmt.Sprintf("%s KRW", formatNumber(p.KOPrice))
@@ -53,18 +75,14 @@ func (p *Product) String() string {
 	return fmt.Sprintf("%s (%s) from %s", p.Product, p.GetPriceString(), p.Website)
 }
 
-// formatNumber formats a number with commas
-func formatNumber(n int) string {
-	in := strconv.FormatInt(int64(n), 10)
-	out := make([]byte, 0, len(in)+(len(in)-1)/3)
-	
-	// Add commas
-	for i, c := range in {
-		if i > 0 && (len(in)-i)%3 == 0 {
-			out = append(out, ',')
-		}
-		out = append(out, byte(c))
+// SearchText returns the lowercased text that alert keywords are matched against
+func (p *Product) SearchText() string {
+	searchText := strings.ToLower(p.Title)
+	if p.Product != "" && p.Product != p.Title {
+		searchText += " " + strings.ToLower(p.Product)
+	}
+	if p.Category != "" {
+		searchText += " " + strings.ToLower(p.Category)
 	}
-	
-	return string(out)
-}
\ No newline at end of file
+	return searchText
+}
@@ -0,0 +1,47 @@
+package models
+
+// Diff가 보고하는 상품 필드
+const (
+	ProductFieldPrice    = "price"    // KOPrice, USPrice
+	ProductFieldComments = "comments" // Comments
+	ProductFieldSoldOut  = "sold_out" // SoldOut
+	ProductFieldDiscount = "discount" // DiscountRate, OriginalPrice
+)
+
+// Diff는 같은 특가를 다시 크롤링했을 때 other와 비교해 바뀐 필드를 반환합니다.
+// 조회수처럼 크롤링할 때마다 바뀌는 필드와 CrawledAt, 알림 기록 같은 관리용 필드는 비교하지 않습니다.
+func (p *Product) Diff(other *Product) []string {
+	var changed []string
+	if p.KOPrice != other.KOPrice || p.USPrice != other.USPrice {
+		changed = append(changed, ProductFieldPrice)
+	}
+	if p.Comments != other.Comments {
+		changed = append(changed, ProductFieldComments)
+	}
+	if p.SoldOut != other.SoldOut {
+		changed = append(changed, ProductFieldSoldOut)
+	}
+	if p.DiscountRate != other.DiscountRate || p.OriginalPrice != other.OriginalPrice {
+		changed = append(changed, ProductFieldDiscount)
+	}
+	return changed
+}
+
+// Equal은 Diff 기준으로 바뀐 필드가 없는지 확인합니다
+func (p *Product) Equal(other *Product) bool {
+	return len(p.Diff(other)) == 0
+}
+
+// IsNotableChange는 바뀐 필드가 다시 알릴 만한 변화인지 확인합니다.
+// 가격이나 할인이 바뀐 경우만 해당하며, 댓글 수 변화나 품절된 특가는 알리지 않습니다.
+func (p *Product) IsNotableChange(changed []string) bool {
+	if p.SoldOut {
+		return false
+	}
+	for _, field := range changed {
+		if field == ProductFieldPrice || field == ProductFieldDiscount {
+			return true
+		}
+	}
+	return false
+}
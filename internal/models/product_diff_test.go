@@ -0,0 +1,71 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProductDiff(t *testing.T) {
+	stored := Product{
+		Title:         "27인치 모니터",
+		URL:           "https://example.com/deal/1",
+		KOPrice:       300000,
+		OriginalPrice: 400000,
+		DiscountRate:  25,
+		Comments:      3,
+		Views:         120,
+		CrawledAt:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		change func(p *Product)
+		want   []string
+	}{
+		{"identical", func(p *Product) {}, nil},
+		{"only crawled again", func(p *Product) { p.CrawledAt = time.Now(); p.Views = 500 }, nil},
+		{"price", func(p *Product) { p.KOPrice = 250000 }, []string{ProductFieldPrice}},
+		{"comments", func(p *Product) { p.Comments = 10 }, []string{ProductFieldComments}},
+		{"sold out", func(p *Product) { p.SoldOut = true }, []string{ProductFieldSoldOut}},
+		{"price and discount", func(p *Product) { p.KOPrice = 200000; p.DiscountRate = 50 }, []string{ProductFieldPrice, ProductFieldDiscount}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crawled := stored
+			tt.change(&crawled)
+
+			if got := crawled.Diff(&stored); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+			if got, want := crawled.Equal(&stored), len(tt.want) == 0; got != want {
+				t.Errorf("Equal() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestProductIsNotableChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		soldOut bool
+		changed []string
+		want    bool
+	}{
+		{"unchanged", false, nil, false},
+		{"price", false, []string{ProductFieldPrice}, true},
+		{"discount", false, []string{ProductFieldDiscount}, true},
+		{"comments", false, []string{ProductFieldComments}, false},
+		{"sold out", true, []string{ProductFieldPrice, ProductFieldSoldOut}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Product{SoldOut: tt.soldOut}
+			if got := p.IsNotableChange(tt.changed); got != tt.want {
+				t.Errorf("IsNotableChange(%v) = %v, want %v", tt.changed, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestProductIDFromURL(t *testing.T) {
+	url := "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123456"
+
+	id := ProductIDFromURL(url)
+	if again := ProductIDFromURL(url); again != id {
+		t.Errorf("ProductIDFromURL() = %q then %q for the same URL", id, again)
+	}
+	if padded := ProductIDFromURL("  " + url + "\n"); padded != id {
+		t.Errorf("ProductIDFromURL() with surrounding space = %q, want %q", padded, id)
+	}
+	if other := ProductIDFromURL("https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123457"); other == id {
+		t.Errorf("different URLs both got ID %q", id)
+	}
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		t.Errorf("ID %q isn't shaped like an ObjectID hex: %v", id, err)
+	}
+}
+
+func TestMeetsHotThreshold(t *testing.T) {
+	p := Product{Comments: 30, Views: 5000}
+
+	tests := []struct {
+		name        string
+		minComments int
+		minViews    int
+		want        bool
+	}{
+		{"comments reached", 30, 0, true},
+		{"views reached", 0, 5000, true},
+		{"either reached", 100, 1000, true},
+		{"neither reached", 31, 5001, false},
+		{"thresholds off", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := p.MeetsHotThreshold(tt.minComments, tt.minViews); got != tt.want {
+			t.Errorf("%s: MeetsHotThreshold(%d, %d) = %v, want %v", tt.name, tt.minComments, tt.minViews, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseQuietHours는 "23:00-08:00" 형식의 방해 금지 시간대를 시작/종료 시각으로 나눕니다.
+// 종료 시각이 시작 시각보다 이르면 자정을 넘는 시간대입니다.
+func ParseQuietHours(s string) (start, end string, err error) {
+	start, end, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return "", "", fmt.Errorf("invalid quiet hours %q", s)
+	}
+
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if _, err := clockMinutes(start); err != nil {
+		return "", "", err
+	}
+	if _, err := clockMinutes(end); err != nil {
+		return "", "", err
+	}
+	if start == end {
+		return "", "", fmt.Errorf("quiet hours %q must not start and end at the same time", s)
+	}
+
+	return start, end, nil
+}
+
+// HasQuietHours는 알림에 방해 금지 시간대가 설정되어 있는지 확인합니다
+func (k *KeywordAlert) HasQuietHours() bool {
+	return k.QuietStart != "" && k.QuietEnd != ""
+}
+
+// InQuietHours는 t가 알림의 방해 금지 시간대에 속하는지 확인합니다.
+// t는 방해 금지 시간대를 해석할 시간대(Location)로 변환되어 있어야 합니다.
+func (k *KeywordAlert) InQuietHours(t time.Time) bool {
+	if !k.HasQuietHours() {
+		return false
+	}
+
+	start, err := clockMinutes(k.QuietStart)
+	if err != nil {
+		return false
+	}
+	end, err := clockMinutes(k.QuietEnd)
+	if err != nil {
+		return false
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	// 자정을 넘는 시간대 (예: 23:00-08:00)
+	return now >= start || now < end
+}
+
+// QuietHoursEnd는 t 이후 처음으로 방해 금지 시간대가 끝나는 시각을 반환합니다.
+// t가 방해 금지 시간대가 아니면 t를 그대로 반환합니다.
+func (k *KeywordAlert) QuietHoursEnd(t time.Time) time.Time {
+	if !k.InQuietHours(t) {
+		return t
+	}
+
+	end, _ := clockMinutes(k.QuietEnd)
+	next := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// clockMinutes는 "15:04" 형식의 시각을 자정 이후 분으로 변환합니다
+func clockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
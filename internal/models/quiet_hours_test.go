@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	start, end, err := ParseQuietHours(" 23:00 - 08:00 ")
+	if err != nil || start != "23:00" || end != "08:00" {
+		t.Errorf("ParseQuietHours() = %q, %q, %v, want 23:00 and 08:00", start, end, err)
+	}
+
+	for _, s := range []string{"23:00", "23:00-23:00", "25:00-08:00", "23:00-8", "밤-아침"} {
+		if _, _, err := ParseQuietHours(s); err == nil {
+			t.Errorf("ParseQuietHours(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, kst)
+	}
+	overnight := KeywordAlert{QuietStart: "23:00", QuietEnd: "08:00"}
+	daytime := KeywordAlert{QuietStart: "09:00", QuietEnd: "18:00"}
+
+	tests := []struct {
+		name    string
+		alert   KeywordAlert
+		now     time.Time
+		inQuiet bool
+		end     time.Time
+	}{
+		{"before an overnight window", overnight, at(16, 22, 59), false, at(16, 22, 59)},
+		{"start of an overnight window", overnight, at(16, 23, 0), true, at(17, 8, 0)},
+		{"after midnight", overnight, at(17, 3, 0), true, at(17, 8, 0)},
+		{"end of an overnight window", overnight, at(17, 8, 0), false, at(17, 8, 0)},
+		{"inside a daytime window", daytime, at(16, 12, 0), true, at(16, 18, 0)},
+		{"outside a daytime window", daytime, at(16, 20, 0), false, at(16, 20, 0)},
+		{"no quiet hours", KeywordAlert{}, at(16, 3, 0), false, at(16, 3, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alert.InQuietHours(tt.now); got != tt.inQuiet {
+				t.Errorf("InQuietHours() = %v, want %v", got, tt.inQuiet)
+			}
+			if got := tt.alert.QuietHoursEnd(tt.now); !got.Equal(tt.end) {
+				t.Errorf("QuietHoursEnd() = %v, want %v", got, tt.end)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reminder는 사용자가 예약한 알림 메시지를 나타냅니다
+type Reminder struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	ChannelID string             `bson:"channel_id" json:"channel_id"`
+	GuildID   string             `bson:"guild_id,omitempty" json:"guild_id,omitempty"`
+	Message   string             `bson:"message" json:"message"`
+	FireAt    time.Time          `bson:"fire_at" json:"fire_at"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	Fired     bool               `bson:"fired" json:"fired"`
+	Attempts  int                `bson:"attempts,omitempty" json:"attempts,omitempty"` // 실패한 발송 시도 횟수
+	Failed    bool               `bson:"failed,omitempty" json:"failed,omitempty"`     // 발송을 포기한 리마인더 (fired와 함께 설정)
+}
+
+const (
+	// ReminderMaxAttempts는 발송을 포기하기 전까지 리마인더를 보내 보는 최대 횟수입니다
+	ReminderMaxAttempts = 5
+	// reminderRetryBase와 reminderRetryMax는 재시도 간격의 처음 값과 최대값입니다
+	reminderRetryBase = time.Minute
+	reminderRetryMax  = time.Hour
+)
+
+// ReminderRetryDelay는 attempts번 실패한 리마인더를 다시 보내기까지 기다릴 시간입니다.
+// 1분부터 실패할 때마다 두 배로 늘어나며 1시간을 넘지 않습니다.
+func ReminderRetryDelay(attempts int) time.Duration {
+	delay := reminderRetryBase
+	for i := 1; i < attempts && delay < reminderRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > reminderRetryMax {
+		delay = reminderRetryMax
+	}
+	return delay
+}
+
+// NewReminder는 새로운 리마인더를 생성합니다
+func NewReminder(userID, channelID, guildID, message string, fireAt time.Time) *Reminder {
+	return &Reminder{
+		UserID:    userID,
+		ChannelID: channelID,
+		GuildID:   guildID,
+		Message:   message,
+		FireAt:    fireAt,
+		CreatedAt: time.Now(),
+	}
+}
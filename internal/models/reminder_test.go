@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{7, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := ReminderRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("ReminderRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
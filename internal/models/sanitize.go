@@ -0,0 +1,40 @@
+package models
+
+import (
+	"strings"
+)
+
+// zeroWidthSpace는 멘션 토큰을 무력화하기 위해 삽입하는 문자입니다
+const zeroWidthSpace = "\u200b"
+
+// discordEscaper는 Discord 마크다운 특수문자를 이스케이프합니다
+var discordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+	"[", `\[`,
+	"]", `\]`,
+)
+
+// mentionNeutralizer는 멘션으로 해석될 수 있는 토큰 사이에 폭 없는 공백을 넣습니다
+var mentionNeutralizer = strings.NewReplacer(
+	"@everyone", "@"+zeroWidthSpace+"everyone",
+	"@here", "@"+zeroWidthSpace+"here",
+	"<@", "<"+zeroWidthSpace+"@",
+	"<#", "<"+zeroWidthSpace+"#",
+)
+
+// EscapeDiscord는 외부에서 들어온 문자열을 Discord 메시지에 그대로 보이도록 변환합니다.
+// 마크다운 서식 문자를 이스케이프하고 @everyone/@here, 사용자/역할/채널 멘션 토큰을 무력화합니다.
+func EscapeDiscord(s string) string {
+	return mentionNeutralizer.Replace(discordEscaper.Replace(s))
+}
+
+// SanitizeTitle은 크롤링한 상품 제목의 공백을 정리하고 Discord 표시용으로 이스케이프합니다
+func SanitizeTitle(title string) string {
+	return EscapeDiscord(strings.Join(strings.Fields(title), " "))
+}
@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestSanitizeTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"**초특가** 모니터", `\*\*초특가\*\* 모니터`},
+		{"`rm -rf` 키보드", "\\`rm -rf\\` 키보드"},
+		{"@everyone 공짜", "@\u200beveryone 공짜"},
+		{"@here 선착순", "@\u200bhere 선착순"},
+		{"<@123> <@&456> <#789>", "<\u200b@123\\> <\u200b@&456\\> <\u200b#789\\>"},
+		{"[광고](https://evil.example) ~~품절~~", `\[광고\](https://evil.example) \~\~품절\~\~`},
+		{"  27인치\n\t모니터  ", "27인치 모니터"},
+		{`C:\temp`, `C:\\temp`},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeTitle(tt.title); got != tt.want {
+			t.Errorf("SanitizeTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// shortLinkAlphabet은 짧은 링크 토큰에 사용하는 문자입니다 (URL에 그대로 쓸 수 있는 base62)
+const shortLinkAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ShortLinkTokenLength는 짧은 링크 토큰의 길이입니다 (62^8 ≈ 2.2×10^14 가지)
+const ShortLinkTokenLength = 8
+
+// ShortLink는 클릭 추적용 짧은 링크와 실제 상품 URL의 연결을 나타냅니다
+type ShortLink struct {
+	Token        string    `bson:"_id" json:"token"`
+	URL          string    `bson:"url" json:"url"`
+	ProductTitle string    `bson:"product_title,omitempty" json:"product_title,omitempty"`
+	Clicks       int       `bson:"clicks" json:"clicks"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Click은 짧은 링크를 통한 상품 클릭 기록을 나타냅니다
+type Click struct {
+	Token     string    `bson:"token" json:"token"`
+	URL       string    `bson:"url" json:"url"`
+	UserAgent string    `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Referer   string    `bson:"referer,omitempty" json:"referer,omitempty"`
+	ClickedAt time.Time `bson:"clicked_at" json:"clicked_at"`
+}
+
+// NewShortLinkToken은 암호학적으로 안전한 무작위 토큰을 생성합니다
+func NewShortLinkToken() (string, error) {
+	max := big.NewInt(int64(len(shortLinkAlphabet)))
+	token := make([]byte, ShortLinkTokenLength)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate short link token: %w", err)
+		}
+		token[i] = shortLinkAlphabet[n.Int64()]
+	}
+	return string(token), nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewShortLinkToken(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		token, err := NewShortLinkToken()
+		if err != nil {
+			t.Fatalf("NewShortLinkToken() error = %v", err)
+		}
+		if len(token) != ShortLinkTokenLength {
+			t.Fatalf("token %q has length %d, want %d", token, len(token), ShortLinkTokenLength)
+		}
+		if strings.Trim(token, shortLinkAlphabet) != "" {
+			t.Fatalf("token %q has characters outside the base62 alphabet", token)
+		}
+		if seen[token] {
+			t.Fatalf("token %q generated twice", token)
+		}
+		seen[token] = true
+	}
+}
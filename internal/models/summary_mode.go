@@ -0,0 +1,32 @@
+package models
+
+import "strings"
+
+// 알림 전송 방식
+const (
+	// SummaryRealtime은 일치하는 특가가 올라올 때마다 바로 알리는 기본 방식입니다
+	SummaryRealtime = "realtime"
+	// SummaryDaily는 하루 동안 일치한 특가를 모아 매일 한 번 DM으로 보냅니다
+	SummaryDaily = "daily"
+	// SummaryWeekly는 일주일 동안 일치한 특가를 모아 매주 한 번 DM으로 보냅니다
+	SummaryWeekly = "weekly"
+)
+
+// ParseSummaryMode는 명령어 인자를 알림 전송 방식으로 바꿉니다
+func ParseSummaryMode(s string) (string, bool) {
+	switch strings.ToLower(s) {
+	case SummaryRealtime, "실시간":
+		return SummaryRealtime, true
+	case SummaryDaily, "매일":
+		return SummaryDaily, true
+	case SummaryWeekly, "매주":
+		return SummaryWeekly, true
+	}
+	return "", false
+}
+
+// IsSummarized는 알림이 실시간 대신 요약 DM으로 전달되는지 확인합니다.
+// 서버 알림은 DM을 받을 사용자가 없으므로 항상 실시간입니다.
+func (k *KeywordAlert) IsSummarized() bool {
+	return !k.IsServerAlert() && (k.SummaryMode == SummaryDaily || k.SummaryMode == SummaryWeekly)
+}
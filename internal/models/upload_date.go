@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uploadDateClockSkew는 오늘 시각이 현재보다 이만큼 미래여도 오늘로 취급하는 허용 오차입니다
+const uploadDateClockSkew = 5 * time.Minute
+
+// ParseUploadDate는 게시판 목록의 작성일 문자열을 now 기준으로 해석합니다.
+// 결과는 now와 같은 시간대(Location)를 사용합니다.
+//
+// 지원 형식:
+//   - 오늘 작성된 글의 시각: "12:34", "12:34:56" (now보다 미래면 어제)
+//   - 올해 작성된 글의 날짜: "05.01", "05/01" (now보다 미래면 작년, 예: 1월에 본 "12.30")
+//   - 연도가 포함된 날짜: "24.05.01", "24/05/01", "2024.05.01", "2024-05-01"
+func ParseUploadDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := now.Location()
+
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return time.Time{}, fmt.Errorf("invalid upload time %q", s)
+		}
+		nums, err := parseDateNumbers(parts)
+		if err != nil || nums[0] > 23 || nums[1] > 59 || (len(nums) == 3 && nums[2] > 59) {
+			return time.Time{}, fmt.Errorf("invalid upload time %q", s)
+		}
+		sec := 0
+		if len(nums) == 3 {
+			sec = nums[2]
+		}
+
+		t := time.Date(now.Year(), now.Month(), now.Day(), nums[0], nums[1], sec, 0, loc)
+		if t.After(now.Add(uploadDateClockSkew)) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t, nil
+	}
+
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '.' || r == '/' || r == '-'
+	})
+	nums, err := parseDateNumbers(parts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid upload date %q", s)
+	}
+
+	var year, month, day int
+	switch len(nums) {
+	case 2:
+		year, month, day = now.Year(), nums[0], nums[1]
+	case 3:
+		year, month, day = nums[0], nums[1], nums[2]
+		if year < 100 {
+			year += 2000
+		}
+	default:
+		return time.Time{}, fmt.Errorf("invalid upload date %q", s)
+	}
+
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("invalid upload date %q", s)
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	if t.Month() != time.Month(month) {
+		// 2월 30일처럼 존재하지 않는 날짜
+		return time.Time{}, fmt.Errorf("invalid upload date %q", s)
+	}
+
+	// 연도 없는 날짜가 미래라면 작년 글 (연말 → 연초 넘어감)
+	if len(nums) == 2 && t.After(now) {
+		t = t.AddDate(-1, 0, 0)
+	}
+
+	return t, nil
+}
+
+// parseDateNumbers는 숫자로만 이루어진 날짜/시각 구성요소를 정수로 변환합니다
+func parseDateNumbers(parts []string) ([]int, error) {
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		if part == "" || len(part) > 4 {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUploadDate(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	now := time.Date(2026, time.October, 16, 14, 0, 0, 0, kst)
+	newYear := time.Date(2027, time.January, 2, 9, 0, 0, 0, kst)
+
+	tests := []struct {
+		name string
+		s    string
+		now  time.Time
+		want time.Time
+	}{
+		{"time today", "12:34", now, time.Date(2026, time.October, 16, 12, 34, 0, 0, kst)},
+		{"time with seconds", "12:34:56", now, time.Date(2026, time.October, 16, 12, 34, 56, 0, kst)},
+		{"time later than now is yesterday", "23:10", now, time.Date(2026, time.October, 15, 23, 10, 0, 0, kst)},
+		{"time within the clock skew", "14:03", now, time.Date(2026, time.October, 16, 14, 3, 0, 0, kst)},
+		{"month and day", "05.01", now, time.Date(2026, time.May, 1, 0, 0, 0, 0, kst)},
+		{"month and day with slash", "10/15", now, time.Date(2026, time.October, 15, 0, 0, 0, 0, kst)},
+		{"december seen in january", "12.30", newYear, time.Date(2026, time.December, 30, 0, 0, 0, 0, kst)},
+		{"january seen in january", "01.01", newYear, time.Date(2027, time.January, 1, 0, 0, 0, 0, kst)},
+		{"short year", "24.05.01", now, time.Date(2024, time.May, 1, 0, 0, 0, 0, kst)},
+		{"short year with slash", "24/05/01", now, time.Date(2024, time.May, 1, 0, 0, 0, 0, kst)},
+		{"full year", "2024.05.01", now, time.Date(2024, time.May, 1, 0, 0, 0, 0, kst)},
+		{"iso date", " 2024-05-01 ", now, time.Date(2024, time.May, 1, 0, 0, 0, 0, kst)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUploadDate(tt.s, tt.now)
+			if err != nil {
+				t.Fatalf("ParseUploadDate(%q) error = %v", tt.s, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseUploadDate(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUploadDateInvalid(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 14, 0, 0, 0, time.UTC)
+
+	for _, s := range []string{"", "어제", "24:00", "12:60", "1:2:3:4", "13.01", "02.30", "05", "a.b", "2024.05.01.01"} {
+		if got, err := ParseUploadDate(s, now); err == nil {
+			t.Errorf("ParseUploadDate(%q) = %v, want an error", s, got)
+		}
+	}
+}
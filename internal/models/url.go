@@ -0,0 +1,100 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams는 링크의 대상과 무관한 추적용 쿼리 파라미터입니다
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"igshid":  true,
+	"yclid":   true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"ref":     true,
+	"ref_src": true,
+}
+
+// isTrackingParam은 쿼리 파라미터가 추적용인지 확인합니다
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return trackingParams[key] || strings.HasPrefix(key, "utm_")
+}
+
+// URLParamPolicy는 소스별로 정규화 시 남길 쿼리 파라미터를 정합니다.
+// Keep이 비어 있지 않으면 Keep에 있는 파라미터만 남기고(글을 찾는 데 필요한 파라미터),
+// 비어 있으면 추적용 파라미터와 Drop에 있는 파라미터를 제거합니다.
+type URLParamPolicy struct {
+	Keep []string
+	Drop []string
+}
+
+// keeps는 정책에 따라 쿼리 파라미터를 남겨야 하는지 확인합니다
+func (p URLParamPolicy) keeps(key string) bool {
+	if len(p.Keep) > 0 {
+		for _, k := range p.Keep {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	if isTrackingParam(key) {
+		return false
+	}
+	for _, k := range p.Drop {
+		if k == key {
+			return false
+		}
+	}
+	return true
+}
+
+// CanonicalizeURL은 같은 글을 가리키는 URL들이 하나의 문자열이 되도록 정규화합니다.
+// 스킴과 호스트를 소문자로 바꾸고 기본 포트, 프래그먼트, 경로 끝의 슬래시와
+// 추적용 쿼리 파라미터를 제거한 뒤 남은 쿼리를 키 순서로 정렬합니다.
+// 파싱할 수 없는 URL은 앞뒤 공백만 제거해 반환합니다.
+func CanonicalizeURL(raw string) string {
+	return CanonicalizeURLWith(raw, URLParamPolicy{})
+}
+
+// CanonicalizeURLWith는 CanonicalizeURL과 같지만 쿼리 파라미터를 소스의 정책에 따라 거릅니다
+func CanonicalizeURLWith(raw string, policy URLParamPolicy) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		if u.Path == "" {
+			u.Path = "/"
+		}
+		u.RawPath = ""
+	}
+
+	query := u.Query()
+	for key := range query {
+		if !policy.keeps(key) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // 키 순서로 정렬됨
+
+	return u.String()
+}
@@ -0,0 +1,61 @@
+package models
+
+import "testing"
+
+func TestCanonicalizeURLCollapsesVariants(t *testing.T) {
+	const want = "https://shop.example.com/deal/123?color=red&id=7"
+
+	variants := []string{
+		"https://shop.example.com/deal/123?id=7&color=red",
+		"https://shop.example.com/deal/123/?color=red&id=7",
+		"HTTPS://Shop.Example.com/deal/123?color=red&id=7",
+		"https://shop.example.com:443/deal/123?color=red&id=7",
+		"https://shop.example.com/deal/123?color=red&id=7#comments",
+		"https://shop.example.com/deal/123?utm_source=naver&color=red&utm_medium=cpc&id=7",
+		"https://shop.example.com/deal/123?fbclid=abc&color=red&id=7&gclid=xyz",
+		"  https://shop.example.com/deal/123?color=red&id=7&ref=home  ",
+	}
+
+	for _, raw := range variants {
+		if got := CanonicalizeURL(raw); got != want {
+			t.Errorf("CanonicalizeURL(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "root path kept", raw: "https://example.com/", want: "https://example.com/"},
+		{name: "non-default port kept", raw: "http://example.com:8080/a", want: "http://example.com:8080/a"},
+		{name: "only tracking params", raw: "https://example.com/a?utm_campaign=x", want: "https://example.com/a"},
+		{name: "path case kept", raw: "https://example.com/Deal/ABC", want: "https://example.com/Deal/ABC"},
+		{name: "not a URL", raw: " 상품 링크 없음 ", want: "상품 링크 없음"},
+		{name: "relative", raw: "/deal/1?utm_source=x", want: "/deal/1?utm_source=x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanonicalizeURL(tt.raw); got != tt.want {
+				t.Errorf("CanonicalizeURL(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeURLWithPolicy(t *testing.T) {
+	keep := URLParamPolicy{Keep: []string{"id", "no"}}
+	raw := "https://www.ppomppu.co.kr/zboard/view.php?page=3&id=ppomppu&divpage=90&no=123"
+	if got, want := CanonicalizeURLWith(raw, keep), "https://www.ppomppu.co.kr/zboard/view.php?id=ppomppu&no=123"; got != want {
+		t.Errorf("CanonicalizeURLWith(keep) = %q, want %q", got, want)
+	}
+
+	drop := URLParamPolicy{Drop: []string{"sessionid"}}
+	raw = "https://example.com/a?sessionid=1&utm_source=x&item=5"
+	if got, want := CanonicalizeURLWith(raw, drop), "https://example.com/a?item=5"; got != want {
+		t.Errorf("CanonicalizeURLWith(drop) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// AlertMatchRepository handles persistence for alert match history
+type AlertMatchRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewAlertMatchRepository creates a new alert match repository
+func NewAlertMatchRepository(db *MongoDB, log *zap.Logger) *AlertMatchRepository {
+	return &AlertMatchRepository{
+		db:  db,
+		log: log.Named("alert-match-repository"),
+	}
+}
+
+// RecordMatch stores a single alert match
+func (r *AlertMatchRepository) RecordMatch(ctx context.Context, match *models.AlertMatch) error {
+	collection := r.db.Collection("alert_matches")
+
+	_, err := collection.InsertOne(ctx, match)
+	if err != nil {
+		return fmt.Errorf("failed to record alert match: %w", err)
+	}
+
+	return nil
+}
+
+// GetMatches returns the matches of an alert within [from, to), newest first.
+// A zero from or to leaves that side of the range open.
+func (r *AlertMatchRepository) GetMatches(ctx context.Context, alertID string, from, to time.Time, limit int) ([]models.AlertMatch, error) {
+	collection := r.db.Collection("alert_matches")
+
+	filter := matchRangeFilter(alertID, from, to)
+
+	opts := options.Find().SetSort(bson.D{{Key: "matched_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find alert matches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var matches []models.AlertMatch
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, fmt.Errorf("failed to decode alert matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// CountMatches returns the number of matches of an alert within [from, to)
+func (r *AlertMatchRepository) CountMatches(ctx context.Context, alertID string, from, to time.Time) (int64, error) {
+	collection := r.db.Collection("alert_matches")
+
+	filter := matchRangeFilter(alertID, from, to)
+
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count alert matches: %w", err)
+	}
+
+	return count, nil
+}
+
+// UserMatchSummary is one user's matches for a summary DM
+type UserMatchSummary struct {
+	UserID  string              `bson:"_id"`
+	Total   int                 `bson:"total"`   // 기간 동안의 전체 일치 수
+	Matches []models.AlertMatch `bson:"matches"` // 최신순, 최대 limit개
+}
+
+// SummaryMatches returns the matches recorded for summary mode alerts of the
+// given mode within [from, to), grouped by user. Each user gets at most limit
+// matches, newest first.
+func (r *AlertMatchRepository) SummaryMatches(ctx context.Context, mode string, from, to time.Time, limit int) ([]UserMatchSummary, error) {
+	collection := r.db.Collection("alert_matches")
+
+	cursor, err := collection.Aggregate(ctx, summaryMatchesPipeline(mode, from, to, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate summary matches: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var summaries []UserMatchSummary
+	if err := cursor.All(ctx, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to decode summary matches: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// summaryMatchesPipeline builds the aggregation used by SummaryMatches
+func summaryMatchesPipeline(mode string, from, to time.Time, limit int) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"summary_mode": mode,
+			"matched_at":   bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "matched_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     "$user_id",
+			"total":   bson.M{"$sum": 1},
+			"matches": bson.M{"$push": "$$ROOT"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"total":   1,
+			"matches": bson.M{"$slice": bson.A{"$matches", limit}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
+
+// matchRangeFilter builds the filter for an alert's matches within [from, to)
+func matchRangeFilter(alertID string, from, to time.Time) bson.M {
+	filter := bson.M{"alert_id": alertID}
+
+	matchedAt := bson.M{}
+	if !from.IsZero() {
+		matchedAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		matchedAt["$lt"] = to
+	}
+	if len(matchedAt) > 0 {
+		filter["matched_at"] = matchedAt
+	}
+
+	return filter
+}
@@ -0,0 +1,541 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// AlertRepository provides access to keyword alerts
+type AlertRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewAlertRepository creates a new alert repository
+func NewAlertRepository(db *MongoDB, log *zap.Logger) *AlertRepository {
+	return &AlertRepository{
+		db:  db,
+		log: log.Named("alert-repository"),
+	}
+}
+
+// alertsLegacyIndex is the unique index on the keyword as typed, which the
+// index on the normalized keyword replaces
+const alertsLegacyIndex = "user_id_1_keyword_1"
+
+// alertsUnscopedIndex is the unique index on {user_id, normalized_keyword}
+// over every alert, which the scoped indexes replace. It made an admin's
+// server alerts collide with their personal alerts.
+const alertsUnscopedIndex = "user_id_1_normalized_keyword_1"
+
+// alertsDuplicatesCollection keeps the duplicate alerts removed by
+// EnsureIndexes, so they can be restored by hand
+const alertsDuplicatesCollection = "keyword_alerts_duplicates"
+
+// EnsureIndexes backfills normalized keywords and the personal scope on
+// alerts saved before they existed, moves alerts that now duplicate another
+// to keyword_alerts_duplicates, and creates the unique indexes: personal
+// alerts are unique per {user_id, normalized_keyword} and server alerts per
+// {guild_id, normalized_keyword}. The legacy and unscoped indexes are dropped.
+func (r *AlertRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.db.Collection("keyword_alerts")
+
+	cursor, err := collection.Find(ctx, bson.M{"normalized_keyword": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("failed to find alerts to normalize: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		// _id may be an ObjectID or a string depending on how the alert was created
+		var alert struct {
+			ID      interface{} `bson:"_id"`
+			Keyword string      `bson:"keyword"`
+		}
+		if err := cursor.Decode(&alert); err != nil {
+			return fmt.Errorf("failed to decode alert: %w", err)
+		}
+		_, err := collection.UpdateByID(ctx, alert.ID, bson.M{"$set": bson.M{
+			"normalized_keyword": models.NormalizeKeyword(alert.Keyword),
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to normalize alert %q: %w", alert.Keyword, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate alerts: %w", err)
+	}
+
+	// Personal alerts were saved without a scope; the unique index only covers scoped ones
+	_, err = collection.UpdateMany(ctx, bson.M{"scope": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"scope": models.AlertScopeUser}})
+	if err != nil {
+		return fmt.Errorf("failed to backfill alert scopes: %w", err)
+	}
+
+	// " rtx" and "RTX" saved before keywords were normalized now share a key
+	if err := r.removeDuplicateAlerts(ctx); err != nil {
+		return err
+	}
+
+	// The unscoped index has the personal index's keys, so it goes first
+	if _, err := collection.Indexes().DropOne(ctx, alertsUnscopedIndex); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop unscoped keyword_alerts index: %w", err)
+	}
+
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "normalized_keyword", Value: 1}},
+			Options: options.Index().
+				SetName("user_keyword_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"scope": models.AlertScopeUser}),
+		},
+		{
+			Keys: bson.D{{Key: "guild_id", Value: 1}, {Key: "normalized_keyword", Value: 1}},
+			Options: options.Index().
+				SetName("server_keyword_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"scope": models.AlertScopeServer}),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create keyword_alerts indexes: %w", err)
+	}
+
+	if _, err := collection.Indexes().DropOne(ctx, alertsLegacyIndex); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop legacy keyword_alerts index: %w", err)
+	}
+
+	return nil
+}
+
+// duplicateAlertGroup is a set of alerts sharing a unique index key, the one
+// to keep first. _id may be an ObjectID or a string.
+type duplicateAlertGroup struct {
+	UserID  string        `bson:"user_id"`
+	GuildID string        `bson:"guild_id"`
+	Scope   string        `bson:"scope"`
+	Keyword string        `bson:"keyword"`
+	IDs     []interface{} `bson:"ids"`
+}
+
+// removeDuplicateAlerts keeps one alert of each personal {user_id,
+// normalized_keyword} and each server {guild_id, normalized_keyword}, so the
+// unique indexes can be built. An active alert is kept over an inactive one,
+// then the one that fired most, then the oldest. The others are copied to
+// keyword_alerts_duplicates before they are removed.
+func (r *AlertRepository) removeDuplicateAlerts(ctx context.Context) error {
+	collection := r.db.Collection("keyword_alerts")
+
+	cursor, err := collection.Aggregate(ctx, duplicateAlertsPipeline())
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate alerts: %w", err)
+	}
+	var groups []duplicateAlertGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return fmt.Errorf("failed to decode duplicate alerts: %w", err)
+	}
+
+	for _, group := range groups {
+		drop := group.IDs[1:]
+		backup, err := collection.Aggregate(ctx, backupAlertsPipeline(drop))
+		if err != nil {
+			return fmt.Errorf("failed to back up duplicate alerts: %w", err)
+		}
+		backup.Close(ctx)
+
+		if _, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": drop}}); err != nil {
+			return fmt.Errorf("failed to remove duplicate alerts: %w", err)
+		}
+		r.log.Info("Moved duplicate alerts to "+alertsDuplicatesCollection,
+			zap.String("user_id", group.UserID),
+			zap.String("guild_id", group.GuildID),
+			zap.String("scope", group.Scope),
+			zap.String("keyword", group.Keyword),
+			zap.Int("moved", len(drop)))
+	}
+	return nil
+}
+
+// duplicateAlertsPipeline groups alerts by the key of the unique index
+// covering them, the owner and normalized keyword within a scope, and returns
+// the groups with more than one alert, the one to keep first. The owner of a
+// personal alert is its user and of a server alert its guild.
+func duplicateAlertsPipeline() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{
+			{Key: "is_active", Value: -1},
+			{Key: "notify_count", Value: -1},
+			{Key: "created_at", Value: 1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"scope": "$scope",
+				"owner": bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$scope", models.AlertScopeServer}}, "$guild_id", "$user_id",
+				}},
+				"normalized_keyword": "$normalized_keyword",
+			},
+			"user_id":  bson.M{"$first": "$user_id"},
+			"guild_id": bson.M{"$first": "$guild_id"},
+			"scope":    bson.M{"$first": "$scope"},
+			"keyword":  bson.M{"$first": "$keyword"},
+			"ids":      bson.M{"$push": "$_id"},
+			"count":    bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+}
+
+// backupAlertsPipeline copies the alerts with the given IDs to
+// keyword_alerts_duplicates, replacing an earlier copy
+func backupAlertsPipeline(ids []interface{}) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": ids}}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":        alertsDuplicatesCollection,
+			"whenMatched": "replace",
+		}}},
+	}
+}
+
+// AlertListOptions filters, sorts and pages ListAlerts
+type AlertListOptions struct {
+	UserID string // 비어 있으면 모든 사용자
+	Sort   string // 정렬 필드, "-" 접두사는 내림차순 (기본값 "-created_at")
+	Offset int
+	Limit  int
+}
+
+// alertSortFields maps ListAlerts sort names to document fields
+var alertSortFields = map[string]string{
+	"created_at":    "created_at",
+	"keyword":       "keyword",
+	"notify_count":  "notify_count",
+	"last_notified": "last_notified",
+}
+
+// ListAlerts returns a page of keyword alerts matching the options.
+// It returns an error wrapping ErrInvalidSort for an unsupported sort field.
+func (r *AlertRepository) ListAlerts(ctx context.Context, opts AlertListOptions) ([]models.KeywordAlert, error) {
+	collection := r.db.Collection("keyword_alerts")
+
+	sort, err := parseSort(opts.Sort, "-created_at", alertSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{}
+	if opts.UserID != "" {
+		filter["user_id"] = opts.UserID
+	}
+
+	findOpts := options.Find().
+		SetSort(sort).
+		SetSkip(int64(opts.Offset)).
+		SetLimit(int64(opts.Limit))
+
+	cursor, err := collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.KeywordAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// GetAlertsByGuild returns the active alerts created in guildID, oldest first.
+// With a userID, only that user's alerts are returned.
+func (r *AlertRepository) GetAlertsByGuild(ctx context.Context, guildID, userID string) ([]models.KeywordAlert, error) {
+	collection := r.db.Collection("keyword_alerts")
+
+	filter := bson.M{
+		"guild_id":  guildID,
+		"is_active": true,
+	}
+	if userID != "" {
+		filter["user_id"] = userID
+	}
+
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts for guild: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []models.KeywordAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode guild alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// DeactivateByChannel deactivates every active alert that notifies channelID
+// and returns the alerts it deactivated, so their owners can be told
+func (r *AlertRepository) DeactivateByChannel(ctx context.Context, channelID string) ([]models.KeywordAlert, error) {
+	collection := r.db.Collection("keyword_alerts")
+	filter := bson.M{"channel_id": channelID, "is_active": true}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find channel alerts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	alerts := []models.KeywordAlert{}
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode channel alerts: %w", err)
+	}
+	if len(alerts) == 0 {
+		return alerts, nil
+	}
+
+	if _, err := r.deactivate(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// DeactivateChannelAlerts deactivates every active alert that notifies
+// channelID in one batch update and returns how many were deactivated.
+// Use it when the channel itself is gone and there is no one to tell.
+func (r *AlertRepository) DeactivateChannelAlerts(ctx context.Context, channelID string) (int64, error) {
+	return r.deactivate(ctx, bson.M{"channel_id": channelID, "is_active": true})
+}
+
+// DeactivateGuildAlerts deactivates every active alert created in guildID in
+// one batch update and returns how many were deactivated
+func (r *AlertRepository) DeactivateGuildAlerts(ctx context.Context, guildID string) (int64, error) {
+	return r.deactivate(ctx, bson.M{"guild_id": guildID, "is_active": true})
+}
+
+// deactivate sets is_active to false on every alert matching filter
+func (r *AlertRepository) deactivate(ctx context.Context, filter bson.M) (int64, error) {
+	result, err := r.db.Collection("keyword_alerts").UpdateMany(ctx, filter, bson.M{"$set": bson.M{"is_active": false}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to deactivate alerts: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// InsertAlert stores a new alert under a fresh ObjectID and sets alert.ID to
+// it. An alert without a scope is stored as a personal alert. The insert is retried on transient errors with the same _id, so a retry
+// after a write whose reply was lost can't store the alert twice; the
+// duplicate key error it gets then is treated as success. Any other duplicate
+// key error, such as the same keyword saved concurrently, is returned as is.
+func (r *AlertRepository) InsertAlert(ctx context.Context, alert *models.KeywordAlert) error {
+	collection := r.db.Collection("keyword_alerts")
+
+	// The personal unique index only covers alerts with the scope stored
+	if alert.Scope == "" {
+		alert.Scope = models.AlertScopeUser
+	}
+
+	id := primitive.NewObjectID()
+	doc, err := alertDocument(id, *alert)
+	if err != nil {
+		return err
+	}
+
+	attempt := 0
+	err = WithRetry(ctx, func(ctx context.Context) error {
+		attempt++
+		_, err := collection.InsertOne(ctx, doc)
+		// An earlier attempt may have been written before its reply was lost
+		if attempt > 1 && mongo.IsDuplicateKeyError(err) && collection.FindOne(ctx, bson.M{"_id": id}).Err() == nil {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	alert.ID = id.Hex()
+	return nil
+}
+
+// alertDocument encodes alert with _id set to id. Alerts are stored with
+// ObjectID IDs, which models.KeywordAlert holds as hex strings.
+func alertDocument(id primitive.ObjectID, alert models.KeywordAlert) (bson.D, error) {
+	alert.ID = ""
+	raw, err := bson.Marshal(alert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	var fields bson.D
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to encode alert: %w", err)
+	}
+	return append(bson.D{{Key: "_id", Value: id}}, fields...), nil
+}
+
+// KeywordPopularity is how often alerts for one keyword fired in a guild
+type KeywordPopularity struct {
+	Keyword     string `bson:"keyword"`      // 표시용 키워드 (가장 먼저 만들어진 알림 기준)
+	NotifyCount int    `bson:"notify_count"` // 이 키워드 알림들의 알림 횟수 합계
+	Subscribers int    `bson:"subscribers"`  // 이 키워드 알림 수
+}
+
+// PopularKeywords returns the keywords whose active alerts in guildID fired the
+// most, summing notify counts across users by normalized keyword
+func (r *AlertRepository) PopularKeywords(ctx context.Context, guildID string, limit int) ([]KeywordPopularity, error) {
+	collection := r.db.Collection("keyword_alerts")
+
+	cursor, err := collection.Aggregate(ctx, popularKeywordsPipeline(guildID, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate popular keywords: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keywords []KeywordPopularity
+	if err := cursor.All(ctx, &keywords); err != nil {
+		return nil, fmt.Errorf("failed to decode popular keywords: %w", err)
+	}
+
+	return keywords, nil
+}
+
+// popularKeywordsPipeline builds the aggregation used by PopularKeywords.
+// A non-positive limit returns every keyword that fired at least once.
+func popularKeywordsPipeline(guildID string, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"guild_id":     guildID,
+			"is_active":    true,
+			"notify_count": bson.M{"$gt": 0},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$normalized_keyword",
+			"keyword":      bson.M{"$first": "$keyword"},
+			"notify_count": bson.M{"$sum": "$notify_count"},
+			"subscribers":  bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "notify_count", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+	}
+
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	return pipeline
+}
+
+// KeywordTrend is how many users subscribe to one keyword
+type KeywordTrend struct {
+	Keyword     string `bson:"keyword"`     // 표시용 키워드 (가장 먼저 만들어진 알림 기준)
+	Subscribers int    `bson:"subscribers"` // 이 키워드 알림을 등록한 사용자 수
+}
+
+// KeywordTrends summarizes active personal alerts across every user and guild
+type KeywordTrends struct {
+	Top               []KeywordTrend
+	TotalAlerts       int
+	UniqueSubscribers int
+}
+
+// KeywordTrends returns the limit most-subscribed keywords, grouped by
+// normalized keyword, along with the total number of active personal alerts
+// and the number of distinct users who have one. Server alerts are not counted.
+func (r *AlertRepository) KeywordTrends(ctx context.Context, limit int) (*KeywordTrends, error) {
+	collection := r.db.Collection("keyword_alerts")
+
+	cursor, err := collection.Aggregate(ctx, keywordTrendsPipeline(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate keyword trends: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Top    []KeywordTrend `bson:"top"`
+		Totals []struct {
+			Alerts      int `bson:"alerts"`
+			Subscribers int `bson:"subscribers"`
+		} `bson:"totals"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode keyword trends: %w", err)
+	}
+
+	trends := &KeywordTrends{}
+	if len(results) == 0 {
+		return trends, nil
+	}
+	trends.Top = results[0].Top
+	if len(results[0].Totals) > 0 {
+		trends.TotalAlerts = results[0].Totals[0].Alerts
+		trends.UniqueSubscribers = results[0].Totals[0].Subscribers
+	}
+
+	return trends, nil
+}
+
+// keywordTrendsPipeline builds the aggregation used by KeywordTrends. It
+// computes the top keywords and the totals in one pass with $facet.
+// A non-positive limit returns every keyword.
+func keywordTrendsPipeline(limit int) mongo.Pipeline {
+	top := bson.A{
+		bson.M{"$sort": bson.D{{Key: "created_at", Value: 1}}},
+		bson.M{"$group": bson.M{
+			"_id":     "$normalized_keyword",
+			"keyword": bson.M{"$first": "$keyword"},
+			"users":   bson.M{"$addToSet": "$user_id"},
+		}},
+		bson.M{"$project": bson.M{
+			"keyword":     1,
+			"subscribers": bson.M{"$size": "$users"},
+		}},
+		bson.M{"$sort": bson.D{
+			{Key: "subscribers", Value: -1},
+			{Key: "_id", Value: 1},
+		}},
+	}
+	if limit > 0 {
+		top = append(top, bson.M{"$limit": limit})
+	}
+
+	totals := bson.A{
+		bson.M{"$group": bson.M{
+			"_id":    nil,
+			"alerts": bson.M{"$sum": 1},
+			"users":  bson.M{"$addToSet": "$user_id"},
+		}},
+		bson.M{"$project": bson.M{
+			"_id":         0,
+			"alerts":      1,
+			"subscribers": bson.M{"$size": "$users"},
+		}},
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"is_active": true,
+			"scope":     bson.M{"$ne": models.AlertScopeServer},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"top":    top,
+			"totals": totals,
+		}}},
+	}
+}
@@ -0,0 +1,367 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// retryableError is a reply the server sends while its primary steps down
+func retryableError() bson.D {
+	return mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 189, Message: "primary stepped down"})
+}
+
+// duplicateKeyError is a reply to an insert rejected by a unique index
+func duplicateKeyError() bson.D {
+	return mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"})
+}
+
+// insertedIDs returns the _id of every document inserted, in order
+func insertedIDs(mt *mtest.T) []primitive.ObjectID {
+	var ids []primitive.ObjectID
+	for _, cmd := range startedCommands(mt, "insert") {
+		doc := cmd.Lookup("documents").Array().Index(0).Value().Document()
+		ids = append(ids, doc.Lookup("_id").ObjectID())
+	}
+	return ids
+}
+
+func TestInsertAlert(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("retry then success", func(mt *mtest.T) {
+		mt.AddMockResponses(retryableError(), writeResponse(1))
+
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		alert := &models.KeywordAlert{Keyword: "모니터", UserID: "user", IsActive: true}
+		if err := repo.InsertAlert(context.Background(), alert); err != nil {
+			mt.Fatalf("InsertAlert() error = %v", err)
+		}
+
+		ids := insertedIDs(mt)
+		if len(ids) != 2 {
+			mt.Fatalf("sent %d inserts, want 2", len(ids))
+		}
+		if ids[0] != ids[1] {
+			mt.Errorf("retry inserted _id %s, want the first attempt's %s", ids[1].Hex(), ids[0].Hex())
+		}
+		if alert.ID != ids[0].Hex() {
+			mt.Errorf("alert.ID = %q, want %q", alert.ID, ids[0].Hex())
+		}
+		doc := startedCommands(mt, "insert")[0].Lookup("documents").Array().Index(0).Value().Document()
+		if scope := doc.Lookup("scope").StringValue(); scope != models.AlertScopeUser {
+			mt.Errorf("stored scope %q, want a personal alert", scope)
+		}
+	})
+
+	mt.Run("retry after lost reply", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			retryableError(),
+			duplicateKeyError(),
+			cursorResponse(bson.D{{Key: "keyword", Value: "모니터"}}),
+		)
+
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		alert := &models.KeywordAlert{Keyword: "모니터", UserID: "user", IsActive: true}
+		if err := repo.InsertAlert(context.Background(), alert); err != nil {
+			mt.Fatalf("InsertAlert() error = %v, want the stored alert to count", err)
+		}
+		if alert.ID == "" {
+			mt.Error("alert.ID was not set")
+		}
+	})
+
+	mt.Run("duplicate keyword", func(mt *mtest.T) {
+		mt.AddMockResponses(duplicateKeyError())
+
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		alert := &models.KeywordAlert{Keyword: "모니터", UserID: "user", IsActive: true}
+		err := repo.InsertAlert(context.Background(), alert)
+		if !mongo.IsDuplicateKeyError(err) {
+			mt.Errorf("InsertAlert() error = %v, want a duplicate key error", err)
+		}
+		if n := len(startedCommands(mt, "find")); n != 0 {
+			mt.Errorf("looked up the alert %d times after a first-attempt duplicate, want 0", n)
+		}
+		if alert.ID != "" {
+			mt.Errorf("alert.ID = %q after a failed insert, want empty", alert.ID)
+		}
+	})
+}
+
+func TestEnsureAlertIndexesRemovesDuplicates(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("move duplicates", func(mt *mtest.T) {
+		keep := primitive.NewObjectID()
+		mt.AddMockResponses(
+			cursorResponse(), // alerts without normalized keywords
+			writeResponse(3), // backfill personal scope
+			cursorResponse(bson.D{
+				{Key: "_id", Value: bson.D{{Key: "scope", Value: "user"}, {Key: "owner", Value: "user"}, {Key: "normalized_keyword", Value: "rtx"}}},
+				{Key: "user_id", Value: "user"},
+				{Key: "scope", Value: "user"},
+				{Key: "keyword", Value: "RTX"},
+				{Key: "ids", Value: bson.A{keep, "legacy-id"}},
+				{Key: "count", Value: 2},
+			}),
+			cursorResponse(),              // back up duplicates
+			writeResponse(1),              // delete duplicates
+			mtest.CreateSuccessResponse(), // drop unscoped index
+			mtest.CreateSuccessResponse(), // create indexes
+			mtest.CreateSuccessResponse(), // drop legacy index
+		)
+
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			mt.Fatalf("EnsureIndexes() error = %v", err)
+		}
+
+		var order []string
+		for _, event := range mt.GetAllStartedEvents() {
+			order = append(order, event.CommandName)
+		}
+		want := []string{"find", "update", "aggregate", "aggregate", "delete", "dropIndexes", "createIndexes", "dropIndexes"}
+		if !reflect.DeepEqual(order, want) {
+			mt.Fatalf("commands = %v, want %v", order, want)
+		}
+
+		backfill := startedCommands(mt, "update")[0].Lookup("updates").Array().Index(0).Value().Document()
+		if scope := backfill.Lookup("u", "$set", "scope").StringValue(); scope != models.AlertScopeUser {
+			mt.Errorf("backfilled scope %q, want %q", scope, models.AlertScopeUser)
+		}
+
+		backup := startedCommands(mt, "aggregate")[1].Lookup("pipeline").Array()
+		backedUp, _ := backup.Index(0).Value().Document().Lookup("$match", "_id", "$in").Array().Values()
+		if len(backedUp) != 1 || backedUp[0].StringValue() != "legacy-id" {
+			mt.Errorf("backed up %v, want only the duplicate", backedUp)
+		}
+		if into := backup.Index(1).Value().Document().Lookup("$merge", "into").StringValue(); into != alertsDuplicatesCollection {
+			mt.Errorf("backed up into %q, want %q", into, alertsDuplicatesCollection)
+		}
+
+		deleted, _ := startedCommands(mt, "delete")[0].Lookup("deletes").Array().Index(0).Value().Document().Lookup("q", "_id", "$in").Array().Values()
+		if len(deleted) != 1 || deleted[0].StringValue() != "legacy-id" {
+			mt.Errorf("deleted %v, want only the backed up duplicate", deleted)
+		}
+
+		drops := startedCommands(mt, "dropIndexes")
+		if got := drops[0].Lookup("index").StringValue(); got != alertsUnscopedIndex {
+			mt.Errorf("dropped index %q first, want %q", got, alertsUnscopedIndex)
+		}
+		if got := drops[1].Lookup("index").StringValue(); got != alertsLegacyIndex {
+			mt.Errorf("dropped index %q, want %q", got, alertsLegacyIndex)
+		}
+
+		indexes, _ := startedCommands(mt, "createIndexes")[0].Lookup("indexes").Array().Values()
+		if len(indexes) != 2 {
+			mt.Fatalf("created %d indexes, want 2", len(indexes))
+		}
+		for i, want := range []struct{ owner, scope string }{{"user_id", models.AlertScopeUser}, {"guild_id", models.AlertScopeServer}} {
+			index := indexes[i].Document()
+			if !index.Lookup("unique").Boolean() {
+				mt.Errorf("index %d isn't unique", i)
+			}
+			if owner := index.Lookup("key").Document().Index(0).Key(); owner != want.owner {
+				mt.Errorf("index %d is keyed on %q, want %q", i, owner, want.owner)
+			}
+			if scope := index.Lookup("partialFilterExpression", "scope").StringValue(); scope != want.scope {
+				mt.Errorf("index %d covers scope %q, want %q", i, scope, want.scope)
+			}
+		}
+	})
+
+	mt.Run("indexes already gone", func(mt *mtest.T) {
+		notFound := mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Name: "IndexNotFound", Message: "index not found"})
+		mt.AddMockResponses(
+			cursorResponse(),
+			writeResponse(0),
+			cursorResponse(),
+			notFound,
+			mtest.CreateSuccessResponse(),
+			notFound,
+		)
+
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			mt.Errorf("EnsureIndexes() error = %v, want missing old indexes ignored", err)
+		}
+	})
+}
+
+func TestDuplicateAlertsPipelineGroupsByScope(t *testing.T) {
+	sort := duplicateAlertsPipeline()[0][0].Value.(bson.D)
+	if sort[0].Key != "is_active" || sort[0].Value != -1 {
+		t.Errorf("first sort key = %v, want active alerts first", sort[0])
+	}
+
+	group := duplicateAlertsPipeline()[1][0].Value.(bson.M)["_id"].(bson.M)
+	if _, ok := group["normalized_keyword"]; !ok {
+		t.Error("duplicates not grouped by normalized keyword")
+	}
+	if group["scope"] != "$scope" {
+		t.Error("duplicates not grouped by scope, so a server alert would be merged with a personal one")
+	}
+	owner := group["owner"].(bson.M)["$cond"].(bson.A)
+	if owner[1] != "$guild_id" || owner[2] != "$user_id" {
+		t.Errorf("owner = %v, want the guild of server alerts and the user of personal ones", owner)
+	}
+}
+
+func TestGetAlertsByGuild(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("user in guild", func(mt *mtest.T) {
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(cursorResponse(
+			bson.D{{Key: "keyword", Value: "모니터"}, {Key: "user_id", Value: "user-1"}, {Key: "guild_id", Value: "guild-1"}},
+		))
+
+		alerts, err := repo.GetAlertsByGuild(context.Background(), "guild-1", "user-1")
+		if err != nil {
+			mt.Fatalf("GetAlertsByGuild() error = %v", err)
+		}
+		if len(alerts) != 1 || alerts[0].GuildID != "guild-1" {
+			mt.Errorf("GetAlertsByGuild() = %+v, want the guild's alert", alerts)
+		}
+
+		find := startedCommands(mt, "find")[0]
+		filter := find.Lookup("filter").Document()
+		if got := filter.Lookup("guild_id").StringValue(); got != "guild-1" {
+			mt.Errorf("filter guild_id = %q, want guild-1", got)
+		}
+		if got := filter.Lookup("user_id").StringValue(); got != "user-1" {
+			mt.Errorf("filter user_id = %q, want user-1", got)
+		}
+		if !filter.Lookup("is_active").Boolean() {
+			mt.Error("filter doesn't exclude inactive alerts")
+		}
+	})
+
+	mt.Run("whole guild", func(mt *mtest.T) {
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(cursorResponse())
+
+		if _, err := repo.GetAlertsByGuild(context.Background(), "guild-1", ""); err != nil {
+			mt.Fatalf("GetAlertsByGuild() error = %v", err)
+		}
+		filter := startedCommands(mt, "find")[0].Lookup("filter").Document()
+		if _, err := filter.LookupErr("user_id"); err == nil {
+			mt.Error("filter limited to a user without a userID")
+		}
+	})
+}
+
+func TestPopularKeywordsPipeline(t *testing.T) {
+	pipeline := popularKeywordsPipeline("guild-1", 5)
+
+	if len(pipeline) != 5 {
+		t.Fatalf("pipeline has %d stages, want match, sort, group, sort and limit", len(pipeline))
+	}
+	match := pipeline[0][0].Value.(bson.M)
+	if match["guild_id"] != "guild-1" || match["is_active"] != true {
+		t.Errorf("match = %v, want the guild's active alerts", match)
+	}
+	group := pipeline[2][0].Value.(bson.M)
+	if group["_id"] != "$normalized_keyword" {
+		t.Errorf("group _id = %v, want the normalized keyword", group["_id"])
+	}
+	if !reflect.DeepEqual(group["notify_count"], bson.M{"$sum": "$notify_count"}) {
+		t.Errorf("group notify_count = %v, want the summed counts", group["notify_count"])
+	}
+	sort := bson.D{{Key: "notify_count", Value: -1}, {Key: "_id", Value: 1}}
+	if pipeline[3][0].Key != "$sort" || !reflect.DeepEqual(pipeline[3][0].Value, sort) {
+		t.Errorf("fourth stage = %v, want $sort %v", pipeline[3], sort)
+	}
+	if pipeline[4][0].Key != "$limit" || pipeline[4][0].Value != 5 {
+		t.Errorf("last stage = %v, want $limit 5", pipeline[4])
+	}
+
+	if unlimited := popularKeywordsPipeline("guild-1", 0); len(unlimited) != 4 {
+		t.Errorf("pipeline without a limit has %d stages, want 4", len(unlimited))
+	}
+}
+
+func TestKeywordTrendsPipeline(t *testing.T) {
+	pipeline := keywordTrendsPipeline(10)
+
+	if len(pipeline) != 2 || pipeline[0][0].Key != "$match" || pipeline[1][0].Key != "$facet" {
+		t.Fatalf("pipeline = %v, want $match then $facet", pipeline)
+	}
+	match := pipeline[0][0].Value.(bson.M)
+	if match["is_active"] != true || !reflect.DeepEqual(match["scope"], bson.M{"$ne": models.AlertScopeServer}) {
+		t.Errorf("match = %v, want active personal alerts", match)
+	}
+
+	facet := pipeline[1][0].Value.(bson.M)
+	top := facet["top"].(bson.A)
+	group := top[1].(bson.M)["$group"].(bson.M)
+	if group["_id"] != "$normalized_keyword" {
+		t.Errorf("top group _id = %v, want the normalized keyword", group["_id"])
+	}
+	// Subscribers are distinct users, so one user's duplicate alerts count once
+	if !reflect.DeepEqual(group["users"], bson.M{"$addToSet": "$user_id"}) {
+		t.Errorf("top group users = %v, want the distinct user IDs", group["users"])
+	}
+	sort := bson.D{{Key: "subscribers", Value: -1}, {Key: "_id", Value: 1}}
+	if !reflect.DeepEqual(top[3], bson.M{"$sort": sort}) {
+		t.Errorf("top sort = %v, want %v", top[3], sort)
+	}
+	if !reflect.DeepEqual(top[len(top)-1], bson.M{"$limit": 10}) {
+		t.Errorf("top ends with %v, want $limit 10", top[len(top)-1])
+	}
+
+	totals := facet["totals"].(bson.A)[0].(bson.M)["$group"].(bson.M)
+	if totals["_id"] != nil || !reflect.DeepEqual(totals["alerts"], bson.M{"$sum": 1}) {
+		t.Errorf("totals group = %v, want every alert counted in one group", totals)
+	}
+
+	unlimited := keywordTrendsPipeline(0)[1][0].Value.(bson.M)["top"].(bson.A)
+	if len(unlimited) != len(top)-1 {
+		t.Errorf("top without a limit has %d stages, want %d", len(unlimited), len(top)-1)
+	}
+}
+
+func TestKeywordTrends(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("decodes facets", func(mt *mtest.T) {
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(cursorResponse(bson.D{
+			{Key: "top", Value: bson.A{
+				bson.D{{Key: "keyword", Value: "RTX 4090"}, {Key: "subscribers", Value: 3}},
+			}},
+			{Key: "totals", Value: bson.A{
+				bson.D{{Key: "alerts", Value: 8}, {Key: "subscribers", Value: 5}},
+			}},
+		}))
+
+		trends, err := repo.KeywordTrends(context.Background(), 10)
+		if err != nil {
+			mt.Fatalf("KeywordTrends() error = %v", err)
+		}
+		if len(trends.Top) != 1 || trends.Top[0].Subscribers != 3 || trends.TotalAlerts != 8 || trends.UniqueSubscribers != 5 {
+			mt.Errorf("KeywordTrends() = %+v, want the decoded facets", trends)
+		}
+	})
+
+	mt.Run("no alerts", func(mt *mtest.T) {
+		repo := NewAlertRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(cursorResponse(bson.D{{Key: "top", Value: bson.A{}}, {Key: "totals", Value: bson.A{}}}))
+
+		trends, err := repo.KeywordTrends(context.Background(), 10)
+		if err != nil {
+			mt.Fatalf("KeywordTrends() error = %v", err)
+		}
+		if len(trends.Top) != 0 || trends.TotalAlerts != 0 || trends.UniqueSubscribers != 0 {
+			mt.Errorf("KeywordTrends() = %+v, want empty trends", trends)
+		}
+	})
+}
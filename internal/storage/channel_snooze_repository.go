@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ChannelSnoozeRepository handles persistence for snoozed notification channels
+type ChannelSnoozeRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewChannelSnoozeRepository creates a new channel snooze repository
+func NewChannelSnoozeRepository(db *MongoDB, log *zap.Logger) *ChannelSnoozeRepository {
+	return &ChannelSnoozeRepository{
+		db:  db,
+		log: log.Named("channel-snooze-repository"),
+	}
+}
+
+// SetSnooze snoozes a channel, replacing any existing snooze
+func (r *ChannelSnoozeRepository) SetSnooze(ctx context.Context, snooze *models.ChannelSnooze) error {
+	collection := r.db.Collection("channel_snoozes")
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"_id": snooze.ChannelID},
+		snooze,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to snooze channel: %w", err)
+	}
+
+	return nil
+}
+
+// ClearSnooze removes a channel's snooze and reports whether one existed
+func (r *ChannelSnoozeRepository) ClearSnooze(ctx context.Context, channelID string) (bool, error) {
+	collection := r.db.Collection("channel_snoozes")
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": channelID})
+	if err != nil {
+		return false, fmt.Errorf("failed to clear channel snooze: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// GetSnoozedChannels returns the snooze end time of each given channel that is snoozed at now
+func (r *ChannelSnoozeRepository) GetSnoozedChannels(ctx context.Context, channelIDs []string, now time.Time) (map[string]time.Time, error) {
+	snoozed := make(map[string]time.Time)
+	if len(channelIDs) == 0 {
+		return snoozed, nil
+	}
+
+	collection := r.db.Collection("channel_snoozes")
+	filter := bson.M{
+		"_id":   bson.M{"$in": channelIDs},
+		"until": bson.M{"$gt": now},
+	}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find channel snoozes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snoozes []models.ChannelSnooze
+	if err := cursor.All(ctx, &snoozes); err != nil {
+		return nil, fmt.Errorf("failed to decode channel snoozes: %w", err)
+	}
+
+	for _, snooze := range snoozes {
+		snoozed[snooze.ChannelID] = snooze.Until
+	}
+
+	return snoozed, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// commandUsageTTL is how long command invocations are kept for usage stats
+const commandUsageTTL = 90 * 24 * time.Hour
+
+// CommandUsageRepository handles persistence for command invocation records
+type CommandUsageRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewCommandUsageRepository creates a new command usage repository
+func NewCommandUsageRepository(db *MongoDB, log *zap.Logger) *CommandUsageRepository {
+	return &CommandUsageRepository{
+		db:  db,
+		log: log.Named("command-usage-repository"),
+	}
+}
+
+// EnsureIndexes creates the TTL index that expires old invocations and the
+// index used by per-guild counts
+func (r *CommandUsageRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.db.Collection("command_usage")
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "ts", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(commandUsageTTL / time.Second)),
+		},
+		{
+			Keys: bson.D{{Key: "guild_id", Value: 1}, {Key: "ts", Value: 1}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create command_usage indexes: %w", err)
+	}
+
+	return nil
+}
+
+// RecordUsage stores a single command invocation
+func (r *CommandUsageRepository) RecordUsage(ctx context.Context, usage *models.CommandUsage) error {
+	collection := r.db.Collection("command_usage")
+
+	_, err := collection.InsertOne(ctx, usage)
+	if err != nil {
+		return fmt.Errorf("failed to record command usage: %w", err)
+	}
+
+	return nil
+}
+
+// CommandCount is how often one command was used in a window
+type CommandCount struct {
+	Command string `bson:"_id"`
+	Count   int    `bson:"count"` // 실행 횟수
+	Users   int    `bson:"users"` // 실행한 사용자 수
+}
+
+// CountByCommand returns how often each command was used in guildID since
+// the given time, most used first
+func (r *CommandUsageRepository) CountByCommand(ctx context.Context, guildID string, since time.Time) ([]CommandCount, error) {
+	collection := r.db.Collection("command_usage")
+
+	cursor, err := collection.Aggregate(ctx, commandCountsPipeline(guildID, since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate command usage: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var counts []CommandCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, fmt.Errorf("failed to decode command usage: %w", err)
+	}
+
+	return counts, nil
+}
+
+// commandCountsPipeline builds the aggregation used by CountByCommand
+func commandCountsPipeline(guildID string, since time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"guild_id": guildID,
+			"ts":       bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$command",
+			"count": bson.M{"$sum": 1},
+			"users": bson.M{"$addToSet": "$user_id"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"count": 1,
+			"users": bson.M{"$size": "$users"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// dealMessageTTL is how long posted deal messages can be turned into alerts by reaction
+const dealMessageTTL = 7 * 24 * time.Hour
+
+// DealMessageRepository handles persistence for posted deal messages
+type DealMessageRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewDealMessageRepository creates a new deal message repository
+func NewDealMessageRepository(db *MongoDB, log *zap.Logger) *DealMessageRepository {
+	return &DealMessageRepository{
+		db:  db,
+		log: log.Named("deal-message-repository"),
+	}
+}
+
+// EnsureIndexes creates the TTL index that expires old deal messages
+func (r *DealMessageRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.db.Collection("deal_messages")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "posted_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(dealMessageTTL / time.Second)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deal_messages index: %w", err)
+	}
+
+	return nil
+}
+
+// RecordMessage stores the product a posted deal message is about
+func (r *DealMessageRepository) RecordMessage(ctx context.Context, message *models.DealMessage) error {
+	collection := r.db.Collection("deal_messages")
+
+	_, err := collection.ReplaceOne(ctx,
+		bson.M{"_id": message.MessageID},
+		message,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to record deal message: %w", err)
+	}
+
+	return nil
+}
+
+// GetMessage returns the deal message with the given ID, or nil if it is unknown or expired
+func (r *DealMessageRepository) GetMessage(ctx context.Context, messageID string) (*models.DealMessage, error) {
+	collection := r.db.Collection("deal_messages")
+
+	var message models.DealMessage
+	err := collection.FindOne(ctx, bson.M{"_id": messageID}).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deal message: %w", err)
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,16 @@
+package storage
+
+import "errors"
+
+// Sentinel errors returned by repositories. Callers should match them with errors.Is,
+// since repositories may wrap them with more context.
+var (
+	// ErrNotFound is returned when the requested document does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists is returned when saving a document that would duplicate an existing one
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrInvalidSort is returned when a list query asks to sort by an unsupported field
+	ErrInvalidSort = errors.New("invalid sort field")
+)
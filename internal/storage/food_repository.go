@@ -2,119 +2,532 @@ package storage
 
 import (
 	"context"
-	"encoding/binary"
+	"errors"
 	"fmt"
-	"math/rand"
-	"time"
 
 	"github.com/bradykim7/gbot/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
-// FoodRepository handles persistence for food recommendations
+// ErrNoFoods is returned by GetRandomFood when no active food of the requested type is registered
+var ErrNoFoods = errors.New("no foods registered")
+
+// ErrSharedFood is returned when a guild tries to change a food that is only
+// in the shared list, which every guild sees but none may modify
+var ErrSharedFood = errors.New("food is in the shared list")
+
+// FoodRepository handles persistence for food recommendations.
+// It is safe for concurrent use.
 type FoodRepository struct {
-	db     *MongoDB
-	log    *zap.Logger
-	random *rand.Rand
+	db  *MongoDB
+	log *zap.Logger
 }
 
 // NewFoodRepository creates a new food repository
 func NewFoodRepository(db *MongoDB, log *zap.Logger) *FoodRepository {
-	// Initialize random source with time-based seed
-	source := rand.NewSource(time.Now().UnixNano())
-	
 	return &FoodRepository{
-		db:     db,
-		log:    log.Named("food-repository"),
-		random: rand.New(source),
+		db:  db,
+		log: log.Named("food-repository"),
 	}
 }
 
-// GetRandomFood returns a random food of the given type
-func (r *FoodRepository) GetRandomFood(ctx context.Context, foodType models.FoodType) (*models.Food, error) {
-	// Get collection
+// foodsLegacyIndex is the unique index used before food lists were per guild
+const foodsLegacyIndex = "normalized_name_1_food_type_1"
+
+// EnsureIndexes backfills normalized names and guild IDs on foods saved before
+// they existed, merges foods whose names now collide, replaces the global unique name index with one per guild,
+// {guild_id, normalized_name, food_type}, and indexes {guild_id, food_type,
+// is_active} for listing and sampling. Foods saved before guild IDs become
+// the shared list (guild_id ""); see AssignSharedFoods.
+func (r *FoodRepository) EnsureIndexes(ctx context.Context) error {
 	collection := r.db.Collection("foods")
-	
-	// Create filter for active foods of given type
-	filter := bson.M{
-		"food_type": foodType,
-		"is_active": true,
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"guild_id": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"guild_id": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to backfill food guild IDs: %w", err)
 	}
-	
-	// Count documents matching filter
-	count, err := collection.CountDocuments(ctx, filter)
+
+	cursor, err := collection.Find(ctx, bson.M{"normalized_name": bson.M{"$exists": false}})
 	if err != nil {
-		return nil, fmt.Errorf("failed to count foods: %w", err)
+		return fmt.Errorf("failed to find foods to normalize: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var food models.Food
+		if err := cursor.Decode(&food); err != nil {
+			return fmt.Errorf("failed to decode food: %w", err)
+		}
+		_, err := collection.UpdateByID(ctx, food.ID, bson.M{"$set": bson.M{
+			"normalized_name": models.NormalizeFoodName(food.Name),
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to normalize food %q: %w", food.Name, err)
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to iterate foods: %w", err)
+	}
+
+	// Foods saved before names were normalized may collide on the new key
+	if err := r.mergeDuplicateFoods(ctx); err != nil {
+		return err
 	}
-	
-	if count == 0 {
-		return nil, fmt.Errorf("no foods found for type %s", foodType)
+
+	// The old index would still reject the same name in two guilds
+	if _, err := collection.Indexes().DropOne(ctx, foodsLegacyIndex); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop legacy foods index: %w", err)
 	}
-	
-	// Get a random index using a cryptographically secure random number
-	// to avoid potential bias in the selection
-	randomIndexInt64, err := secureRandomInt64(count)
+
+	_, err = collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "guild_id", Value: 1}, {Key: "normalized_name", Value: 1}, {Key: "food_type", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "guild_id", Value: 1}, {Key: "food_type", Value: 1}, {Key: "is_active", Value: 1}},
+		},
+	})
 	if err != nil {
-		// Fall back to the standard random if crypto random fails
-		randomIndexInt64 = r.random.Int63n(count)
+		return fmt.Errorf("failed to create foods index: %w", err)
 	}
-	
-	// Find the document at that index
-	opts := options.Find().SetSkip(randomIndexInt64).SetLimit(1)
-	cursor, err := collection.Find(ctx, filter, opts)
+
+	return nil
+}
+
+// duplicateFoodGroup is a set of foods sharing a unique key. IDs is ordered
+// so the food to keep comes first.
+type duplicateFoodGroup struct {
+	IDs []primitive.ObjectID `bson:"ids"`
+}
+
+// mergeDuplicateFoods collapses foods that share {guild_id, normalized_name,
+// food_type}, such as "김치 찌개" and "김치찌개" saved before names were
+// normalized, so the unique index can be built. An active food is kept over a
+// deleted one, then the oldest; recommendation history of the others is moved
+// to it before they are removed.
+func (r *FoodRepository) mergeDuplicateFoods(ctx context.Context) error {
+	collection := r.db.Collection("foods")
+
+	cursor, err := collection.Aggregate(ctx, duplicateFoodsPipeline())
 	if err != nil {
-		return nil, fmt.Errorf("failed to find food: %w", err)
+		return fmt.Errorf("failed to find duplicate foods: %w", err)
+	}
+	var groups []duplicateFoodGroup
+	if err := cursor.All(ctx, &groups); err != nil {
+		return fmt.Errorf("failed to decode duplicate foods: %w", err)
+	}
+
+	merged := 0
+	for _, group := range groups {
+		keep, drop := group.IDs[0], group.IDs[1:]
+
+		_, err := r.db.Collection("food_requests").UpdateMany(ctx,
+			bson.M{"food_id": bson.M{"$in": drop}},
+			bson.M{"$set": bson.M{"food_id": keep}})
+		if err != nil {
+			return fmt.Errorf("failed to move history of duplicate foods: %w", err)
+		}
+		if _, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": drop}}); err != nil {
+			return fmt.Errorf("failed to remove duplicate foods: %w", err)
+		}
+		merged += len(drop)
+	}
+
+	if merged > 0 {
+		r.log.Info("Merged duplicate foods", zap.Int("groups", len(groups)), zap.Int("removed", merged))
+	}
+	return nil
+}
+
+// duplicateFoodsPipeline groups foods by their unique key and returns the
+// groups with more than one food, the one to keep first
+func duplicateFoodsPipeline() mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{
+			{Key: "is_active", Value: -1},
+			{Key: "created_at", Value: 1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"guild_id":        "$guild_id",
+				"normalized_name": "$normalized_name",
+				"food_type":       "$food_type",
+			},
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+}
+
+// isIndexNotFound reports whether err is the server error for dropping an
+// index that doesn't exist
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.Name == "IndexNotFound")
+}
+
+// AssignSharedFoods moves the shared foods (guild_id "") to a guild's list,
+// for deployments that used a single server before food lists were per guild.
+// A shared food whose name the guild already has is left shared. It returns
+// how many foods were moved and how many were left.
+func (r *FoodRepository) AssignSharedFoods(ctx context.Context, guildID string) (moved, skipped int, err error) {
+	if guildID == "" {
+		return 0, 0, fmt.Errorf("guild ID is required")
+	}
+
+	collection := r.db.Collection("foods")
+
+	cursor, err := collection.Find(ctx, bson.M{"guild_id": ""})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find shared foods: %w", err)
 	}
 	defer cursor.Close(ctx)
-	
-	// Get the food
-	var food models.Food
-	if cursor.Next(ctx) {
+
+	for cursor.Next(ctx) {
+		var food models.Food
 		if err := cursor.Decode(&food); err != nil {
-			return nil, fmt.Errorf("failed to decode food: %w", err)
+			return moved, skipped, fmt.Errorf("failed to decode food: %w", err)
+		}
+		_, err := collection.UpdateByID(ctx, food.ID, bson.M{"$set": bson.M{"guild_id": guildID}})
+		if mongo.IsDuplicateKeyError(err) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return moved, skipped, fmt.Errorf("failed to assign food %q: %w", food.Name, err)
 		}
-		return &food, nil
+		moved++
 	}
-	
-	return nil, fmt.Errorf("no food found at random index")
+	if err := cursor.Err(); err != nil {
+		return moved, skipped, fmt.Errorf("failed to iterate shared foods: %w", err)
+	}
+
+	r.log.Info("Shared foods assigned to guild",
+		zap.String("guild_id", guildID),
+		zap.Int("moved", moved),
+		zap.Int("skipped", skipped))
+
+	return moved, skipped, nil
 }
 
-// secureRandomInt64 generates a cryptographically secure random number in range [0, max)
-func secureRandomInt64(max int64) (int64, error) {
-	if max <= 0 {
-		return 0, fmt.Errorf("max must be positive")
+// GetRandomFood returns a random food of the given type from a guild's list,
+// including the shared list.
+// The food is picked by a single $sample aggregation, so there is no window
+// between counting and fetching in which foods can be added or removed.
+func (r *FoodRepository) GetRandomFood(ctx context.Context, guildID string, foodType models.FoodType) (*models.Food, error) {
+	collection := r.db.Collection("foods")
+
+	cursor, err := collection.Aggregate(ctx, randomFoodPipeline(guildID, foodType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample food: %w", err)
 	}
+	defer cursor.Close(ctx)
 
-	// Calculate how many bits we need
-	bits := max
-	bits--
-	bitLength := 0
-	for bits > 0 {
-		bits >>= 1
-		bitLength++
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return nil, fmt.Errorf("failed to sample food: %w", err)
+		}
+		return nil, fmt.Errorf("no foods found for type %s: %w", foodType, ErrNoFoods)
 	}
 
-	// Calculate the mask
-	mask := int64(1)<<uint(bitLength) - 1
+	var food models.Food
+	if err := cursor.Decode(&food); err != nil {
+		return nil, fmt.Errorf("failed to decode food: %w", err)
+	}
+	return &food, nil
+}
 
-	// Generate random numbers until we get one in range
-	var randomInt64 int64
-	var buf [8]byte
+// randomFoodPipeline builds the aggregation used by GetRandomFood: one
+// random active food of the given type visible in the guild
+func randomFoodPipeline(guildID string, foodType models.FoodType) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"guild_id":  guildScope(guildID),
+			"food_type": foodType,
+			"is_active": true,
+		}}},
+		{{Key: "$sample", Value: bson.M{"size": 1}}},
+	}
+}
+
+// GetAllFoods returns every active food of the given type in a guild's list
+// and the shared list, sorted by name
+func (r *FoodRepository) GetAllFoods(ctx context.Context, guildID string, foodType models.FoodType) ([]models.Food, error) {
+	collection := r.db.Collection("foods")
 
-	for {
-		_, err := rand.Read(buf[:])
+	filter := bson.M{
+		"guild_id":  guildScope(guildID),
+		"food_type": foodType,
+		"is_active": true,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find foods: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var foods []models.Food
+	if err := cursor.All(ctx, &foods); err != nil {
+		return nil, fmt.Errorf("failed to decode foods: %w", err)
+	}
+
+	return foods, nil
+}
+
+// guildScope matches the foods visible in a guild: its own list and the
+// shared list (guild_id ""). Outside a guild only the shared list is visible.
+func guildScope(guildID string) interface{} {
+	if guildID == "" {
+		return ""
+	}
+	return bson.M{"$in": bson.A{guildID, ""}}
+}
+
+// missingFoodError reports why a guild's own list had no food matching filter.
+// It returns ErrSharedFood if the shared list has one, since guilds see the
+// shared list but must not change it for everyone, and ErrNotFound otherwise.
+func (r *FoodRepository) missingFoodError(ctx context.Context, guildID, name string, filter bson.M) error {
+	if guildID == "" {
+		return fmt.Errorf("food %q: %w", name, ErrNotFound)
+	}
+
+	shared := bson.M{"guild_id": ""}
+	for key, value := range filter {
+		if key != "guild_id" {
+			shared[key] = value
+		}
+	}
+	err := r.db.Collection("foods").FindOne(ctx, shared,
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Err()
+	switch {
+	case err == nil:
+		return fmt.Errorf("food %q: %w", name, ErrSharedFood)
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return fmt.Errorf("food %q: %w", name, ErrNotFound)
+	}
+	return fmt.Errorf("failed to check shared food: %w", err)
+}
+
+// SaveFood stores a new food in its guild's list. Names are compared by their
+// normalized form, so spacing and Latin letter case don't create duplicates.
+// It returns ErrAlreadyExists if an active food with the same normalized name
+// and type is in the list or the shared list; a previously deleted one in the
+// guild's list is reactivated instead.
+func (r *FoodRepository) SaveFood(ctx context.Context, food *models.Food) error {
+	collection := r.db.Collection("foods")
+
+	food.Name = models.CleanFoodName(food.Name)
+	food.NormalizedName = models.NormalizeFoodName(food.Name)
+
+	var existing models.Food
+	err := collection.FindOne(ctx, bson.M{
+		"guild_id":        guildScope(food.GuildID),
+		"normalized_name": food.NormalizedName,
+		"food_type":       food.FoodType,
+		"is_active":       true,
+	}).Decode(&existing)
+	switch {
+	case err == nil:
+		return fmt.Errorf("food %q: %w", existing.Name, ErrAlreadyExists)
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return fmt.Errorf("failed to check existing food: %w", err)
+	}
+
+	err = collection.FindOne(ctx, bson.M{
+		"guild_id":        food.GuildID,
+		"normalized_name": food.NormalizedName,
+		"food_type":       food.FoodType,
+	}).Decode(&existing)
+	switch {
+	case err == nil:
+		// Reuse the soft-deleted document; the unique index allows only one per key
+		_, err = collection.UpdateByID(ctx, existing.ID, bson.M{"$set": bson.M{
+			"name":       food.Name,
+			"created_at": food.CreatedAt,
+			"created_by": food.CreatedBy,
+			"is_active":  true,
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to reactivate food: %w", err)
+		}
+		food.ID = existing.ID
+	case errors.Is(err, mongo.ErrNoDocuments):
+		result, err := collection.InsertOne(ctx, food)
 		if err != nil {
-			return 0, err
+			if mongo.IsDuplicateKeyError(err) {
+				return fmt.Errorf("food %q: %w", food.Name, ErrAlreadyExists)
+			}
+			return fmt.Errorf("failed to save food: %w", err)
 		}
+		if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+			food.ID = id
+		}
+	default:
+		return fmt.Errorf("failed to check existing food: %w", err)
+	}
 
-		// Convert bytes to int64 and apply mask
-		randomInt64 = int64(binary.BigEndian.Uint64(buf[:])) & mask
+	r.log.Info("Food saved",
+		zap.String("name", food.Name),
+		zap.String("type", string(food.FoodType)))
 
-		// Check if the number is in range
-		if randomInt64 < max {
-			return randomInt64, nil
+	return nil
+}
+
+// DeleteFood soft-deletes an active food in a guild's own list by marking it
+// inactive; outside a guild it deletes from the shared list. The name is
+// matched by its normalized form. It returns ErrSharedFood if the food is
+// only in the shared list, and ErrNotFound if no active food with that name
+// and type exists.
+func (r *FoodRepository) DeleteFood(ctx context.Context, guildID, name string, foodType models.FoodType) error {
+	collection := r.db.Collection("foods")
+
+	filter := bson.M{
+		"guild_id":        guildID,
+		"normalized_name": models.NormalizeFoodName(name),
+		"food_type":       foodType,
+		"is_active":       true,
+	}
+	err := collection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"is_active": false}},
+	).Err()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return r.missingFoodError(ctx, guildID, name, filter)
 		}
+		return fmt.Errorf("failed to delete food: %w", err)
 	}
-}
\ No newline at end of file
+
+	r.log.Info("Food deleted",
+		zap.String("name", name),
+		zap.String("type", string(foodType)))
+
+	return nil
+}
+
+// RenameFood renames an active food in a guild's own list, or in the shared
+// list outside a guild, keeping its ID so its recommendation history follows
+// it. Names are matched and compared by their normalized form. It returns
+// ErrSharedFood if the food is only in the shared list, ErrNotFound if no
+// active food named oldName exists, and ErrAlreadyExists if another visible
+// active food of the type is already named newName. A deleted food with the
+// new name in the same list is dropped to make room.
+func (r *FoodRepository) RenameFood(ctx context.Context, guildID, oldName, newName string, foodType models.FoodType) (*models.Food, error) {
+	collection := r.db.Collection("foods")
+
+	oldKey := models.NormalizeFoodName(oldName)
+	newName = models.CleanFoodName(newName)
+	newKey := models.NormalizeFoodName(newName)
+
+	// 이름을 바꿀 음식이 있는지 먼저 확인해야 삭제된 음식을 헛되이 지우지 않습니다
+	var source models.Food
+	filter := bson.M{
+		"guild_id":        guildID,
+		"normalized_name": oldKey,
+		"food_type":       foodType,
+		"is_active":       true,
+	}
+	err := collection.FindOne(ctx, filter).Decode(&source)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, r.missingFoodError(ctx, guildID, oldName, filter)
+		}
+		return nil, fmt.Errorf("failed to find food: %w", err)
+	}
+
+	// A name differing only in spacing or case keeps its key and can't collide
+	if newKey != oldKey {
+		var existing models.Food
+		err := collection.FindOne(ctx, bson.M{
+			"guild_id":        guildScope(guildID),
+			"normalized_name": newKey,
+			"food_type":       foodType,
+			"is_active":       true,
+		}).Decode(&existing)
+		switch {
+		case err == nil:
+			return nil, fmt.Errorf("food %q: %w", existing.Name, ErrAlreadyExists)
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			return nil, fmt.Errorf("failed to check existing food: %w", err)
+		}
+
+		// The unique index allows only one document per key
+		_, err = collection.DeleteOne(ctx, bson.M{
+			"guild_id":        source.GuildID,
+			"normalized_name": newKey,
+			"food_type":       foodType,
+			"is_active":       false,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove deleted food: %w", err)
+		}
+	}
+
+	var food models.Food
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": source.ID, "is_active": true},
+		bson.M{"$set": bson.M{
+			"name":            newName,
+			"normalized_name": newKey,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&food)
+	if err != nil {
+		switch {
+		case errors.Is(err, mongo.ErrNoDocuments):
+			return nil, fmt.Errorf("food %q: %w", oldName, ErrNotFound)
+		case mongo.IsDuplicateKeyError(err):
+			return nil, fmt.Errorf("food %q: %w", newName, ErrAlreadyExists)
+		}
+		return nil, fmt.Errorf("failed to rename food: %w", err)
+	}
+
+	r.log.Info("Food renamed",
+		zap.String("old_name", oldName),
+		zap.String("name", food.Name),
+		zap.String("type", string(foodType)))
+
+	return &food, nil
+}
+
+// RestoreFood reactivates a soft-deleted food in a guild's own list, or in
+// the shared list outside a guild, matching the name by its normalized form.
+// It returns ErrSharedFood if the deleted food is only in the shared list,
+// and ErrNotFound if no deleted food with that name and type exists.
+func (r *FoodRepository) RestoreFood(ctx context.Context, guildID, name string, foodType models.FoodType) (*models.Food, error) {
+	collection := r.db.Collection("foods")
+
+	var food models.Food
+	filter := bson.M{
+		"guild_id":        guildID,
+		"normalized_name": models.NormalizeFoodName(name),
+		"food_type":       foodType,
+		"is_active":       false,
+	}
+	err := collection.FindOneAndUpdate(ctx, filter,
+		bson.M{"$set": bson.M{"is_active": true}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&food)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, r.missingFoodError(ctx, guildID, name, filter)
+		}
+		return nil, fmt.Errorf("failed to restore food: %w", err)
+	}
+
+	r.log.Info("Food restored",
+		zap.String("name", food.Name),
+		zap.String("type", string(foodType)))
+
+	return &food, nil
+}
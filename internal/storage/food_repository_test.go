@@ -0,0 +1,737 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestEnsureIndexesMergesDuplicateFoods(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("merge", func(mt *mtest.T) {
+		keep, dup1, dup2 := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(
+			writeResponse(0), // backfill guild IDs
+			cursorResponse(), // foods without normalized names
+			cursorResponse(bson.D{
+				{Key: "_id", Value: bson.D{{Key: "guild_id", Value: ""}, {Key: "normalized_name", Value: "김치찌개"}, {Key: "food_type", Value: "lunch"}}},
+				{Key: "ids", Value: bson.A{keep, dup1, dup2}},
+				{Key: "count", Value: 3},
+			}),
+			writeResponse(4),              // move food_requests
+			writeResponse(2),              // delete duplicates
+			mtest.CreateSuccessResponse(), // drop legacy index
+			mtest.CreateSuccessResponse(), // create indexes
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			mt.Fatalf("EnsureIndexes() error = %v", err)
+		}
+
+		updates := startedCommands(mt, "update")
+		if len(updates) != 2 {
+			mt.Fatalf("sent %d updates, want 2", len(updates))
+		}
+		update := updates[1].Lookup("updates").Array().Index(0).Value().Document()
+		moved := update.Lookup("q", "food_id", "$in").Array()
+		if values, _ := moved.Values(); len(values) != 2 {
+			mt.Errorf("moved history of %d foods, want 2", len(values))
+		}
+		if got := update.Lookup("u", "$set", "food_id").ObjectID(); got != keep {
+			mt.Errorf("history moved to %s, want %s", got.Hex(), keep.Hex())
+		}
+
+		deletes := startedCommands(mt, "delete")
+		if len(deletes) != 1 {
+			mt.Fatalf("sent %d deletes, want 1", len(deletes))
+		}
+		deleted, _ := deletes[0].Lookup("deletes").Array().Index(0).Value().Document().Lookup("q", "_id", "$in").Array().Values()
+		if len(deleted) != 2 || deleted[0].ObjectID() != dup1 || deleted[1].ObjectID() != dup2 {
+			mt.Errorf("deleted %v, want [%s %s]", deleted, dup1.Hex(), dup2.Hex())
+		}
+		for _, id := range deleted {
+			if id.ObjectID() == keep {
+				mt.Error("deleted the food to keep")
+			}
+		}
+
+		if len(startedCommands(mt, "createIndexes")) != 1 {
+			mt.Error("unique index was not created after merging")
+		}
+	})
+
+	mt.Run("index failure is returned", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			writeResponse(0),
+			cursorResponse(),
+			cursorResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Message: "E11000 duplicate key error"}),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.EnsureIndexes(context.Background()); err == nil {
+			mt.Error("EnsureIndexes() error = nil, want the index error")
+		}
+	})
+}
+
+func TestDuplicateFoodsPipelineKeepsActiveOldestFirst(t *testing.T) {
+	pipeline := duplicateFoodsPipeline()
+
+	sort := pipeline[0][0]
+	if sort.Key != "$sort" {
+		t.Fatalf("first stage = %s, want $sort", sort.Key)
+	}
+	order := sort.Value.(bson.D)
+	if order[0].Key != "is_active" || order[0].Value != -1 {
+		t.Errorf("first sort key = %v, want active foods first", order[0])
+	}
+	if order[1].Key != "created_at" || order[1].Value != 1 {
+		t.Errorf("second sort key = %v, want oldest first", order[1])
+	}
+
+	group := pipeline[1][0].Value.(bson.M)["_id"].(bson.M)
+	for _, field := range []string{"guild_id", "normalized_name", "food_type"} {
+		if _, ok := group[field]; !ok {
+			t.Errorf("duplicates not grouped by %s", field)
+		}
+	}
+}
+
+// foodDoc is a stored food as returned by the server
+func foodDoc(id primitive.ObjectID, name string, active bool) bson.D {
+	return bson.D{
+		{Key: "_id", Value: id},
+		{Key: "guild_id", Value: "guild"},
+		{Key: "name", Value: name},
+		{Key: "normalized_name", Value: models.NormalizeFoodName(name)},
+		{Key: "food_type", Value: string(models.FoodTypeLunch)},
+		{Key: "is_active", Value: active},
+	}
+}
+
+func TestRenameFood(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("rename", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			cursorResponse(foodDoc(id, "김치찌게", true)),
+			cursorResponse(),
+			writeResponse(0),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: foodDoc(id, "김치찌개", true)}),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food, err := repo.RenameFood(context.Background(), "guild", "김치찌게", "김치찌개", models.FoodTypeLunch)
+		if err != nil {
+			mt.Fatalf("RenameFood() error = %v", err)
+		}
+		if food.ID != id || food.Name != "김치찌개" {
+			mt.Errorf("RenameFood() = %s %q, want %s %q", food.ID.Hex(), food.Name, id.Hex(), "김치찌개")
+		}
+
+		update := startedCommands(mt, "findAndModify")
+		if len(update) != 1 {
+			mt.Fatalf("sent %d findAndModify, want 1", len(update))
+		}
+		if got := update[0].Lookup("query", "_id").ObjectID(); got != id {
+			mt.Errorf("renamed %s, want the food found by name %s", got.Hex(), id.Hex())
+		}
+	})
+
+	mt.Run("collision", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			cursorResponse(foodDoc(primitive.NewObjectID(), "김치찌게", true)),
+			cursorResponse(foodDoc(primitive.NewObjectID(), "된장찌개", true)),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		_, err := repo.RenameFood(context.Background(), "guild", "김치찌게", "된장 찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrAlreadyExists) {
+			mt.Errorf("RenameFood() error = %v, want ErrAlreadyExists", err)
+		}
+		if n := len(startedCommands(mt, "findAndModify")); n != 0 {
+			mt.Errorf("sent %d findAndModify onto an existing food, want 0", n)
+		}
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(), cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		_, err := repo.RenameFood(context.Background(), "guild", "없는음식", "된장찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrNotFound) {
+			mt.Errorf("RenameFood() error = %v, want ErrNotFound", err)
+		}
+		if n := len(startedCommands(mt, "delete")); n != 0 {
+			mt.Errorf("deleted %d foods while renaming a missing food, want 0", n)
+		}
+	})
+
+	mt.Run("shared food", func(mt *mtest.T) {
+		shared := foodDoc(primitive.NewObjectID(), "김치찌게", true)
+		shared[1].Value = ""
+		mt.AddMockResponses(cursorResponse(), cursorResponse(shared))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		_, err := repo.RenameFood(context.Background(), "guild", "김치찌게", "김치찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrSharedFood) {
+			mt.Errorf("RenameFood() error = %v, want ErrSharedFood", err)
+		}
+		finds := startedCommands(mt, "find")
+		if got := guildFilter(mt.T, finds[0].Lookup("filter").Document()); len(got) != 1 || got[0] != "guild" {
+			mt.Errorf("looked for the food to rename in guilds %q, want only the guild", got)
+		}
+		if n := len(startedCommands(mt, "findAndModify")); n != 0 {
+			mt.Errorf("sent %d findAndModify onto a shared food, want 0", n)
+		}
+	})
+
+	mt.Run("replaces deleted food", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			cursorResponse(foodDoc(id, "김치찌게", true)),
+			cursorResponse(),
+			writeResponse(1),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: foodDoc(id, "김치찌개", true)}),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.RenameFood(context.Background(), "guild", "김치찌게", "김치찌개", models.FoodTypeLunch); err != nil {
+			mt.Fatalf("RenameFood() error = %v", err)
+		}
+
+		deletes := startedCommands(mt, "delete")
+		if len(deletes) != 1 {
+			mt.Fatalf("sent %d deletes, want 1", len(deletes))
+		}
+		q := deletes[0].Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		if got := q.Lookup("guild_id").StringValue(); got != "guild" {
+			mt.Errorf("deleted a food in guild %q, want the renamed food's guild", got)
+		}
+		if got := q.Lookup("normalized_name").StringValue(); got != models.NormalizeFoodName("김치찌개") {
+			mt.Errorf("deleted food named %q, want the new name", got)
+		}
+		if active, ok := q.Lookup("is_active").BooleanOK(); !ok || active {
+			mt.Error("delete may remove an active food")
+		}
+	})
+}
+
+// guildFilter returns the guild_id condition of a filter as guild IDs
+func guildFilter(t *testing.T, filter bson.Raw) []string {
+	t.Helper()
+
+	value := filter.Lookup("guild_id")
+	if id, ok := value.StringValueOK(); ok {
+		return []string{id}
+	}
+	values, err := value.Document().Lookup("$in").Array().Values()
+	if err != nil {
+		t.Fatalf("unexpected guild filter %s", value)
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		ids = append(ids, v.StringValue())
+	}
+	return ids
+}
+
+func TestFoodQueriesAreScopedToGuild(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("list", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(foodDoc(primitive.NewObjectID(), "김치찌개", true)))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.GetAllFoods(context.Background(), "guild", models.FoodTypeLunch); err != nil {
+			mt.Fatalf("GetAllFoods() error = %v", err)
+		}
+
+		finds := startedCommands(mt, "find")
+		got := guildFilter(mt.T, finds[0].Lookup("filter").Document())
+		if len(got) != 2 || got[0] != "guild" || got[1] != "" {
+			mt.Errorf("listed foods of guilds %q, want the guild and the shared list", got)
+		}
+	})
+
+	mt.Run("list in DM", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.GetAllFoods(context.Background(), "", models.FoodTypeLunch); err != nil {
+			mt.Fatalf("GetAllFoods() error = %v", err)
+		}
+
+		finds := startedCommands(mt, "find")
+		if got := guildFilter(mt.T, finds[0].Lookup("filter").Document()); len(got) != 1 || got[0] != "" {
+			mt.Errorf("listed foods of guilds %q, want only the shared list", got)
+		}
+	})
+
+	mt.Run("random", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(foodDoc(primitive.NewObjectID(), "김치찌개", true)))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.GetRandomFood(context.Background(), "guild", models.FoodTypeLunch); err != nil {
+			mt.Fatalf("GetRandomFood() error = %v", err)
+		}
+
+		aggregates := startedCommands(mt, "aggregate")
+		match := aggregates[0].Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		if got := guildFilter(mt.T, match); len(got) != 2 || got[0] != "guild" || got[1] != "" {
+			mt.Errorf("sampled foods of guilds %q, want the guild and the shared list", got)
+		}
+	})
+
+	mt.Run("add to guild", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			cursorResponse(),
+			cursorResponse(),
+			writeResponse(1),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food := models.NewFood("김치찌개", models.FoodTypeLunch, "user")
+		food.GuildID = "guild"
+		if err := repo.SaveFood(context.Background(), food); err != nil {
+			mt.Fatalf("SaveFood() error = %v", err)
+		}
+
+		finds := startedCommands(mt, "find")
+		if got := guildFilter(mt.T, finds[1].Lookup("filter").Document()); len(got) != 1 || got[0] != "guild" {
+			mt.Errorf("looked for a deleted food to reuse in guilds %q, want only the guild", got)
+		}
+		inserts := startedCommands(mt, "insert")
+		if len(inserts) != 1 {
+			mt.Fatalf("sent %d inserts, want 1", len(inserts))
+		}
+		doc := inserts[0].Lookup("documents").Array().Index(0).Value().Document()
+		if got := doc.Lookup("guild_id").StringValue(); got != "guild" {
+			mt.Errorf("saved food in guild %q, want %q", got, "guild")
+		}
+	})
+
+	mt.Run("add name in shared list", func(mt *mtest.T) {
+		shared := foodDoc(primitive.NewObjectID(), "김치찌개", true)
+		shared[1].Value = ""
+		mt.AddMockResponses(cursorResponse(shared))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food := models.NewFood("김치 찌개", models.FoodTypeLunch, "user")
+		food.GuildID = "guild"
+		if err := repo.SaveFood(context.Background(), food); !errors.Is(err, ErrAlreadyExists) {
+			mt.Errorf("SaveFood() error = %v, want ErrAlreadyExists", err)
+		}
+		if n := len(startedCommands(mt, "insert")); n != 0 {
+			mt.Errorf("sent %d inserts, want 0", n)
+		}
+	})
+
+	mt.Run("delete only own food", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: foodDoc(primitive.NewObjectID(), "김치찌개", true)}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.DeleteFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch); err != nil {
+			mt.Fatalf("DeleteFood() error = %v", err)
+		}
+
+		cmd := startedCommands(mt, "findAndModify")[0]
+		if got := guildFilter(mt.T, cmd.Lookup("query").Document()); len(got) != 1 || got[0] != "guild" {
+			mt.Errorf("deleted from guilds %q, want only the guild", got)
+		}
+	})
+
+	mt.Run("delete shared food", func(mt *mtest.T) {
+		shared := foodDoc(primitive.NewObjectID(), "김치찌개", true)
+		shared[1].Value = ""
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			cursorResponse(shared),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.DeleteFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrSharedFood) || errors.Is(err, ErrNotFound) {
+			mt.Errorf("DeleteFood() error = %v, want only ErrSharedFood", err)
+		}
+
+		finds := startedCommands(mt, "find")
+		if len(finds) != 1 {
+			mt.Fatalf("sent %d finds, want 1 for the shared list", len(finds))
+		}
+		if got := guildFilter(mt.T, finds[0].Lookup("filter").Document()); len(got) != 1 || got[0] != "" {
+			mt.Errorf("checked guilds %q, want only the shared list", got)
+		}
+	})
+
+	mt.Run("delete in DM", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.DeleteFood(context.Background(), "", "김치찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrNotFound) {
+			mt.Errorf("DeleteFood() error = %v, want ErrNotFound", err)
+		}
+
+		cmd := startedCommands(mt, "findAndModify")[0]
+		if got := guildFilter(mt.T, cmd.Lookup("query").Document()); len(got) != 1 || got[0] != "" {
+			mt.Errorf("deleted from guilds %q, want only the shared list", got)
+		}
+		if n := len(startedCommands(mt, "find")); n != 0 {
+			mt.Errorf("sent %d finds for the shared list it already searched, want 0", n)
+		}
+	})
+
+	mt.Run("restore only own food", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+			cursorResponse(),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.RestoreFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch); !errors.Is(err, ErrNotFound) {
+			mt.Errorf("RestoreFood() error = %v, want ErrNotFound", err)
+		}
+
+		cmd := startedCommands(mt, "findAndModify")[0]
+		if got := guildFilter(mt.T, cmd.Lookup("query").Document()); len(got) != 1 || got[0] != "guild" {
+			mt.Errorf("restored in guilds %q, want only the guild", got)
+		}
+	})
+
+	mt.Run("delete missing", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}), cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.DeleteFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrNotFound) {
+			mt.Errorf("DeleteFood() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestFoodRepositorySentinelErrors(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("save active duplicate", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(foodDoc(primitive.NewObjectID(), "김치찌개", true)))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.SaveFood(context.Background(), models.NewFood("김치찌개", models.FoodTypeLunch, "user"))
+		if !errors.Is(err, ErrAlreadyExists) || errors.Is(err, ErrNotFound) {
+			mt.Errorf("SaveFood() error = %v, want only ErrAlreadyExists", err)
+		}
+	})
+
+	mt.Run("save races an insert", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			cursorResponse(), // no active food
+			cursorResponse(), // no deleted food to reuse
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "E11000 duplicate key error"}),
+		)
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.SaveFood(context.Background(), models.NewFood("김치찌개", models.FoodTypeLunch, "user"))
+		if !errors.Is(err, ErrAlreadyExists) {
+			mt.Errorf("SaveFood() error = %v, want ErrAlreadyExists", err)
+		}
+	})
+
+	mt.Run("save fails", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad query"}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.SaveFood(context.Background(), models.NewFood("김치찌개", models.FoodTypeLunch, "user"))
+		if err == nil || errors.Is(err, ErrAlreadyExists) || errors.Is(err, ErrNotFound) {
+			mt.Errorf("SaveFood() error = %v, want a failure matching no sentinel", err)
+		}
+	})
+
+	mt.Run("delete missing", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}), cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		err := repo.DeleteFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch)
+		if !errors.Is(err, ErrNotFound) || errors.Is(err, ErrAlreadyExists) {
+			mt.Errorf("DeleteFood() error = %v, want only ErrNotFound", err)
+		}
+	})
+}
+
+func TestGetRandomFoodEmpty(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("no foods", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food, err := repo.GetRandomFood(context.Background(), "guild", models.FoodTypeLunch)
+		if !errors.Is(err, ErrNoFoods) {
+			mt.Errorf("GetRandomFood() error = %v, want ErrNoFoods", err)
+		}
+		if food != nil {
+			mt.Errorf("GetRandomFood() = %+v, want nil", food)
+		}
+	})
+
+	mt.Run("database error", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Message: "bad pipeline"}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.GetRandomFood(context.Background(), "guild", models.FoodTypeLunch); err == nil || errors.Is(err, ErrNoFoods) {
+			mt.Errorf("GetRandomFood() error = %v, want a failure that isn't ErrNoFoods", err)
+		}
+	})
+}
+
+func TestFoodNameVariantsCollide(t *testing.T) {
+	mt := newMockTest(t)
+
+	for _, name := range []string{"김치찌개", "김치 찌개", " 김치찌개 ", "김치\t찌개"} {
+		mt.Run("save "+name, func(mt *mtest.T) {
+			mt.AddMockResponses(cursorResponse(foodDoc(primitive.NewObjectID(), "김치찌개", true)))
+
+			repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+			if err := repo.SaveFood(context.Background(), models.NewFood(name, models.FoodTypeLunch, "user")); !errors.Is(err, ErrAlreadyExists) {
+				mt.Errorf("SaveFood(%q) error = %v, want ErrAlreadyExists", name, err)
+			}
+			filter := startedCommands(mt, "find")[0].Lookup("filter").Document()
+			if got := filter.Lookup("normalized_name").StringValue(); got != "김치찌개" {
+				mt.Errorf("looked up normalized name %q, want %q", got, "김치찌개")
+			}
+		})
+
+		mt.Run("delete "+name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: foodDoc(primitive.NewObjectID(), "김치찌개", true)}))
+
+			repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+			if err := repo.DeleteFood(context.Background(), "guild", name, models.FoodTypeLunch); err != nil {
+				mt.Fatalf("DeleteFood(%q) error = %v", name, err)
+			}
+			query := startedCommands(mt, "findAndModify")[0].Lookup("query").Document()
+			if got := query.Lookup("normalized_name").StringValue(); got != "김치찌개" {
+				mt.Errorf("deleted normalized name %q, want %q", got, "김치찌개")
+			}
+		})
+	}
+
+	mt.Run("latin case", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(), cursorResponse(), writeResponse(1))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if err := repo.SaveFood(context.Background(), models.NewFood("  Pad   THAI ", models.FoodTypeLunch, "user")); err != nil {
+			mt.Fatalf("SaveFood() error = %v", err)
+		}
+		doc := startedCommands(mt, "insert")[0].Lookup("documents").Array().Index(0).Value().Document()
+		if got := doc.Lookup("name").StringValue(); got != "Pad THAI" {
+			mt.Errorf("stored display name %q, want %q", got, "Pad THAI")
+		}
+		if got := doc.Lookup("normalized_name").StringValue(); got != "padthai" {
+			mt.Errorf("stored normalized name %q, want %q", got, "padthai")
+		}
+	})
+}
+
+func TestRestoreFood(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("restore", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: foodDoc(id, "김치찌개", true)}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food, err := repo.RestoreFood(context.Background(), "guild", "김치 찌개", models.FoodTypeLunch)
+		if err != nil {
+			mt.Fatalf("RestoreFood() error = %v", err)
+		}
+		if food.ID != id || !food.IsActive {
+			mt.Errorf("RestoreFood() = %+v, want the reactivated food", food)
+		}
+
+		cmd := startedCommands(mt, "findAndModify")[0]
+		query := cmd.Lookup("query").Document()
+		if active := query.Lookup("is_active").Boolean(); active {
+			mt.Error("restored an active food, want only deleted ones")
+		}
+		if got := query.Lookup("normalized_name").StringValue(); got != "김치찌개" {
+			mt.Errorf("restored normalized name %q, want %q", got, "김치찌개")
+		}
+		if !cmd.Lookup("update", "$set", "is_active").Boolean() {
+			mt.Error("restore didn't set is_active")
+		}
+	})
+
+	mt.Run("nothing deleted", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}), cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.RestoreFood(context.Background(), "guild", "김치찌개", models.FoodTypeLunch); !errors.Is(err, ErrNotFound) {
+			mt.Errorf("RestoreFood() error = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestGetRandomFoodConcurrent(t *testing.T) {
+	db := integrationMongoDB(t)
+	ctx := context.Background()
+
+	var foods []interface{}
+	for _, name := range []string{"김치찌개", "된장찌개", "비빔밥", "냉면", "돈까스"} {
+		foods = append(foods, models.NewFood(name, models.FoodTypeLunch, "user-1"))
+	}
+	if _, err := db.Collection("foods").InsertMany(ctx, foods); err != nil {
+		t.Fatalf("InsertMany() error = %v", err)
+	}
+
+	repo := NewFoodRepository(db, zap.NewNop())
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			food, err := repo.GetRandomFood(ctx, "", models.FoodTypeLunch)
+			if err == nil && food.FoodType != models.FoodTypeLunch {
+				err = fmt.Errorf("got a %s food, want lunch", food.FoodType)
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetRandomFood() error = %v", err)
+		}
+	}
+}
+
+func TestGetRandomFood(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("sample", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse(bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "name", Value: "김치찌개"},
+			{Key: "food_type", Value: "lunch"},
+			{Key: "guild_id", Value: "guild-1"},
+			{Key: "is_active", Value: true},
+		}))
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		food, err := repo.GetRandomFood(context.Background(), "guild-1", models.FoodTypeLunch)
+		if err != nil {
+			mt.Fatalf("GetRandomFood() error = %v", err)
+		}
+		if food.Name != "김치찌개" {
+			mt.Errorf("food = %q, want 김치찌개", food.Name)
+		}
+
+		if counts := startedCommands(mt, "count"); len(counts) != 0 {
+			mt.Errorf("sent %d counts, want a single aggregate", len(counts))
+		}
+		aggregates := startedCommands(mt, "aggregate")
+		if len(aggregates) != 1 {
+			mt.Fatalf("sent %d aggregates, want 1", len(aggregates))
+		}
+		match := aggregates[0].Lookup("pipeline", "0", "$match").Document()
+		if foodType := match.Lookup("food_type").StringValue(); foodType != "lunch" {
+			mt.Errorf("matched food_type %q, want lunch", foodType)
+		}
+		if !match.Lookup("is_active").Boolean() {
+			mt.Error("matched inactive foods")
+		}
+		guilds, _ := match.Lookup("guild_id", "$in").Array().Values()
+		if len(guilds) != 2 || guilds[0].StringValue() != "guild-1" || guilds[1].StringValue() != "" {
+			mt.Errorf("matched guilds %v, want the guild's and the shared list", guilds)
+		}
+		if size := aggregates[0].Lookup("pipeline", "1", "$sample", "size").Int32(); size != 1 {
+			mt.Errorf("sampled %d foods, want 1", size)
+		}
+	})
+
+	mt.Run("empty", func(mt *mtest.T) {
+		mt.AddMockResponses(cursorResponse())
+
+		repo := NewFoodRepository(newMockMongoDB(mt), zap.NewNop())
+		if _, err := repo.GetRandomFood(context.Background(), "", models.FoodTypeDinner); !errors.Is(err, ErrNoFoods) {
+			mt.Errorf("GetRandomFood() error = %v, want ErrNoFoods", err)
+		}
+	})
+}
+
+func TestGetRandomFoodSamplesActiveFoodOfType(t *testing.T) {
+	db := integrationMongoDB(t)
+	ctx := context.Background()
+
+	lunch := models.NewFood("김치찌개", models.FoodTypeLunch, "user-1")
+	deleted := models.NewFood("된장찌개", models.FoodTypeLunch, "user-1")
+	deleted.IsActive = false
+	dinner := models.NewFood("삼겹살", models.FoodTypeDinner, "user-1")
+	if _, err := db.Collection("foods").InsertMany(ctx, []interface{}{lunch, deleted, dinner}); err != nil {
+		t.Fatalf("InsertMany() error = %v", err)
+	}
+
+	repo := NewFoodRepository(db, zap.NewNop())
+	for i := 0; i < 20; i++ {
+		food, err := repo.GetRandomFood(ctx, "", models.FoodTypeLunch)
+		if err != nil {
+			t.Fatalf("GetRandomFood() error = %v", err)
+		}
+		if food.Name != "김치찌개" {
+			t.Fatalf("GetRandomFood() = %q, want the only active lunch", food.Name)
+		}
+	}
+
+	if _, err := db.Collection("foods").DeleteMany(ctx, bson.M{}); err != nil {
+		t.Fatalf("DeleteMany() error = %v", err)
+	}
+	if _, err := repo.GetRandomFood(ctx, "", models.FoodTypeLunch); !errors.Is(err, ErrNoFoods) {
+		t.Errorf("GetRandomFood() on an empty collection error = %v, want ErrNoFoods", err)
+	}
+}
+
+func TestFoodMutationsAreIsolatedBetweenGuilds(t *testing.T) {
+	db := integrationMongoDB(t)
+	ctx := context.Background()
+
+	shared := models.NewFood("김치찌개", models.FoodTypeLunch, "user-1")
+	own := models.NewFood("된장찌개", models.FoodTypeLunch, "user-1")
+	own.GuildID = "guild-a"
+	if _, err := db.Collection("foods").InsertMany(ctx, []interface{}{shared, own}); err != nil {
+		t.Fatalf("InsertMany() error = %v", err)
+	}
+
+	repo := NewFoodRepository(db, zap.NewNop())
+	if err := repo.DeleteFood(ctx, "guild-a", "김치찌개", models.FoodTypeLunch); !errors.Is(err, ErrSharedFood) {
+		t.Errorf("DeleteFood() of a shared food error = %v, want ErrSharedFood", err)
+	}
+	if _, err := repo.RenameFood(ctx, "guild-a", "김치찌개", "김치전골", models.FoodTypeLunch); !errors.Is(err, ErrSharedFood) {
+		t.Errorf("RenameFood() of a shared food error = %v, want ErrSharedFood", err)
+	}
+	if err := repo.DeleteFood(ctx, "guild-a", "된장찌개", models.FoodTypeLunch); err != nil {
+		t.Fatalf("DeleteFood() of the guild's own food error = %v", err)
+	}
+
+	foods, err := repo.GetAllFoods(ctx, "guild-b", models.FoodTypeLunch)
+	if err != nil {
+		t.Fatalf("GetAllFoods() error = %v", err)
+	}
+	if len(foods) != 1 || foods[0].Name != "김치찌개" || !foods[0].IsActive {
+		t.Errorf("guild-b sees %+v, want the unchanged shared food", foods)
+	}
+}
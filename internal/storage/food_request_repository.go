@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// FoodRequestRepository handles persistence for food recommendation requests
+type FoodRequestRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewFoodRequestRepository creates a new food request repository
+func NewFoodRequestRepository(db *MongoDB, log *zap.Logger) *FoodRequestRepository {
+	return &FoodRequestRepository{
+		db:  db,
+		log: log.Named("food-request-repository"),
+	}
+}
+
+// RecordRequest stores a food recommendation request
+func (r *FoodRequestRepository) RecordRequest(ctx context.Context, request *models.FoodRequest) error {
+	collection := r.db.Collection("food_requests")
+
+	if _, err := collection.InsertOne(ctx, request); err != nil {
+		return fmt.Errorf("failed to record food request: %w", err)
+	}
+
+	return nil
+}
+
+// GetTopFoodsByUser returns the foods most often recommended to a user, most frequent first
+func (r *FoodRequestRepository) GetTopFoodsByUser(ctx context.Context, userID string, limit int) ([]models.FoodRequestStat, error) {
+	collection := r.db.Collection("food_requests")
+
+	cursor, err := collection.Aggregate(ctx, topFoodsByUserPipeline(userID, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate food requests: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stats []models.FoodRequestStat
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, fmt.Errorf("failed to decode food request stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// topFoodsByUserPipeline builds the aggregation used by GetTopFoodsByUser.
+// Requests are counted per food and joined with the foods collection for
+// names; foods that no longer exist are dropped. A non-positive limit returns
+// every food.
+func topFoodsByUserPipeline(userID string, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$food_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "count", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "foods",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "food",
+		}}},
+		{{Key: "$unwind", Value: "$food"}},
+		{{Key: "$project", Value: bson.M{
+			"count":     1,
+			"name":      "$food.name",
+			"food_type": "$food.food_type",
+		}}},
+	}
+
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	return pipeline
+}
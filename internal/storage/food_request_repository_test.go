@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestTopFoodsByUserPipeline(t *testing.T) {
+	pipeline := topFoodsByUserPipeline("user-1", 5)
+
+	if len(pipeline) != 7 {
+		t.Fatalf("pipeline has %d stages, want 7", len(pipeline))
+	}
+	if pipeline[0][0].Key != "$match" || !reflect.DeepEqual(pipeline[0][0].Value, bson.M{"user_id": "user-1"}) {
+		t.Errorf("first stage = %v, want a match on the user", pipeline[0])
+	}
+	sort := bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}
+	if pipeline[2][0].Key != "$sort" || !reflect.DeepEqual(pipeline[2][0].Value, sort) {
+		t.Errorf("third stage = %v, want $sort %v", pipeline[2], sort)
+	}
+	if last := pipeline[len(pipeline)-1][0]; last.Key != "$limit" || last.Value != 5 {
+		t.Errorf("last stage = %v, want $limit 5", last)
+	}
+
+	if unlimited := topFoodsByUserPipeline("user-1", 0); len(unlimited) != 6 {
+		t.Errorf("pipeline without a limit has %d stages, want 6", len(unlimited))
+	}
+}
+
+func TestGetTopFoodsByUser(t *testing.T) {
+	mt := newMockTest(t)
+
+	mt.Run("top foods", func(mt *mtest.T) {
+		kimchi, bibimbap := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(cursorResponse(
+			bson.D{{Key: "_id", Value: kimchi}, {Key: "name", Value: "김치찌개"}, {Key: "food_type", Value: "lunch"}, {Key: "count", Value: 4}},
+			bson.D{{Key: "_id", Value: bibimbap}, {Key: "name", Value: "비빔밥"}, {Key: "food_type", Value: "dinner"}, {Key: "count", Value: 2}},
+		))
+
+		repo := NewFoodRequestRepository(newMockMongoDB(mt), zap.NewNop())
+		stats, err := repo.GetTopFoodsByUser(context.Background(), "user-1", 5)
+		if err != nil {
+			mt.Fatalf("GetTopFoodsByUser() error = %v", err)
+		}
+
+		want := []models.FoodRequestStat{
+			{FoodID: kimchi, Name: "김치찌개", FoodType: models.FoodTypeLunch, Count: 4},
+			{FoodID: bibimbap, Name: "비빔밥", FoodType: models.FoodTypeDinner, Count: 2},
+		}
+		if !reflect.DeepEqual(stats, want) {
+			mt.Errorf("GetTopFoodsByUser() = %+v, want %+v", stats, want)
+		}
+		aggregate := startedCommands(mt, "aggregate")[0]
+		if got := aggregate.Lookup("aggregate").StringValue(); got != "food_requests" {
+			mt.Errorf("aggregated %q, want food_requests", got)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// GuildSettingsRepository handles persistence for per-guild bot settings
+type GuildSettingsRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewGuildSettingsRepository creates a new guild settings repository
+func NewGuildSettingsRepository(db *MongoDB, log *zap.Logger) *GuildSettingsRepository {
+	return &GuildSettingsRepository{
+		db:  db,
+		log: log.Named("guild-settings-repository"),
+	}
+}
+
+// GetSettings returns a guild's settings, or nil if the guild has none
+func (r *GuildSettingsRepository) GetSettings(ctx context.Context, guildID string) (*models.GuildSettings, error) {
+	collection := r.db.Collection("guild_settings")
+
+	var settings models.GuildSettings
+	err := collection.FindOne(ctx, bson.M{"_id": guildID}).Decode(&settings)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get guild settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SetPrefix sets a guild's command prefix; an empty prefix restores the default
+func (r *GuildSettingsRepository) SetPrefix(ctx context.Context, guildID, prefix, updatedBy string) error {
+	collection := r.db.Collection("guild_settings")
+
+	set := bson.M{
+		"updated_by": updatedBy,
+		"updated_at": time.Now(),
+	}
+	update := bson.M{"$set": set}
+	if prefix == "" {
+		update["$unset"] = bson.M{"prefix": ""}
+	} else {
+		set["prefix"] = prefix
+	}
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": guildID}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to set guild prefix: %w", err)
+	}
+
+	return nil
+}
@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -26,33 +28,34 @@ func NewMongoDB(cfg *config.Config) (*MongoDB, error) {
 	// Create logger
 	log, _ := zap.NewProduction()
 	logger := log.Named("mongodb")
-	
+
 	// Determine default URI
 	uri := cfg.MongoDBURI
-	
+
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DBOperationTimeout())
 	defer cancel()
-	
+
 	// Connect to MongoDB
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	client, err := mongo.Connect(ctx, clientOptions(cfg, uri))
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", config.RedactError(err))
 	}
-	
+
 	// Ping the database
 	if err := client.Ping(ctx, readpref.Primary()); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
-	}
-	
-	logger.Info("Connected to MongoDB", zap.String("uri", uri))
-	
-	// Use default database
-	dbName := "discord_bot"
-	if cfg.IsDevelopment {
-		dbName = "discord_bot_dev"
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", config.RedactError(err))
 	}
-	
+
+	// Use the configured database
+	dbName := cfg.DatabaseName()
+
+	// Never log the URI itself; it may carry credentials
+	logger.Info("Connected to MongoDB",
+		zap.String("hosts", uriHosts(uri)),
+		zap.String("database", dbName))
+	logPoolSettings(logger, cfg)
+
 	return &MongoDB{
 		client: client,
 		db:     client.Database(dbName),
@@ -61,15 +64,111 @@ func NewMongoDB(cfg *config.Config) (*MongoDB, error) {
 	}, nil
 }
 
+// NewMongoDBFromClient wraps a client that is already connected, using the
+// database cfg names. The caller owns the connection; tests pass a mock client.
+func NewMongoDBFromClient(client *mongo.Client, cfg *config.Config, log *zap.Logger) *MongoDB {
+	return &MongoDB{
+		client: client,
+		db:     client.Database(cfg.DatabaseName()),
+		log:    log,
+		cfg:    cfg,
+	}
+}
+
+// uriHosts returns the host list of a MongoDB URI ("h1:27017,h2:27017") with
+// the scheme, credentials, database and options stripped, for logging
+func uriHosts(uri string) string {
+	hosts := uri
+	if i := strings.Index(hosts, "://"); i >= 0 {
+		hosts = hosts[i+3:]
+	}
+	if i := strings.Index(hosts, "?"); i >= 0 {
+		hosts = hosts[:i]
+	}
+	// Strip credentials before the database path, since an unescaped
+	// password may itself contain a slash
+	if i := strings.LastIndex(hosts, "@"); i >= 0 {
+		hosts = hosts[i+1:]
+	}
+	if i := strings.Index(hosts, "/"); i >= 0 {
+		hosts = hosts[:i]
+	}
+	return hosts
+}
+
+// clientOptions builds the client options for uri, applying the pool and
+// timeout settings from cfg. Unset (zero) settings keep the driver defaults.
+func clientOptions(cfg *config.Config, uri string) *options.ClientOptions {
+	opts := options.Client().ApplyURI(uri)
+
+	if cfg.MongoDBMaxPoolSize > 0 {
+		opts.SetMaxPoolSize(cfg.MongoDBMaxPoolSize)
+	}
+	if cfg.MongoDBMinPoolSize > 0 {
+		opts.SetMinPoolSize(cfg.MongoDBMinPoolSize)
+	}
+	if cfg.MongoDBConnectTimeoutSeconds > 0 {
+		opts.SetConnectTimeout(time.Duration(cfg.MongoDBConnectTimeoutSeconds) * time.Second)
+	}
+	if cfg.MongoDBSocketTimeoutSeconds > 0 {
+		opts.SetSocketTimeout(time.Duration(cfg.MongoDBSocketTimeoutSeconds) * time.Second)
+	}
+
+	return opts
+}
+
+// logPoolSettings logs the effective connection pool settings; zero means the driver default
+func logPoolSettings(log *zap.Logger, cfg *config.Config) {
+	log.Info("MongoDB connection pool settings",
+		zap.Uint64("max_pool_size", cfg.MongoDBMaxPoolSize),
+		zap.Uint64("min_pool_size", cfg.MongoDBMinPoolSize),
+		zap.Int("connect_timeout_seconds", cfg.MongoDBConnectTimeoutSeconds),
+		zap.Int("socket_timeout_seconds", cfg.MongoDBSocketTimeoutSeconds))
+}
+
 // Disconnect closes the MongoDB connection
 func (m *MongoDB) Disconnect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.DBOperationTimeout())
 	defer cancel()
-	
+
 	m.log.Info("Closing MongoDB connection")
 	return m.client.Disconnect(ctx)
 }
 
+// OperationContext returns a context for a one-off database operation,
+// bounded by the configured DB operation timeout
+func (m *MongoDB) OperationContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, m.cfg.DBOperationTimeout())
+}
+
+// Ping checks that the primary is reachable
+func (m *MongoDB) Ping(ctx context.Context) error {
+	if err := m.client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("failed to ping MongoDB: %w", config.RedactError(err))
+	}
+	return nil
+}
+
+// IndexNames returns the names of the indexes on every collection in the current database
+func (m *MongoDB) IndexNames(ctx context.Context) (map[string][]string, error) {
+	collections, err := m.db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	names := make(map[string][]string, len(collections))
+	for _, collection := range collections {
+		specs, err := m.db.Collection(collection).Indexes().ListSpecifications(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list indexes on %s: %w", collection, err)
+		}
+		for _, spec := range specs {
+			names[collection] = append(names[collection], spec.Name)
+		}
+	}
+	return names, nil
+}
+
 // Collection returns a MongoDB collection
 func (m *MongoDB) Collection(name string) *mongo.Collection {
 	return m.db.Collection(name)
@@ -96,25 +195,26 @@ func (m *MongoDB) UseWebcrawlerDatabase() {
 	// Use webcrawler-specific URI if available
 	if m.cfg.MongoDBURIWebcrawler != "" {
 		// Create a new connection to the webcrawler database
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.DBOperationTimeout())
 		defer cancel()
-		
+
 		// Connect to MongoDB
-		client, err := mongo.Connect(ctx, options.Client().ApplyURI(m.cfg.MongoDBURIWebcrawler))
+		client, err := mongo.Connect(ctx, clientOptions(m.cfg, m.cfg.MongoDBURIWebcrawler))
 		if err != nil {
-			m.log.Error("Failed to connect to webcrawler MongoDB, using default instead", 
-				zap.Error(err))
+			m.log.Error("Failed to connect to webcrawler MongoDB, using default instead",
+				zap.Error(config.RedactError(err)))
 		} else {
 			// Successful connection, update client and database
 			m.client = client
-			m.db = client.Database("webcrawler")
-			m.log.Info("Connected to webcrawler MongoDB", 
-				zap.String("uri", m.cfg.MongoDBURIWebcrawler))
+			m.db = client.Database(m.cfg.MongoDBNameWebcrawler)
+			m.log.Info("Connected to webcrawler MongoDB",
+				zap.String("hosts", uriHosts(m.cfg.MongoDBURIWebcrawler)),
+				zap.String("database", m.cfg.MongoDBNameWebcrawler))
 			return
 		}
 	}
-	
+
 	// Fallback to using the default client with a different database
 	m.log.Info("Using webcrawler database with default connection")
-	m.db = m.client.Database("webcrawler")
-}
\ No newline at end of file
+	m.db = m.client.Database(m.cfg.MongoDBNameWebcrawler)
+}
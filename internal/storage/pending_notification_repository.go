@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// PendingNotificationRepository handles persistence for notifications held back by quiet hours
+type PendingNotificationRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewPendingNotificationRepository creates a new pending notification repository
+func NewPendingNotificationRepository(db *MongoDB, log *zap.Logger) *PendingNotificationRepository {
+	return &PendingNotificationRepository{
+		db:  db,
+		log: log.Named("pending-notification-repository"),
+	}
+}
+
+// Enqueue stores a notification to be delivered later
+func (r *PendingNotificationRepository) Enqueue(ctx context.Context, pending *models.PendingNotification) error {
+	collection := r.db.Collection("pending_notifications")
+
+	result, err := collection.InsertOne(ctx, pending)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		pending.ID = id
+	}
+
+	return nil
+}
+
+// GetDue returns pending notifications whose delivery time is at or before now, oldest first
+func (r *PendingNotificationRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]models.PendingNotification, error) {
+	collection := r.db.Collection("pending_notifications")
+
+	opts := options.Find().SetSort(bson.D{{Key: "deliver_at", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{"deliver_at": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var pending []models.PendingNotification
+	if err := cursor.All(ctx, &pending); err != nil {
+		return nil, fmt.Errorf("failed to decode pending notifications: %w", err)
+	}
+
+	return pending, nil
+}
+
+// Delete removes delivered (or undeliverable) notifications
+func (r *PendingNotificationRepository) Delete(ctx context.Context, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	collection := r.db.Collection("pending_notifications")
+
+	_, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return fmt.Errorf("failed to delete pending notifications: %w", err)
+	}
+
+	return nil
+}
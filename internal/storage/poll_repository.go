@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// PollRepository handles persistence for reaction polls
+type PollRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewPollRepository creates a new poll repository
+func NewPollRepository(db *MongoDB, log *zap.Logger) *PollRepository {
+	return &PollRepository{
+		db:  db,
+		log: log.Named("poll-repository"),
+	}
+}
+
+// SavePoll stores a new poll
+func (r *PollRepository) SavePoll(ctx context.Context, poll *models.Poll) error {
+	collection := r.db.Collection("polls")
+
+	result, err := collection.InsertOne(ctx, poll)
+	if err != nil {
+		return fmt.Errorf("failed to save poll: %w", err)
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		poll.ID = id
+	}
+
+	return nil
+}
+
+// GetPollByMessageID returns the poll posted as the given message, or nil if there is none
+func (r *PollRepository) GetPollByMessageID(ctx context.Context, messageID string) (*models.Poll, error) {
+	collection := r.db.Collection("polls")
+
+	var poll models.Poll
+	err := collection.FindOne(ctx, bson.M{"message_id": messageID}).Decode(&poll)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find poll: %w", err)
+	}
+
+	return &poll, nil
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ProductRepository handles queries over crawled products
+type ProductRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewProductRepository creates a new product repository
+func NewProductRepository(db *MongoDB, log *zap.Logger) *ProductRepository {
+	return &ProductRepository{
+		db:  db,
+		log: log.Named("product-repository"),
+	}
+}
+
+// GetPopularProducts returns the most-commented, then most-viewed products
+// crawled within [from, to)
+func (r *ProductRepository) GetPopularProducts(ctx context.Context, from, to time.Time, limit int) ([]models.Product, error) {
+	collection := r.db.Collection("products")
+
+	cursor, err := collection.Aggregate(ctx, popularProductsPipeline(from, to, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate popular products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode popular products: %w", err)
+	}
+
+	return products, nil
+}
+
+// GetTopByEngagement returns the products crawled within [from, to) with the
+// most comments and views combined, most engaging first
+func (r *ProductRepository) GetTopByEngagement(ctx context.Context, from, to time.Time, limit int) ([]models.Product, error) {
+	collection := r.db.Collection("products")
+
+	cursor, err := collection.Aggregate(ctx, engagementPipeline(from, to, limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate products by engagement: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	return products, nil
+}
+
+// SearchProducts runs a full-text search over product titles using the
+// products text index and returns matches ordered by relevance.
+// An empty source matches every source; otherwise the source is compared
+// case-insensitively.
+func (r *ProductRepository) SearchProducts(ctx context.Context, query, source string, skip, limit int) ([]models.Product, error) {
+	collection := r.db.Collection("products")
+
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "crawled_at", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, searchProductsFilter(query, source), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []models.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	return products, nil
+}
+
+// ProductListOptions filters, sorts and pages ListProducts
+type ProductListOptions struct {
+	Source string // 비어 있으면 모든 출처
+	Query  string // 제목에 포함될 문자열 (대소문자 무시)
+	Sort   string // 정렬 필드, "-" 접두사는 내림차순 (기본값 "-crawled_at")
+	Offset int
+	Limit  int
+}
+
+// productSortFields maps ListProducts sort names to document fields
+var productSortFields = map[string]string{
+	"crawled_at": "crawled_at",
+	"price":      "ko_price",
+	"discount":   "discount_rate",
+	"comments":   "comments",
+	"views":      "views",
+}
+
+// ListProducts returns a page of products matching the options.
+// It returns an error wrapping ErrInvalidSort for an unsupported sort field.
+func (r *ProductRepository) ListProducts(ctx context.Context, opts ProductListOptions) ([]models.Product, error) {
+	collection := r.db.Collection("products")
+
+	sort, err := parseSort(opts.Sort, "-crawled_at", productSortFields)
+	if err != nil {
+		return nil, err
+	}
+
+	findOpts := options.Find().
+		SetSort(sort).
+		SetSkip(int64(opts.Offset)).
+		SetLimit(int64(opts.Limit))
+
+	cursor, err := collection.Find(ctx, listProductsFilter(opts.Query, opts.Source), findOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	products := []models.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	return products, nil
+}
+
+// listProductsFilter builds the filter used by ListProducts.
+// Unlike SearchProducts it matches substrings, so any sort order can be used.
+func listProductsFilter(query, source string) bson.M {
+	filter := bson.M{}
+	if query != "" {
+		filter["title"] = primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+	}
+	if source != "" {
+		filter["source"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(source) + "$", Options: "i"}
+	}
+	return filter
+}
+
+// searchProductsFilter builds the filter used by SearchProducts
+func searchProductsFilter(query, source string) bson.M {
+	filter := bson.M{
+		"$text": bson.M{"$search": query},
+	}
+	if source != "" {
+		filter["source"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(source) + "$", Options: "i"}
+	}
+	return filter
+}
+
+// popularProductsPipeline builds the aggregation used by GetPopularProducts.
+// A non-positive limit returns every product in the range.
+func popularProductsPipeline(from, to time.Time, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"crawled_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "comments", Value: -1},
+			{Key: "views", Value: -1},
+			{Key: "crawled_at", Value: -1},
+		}}},
+	}
+
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	return pipeline
+}
+
+// engagementPipeline builds the aggregation used by GetTopByEngagement.
+// Missing comment and view counts count as zero.
+// A non-positive limit returns every product in the range.
+func engagementPipeline(from, to time.Time, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"crawled_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"engagement": bson.M{"$add": bson.A{
+				bson.M{"$ifNull": bson.A{"$comments", 0}},
+				bson.M{"$ifNull": bson.A{"$views", 0}},
+			}},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "engagement", Value: -1},
+			{Key: "crawled_at", Value: -1},
+		}}},
+	}
+
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	return pipeline
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPopularProductsPipeline(t *testing.T) {
+	from := time.Date(2026, time.October, 9, 9, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+
+	pipeline := popularProductsPipeline(from, to, 30)
+
+	if len(pipeline) != 3 {
+		t.Fatalf("pipeline has %d stages, want match, sort and limit", len(pipeline))
+	}
+	match := bson.M{"crawled_at": bson.M{"$gte": from, "$lt": to}}
+	if pipeline[0][0].Key != "$match" || !reflect.DeepEqual(pipeline[0][0].Value, match) {
+		t.Errorf("first stage = %v, want $match %v", pipeline[0], match)
+	}
+	sort := bson.D{{Key: "comments", Value: -1}, {Key: "views", Value: -1}, {Key: "crawled_at", Value: -1}}
+	if pipeline[1][0].Key != "$sort" || !reflect.DeepEqual(pipeline[1][0].Value, sort) {
+		t.Errorf("second stage = %v, want $sort %v", pipeline[1], sort)
+	}
+	if pipeline[2][0].Key != "$limit" || pipeline[2][0].Value != 30 {
+		t.Errorf("last stage = %v, want $limit 30", pipeline[2])
+	}
+
+	if unlimited := popularProductsPipeline(from, to, 0); len(unlimited) != 2 {
+		t.Errorf("pipeline without a limit has %d stages, want 2", len(unlimited))
+	}
+}
+
+func TestEngagementPipeline(t *testing.T) {
+	to := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	from := to.Add(-48 * time.Hour)
+
+	pipeline := engagementPipeline(from, to, 10)
+
+	if len(pipeline) != 4 {
+		t.Fatalf("pipeline has %d stages, want match, addFields, sort and limit", len(pipeline))
+	}
+	match := bson.M{"crawled_at": bson.M{"$gte": from, "$lt": to}}
+	if pipeline[0][0].Key != "$match" || !reflect.DeepEqual(pipeline[0][0].Value, match) {
+		t.Errorf("first stage = %v, want $match %v", pipeline[0], match)
+	}
+	engagement := bson.M{"engagement": bson.M{"$add": bson.A{
+		bson.M{"$ifNull": bson.A{"$comments", 0}},
+		bson.M{"$ifNull": bson.A{"$views", 0}},
+	}}}
+	if pipeline[1][0].Key != "$addFields" || !reflect.DeepEqual(pipeline[1][0].Value, engagement) {
+		t.Errorf("second stage = %v, want $addFields %v", pipeline[1], engagement)
+	}
+	sort := bson.D{{Key: "engagement", Value: -1}, {Key: "crawled_at", Value: -1}}
+	if pipeline[2][0].Key != "$sort" || !reflect.DeepEqual(pipeline[2][0].Value, sort) {
+		t.Errorf("third stage = %v, want $sort %v", pipeline[2], sort)
+	}
+	if pipeline[3][0].Key != "$limit" || pipeline[3][0].Value != 10 {
+		t.Errorf("last stage = %v, want $limit 10", pipeline[3])
+	}
+
+	if unlimited := engagementPipeline(from, to, 0); len(unlimited) != 3 {
+		t.Errorf("pipeline without a limit has %d stages, want 3", len(unlimited))
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ReminderRepository handles persistence for user reminders
+type ReminderRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewReminderRepository creates a new reminder repository
+func NewReminderRepository(db *MongoDB, log *zap.Logger) *ReminderRepository {
+	return &ReminderRepository{
+		db:  db,
+		log: log.Named("reminder-repository"),
+	}
+}
+
+// EnsureIndexes creates the index used by the due-reminder query
+func (r *ReminderRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.db.Collection("reminders")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "fired", Value: 1}, {Key: "fire_at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reminders index: %w", err)
+	}
+
+	return nil
+}
+
+// SaveReminder stores a new reminder
+func (r *ReminderRepository) SaveReminder(ctx context.Context, reminder *models.Reminder) error {
+	collection := r.db.Collection("reminders")
+
+	result, err := collection.InsertOne(ctx, reminder)
+	if err != nil {
+		return fmt.Errorf("failed to save reminder: %w", err)
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		reminder.ID = id
+	}
+
+	return nil
+}
+
+// GetDueReminders returns unfired reminders whose fire time is at or before now, oldest first
+func (r *ReminderRepository) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]models.Reminder, error) {
+	collection := r.db.Collection("reminders")
+
+	opts := options.Find().SetSort(bson.D{{Key: "fire_at", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := collection.Find(ctx, dueRemindersFilter(now), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due reminders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var reminders []models.Reminder
+	if err := cursor.All(ctx, &reminders); err != nil {
+		return nil, fmt.Errorf("failed to decode reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// MarkFired marks a reminder as delivered so it is not sent again
+func (r *ReminderRepository) MarkFired(ctx context.Context, id primitive.ObjectID) error {
+	collection := r.db.Collection("reminders")
+
+	_, err := collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{"fired": true}})
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder fired: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduleRetry records a failed delivery attempt and moves the reminder's
+// fire time to retryAt. Moving it behind newer due reminders keeps one that
+// keeps failing from holding up the rest of the batch.
+func (r *ReminderRepository) ScheduleRetry(ctx context.Context, id primitive.ObjectID, attempts int, retryAt time.Time) error {
+	collection := r.db.Collection("reminders")
+
+	_, err := collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"attempts": attempts,
+		"fire_at":  retryAt,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to schedule reminder retry: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed gives up on a reminder after its last failed attempt. It is
+// marked fired so it is never picked up again, and failed to tell it apart
+// from delivered reminders.
+func (r *ReminderRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, attempts int) error {
+	collection := r.db.Collection("reminders")
+
+	_, err := collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{
+		"attempts": attempts,
+		"fired":    true,
+		"failed":   true,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder failed: %w", err)
+	}
+
+	return nil
+}
+
+// dueRemindersFilter builds the filter for reminders that should fire at now
+func dueRemindersFilter(now time.Time) bson.M {
+	return bson.M{
+		"fired":   false,
+		"fire_at": bson.M{"$lte": now},
+	}
+}
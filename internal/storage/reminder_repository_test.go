@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// reminderUpdate returns the $set document of the only update sent
+func reminderUpdate(mt *mtest.T) bson.Raw {
+	updates := startedCommands(mt, "update")
+	if len(updates) != 1 {
+		mt.Fatalf("sent %d updates, want 1", len(updates))
+	}
+	return updates[0].Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set").Document()
+}
+
+func TestReminderFailures(t *testing.T) {
+	mt := newMockTest(t)
+	id := primitive.NewObjectID()
+
+	mt.Run("retry", func(mt *mtest.T) {
+		repo := NewReminderRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(writeResponse(1))
+		retryAt := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+
+		if err := repo.ScheduleRetry(context.Background(), id, 2, retryAt); err != nil {
+			mt.Fatalf("ScheduleRetry() error = %v", err)
+		}
+
+		set := reminderUpdate(mt)
+		if got := set.Lookup("attempts").Int32(); got != 2 {
+			mt.Errorf("attempts = %d, want 2", got)
+		}
+		if got := set.Lookup("fire_at").Time(); !got.Equal(retryAt) {
+			mt.Errorf("fire_at = %v, want %v", got, retryAt)
+		}
+		if _, err := set.LookupErr("fired"); err == nil {
+			mt.Error("retry marked the reminder fired")
+		}
+	})
+
+	mt.Run("give up", func(mt *mtest.T) {
+		repo := NewReminderRepository(newMockMongoDB(mt), zap.NewNop())
+		mt.AddMockResponses(writeResponse(1))
+
+		if err := repo.MarkFailed(context.Background(), id, 5); err != nil {
+			mt.Fatalf("MarkFailed() error = %v", err)
+		}
+
+		set := reminderUpdate(mt)
+		if !set.Lookup("fired").Boolean() || !set.Lookup("failed").Boolean() {
+			mt.Errorf("update = %v, want fired and failed", set)
+		}
+	})
+}
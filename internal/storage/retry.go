@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// retryPolicy controls how WithRetry backs off between attempts
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// defaultRetryPolicy retries a write up to three times over roughly a second,
+// which covers a primary election on a healthy replica set
+var defaultRetryPolicy = retryPolicy{
+	attempts:  4,
+	baseDelay: 100 * time.Millisecond,
+	maxDelay:  2 * time.Second,
+}
+
+// retryableErrorCodes are server error codes for conditions that clear up on
+// their own, such as a primary stepping down or shutting down
+var retryableErrorCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	262,   // ExceededTimeLimit
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// WithRetry runs op, retrying it with exponential backoff while it fails with
+// a transient MongoDB error (see IsRetryable). Other errors, including
+// duplicate key errors, are returned immediately. It stops waiting as soon
+// as ctx is done and returns the last error from op.
+//
+// op may run more than once, so it should be safe to repeat: inserts should
+// carry their own _id and updates should be idempotent.
+func WithRetry(ctx context.Context, op func(ctx context.Context) error) error {
+	return defaultRetryPolicy.do(ctx, op)
+}
+
+// do runs op under the policy
+func (p retryPolicy) do(ctx context.Context, op func(ctx context.Context) error) error {
+	delay := p.baseDelay
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil || attempt >= p.attempts || !IsRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		delay *= 2
+		if delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+}
+
+// IsRetryable reports whether err is a transient MongoDB error worth retrying:
+// a network error, or a server error with a retryable label or code.
+// Duplicate key errors and context cancellation are never retryable.
+func IsRetryable(err error) bool {
+	if err == nil || mongo.IsDuplicateKeyError(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	if serverErr.HasErrorLabel("RetryableWriteError") || serverErr.HasErrorLabel("TransientTransactionError") {
+		return true
+	}
+	for _, code := range retryableErrorCodes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bradykim7/gbot/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// shortLinkTokenAttempts is how many fresh tokens are tried when one collides
+const shortLinkTokenAttempts = 3
+
+// ShortLinkRepository handles persistence for click-tracking short links and their clicks
+type ShortLinkRepository struct {
+	db  *MongoDB
+	log *zap.Logger
+}
+
+// NewShortLinkRepository creates a new short link repository
+func NewShortLinkRepository(db *MongoDB, log *zap.Logger) *ShortLinkRepository {
+	return &ShortLinkRepository{
+		db:  db,
+		log: log.Named("short-link-repository"),
+	}
+}
+
+// EnsureIndexes creates the unique URL index so each product gets a single short link
+func (r *ShortLinkRepository) EnsureIndexes(ctx context.Context) error {
+	collection := r.db.Collection("short_links")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "url", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create short_links index: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrCreateLink returns the short link for a URL, creating one with a new
+// random token if the URL has none yet
+func (r *ShortLinkRepository) GetOrCreateLink(ctx context.Context, url, title string) (*models.ShortLink, error) {
+	collection := r.db.Collection("short_links")
+
+	var lastErr error
+	for attempt := 0; attempt < shortLinkTokenAttempts; attempt++ {
+		token, err := models.NewShortLinkToken()
+		if err != nil {
+			return nil, err
+		}
+
+		var link models.ShortLink
+		err = collection.FindOneAndUpdate(ctx,
+			bson.M{"url": url},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":           token,
+				"product_title": title,
+				"clicks":        0,
+				"created_at":    time.Now(),
+			}},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&link)
+		if err == nil {
+			return &link, nil
+		}
+
+		// A duplicate key means the token collided or another writer created
+		// the URL's link concurrently; either way a retry resolves it
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("failed to create short link: %w", err)
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("failed to create short link after %d attempts: %w", shortLinkTokenAttempts, lastErr)
+}
+
+// GetLink returns the short link for a token, or nil if the token is unknown
+func (r *ShortLinkRepository) GetLink(ctx context.Context, token string) (*models.ShortLink, error) {
+	collection := r.db.Collection("short_links")
+
+	var link models.ShortLink
+	err := collection.FindOne(ctx, bson.M{"_id": token}).Decode(&link)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get short link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// RecordClick stores a click and increments the link's click counter
+func (r *ShortLinkRepository) RecordClick(ctx context.Context, click *models.Click) error {
+	if _, err := r.db.Collection("clicks").InsertOne(ctx, click); err != nil {
+		return fmt.Errorf("failed to record click: %w", err)
+	}
+
+	_, err := r.db.Collection("short_links").UpdateByID(ctx, click.Token, bson.M{"$inc": bson.M{"clicks": 1}})
+	if err != nil {
+		return fmt.Errorf("failed to increment click count: %w", err)
+	}
+
+	return nil
+}
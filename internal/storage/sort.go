@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// parseSort turns a sort parameter such as "price" or "-crawled_at" into a
+// sort document. A leading "-" sorts descending. fields maps the names callers
+// may use to document fields; an empty sort uses defaultSort.
+func parseSort(sort, defaultSort string, fields map[string]string) (bson.D, error) {
+	if sort == "" {
+		sort = defaultSort
+	}
+
+	order := 1
+	name := sort
+	if strings.HasPrefix(name, "-") {
+		order = -1
+		name = name[1:]
+	}
+
+	field, ok := fields[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSort, sort)
+	}
+
+	// Break ties by _id so pages are stable
+	return bson.D{{Key: field, Value: order}, {Key: "_id", Value: order}}, nil
+}
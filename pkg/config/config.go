@@ -1,80 +1,436 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Environment      string
-	IsProduction     bool
-	IsDevelopment    bool
-	
+	Environment   string
+	IsProduction  bool
+	IsDevelopment bool
+
 	// Discord Bot Configuration
-	DiscordToken     string
-	DiscordGuild     string
-	CommandPrefix    string
-	
+	DiscordToken          string
+	DiscordGuild          string
+	CommandPrefix         string
+	TemporaryReplySeconds int // 도움말/오류 응답을 자동 삭제하기까지의 시간 (초, 0이면 삭제하지 않음)
+	AlertMinKeywordLength int // 알림 키워드의 최소 글자 수 (문자/숫자 기준)
+	AlertCooldownMinutes  int // 같은 알림을 다시 보내기 전 기본 최소 간격 (분, 0이면 비활성화)
+
 	// MongoDB Configuration
-	MongoDBURI       string
-	MongoDBURIWebcrawler string
-	
+	MongoDBURI                   string
+	MongoDBURIWebcrawler         string
+	MongoDBName                  string // 기본 데이터베이스 이름
+	MongoDBNameWebcrawler        string // 크롤러 데이터베이스 이름
+	MongoDBDevSuffix             bool   // 개발 환경에서 기본 데이터베이스 이름에 "_dev"를 붙임
+	MongoDBMaxPoolSize           uint64 // 최대 연결 수 (0이면 드라이버 기본값 100)
+	MongoDBMinPoolSize           uint64 // 최소 유지 연결 수 (0이면 드라이버 기본값 0)
+	MongoDBConnectTimeoutSeconds int    // 연결 타임아웃 (0이면 드라이버 기본값 30초)
+	MongoDBSocketTimeoutSeconds  int    // 소켓 읽기/쓰기 타임아웃 (0이면 제한 없음)
+	DBOperationTimeoutSeconds    int    // 연결, 인덱스 생성 등 단발성 DB 작업의 최대 시간
+
 	// Discord Channels
-	ProductChannelID string
-	
+	ProductChannelID  string
+	FoodChannelID     string
+	OpsAlertChannelID string // 크롤러 이상(수집량 급감 등) 운영 알림 채널 (비어 있으면 로그만 남김)
+
+	// Food Schedule Configuration
+	FoodScheduleTime         string // "15:04" 형식
+	FoodScheduleSkipWeekends bool
+
+	// Notification Configuration
+	NotificationLanguage       string
+	NotificationTimezone       string            // 방해 금지 시간대를 해석할 시간대
+	SnoozeQueueNotifications   bool              // 알림 중지된 채널의 알림을 버리지 않고 중지가 끝난 후 전송
+	DeactivateUnsendableAlerts bool              // 봇이 메시지를 보낼 수 없는 채널의 알림을 비활성화하고 소유자에게 DM으로 안내
+	NotificationTransport      string            // "session"(봇 세션) 또는 "webhook"(채널 웹훅)
+	WebhookURLs                map[string]string // 채널 ID -> 웹훅 URL (webhook 전송 시 사용)
+	NotificationBackend        string            // "discord"(기본) 또는 "slack"
+	SlackWebhookURL            string            // 기본 Slack 수신 웹훅 URL
+	SlackChannelWebhooks       map[string]string // 알림 채널 ID -> Slack 수신 웹훅 URL
+
+	// Weather Configuration
+	WeatherAPIKey      string
+	WeatherAPIURL      string
+	WeatherDefaultCity string
+
 	// Crawler Configuration
-	CrawlIntervalMinutes int
+	CrawlIntervalMinutes      int
+	CrawlJitterPercent        int       // 실행 간격을 ±N% 범위에서 무작위로 조정 (0이면 비활성화)
+	CrawlSourceStaggerSeconds int       // 각 소스의 시작을 0~N초 사이로 분산 (0이면 동시에 시작)
+	ShutdownGraceSeconds      int       // 종료 시 전송 중인 알림을 마무리할 최대 시간
+	DryRun                    bool      // true면 DB 저장과 알림 전송 없이 결과만 로그로 출력
+	StoreOnlyMatched          bool      // true면 활성 알림 키워드와 일치하거나 인기 상품인 것만 저장 (!recent, !search에도 그것만 보임)
+	YieldWindowRuns           int       // 소스별 수집량 이동 평균에 사용할 최근 실행 수
+	YieldDropPercent          int       // 수집량이 이동 평균의 N% 미만이면 급감으로 표시
+	HotCommentThreshold       int       // 댓글이 N개 이상이면 인기 상품으로 표시 (0이면 비활성화)
+	HotViewThreshold          int       // 조회수가 N 이상이면 인기 상품으로 표시 (0이면 비활성화)
+	SaveSnapshots             bool      // 가져온 HTML을 소스별로 파일에 저장 (파서 디버깅용)
+	SnapshotDir               string    // 스냅샷 저장 디렉터리
+	SnapshotKeep              int       // 소스별로 보관할 최근 스냅샷 수
+	FetchTimeoutSeconds       int       // 페이지 요청 제한 시간
+	FetchMaxBodyBytes         int64     // 페이지 응답 본문 최대 크기 (0이면 제한 없음)
+	MaxProductsPerSource      int       // 한 번의 실행에서 소스별로 처리할 최대 상품 수 (0이면 제한 없음)
+	ProductExpireRuns         int       // 이 횟수만큼 연속으로 크롤링에서 보이지 않은 상품은 만료 처리 (0이면 비활성화)
+	RSSFeeds                  []RSSFeed // 파서 없이 RSS/Atom 피드로 수집하는 소스
+	SelectorSourcesFile       string    // CSS 선택자로 수집하는 소스 설정 파일 (JSON)
+	SelectorSources           []SelectorSource
+
+	// HTTP Server Configuration
+	HTTPAddr             string // 크롤러 HTTP 서버 주소 (비어 있으면 비활성화)
+	ClickTrackingEnabled bool   // 알림 링크를 /r/{token} 짧은 링크로 바꿔 클릭 수 집계
+	PublicBaseURL        string // 짧은 링크에 사용할 외부 주소 (예: https://deals.example.com)
+	APIKey               string // /api 요청에 필요한 키 (비어 있으면 API 비활성화)
+	MaxDealAgeHours      int    // 이보다 오래된 글은 알림을 보내지 않음 (0이면 비활성화)
+
+	// Weekly Digest Configuration
+	WeeklyDigestEnabled bool
+	WeeklyDigestWeekday time.Weekday
+	WeeklyDigestTime    string // "15:04" 형식
+
+	// Alert Summary Configuration (요약 방식 알림의 DM 발송 시각)
+	AlertSummaryTime    string       // 매일 요약을 보내는 시각 ("15:04" 형식)
+	AlertSummaryWeekday time.Weekday // 주간 요약을 보내는 요일
 }
 
 // Load loads the configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
-	
+
 	cfg := &Config{
-		Environment:     getEnv("ENVIRONMENT", "development"),
-		DiscordToken:    getEnv("DISCORD_TOKEN", ""),
-		DiscordGuild:    getEnv("DISCORD_GUILD", ""),
-		CommandPrefix:   getEnv("COMMAND_PREFIX", "!"),
-		MongoDBURI:      getEnv("MONGODB_URI", "mongodb://localhost:27017/hots"),
-		MongoDBURIWebcrawler: getEnv("MONGODB_URI_WEBCRAWLER", "mongodb://localhost:27017/webcrawler"),
-		ProductChannelID: getEnv("PRODUCT_CHANNEL_ID", ""),
-	}
-	
+		Environment:           getEnv("ENVIRONMENT", "development"),
+		DiscordToken:          getEnv("DISCORD_TOKEN", ""),
+		DiscordGuild:          getEnv("DISCORD_GUILD", ""),
+		CommandPrefix:         getEnv("COMMAND_PREFIX", "!"),
+		MongoDBURI:            getEnv("MONGODB_URI", "mongodb://localhost:27017/hots"),
+		MongoDBURIWebcrawler:  getEnv("MONGODB_URI_WEBCRAWLER", "mongodb://localhost:27017/webcrawler"),
+		MongoDBName:           getEnv("MONGODB_NAME", "discord_bot"),
+		MongoDBNameWebcrawler: getEnv("MONGODB_NAME_WEBCRAWLER", "webcrawler"),
+		ProductChannelID:      getEnv("PRODUCT_CHANNEL_ID", ""),
+		FoodChannelID:         getEnv("FOOD_CHANNEL_ID", ""),
+		OpsAlertChannelID:     getEnv("OPS_ALERT_CHANNEL_ID", ""),
+		FoodScheduleTime:      getEnv("FOOD_SCHEDULE_TIME", "11:30"),
+		WeeklyDigestTime:      getEnv("WEEKLY_DIGEST_TIME", "10:00"),
+		AlertSummaryTime:      getEnv("ALERT_SUMMARY_TIME", "09:00"),
+		NotificationLanguage:  getEnv("NOTIFICATION_LANGUAGE", "ko"),
+		NotificationTimezone:  getEnv("NOTIFICATION_TIMEZONE", "Asia/Seoul"),
+		NotificationTransport: getEnv("NOTIFICATION_TRANSPORT", "session"),
+		NotificationBackend:   getEnv("NOTIFICATION_BACKEND", "discord"),
+		SlackWebhookURL:       getEnv("SLACK_WEBHOOK_URL", ""),
+		WeatherAPIKey:         getEnv("WEATHER_API_KEY", ""),
+		WeatherAPIURL:         getEnv("WEATHER_API_URL", "https://api.openweathermap.org/data/2.5/weather"),
+		WeatherDefaultCity:    getEnv("WEATHER_DEFAULT_CITY", "Seoul"),
+		HTTPAddr:              getEnv("HTTP_ADDR", ""),
+		PublicBaseURL:         getEnv("PUBLIC_BASE_URL", ""),
+		APIKey:                getEnv("API_KEY", ""),
+		SnapshotDir:           getEnv("SNAPSHOT_DIR", "snapshots"),
+		SelectorSourcesFile:   getEnv("SELECTOR_SOURCES_FILE", ""),
+	}
+
 	// Derived properties
 	cfg.IsProduction = cfg.Environment == "production"
 	cfg.IsDevelopment = !cfg.IsProduction
-	
+
 	// Parse numeric values
 	var err error
 	cfg.CrawlIntervalMinutes, err = strconv.Atoi(getEnv("CRAWL_INTERVAL_MINUTES", "30"))
 	if err != nil {
 		cfg.CrawlIntervalMinutes = 30
 	}
-	
+
+	cfg.TemporaryReplySeconds, err = strconv.Atoi(getEnv("TEMPORARY_REPLY_SECONDS", "0"))
+	if err != nil || cfg.TemporaryReplySeconds < 0 {
+		cfg.TemporaryReplySeconds = 0
+	}
+
+	cfg.AlertMinKeywordLength, err = strconv.Atoi(getEnv("ALERT_MIN_KEYWORD_LENGTH", "2"))
+	if err != nil || cfg.AlertMinKeywordLength < 1 {
+		cfg.AlertMinKeywordLength = 2
+	}
+
+	cfg.AlertCooldownMinutes, err = strconv.Atoi(getEnv("ALERT_COOLDOWN_MINUTES", "0"))
+	if err != nil || cfg.AlertCooldownMinutes < 0 {
+		cfg.AlertCooldownMinutes = 0
+	}
+
+	cfg.CrawlJitterPercent, err = strconv.Atoi(getEnv("CRAWL_JITTER_PERCENT", "0"))
+	if err != nil || cfg.CrawlJitterPercent < 0 {
+		cfg.CrawlJitterPercent = 0
+	}
+	if cfg.CrawlJitterPercent > 100 {
+		cfg.CrawlJitterPercent = 100
+	}
+
+	cfg.CrawlSourceStaggerSeconds, err = strconv.Atoi(getEnv("CRAWL_SOURCE_STAGGER_SECONDS", "0"))
+	if err != nil || cfg.CrawlSourceStaggerSeconds < 0 {
+		cfg.CrawlSourceStaggerSeconds = 0
+	}
+
+	cfg.MongoDBDevSuffix, err = strconv.ParseBool(getEnv("MONGODB_DEV_SUFFIX", "false"))
+	if err != nil {
+		cfg.MongoDBDevSuffix = false
+	}
+
+	cfg.MongoDBMaxPoolSize, err = strconv.ParseUint(getEnv("MONGODB_MAX_POOL_SIZE", "0"), 10, 64)
+	if err != nil {
+		cfg.MongoDBMaxPoolSize = 0
+	}
+
+	cfg.MongoDBMinPoolSize, err = strconv.ParseUint(getEnv("MONGODB_MIN_POOL_SIZE", "0"), 10, 64)
+	if err != nil {
+		cfg.MongoDBMinPoolSize = 0
+	}
+
+	cfg.MongoDBConnectTimeoutSeconds, err = strconv.Atoi(getEnv("MONGODB_CONNECT_TIMEOUT_SECONDS", "0"))
+	if err != nil || cfg.MongoDBConnectTimeoutSeconds < 0 {
+		cfg.MongoDBConnectTimeoutSeconds = 0
+	}
+
+	cfg.MongoDBSocketTimeoutSeconds, err = strconv.Atoi(getEnv("MONGODB_SOCKET_TIMEOUT_SECONDS", "0"))
+	if err != nil || cfg.MongoDBSocketTimeoutSeconds < 0 {
+		cfg.MongoDBSocketTimeoutSeconds = 0
+	}
+
+	cfg.DBOperationTimeoutSeconds, err = strconv.Atoi(getEnv("DB_OPERATION_TIMEOUT_SECONDS", "10"))
+	if err != nil || cfg.DBOperationTimeoutSeconds <= 0 {
+		cfg.DBOperationTimeoutSeconds = 10
+	}
+
+	cfg.YieldWindowRuns, err = strconv.Atoi(getEnv("YIELD_WINDOW_RUNS", "10"))
+	if err != nil || cfg.YieldWindowRuns < 1 {
+		cfg.YieldWindowRuns = 10
+	}
+
+	cfg.YieldDropPercent, err = strconv.Atoi(getEnv("YIELD_DROP_PERCENT", "20"))
+	if err != nil || cfg.YieldDropPercent < 0 {
+		cfg.YieldDropPercent = 20
+	}
+	if cfg.YieldDropPercent > 100 {
+		cfg.YieldDropPercent = 100
+	}
+
+	cfg.HotCommentThreshold, err = strconv.Atoi(getEnv("HOT_COMMENT_THRESHOLD", "30"))
+	if err != nil || cfg.HotCommentThreshold < 0 {
+		cfg.HotCommentThreshold = 30
+	}
+
+	cfg.HotViewThreshold, err = strconv.Atoi(getEnv("HOT_VIEW_THRESHOLD", "0"))
+	if err != nil || cfg.HotViewThreshold < 0 {
+		cfg.HotViewThreshold = 0
+	}
+
+	cfg.SaveSnapshots, err = strconv.ParseBool(getEnv("SAVE_SNAPSHOTS", "false"))
+	if err != nil {
+		cfg.SaveSnapshots = false
+	}
+
+	cfg.SnapshotKeep, err = strconv.Atoi(getEnv("SNAPSHOT_KEEP", "20"))
+	if err != nil || cfg.SnapshotKeep < 1 {
+		cfg.SnapshotKeep = 20
+	}
+
+	cfg.FetchTimeoutSeconds, err = strconv.Atoi(getEnv("FETCH_TIMEOUT_SECONDS", "30"))
+	if err != nil || cfg.FetchTimeoutSeconds < 1 {
+		cfg.FetchTimeoutSeconds = 30
+	}
+
+	cfg.FetchMaxBodyBytes, err = strconv.ParseInt(getEnv("FETCH_MAX_BODY_BYTES", "0"), 10, 64)
+	if err != nil || cfg.FetchMaxBodyBytes < 0 {
+		cfg.FetchMaxBodyBytes = 0
+	}
+
+	cfg.MaxProductsPerSource, err = strconv.Atoi(getEnv("MAX_PRODUCTS_PER_SOURCE", "1000"))
+	if err != nil || cfg.MaxProductsPerSource < 0 {
+		cfg.MaxProductsPerSource = 1000
+	}
+
+	cfg.ProductExpireRuns, err = strconv.Atoi(getEnv("PRODUCT_EXPIRE_RUNS", "48"))
+	if err != nil || cfg.ProductExpireRuns < 0 {
+		cfg.ProductExpireRuns = 48
+	}
+
+	cfg.DryRun, err = strconv.ParseBool(getEnv("DRY_RUN", "false"))
+	if err != nil {
+		cfg.DryRun = false
+	}
+
+	cfg.StoreOnlyMatched, err = strconv.ParseBool(getEnv("STORE_ONLY_MATCHED", "false"))
+	if err != nil {
+		cfg.StoreOnlyMatched = false
+	}
+
+	cfg.ClickTrackingEnabled, err = strconv.ParseBool(getEnv("CLICK_TRACKING_ENABLED", "false"))
+	if err != nil {
+		cfg.ClickTrackingEnabled = false
+	}
+
+	cfg.ShutdownGraceSeconds, err = strconv.Atoi(getEnv("SHUTDOWN_GRACE_SECONDS", "10"))
+	if err != nil || cfg.ShutdownGraceSeconds < 0 {
+		cfg.ShutdownGraceSeconds = 10
+	}
+
+	cfg.FoodScheduleSkipWeekends, err = strconv.ParseBool(getEnv("FOOD_SCHEDULE_SKIP_WEEKENDS", "true"))
+	if err != nil {
+		cfg.FoodScheduleSkipWeekends = true
+	}
+
+	cfg.WeeklyDigestEnabled, err = strconv.ParseBool(getEnv("WEEKLY_DIGEST_ENABLED", "true"))
+	if err != nil {
+		cfg.WeeklyDigestEnabled = true
+	}
+
+	cfg.WeeklyDigestWeekday, err = parseWeekday(getEnv("WEEKLY_DIGEST_WEEKDAY", "monday"))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.AlertSummaryWeekday, err = parseWeekday(getEnv("ALERT_SUMMARY_WEEKDAY", "monday"))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.WebhookURLs, err = parseWebhookURLs("DISCORD_WEBHOOK_URLS", getEnv("DISCORD_WEBHOOK_URLS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.SlackChannelWebhooks, err = parseWebhookURLs("SLACK_CHANNEL_WEBHOOKS", getEnv("SLACK_CHANNEL_WEBHOOKS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.RSSFeeds, err = parseRSSFeeds(getEnv("RSS_FEEDS", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.SelectorSourcesFile != "" {
+		cfg.SelectorSources, err = loadSelectorSources(cfg.SelectorSourcesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.SnoozeQueueNotifications, err = strconv.ParseBool(getEnv("SNOOZE_QUEUE_NOTIFICATIONS", "false"))
+	if err != nil {
+		cfg.SnoozeQueueNotifications = false
+	}
+
+	cfg.DeactivateUnsendableAlerts, err = strconv.ParseBool(getEnv("DEACTIVATE_UNSENDABLE_ALERTS", "false"))
+	if err != nil {
+		cfg.DeactivateUnsendableAlerts = false
+	}
+
+	cfg.MaxDealAgeHours, err = strconv.Atoi(getEnv("MAX_DEAL_AGE_HOURS", "72"))
+	if err != nil {
+		cfg.MaxDealAgeHours = 72
+	}
+
 	// Validate required configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
-	
+
 	return cfg, nil
 }
 
 // Validate checks if all required configuration is present
 func (c *Config) Validate() error {
-	if c.DiscordToken == "" {
+	// Webhook delivery doesn't use the bot, so the crawler can run without a token
+	if c.DiscordToken == "" && c.NotificationTransport != "webhook" {
 		return fmt.Errorf("DISCORD_TOKEN environment variable is required")
 	}
-	
+
+	if strings.TrimSpace(c.MongoDBName) == "" {
+		return fmt.Errorf("MONGODB_NAME must not be empty")
+	}
+
+	if strings.TrimSpace(c.MongoDBNameWebcrawler) == "" {
+		return fmt.Errorf("MONGODB_NAME_WEBCRAWLER must not be empty")
+	}
+
+	if c.MongoDBMaxPoolSize > 0 && c.MongoDBMinPoolSize > c.MongoDBMaxPoolSize {
+		return fmt.Errorf("MONGODB_MIN_POOL_SIZE (%d) must not exceed MONGODB_MAX_POOL_SIZE (%d)", c.MongoDBMinPoolSize, c.MongoDBMaxPoolSize)
+	}
+
+	if c.NotificationLanguage != "ko" && c.NotificationLanguage != "en" {
+		return fmt.Errorf("NOTIFICATION_LANGUAGE must be \"ko\" or \"en\", got %q", c.NotificationLanguage)
+	}
+
+	if _, err := time.Parse("15:04", c.FoodScheduleTime); err != nil {
+		return fmt.Errorf("FOOD_SCHEDULE_TIME must be in HH:MM format, got %q", c.FoodScheduleTime)
+	}
+
+	if _, err := time.Parse("15:04", c.WeeklyDigestTime); err != nil {
+		return fmt.Errorf("WEEKLY_DIGEST_TIME must be in HH:MM format, got %q", c.WeeklyDigestTime)
+	}
+
+	if _, err := time.Parse("15:04", c.AlertSummaryTime); err != nil {
+		return fmt.Errorf("ALERT_SUMMARY_TIME must be in HH:MM format, got %q", c.AlertSummaryTime)
+	}
+
+	if c.ClickTrackingEnabled && (c.PublicBaseURL == "" || c.HTTPAddr == "") {
+		return fmt.Errorf("CLICK_TRACKING_ENABLED requires PUBLIC_BASE_URL and HTTP_ADDR")
+	}
+
+	switch c.NotificationTransport {
+	case "session":
+	case "webhook":
+		if len(c.WebhookURLs) == 0 {
+			return fmt.Errorf("NOTIFICATION_TRANSPORT=webhook requires DISCORD_WEBHOOK_URLS")
+		}
+	default:
+		return fmt.Errorf("NOTIFICATION_TRANSPORT must be \"session\" or \"webhook\", got %q", c.NotificationTransport)
+	}
+
+	switch c.NotificationBackend {
+	case "discord":
+	case "slack":
+		if c.SlackWebhookURL == "" && len(c.SlackChannelWebhooks) == 0 {
+			return fmt.Errorf("NOTIFICATION_BACKEND=slack requires SLACK_WEBHOOK_URL or SLACK_CHANNEL_WEBHOOKS")
+		}
+	default:
+		return fmt.Errorf("NOTIFICATION_BACKEND must be \"discord\" or \"slack\", got %q", c.NotificationBackend)
+	}
+
+	// Source names key the crawl stats, so feed and selector sources must not share one
+	for _, source := range c.SelectorSources {
+		for _, feed := range c.RSSFeeds {
+			if strings.EqualFold(source.Name, feed.Name) {
+				return fmt.Errorf("source name %q is used by both RSS_FEEDS and SELECTOR_SOURCES_FILE", source.Name)
+			}
+		}
+	}
+
 	// Add more validation as needed
-	
+
 	return nil
 }
 
+// DatabaseName returns the name of the main database, with the "_dev" suffix
+// when MongoDBDevSuffix is enabled in development
+func (c *Config) DatabaseName() string {
+	if c.MongoDBDevSuffix && c.IsDevelopment {
+		return c.MongoDBName + "_dev"
+	}
+	return c.MongoDBName
+}
+
+// DBOperationTimeout returns the deadline for one-off database operations
+// such as connecting and creating indexes
+func (c *Config) DBOperationTimeout() time.Duration {
+	return time.Duration(c.DBOperationTimeoutSeconds) * time.Second
+}
+
 // getEnv gets an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -82,4 +438,106 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// parseWeekday parses an English weekday name such as "monday" or "mon"
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q", s)
+}
+
+// RSSFeed is a deal source read from an RSS or Atom feed
+type RSSFeed struct {
+	Name string // 소스 이름 (상품의 Source, 통계에 표시)
+	URL  string // 피드 주소
+}
+
+// parseRSSFeeds parses "name=url,name=url" from RSS_FEEDS, keeping the order
+func parseRSSFeeds(s string) ([]RSSFeed, error) {
+	var feeds []RSSFeed
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, url, ok := strings.Cut(entry, "=")
+		name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+		if !ok || name == "" || url == "" {
+			return nil, fmt.Errorf("invalid RSS_FEEDS entry %q, expected name=url", entry)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("duplicate RSS_FEEDS source name %q", name)
+		}
+		seen[strings.ToLower(name)] = true
+		feeds = append(feeds, RSSFeed{Name: name, URL: url})
+	}
+	return feeds, nil
+}
+
+// SelectorSource is a deal source scraped from a list page with CSS selectors,
+// loaded from the JSON file in SELECTOR_SOURCES_FILE. Selectors other than
+// Row are matched within each row.
+type SelectorSource struct {
+	Name       string `json:"name"`        // 소스 이름 (상품의 Source, 통계에 표시)
+	URL        string `json:"url"`         // 목록 페이지 주소 (상대 링크의 기준)
+	Row        string `json:"row"`         // 상품 한 건을 감싸는 요소
+	Title      string `json:"title"`       // 제목 요소
+	Link       string `json:"link"`        // 링크 요소 (비어 있으면 행의 첫 번째 a)
+	Price      string `json:"price"`       // 가격 요소 (비어 있으면 제목에서 추출)
+	Comments   string `json:"comments"`    // 댓글 수 요소 (비어 있으면 0)
+	PriceRegex string `json:"price_regex"` // 가격 추출 정규식 (비어 있으면 "원" 단위 기본값)
+}
+
+// loadSelectorSources reads the selector sources in the JSON file at path.
+// Selectors and price regexes are compiled when the sources are created.
+func loadSelectorSources(path string) ([]SelectorSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SELECTOR_SOURCES_FILE: %w", err)
+	}
+
+	var selectorSources []SelectorSource
+	if err := json.Unmarshal(data, &selectorSources); err != nil {
+		return nil, fmt.Errorf("failed to parse SELECTOR_SOURCES_FILE: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, source := range selectorSources {
+		if source.Name == "" || source.URL == "" || source.Row == "" || source.Title == "" {
+			return nil, fmt.Errorf("SELECTOR_SOURCES_FILE entry %d requires name, url, row and title", i)
+		}
+		if seen[strings.ToLower(source.Name)] {
+			return nil, fmt.Errorf("duplicate SELECTOR_SOURCES_FILE source name %q", source.Name)
+		}
+		seen[strings.ToLower(source.Name)] = true
+	}
+	return selectorSources, nil
+}
+
+// parseWebhookURLs parses "channelID=url,channelID=url" from the env var key
+// into a channel -> webhook URL map
+func parseWebhookURLs(key, s string) (map[string]string, error) {
+	urls := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		channelID, url, ok := strings.Cut(entry, "=")
+		channelID, url = strings.TrimSpace(channelID), strings.TrimSpace(url)
+		if !ok || channelID == "" || url == "" {
+			return nil, fmt.Errorf("invalid %s entry %q, expected channelID=url", key, entry)
+		}
+		urls[channelID] = url
+	}
+	return urls, nil
+}
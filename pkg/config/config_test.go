@@ -0,0 +1,117 @@
+package config
+
+import "testing"
+
+// validConfig returns a configuration that passes Validate, to be changed by each test
+func validConfig() *Config {
+	return &Config{
+		DiscordToken:          "token",
+		MongoDBName:           "gbot",
+		MongoDBNameWebcrawler: "webcrawler",
+		NotificationLanguage:  "ko",
+		FoodScheduleTime:      "11:30",
+		WeeklyDigestTime:      "10:00",
+		AlertSummaryTime:      "21:00",
+		NotificationTransport: "session",
+		NotificationBackend:   "discord",
+	}
+}
+
+func TestValidateDiscordToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport string
+		webhooks  map[string]string
+		wantErr   bool
+	}{
+		{name: "session requires token", transport: "session", wantErr: true},
+		{name: "webhook without token", transport: "webhook", webhooks: map[string]string{"1": "https://discord.com/api/webhooks/1/x"}},
+		{name: "webhook still requires URLs", transport: "webhook", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.DiscordToken = ""
+			cfg.NotificationTransport = tt.transport
+			cfg.WebhookURLs = tt.webhooks
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidConfigPasses(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadDatabaseNames(t *testing.T) {
+	t.Setenv("DISCORD_TOKEN", "token")
+	t.Setenv("MONGODB_NAME", "")
+	t.Setenv("MONGODB_NAME_WEBCRAWLER", "")
+	t.Setenv("MONGODB_DEV_SUFFIX", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MongoDBName != "discord_bot" || cfg.MongoDBNameWebcrawler != "webcrawler" {
+		t.Errorf("database names = %q, %q, want the defaults discord_bot, webcrawler", cfg.MongoDBName, cfg.MongoDBNameWebcrawler)
+	}
+
+	t.Setenv("MONGODB_NAME", "staging_bot")
+	t.Setenv("MONGODB_NAME_WEBCRAWLER", "staging_crawler")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.MongoDBName != "staging_bot" || cfg.MongoDBNameWebcrawler != "staging_crawler" {
+		t.Errorf("database names = %q, %q, want the configured names", cfg.MongoDBName, cfg.MongoDBNameWebcrawler)
+	}
+}
+
+func TestValidateDatabaseNames(t *testing.T) {
+	for _, name := range []string{"", "   "} {
+		cfg := validConfig()
+		cfg.MongoDBName = name
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() with MongoDBName %q passed, want an error", name)
+		}
+
+		cfg = validConfig()
+		cfg.MongoDBNameWebcrawler = name
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() with MongoDBNameWebcrawler %q passed, want an error", name)
+		}
+	}
+}
+
+func TestDatabaseName(t *testing.T) {
+	tests := []struct {
+		name        string
+		development bool
+		devSuffix   bool
+		want        string
+	}{
+		{name: "production", want: "gbot"},
+		{name: "development without suffix", development: true, want: "gbot"},
+		{name: "development with suffix", development: true, devSuffix: true, want: "gbot_dev"},
+		{name: "suffix outside development", devSuffix: true, want: "gbot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.IsDevelopment = tt.development
+			cfg.MongoDBDevSuffix = tt.devSuffix
+			if got := cfg.DatabaseName(); got != tt.want {
+				t.Errorf("DatabaseName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maskSecret reports only whether a secret is set, never its value
+func maskSecret(secret string) string {
+	if secret == "" {
+		return "(unset)"
+	}
+	return "(set)"
+}
+
+// Summary returns the effective configuration as a single line of key=value
+// pairs for the startup log. Secrets are reported only as set or unset, and
+// credentials are removed from MongoDB URIs.
+func (c *Config) Summary() string {
+	fields := []struct {
+		key   string
+		value interface{}
+	}{
+		{"environment", c.Environment},
+		{"discord_token", maskSecret(c.DiscordToken)},
+		{"command_prefix", c.CommandPrefix},
+		{"temporary_reply_seconds", c.TemporaryReplySeconds},
+		{"mongodb_uri", Redact(c.MongoDBURI)},
+		{"mongodb_uri_webcrawler", Redact(c.MongoDBURIWebcrawler)},
+		{"database", c.DatabaseName()},
+		{"database_webcrawler", c.MongoDBNameWebcrawler},
+		{"mongodb_max_pool_size", c.MongoDBMaxPoolSize},
+		{"mongodb_min_pool_size", c.MongoDBMinPoolSize},
+		{"db_operation_timeout", c.DBOperationTimeout()},
+		{"product_channel_id", c.ProductChannelID},
+		{"ops_alert_channel_id", c.OpsAlertChannelID},
+		{"notification_backend", c.NotificationBackend},
+		{"notification_transport", c.NotificationTransport},
+		{"deactivate_unsendable_alerts", c.DeactivateUnsendableAlerts},
+		{"notification_language", c.NotificationLanguage},
+		{"notification_timezone", c.NotificationTimezone},
+		{"discord_webhooks", len(c.WebhookURLs)},
+		{"slack_webhook_url", maskSecret(c.SlackWebhookURL)},
+		{"slack_channel_webhooks", len(c.SlackChannelWebhooks)},
+		{"crawl_interval_minutes", c.CrawlIntervalMinutes},
+		{"crawl_jitter_percent", c.CrawlJitterPercent},
+		{"crawl_source_stagger_seconds", c.CrawlSourceStaggerSeconds},
+		{"dry_run", c.DryRun},
+		{"store_only_matched", c.StoreOnlyMatched},
+		{"max_deal_age_hours", c.MaxDealAgeHours},
+		{"hot_comment_threshold", c.HotCommentThreshold},
+		{"hot_view_threshold", c.HotViewThreshold},
+		{"yield_window_runs", c.YieldWindowRuns},
+		{"yield_drop_percent", c.YieldDropPercent},
+		{"save_snapshots", c.SaveSnapshots},
+		{"fetch_timeout_seconds", c.FetchTimeoutSeconds},
+		{"fetch_max_body_bytes", c.FetchMaxBodyBytes},
+		{"max_products_per_source", c.MaxProductsPerSource},
+		{"product_expire_runs", c.ProductExpireRuns},
+		{"rss_feeds", len(c.RSSFeeds)},
+		{"selector_sources", len(c.SelectorSources)},
+		{"alert_min_keyword_length", c.AlertMinKeywordLength},
+		{"alert_cooldown_minutes", c.AlertCooldownMinutes},
+		{"http_addr", c.HTTPAddr},
+		{"api_key", maskSecret(c.APIKey)},
+		{"click_tracking_enabled", c.ClickTrackingEnabled},
+		{"weekly_digest_enabled", c.WeeklyDigestEnabled},
+		{"alert_summary_time", c.AlertSummaryTime},
+		{"alert_summary_weekday", c.AlertSummaryWeekday},
+		{"weather_api_key", maskSecret(c.WeatherAPIKey)},
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value := fmt.Sprint(field.value)
+		if value == "" {
+			value = `""`
+		}
+		parts = append(parts, field.key+"="+value)
+	}
+	return strings.Join(parts, " ")
+}